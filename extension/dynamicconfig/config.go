@@ -15,6 +15,10 @@
 package dynamicconfig
 
 import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
@@ -35,6 +39,13 @@ type Config struct {
 	// specified, then "RemoteConfigAddress" will take precedence.
 	RemoteConfigAddress string `mapstructure:"remote_config_address"`
 
+	// RemoteConfig configures the connection to an upstream remote
+	// configuration service, like RemoteConfigAddress, but additionally
+	// supports TLS, per-RPC authentication, failover and caching. Unlike
+	// RemoteConfigAddress, the connection uses TLS unless "insecure" is set.
+	// If specified, it takes precedence over RemoteConfigAddress.
+	RemoteConfig *RemoteConfig `mapstructure:"remote_config"`
+
 	// LocalConfigFile is the local record of configuration updates, applied
 	// when a third-party config service backend is not used. If this file is
 	// not specified, and no other config backends are specified, then it
@@ -48,4 +59,57 @@ type Config struct {
 	// before querying the collector for an updated configuration. Defaults to
 	// 30 seconds.
 	WaitTime int `mapstructure:"wait_time"`
+
+	// AdminAPI enables an HTTP/JSON API used to inspect the config blocks and
+	// edit them at runtime. It is only available with the local config file
+	// backend. Disabled by default.
+	AdminAPI *AdminAPIConfig `mapstructure:"admin_api"`
+}
+
+// RemoteConfig has the settings used to connect to upstream remote
+// configuration services.
+type RemoteConfig struct {
+	// Endpoint, TLS and per-RPC authentication settings of the primary
+	// upstream service. TLS settings and credentials are shared with the
+	// failover endpoints.
+	configgrpc.GRPCClientSettings `mapstructure:",squash"`
+
+	// FailoverEndpoints are the addresses of further upstream services, tried
+	// in order when the primary endpoint cannot be reached.
+	FailoverEndpoints []string `mapstructure:"failover_endpoints"`
+
+	// CacheTTL is how long the response for a resource is served from the
+	// cache before the upstream services are queried again. When all upstream
+	// services are unreachable, the last known response is served regardless
+	// of its age. Defaults to 0, which forwards every request upstream.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+
+	// Timeout is how long a call to an upstream service may take before the
+	// next upstream service is tried, or the last known response is served.
+	// Defaults to 10 seconds.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// AdminAPIConfig has the settings of the HTTP/JSON admin API.
+type AdminAPIConfig struct {
+	// Endpoint and TLS settings of the HTTP server serving the admin API.
+	confighttp.HTTPServerSettings `mapstructure:",squash"`
+
+	// BearerToken, if set, is required in the Authorization header of every
+	// request to the admin API, as "Bearer <token>".
+	BearerToken string `mapstructure:"bearer_token"`
+
+	// Persist specifies whether changes made through the admin API are written
+	// back to LocalConfigFile. Otherwise, changes are lost the next time the
+	// file is modified or the collector restarts. Requires BearerToken, or
+	// client certificates through tls_settings.client_ca_file. Defaults to
+	// false.
+	Persist bool `mapstructure:"persist"`
+}
+
+// authenticated reports whether the admin API requires either a bearer token
+// or a client certificate.
+func (cfg *AdminAPIConfig) authenticated() bool {
+	return cfg.BearerToken != "" ||
+		(cfg.TLSSetting != nil && cfg.TLSSetting.ClientCAFile != "")
 }
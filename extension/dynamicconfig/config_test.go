@@ -17,13 +17,17 @@ package dynamicconfig
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -55,6 +59,60 @@ func TestLoadConfig(t *testing.T) {
 		ext1,
 	)
 
+	ext2 := cfg.Extensions["dynamicconfig/2"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "dynamicconfig",
+				NameVal: "dynamicconfig/2",
+			},
+			Endpoint:        "0.0.0.0:55700",
+			LocalConfigFile: "schedules.yaml",
+			WaitTime:        30,
+			RemoteConfig: &RemoteConfig{
+				GRPCClientSettings: configgrpc.GRPCClientSettings{
+					Endpoint: "config.example.com:443",
+					TLSSetting: configtls.TLSClientSetting{
+						TLSSetting: configtls.TLSSetting{
+							CAFile:   "/var/lib/certs/ca.pem",
+							CertFile: "/var/lib/certs/client.pem",
+							KeyFile:  "/var/lib/certs/client-key.pem",
+						},
+					},
+					PerRPCAuth: &configgrpc.PerRPCAuthConfig{
+						AuthType:    "bearer",
+						BearerToken: "some-token",
+					},
+				},
+				FailoverEndpoints: []string{"config-backup.example.com:443"},
+				CacheTTL:          time.Minute,
+				Timeout:           5 * time.Second,
+			},
+		},
+		ext2,
+	)
+
+	ext3 := cfg.Extensions["dynamicconfig/3"]
+	assert.Equal(t,
+		&Config{
+			ExtensionSettings: configmodels.ExtensionSettings{
+				TypeVal: "dynamicconfig",
+				NameVal: "dynamicconfig/3",
+			},
+			Endpoint:        "0.0.0.0:55700",
+			LocalConfigFile: "schedules.yaml",
+			WaitTime:        30,
+			AdminAPI: &AdminAPIConfig{
+				HTTPServerSettings: confighttp.HTTPServerSettings{
+					Endpoint: "localhost:55701",
+				},
+				BearerToken: "admin-token",
+				Persist:     true,
+			},
+		},
+		ext3,
+	)
+
 	assert.Equal(t, 1, len(cfg.Service.Extensions))
 	assert.Equal(t, "dynamicconfig/1", cfg.Service.Extensions[0])
 }
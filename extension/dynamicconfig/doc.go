@@ -14,6 +14,7 @@
 
 // Package dynamicconfig implements an extension that enables dynamically
 // configuring metric collection schedule, trace parameters, and other
-// tunable knobs on an instrumented system. At the moment, only metric
-// collection schedules have been implemented.
+// tunable knobs on an instrumented system. At the moment, metric collection
+// schedules and trace parameters (sampling and span limits) have been
+// implemented.
 package dynamicconfig
@@ -16,7 +16,9 @@ package dynamicconfig
 
 import (
 	"context"
+	"errors"
 	"net"
+	"net/http"
 
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
@@ -24,6 +26,7 @@ import (
 
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/service"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/service/admin"
 )
 
 type dynamicConfigExtension struct {
@@ -31,6 +34,7 @@ type dynamicConfigExtension struct {
 	logger        *zap.Logger
 	server        *grpc.Server           // gRPC server that physically serves the ConfigService
 	configService *service.ConfigService // implements the gRPC configuration service
+	adminServer   *http.Server           // serves the admin API, if enabled
 }
 
 func newServer(config Config, logger *zap.Logger) (*dynamicConfigExtension, error) {
@@ -44,14 +48,29 @@ func newServer(config Config, logger *zap.Logger) (*dynamicConfigExtension, erro
 }
 
 func (de *dynamicConfigExtension) Start(ctx context.Context, host component.Host) error {
-	de.logger.Info("Starting dynamic config extension", zap.Any("config", de.config))
+	de.logger.Info("Starting dynamic config extension", zap.String("endpoint", de.config.Endpoint))
 	listen, err := net.Listen("tcp", de.config.Endpoint)
 	if err != nil {
 		return err
 	}
 
 	var configService *service.ConfigService
-	if de.config.RemoteConfigAddress != "" {
+	if remoteConfig := de.config.RemoteConfig; remoteConfig != nil {
+		var dialOptions []grpc.DialOption
+		dialOptions, err = remoteConfig.ToDialOptions()
+		if err != nil {
+			listen.Close()
+			return err
+		}
+
+		configService, err = service.NewConfigService(
+			service.WithRemoteConfig(remoteConfig.Endpoint),
+			service.WithRemoteFailover(remoteConfig.FailoverEndpoints...),
+			service.WithRemoteDialOptions(dialOptions...),
+			service.WithRemoteCacheTTL(remoteConfig.CacheTTL),
+			service.WithRemoteRequestTimeout(remoteConfig.Timeout),
+		)
+	} else if de.config.RemoteConfigAddress != "" {
 		configService, err = service.NewConfigService(
 			service.WithRemoteConfig(de.config.RemoteConfigAddress),
 		)
@@ -59,14 +78,28 @@ func (de *dynamicConfigExtension) Start(ctx context.Context, host component.Host
 		configService, err = service.NewConfigService(
 			service.WithFileConfig(de.config.LocalConfigFile),
 			service.WithWaitTime(int32(de.config.WaitTime)),
+			service.WithPersist(de.config.AdminAPI != nil && de.config.AdminAPI.Persist),
 		)
 	}
 	if err != nil {
+		listen.Close()
 		return err
 	}
 
 	de.configService = configService
 	pb.RegisterMetricConfigServer(de.server, configService)
+	pb.RegisterTraceConfigServer(de.server, configService)
+
+	if de.config.AdminAPI != nil {
+		if err := de.startAdminServer(host); err != nil {
+			// The gRPC server is not serving yet, release its listener so
+			// that the extension can be started again.
+			configService.Stop()
+			de.server.Stop()
+			listen.Close()
+			return err
+		}
+	}
 
 	go func() {
 		if err := de.server.Serve(listen); err != nil {
@@ -77,9 +110,62 @@ func (de *dynamicConfigExtension) Start(ctx context.Context, host component.Host
 	return nil
 }
 
+func (de *dynamicConfigExtension) startAdminServer(host component.Host) error {
+	editor, ok := de.configService.Backend().(admin.Editor)
+	if !ok {
+		return errors.New("the admin API requires the local config file backend")
+	}
+
+	cfg := de.config.AdminAPI
+	de.logger.Info("Starting dynamic config admin API", zap.String("endpoint", cfg.Endpoint))
+	if !cfg.authenticated() && !isLoopback(cfg.Endpoint) {
+		de.logger.Warn("The dynamic config admin API accepts unauthenticated requests on a non-loopback address",
+			zap.String("endpoint", cfg.Endpoint))
+	}
+
+	listen, err := cfg.ToListener()
+	if err != nil {
+		return err
+	}
+
+	handler := admin.NewHandler(editor, de.logger)
+	if cfg.BearerToken != "" {
+		handler = admin.RequireBearerToken(cfg.BearerToken, handler)
+	}
+
+	de.adminServer = cfg.ToServer(handler)
+	go func() {
+		if err := de.adminServer.Serve(listen); err != nil && err != http.ErrServerClosed {
+			host.ReportFatalError(err)
+		}
+	}()
+
+	return nil
+}
+
+// isLoopback reports whether endpoint only listens on the loopback interface.
+func isLoopback(endpoint string) bool {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func (de *dynamicConfigExtension) Shutdown(ctx context.Context) error {
 	de.logger.Info("Shutting down dynamic config extension")
+	var err error
+	if de.adminServer != nil {
+		err = de.adminServer.Close()
+	}
+
 	de.configService.Stop()
 	de.server.GracefulStop()
-	return nil
+	return err
 }
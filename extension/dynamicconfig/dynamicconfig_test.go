@@ -17,6 +17,7 @@ package dynamicconfig
 import (
 	"context"
 	"net"
+	"net/http"
 	"runtime"
 	"testing"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/service/mock"
 
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/testutil"
 
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
@@ -194,3 +196,97 @@ func TestDynamicConfigBadSchedules(t *testing.T) {
 
 	defer dynamicconfigExt.Shutdown(context.Background())
 }
+
+func TestDynamicConfigAdminAPI(t *testing.T) {
+	config := Config{
+		Endpoint:        testutil.GetAvailableLocalAddress(t),
+		LocalConfigFile: "testdata/schedules.yaml",
+		AdminAPI: &AdminAPIConfig{
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: testutil.GetAvailableLocalAddress(t),
+			},
+		},
+	}
+
+	dynamicconfigExt, err := newServer(config, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, dynamicconfigExt)
+
+	require.NoError(t, dynamicconfigExt.Start(context.Background(), componenttest.NewNopHost()))
+	defer dynamicconfigExt.Shutdown(context.Background())
+
+	resp, err := http.Get("http://" + config.AdminAPI.Endpoint + "/v1/blocks")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDynamicConfigAdminAPIBearerToken(t *testing.T) {
+	config := Config{
+		Endpoint:        testutil.GetAvailableLocalAddress(t),
+		LocalConfigFile: "testdata/schedules.yaml",
+		AdminAPI: &AdminAPIConfig{
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: testutil.GetAvailableLocalAddress(t),
+			},
+			BearerToken: "secret",
+		},
+	}
+
+	dynamicconfigExt, err := newServer(config, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, dynamicconfigExt)
+
+	require.NoError(t, dynamicconfigExt.Start(context.Background(), componenttest.NewNopHost()))
+	defer dynamicconfigExt.Shutdown(context.Background())
+
+	url := "http://" + config.AdminAPI.Endpoint + "/v1/blocks/0"
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, err = http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestIsLoopback(t *testing.T) {
+	require.True(t, isLoopback("localhost:55701"))
+	require.True(t, isLoopback("127.0.0.1:55701"))
+	require.True(t, isLoopback("[::1]:55701"))
+	require.False(t, isLoopback("0.0.0.0:55701"))
+	require.False(t, isLoopback(":55701"))
+	require.False(t, isLoopback("example.com:55701"))
+}
+
+func TestDynamicConfigAdminAPIRemoteBackend(t *testing.T) {
+	config := Config{
+		Endpoint:            testutil.GetAvailableLocalAddress(t),
+		RemoteConfigAddress: testutil.GetAvailableLocalAddress(t),
+		AdminAPI: &AdminAPIConfig{
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: testutil.GetAvailableLocalAddress(t),
+			},
+		},
+	}
+
+	dynamicconfigExt, err := newServer(config, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, dynamicconfigExt)
+
+	require.Error(t, dynamicconfigExt.Start(context.Background(), componenttest.NewNopHost()))
+	dynamicconfigExt.Shutdown(context.Background())
+
+	// The failed start released the gRPC endpoint.
+	listen, err := net.Listen("tcp", config.Endpoint)
+	require.NoError(t, err)
+	listen.Close()
+}
@@ -57,7 +57,19 @@ func (f *Factory) CreateExtension(ctx context.Context, params component.Extensio
 		return nil, errors.New("\"endpoint\" is required when using the \"dynamicconfig\" extension")
 	}
 
-	if config.LocalConfigFile == "" && config.RemoteConfigAddress == "" {
+	if config.RemoteConfig != nil && config.RemoteConfig.Endpoint == "" {
+		return nil, errors.New("\"endpoint\" is required in \"remote_config\"")
+	}
+
+	if config.AdminAPI != nil && config.AdminAPI.Endpoint == "" {
+		return nil, errors.New("\"endpoint\" is required in \"admin_api\"")
+	}
+
+	if config.AdminAPI != nil && config.AdminAPI.Persist && !config.AdminAPI.authenticated() {
+		return nil, errors.New("\"persist\" in \"admin_api\" requires \"bearer_token\" or \"tls_settings\" with \"client_ca_file\"")
+	}
+
+	if config.LocalConfigFile == "" && config.RemoteConfigAddress == "" && config.RemoteConfig == nil {
 		return nil, errors.New("\" local_config_file is required when a remote configuration service is not specified\"")
 	}
 
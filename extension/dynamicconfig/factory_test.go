@@ -25,6 +25,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/testutil"
 )
@@ -102,6 +103,31 @@ func TestFactory_MissingConfigs(t *testing.T) {
 		t.Errorf("expected incomplete config to throw error: %v", cfg)
 	}
 
+	cfg.RemoteConfig = &RemoteConfig{}
+	ext, err = factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	if ext != nil || err == nil {
+		t.Errorf("expected remote config without endpoint to throw error: %v", cfg)
+	}
+
+	cfg.RemoteConfig = nil
+	cfg.AdminAPI = &AdminAPIConfig{}
+	ext, err = factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	if ext != nil || err == nil {
+		t.Errorf("expected admin API without endpoint to throw error: %v", cfg)
+	}
+
+	cfg.AdminAPI = &AdminAPIConfig{
+		HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "localhost:55702"},
+		Persist:            true,
+	}
+	cfg.LocalConfigFile = "testdata/schedules.yaml"
+	ext, err = factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	if ext != nil || err == nil {
+		t.Errorf("expected admin API persisting changes without authentication to throw error: %v", cfg)
+	}
+	cfg.LocalConfigFile = ""
+
+	cfg.AdminAPI = nil
 	cfg.RemoteConfigAddress = "localhost:55701"
 	ext, err = factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
 	require.NoError(t, err)
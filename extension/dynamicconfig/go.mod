@@ -13,6 +13,7 @@ require (
 	go.opentelemetry.io/collector v0.8.0
 	go.uber.org/zap v1.15.0
 	google.golang.org/grpc v1.31.0
+	gopkg.in/yaml.v2 v2.3.0
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig => ./
@@ -21,6 +21,7 @@ package model
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	com "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/common/v1"
@@ -31,28 +32,87 @@ import (
 // It has the ability to generate a new ConfigBlock that contains the relevant
 // configuration data matching a particular resource (see Match).
 type Config struct {
-	ConfigBlocks []*ConfigBlock
+	ConfigBlocks []*ConfigBlock `yaml:"ConfigBlocks"`
 }
 
 // Given a resource, Match will compile a config block that contains the
-// relevant configs. If all resource labels in a config block match a key-value
-// pair in the given resource, then the configs from this block will be included
-// in the returned config block.  If a config block specifies no resource
-// labels, then it will be included in all matches. In this way, a user may
-// specify default configs to be included for all resources.
+// relevant configs. A config block matches the resource if all resource labels
+// in the block match a key-value pair in the given resource, and if all of the
+// block's selectors are satisfied by the resource. Selectors take one of the
+// following forms:
+//    * key=value, key==value or key:value
+//    * key!=value
+//    * key in (value1, value2, ...)
+//    * key notin (value1, value2, ...)
+//    * key (the label exists) or !key (the label does not exist)
+//    * key=~regex or key!~regex, where the regex must match the entire value
+// If a config block specifies no resource labels and no selectors, then it will
+// be included in all matches. In this way, a user may specify default configs
+// to be included for all resources.
+//
+// Matching blocks are combined in order of increasing priority, keeping the
+// file order between blocks of equal priority, so that the trace parameters of
+// higher priority blocks take precedence. If an exclusive block matches, then
+// matching blocks with a lower priority are left out entirely.
 func (config *Config) Match(resource *res.Resource) *ConfigBlock {
-	labelSet, labelList := embed(resource)
+	_, labelList := embed(resource)
 	totalBlock := &ConfigBlock{
 		Resource: labelList,
 	}
 
+	for _, i := range config.MatchingBlocks(resource) {
+		totalBlock.Add(config.ConfigBlocks[i])
+	}
+
+	return totalBlock
+}
+
+// MatchingBlocks returns the indices of the config blocks that Match combines
+// for the given resource, in the order in which they are combined.
+func (config *Config) MatchingBlocks(resource *res.Resource) []int {
+	labelSet, _ := embed(resource)
+	labelMap := embedMap(resource)
+
+	var matched []int
+	for i, block := range config.ConfigBlocks {
+		if doInclude(block, labelSet) && doSelect(block, labelMap) {
+			matched = append(matched, i)
+		}
+	}
+
+	priority := func(i int) int {
+		return config.ConfigBlocks[matched[i]].Priority
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return priority(i) < priority(j)
+	})
+
+	// matched is sorted, so the last exclusive block has the highest priority
+	start := 0
+	for i, idx := range matched {
+		if config.ConfigBlocks[idx].Exclusive {
+			start = i
+			for start > 0 && priority(start-1) == priority(i) {
+				start--
+			}
+		}
+	}
+
+	return matched[start:]
+}
+
+// Validate checks that the selectors of every config block can be parsed.
+// Blocks with invalid selectors never match any resource. Unlike
+// ConfigBlock.Validate, it does not check schedules and trace parameters,
+// whose errors are reported when building a response.
+func (config *Config) Validate() error {
 	for _, block := range config.ConfigBlocks {
-		if doInclude(block, labelSet) {
-			totalBlock.Add(block)
+		if _, err := parseSelectors(block.Selectors); err != nil {
+			return err
 		}
 	}
 
-	return totalBlock
+	return nil
 }
 
 func embed(resource *res.Resource) (map[string]bool, []string) {
@@ -72,14 +132,32 @@ func embed(resource *res.Resource) (map[string]bool, []string) {
 	return labelSet, labelList
 }
 
+func embedMap(resource *res.Resource) map[string]string {
+	labelMap := make(map[string]string)
+	if resource == nil {
+		return labelMap
+	}
+
+	for _, attr := range resource.Attributes {
+		labelMap[attr.Key] = attrValue(attr)
+	}
+
+	return labelMap
+}
+
 func attrToString(attr *com.KeyValue) string {
-	rawValue := attr.Value.String()
-	value := strings.Split(rawValue, ":")[1]
-	attrString := clean(fmt.Sprintf("%s:%s", attr.Key, value))
+	attrString := clean(fmt.Sprintf("%s:%s", attr.Key, attrValue(attr)))
 
 	return attrString
 }
 
+func attrValue(attr *com.KeyValue) string {
+	rawValue := attr.Value.String()
+	value := strings.SplitN(rawValue, ":", 2)[1]
+
+	return clean(value)
+}
+
 func doInclude(block *ConfigBlock, labelSet map[string]bool) bool {
 	include := true
 	for _, label := range block.Resource {
@@ -90,6 +168,21 @@ func doInclude(block *ConfigBlock, labelSet map[string]bool) bool {
 	return include
 }
 
+func doSelect(block *ConfigBlock, labelMap map[string]string) bool {
+	selectors, err := parseSelectors(block.Selectors)
+	if err != nil {
+		return false
+	}
+
+	for _, sel := range selectors {
+		if !sel.matches(labelMap) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func clean(label string) string {
 	label = strings.ReplaceAll(label, " ", "")
 	label = strings.ReplaceAll(label, `"`, "")
@@ -18,13 +18,23 @@ import (
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
 )
 
-// A ConfigBlock associates a set of schedules with a resource. The resource
-// is represented as a list of strings. Each string takes the form "key:value",
-// where "key" and "value" are the string representations of the resource's
-// corresponding fields.
+// A ConfigBlock associates a set of schedules and trace parameters with a
+// resource. The resource is represented as a list of strings. Each string takes
+// the form "key:value", where "key" and "value" are the string representations
+// of the resource's corresponding fields. Selectors further restrict the
+// resources the block applies to, using richer requirements on resource labels
+// (see Config.Match for the supported forms).
+//
+// When several blocks match a resource, Priority and Exclusive determine their
+// precedence: blocks are combined in order of increasing Priority, and a
+// matching Exclusive block discards all matching blocks of lower Priority.
 type ConfigBlock struct {
-	Resource  []string
-	Schedules []*Schedule
+	Resource    []string     `yaml:"Resource,omitempty" json:",omitempty"`
+	Selectors   []string     `yaml:"Selectors,omitempty" json:",omitempty"`
+	Priority    int          `yaml:"Priority,omitempty" json:",omitempty"`
+	Exclusive   bool         `yaml:"Exclusive,omitempty" json:",omitempty"`
+	Schedules   []*Schedule  `yaml:"Schedules,omitempty" json:",omitempty"`
+	TraceConfig *TraceConfig `yaml:"TraceConfig,omitempty" json:",omitempty"`
 }
 
 // Proto converts the ConfigBlock into a slice of MetricConfigResponse_Schedule
@@ -44,6 +54,24 @@ func (block *ConfigBlock) Proto() ([]*pb.MetricConfigResponse_Schedule, error) {
 	return scheduleSlice, nil
 }
 
+// Validate checks that the selectors of the ConfigBlock can be parsed, and
+// that its schedules and trace parameters can be converted to protobufs.
+func (block *ConfigBlock) Validate() error {
+	if _, err := parseSelectors(block.Selectors); err != nil {
+		return err
+	}
+
+	if _, err := block.Proto(); err != nil {
+		return err
+	}
+
+	if _, err := block.TraceConfig.Proto(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // Hash calculates an FNVa 64 bit hash of the ConfigBlock. The ordering of the
 // schedules does not impact the hash. If there are no schedules, then
 // zero is returned.
@@ -62,9 +90,18 @@ func (block *ConfigBlock) Hash() []byte {
 
 // Add combines this ConfigBlock with another ConfigBlock. It does so by
 // concatenating the schedules of the two blocks, and does not attempt to
-// resolve potential conflicts.
+// resolve potential conflicts. The trace parameters of the other block are
+// merged over those of this block, so later blocks take precedence.
 func (block *ConfigBlock) Add(other *ConfigBlock) {
 	block.Schedules = append(
 		block.Schedules,
 		other.Schedules...)
+
+	if other.TraceConfig != nil {
+		if block.TraceConfig == nil {
+			block.TraceConfig = &TraceConfig{}
+		}
+
+		block.TraceConfig.Merge(other.TraceConfig)
+	}
 }
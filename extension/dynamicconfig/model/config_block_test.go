@@ -51,6 +51,28 @@ func TestAddConfigBlock(t *testing.T) {
 	}
 }
 
+func TestAddConfigBlockTraceConfig(t *testing.T) {
+	original := &TraceConfig{RateLimitQPS: 10, MaxNumberOfLinks: 3}
+	configBlocks := []*ConfigBlock{
+		{TraceConfig: original},
+		{Schedules: []*Schedule{{Period: "SEC_1"}}},
+		{TraceConfig: &TraceConfig{RateLimitQPS: 20}},
+	}
+
+	var totalBlock ConfigBlock
+	for _, block := range configBlocks {
+		totalBlock.Add(block)
+	}
+
+	if totalBlock.TraceConfig.RateLimitQPS != 20 || totalBlock.TraceConfig.MaxNumberOfLinks != 3 {
+		t.Errorf("expected qps 20 and 3 links, found: %v", totalBlock.TraceConfig)
+	}
+
+	if original.RateLimitQPS != 10 {
+		t.Errorf("adding blocks should not alter the original trace config: %v", original)
+	}
+}
+
 func TestConfigBlockProto(t *testing.T) {
 	config := ConfigBlock{
 		Schedules: []*Schedule{{Period: "MIN_5"}, {Period: "MIN_1"}},
@@ -150,3 +150,109 @@ func TestMatchNoResource(t *testing.T) {
 		t.Errorf("result resource list incorrect: %v", result)
 	}
 }
+
+func TestMatchSelectors(t *testing.T) {
+	config := Config{
+		ConfigBlocks: []*ConfigBlock{
+			{
+				Schedules: []*Schedule{{Period: "DAY_1"}},
+			},
+			{
+				Selectors: []string{`service.name=~"checkout-.*"`, "env!=dev"},
+				Schedules: []*Schedule{{Period: "SEC_1"}},
+			},
+			{
+				Selectors: []string{"env in (dev, staging)"},
+				Schedules: []*Schedule{{Period: "SEC_5"}},
+			},
+		},
+	}
+
+	newResource := func(service, env string) *res.Resource {
+		return &res.Resource{
+			Attributes: []*com.KeyValue{
+				{Key: "service.name", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: service}}},
+				{Key: "env", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: env}}},
+			},
+		}
+	}
+
+	scheds := config.Match(newResource("checkout-api", "prod")).Schedules
+	if len(scheds) != 2 || scheds[0].Period != "DAY_1" || scheds[1].Period != "SEC_1" {
+		t.Errorf("expected periods DAY_1 and SEC_1, got: %v", scheds)
+	}
+
+	scheds = config.Match(newResource("checkout-api", "dev")).Schedules
+	if len(scheds) != 2 || scheds[0].Period != "DAY_1" || scheds[1].Period != "SEC_5" {
+		t.Errorf("expected periods DAY_1 and SEC_5, got: %v", scheds)
+	}
+
+	scheds = config.Match(nil).Schedules
+	if len(scheds) != 1 || scheds[0].Period != "DAY_1" {
+		t.Errorf("expected period DAY_1, got: %v", scheds)
+	}
+}
+
+func TestMatchPriority(t *testing.T) {
+	config := Config{
+		ConfigBlocks: []*ConfigBlock{
+			{
+				Priority:    10,
+				Selectors:   []string{"env=prod"},
+				Schedules:   []*Schedule{{Period: "MIN_1"}},
+				TraceConfig: &TraceConfig{RateLimitQPS: 100},
+			},
+			{
+				Schedules:   []*Schedule{{Period: "SEC_1"}},
+				TraceConfig: &TraceConfig{RateLimitQPS: 1, MaxNumberOfLinks: 5},
+			},
+			{
+				Priority:  5,
+				Exclusive: true,
+				Selectors: []string{"region"},
+				Schedules: []*Schedule{{Period: "MIN_5"}},
+			},
+		},
+	}
+
+	prod := &com.KeyValue{Key: "env", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: "prod"}}}
+	region := &com.KeyValue{Key: "region", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: "eu"}}}
+
+	result := config.Match(&res.Resource{Attributes: []*com.KeyValue{prod}})
+	if len(result.Schedules) != 2 || result.Schedules[0].Period != "SEC_1" || result.Schedules[1].Period != "MIN_1" {
+		t.Errorf("expected periods SEC_1 and MIN_1, got: %v", result.Schedules)
+	}
+
+	if result.TraceConfig.RateLimitQPS != 100 || result.TraceConfig.MaxNumberOfLinks != 5 {
+		t.Errorf("expected higher priority trace config to take precedence, got: %v", result.TraceConfig)
+	}
+
+	result = config.Match(&res.Resource{Attributes: []*com.KeyValue{prod, region}})
+	if len(result.Schedules) != 2 || result.Schedules[0].Period != "MIN_5" || result.Schedules[1].Period != "MIN_1" {
+		t.Errorf("expected periods MIN_5 and MIN_1, got: %v", result.Schedules)
+	}
+
+	if result.TraceConfig.MaxNumberOfLinks != 0 {
+		t.Errorf("expected lower priority trace config to be excluded, got: %v", result.TraceConfig)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	config := Config{
+		ConfigBlocks: []*ConfigBlock{
+			{Selectors: []string{"env=prod"}},
+		},
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Errorf("fail to validate config: %v", err)
+	}
+
+	config.ConfigBlocks = append(config.ConfigBlocks, &ConfigBlock{
+		Selectors: []string{"service.name=~("},
+	})
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("fail to catch invalid selector")
+	}
+}
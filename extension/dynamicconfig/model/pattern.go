@@ -21,6 +21,8 @@ package model
 import (
 	"fmt"
 	"hash/fnv"
+	"path"
+	"regexp"
 
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
 )
@@ -29,26 +31,54 @@ import (
 // a given schedule. Using the field Equals implies that the string should
 // match a metric name exactly. Using the field StartsWith implies that the
 // string should be a prefix match to one or more metrics. A Pattern with
-// StartsWith="*" is a special case that means match all metrics.
+// StartsWith="*" is a special case that means match all metrics. Using the
+// field Regex implies that the RE2 regular expression should match the entire
+// metric name, and using the field Glob implies that the shell glob (in the
+// syntax of Go's path.Match) should match the entire metric name.
 type Pattern struct {
-	Equals     string
-	StartsWith string
+	Equals     string `yaml:"Equals,omitempty" json:",omitempty"`
+	StartsWith string `yaml:"StartsWith,omitempty" json:",omitempty"`
+	Regex      string `yaml:"Regex,omitempty" json:",omitempty"`
+	Glob       string `yaml:"Glob,omitempty" json:",omitempty"`
 }
 
 // Proto converts the Pattern into a MetricConfigResponse_Schedule_Pattern
-// pointer. If both "Equals" and "StartsWith" are specified in the struct,
-// then an error is returned.
+// pointer. If more than one of the fields is specified in the struct, or if
+// Regex or Glob cannot be compiled, then an error is returned.
 func (p *Pattern) Proto() (*pb.MetricConfigResponse_Schedule_Pattern, error) {
-	if len(p.Equals) > 0 {
-		if len(p.StartsWith) > 0 {
-			return nil, fmt.Errorf("only specify StartsWith or Equals, not both")
-		}
+	if p.fieldCount() > 1 {
+		return nil, fmt.Errorf("only specify one of StartsWith, Equals, Regex or Glob")
+	}
 
+	switch {
+	case len(p.Equals) > 0:
 		return &pb.MetricConfigResponse_Schedule_Pattern{
 			Match: &pb.MetricConfigResponse_Schedule_Pattern_Equals{
 				Equals: p.Equals,
 			},
 		}, nil
+
+	case len(p.Regex) > 0:
+		if _, err := regexp.Compile(p.Regex); err != nil {
+			return nil, fmt.Errorf("invalid regex pattern: %w", err)
+		}
+
+		return &pb.MetricConfigResponse_Schedule_Pattern{
+			Match: &pb.MetricConfigResponse_Schedule_Pattern_Regex{
+				Regex: p.Regex,
+			},
+		}, nil
+
+	case len(p.Glob) > 0:
+		if _, err := path.Match(p.Glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", p.Glob, err)
+		}
+
+		return &pb.MetricConfigResponse_Schedule_Pattern{
+			Match: &pb.MetricConfigResponse_Schedule_Pattern_Glob{
+				Glob: p.Glob,
+			},
+		}, nil
 	}
 
 	return &pb.MetricConfigResponse_Schedule_Pattern{
@@ -58,15 +88,33 @@ func (p *Pattern) Proto() (*pb.MetricConfigResponse_Schedule_Pattern, error) {
 	}, nil
 }
 
+func (p *Pattern) fieldCount() int {
+	count := 0
+	for _, field := range []string{p.Equals, p.StartsWith, p.Regex, p.Glob} {
+		if len(field) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
 // Hash computes an FNVa 64 bit hash of the Pattern. Two Patterns with the
 // same string value but in different fields will yield different hashes.
 func (p *Pattern) Hash() []byte {
 	hasher := fnv.New64a()
 
-	if len(p.Equals) > 0 {
+	switch {
+	case len(p.Equals) > 0:
 		hasher.Write([]byte("Equals"))
 		hasher.Write([]byte(p.Equals))
-	} else {
+	case len(p.Regex) > 0:
+		hasher.Write([]byte("Regex"))
+		hasher.Write([]byte(p.Regex))
+	case len(p.Glob) > 0:
+		hasher.Write([]byte("Glob"))
+		hasher.Write([]byte(p.Glob))
+	default:
 		hasher.Write([]byte("StartsWith"))
 		hasher.Write([]byte(p.StartsWith))
 	}
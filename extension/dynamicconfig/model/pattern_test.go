@@ -32,15 +32,39 @@ func TestPatternProto(t *testing.T) {
 	}
 }
 
-func TestPatternBadProto(t *testing.T) {
+func TestPatternRegexGlobProto(t *testing.T) {
 	pattern := Pattern{
-		StartsWith: "/my/metric",
-		Equals:     "/my/metric",
+		Regex: "/my/.*/metric",
 	}
 
 	p, err := pattern.Proto()
-	if err == nil {
-		t.Errorf("expected Proto() to fail, built: %v", p)
+	if err != nil || p.Match.(*pb.MetricConfigResponse_Schedule_Pattern_Regex).Regex != "/my/.*/metric" {
+		t.Errorf("improper conversion to proto")
+	}
+
+	pattern = Pattern{
+		Glob: "/my/*/metric",
+	}
+
+	p, err = pattern.Proto()
+	if err != nil || p.Match.(*pb.MetricConfigResponse_Schedule_Pattern_Glob).Glob != "/my/*/metric" {
+		t.Errorf("improper conversion to proto")
+	}
+}
+
+func TestPatternBadProto(t *testing.T) {
+	patterns := []Pattern{
+		{StartsWith: "/my/metric", Equals: "/my/metric"},
+		{Regex: "/my/.*", Glob: "/my/*"},
+		{Regex: "/my/(metric"},
+		{Glob: "/my/[metric"},
+	}
+
+	for _, pattern := range patterns {
+		p, err := pattern.Proto()
+		if err == nil {
+			t.Errorf("expected Proto() to fail, built: %v", p)
+		}
 	}
 }
 
@@ -64,4 +88,16 @@ func TestPatternHash(t *testing.T) {
 	if bytes.Equal(configA.Hash(), configC.Hash()) {
 		t.Errorf("different configs with identical hashes")
 	}
+
+	configD := Pattern{
+		Regex: "/use/this/rule",
+	}
+
+	configE := Pattern{
+		Glob: "/use/this/rule",
+	}
+
+	if bytes.Equal(configA.Hash(), configD.Hash()) || bytes.Equal(configD.Hash(), configE.Hash()) {
+		t.Errorf("different configs with identical hashes")
+	}
 }
@@ -28,9 +28,9 @@ import (
 // of metrics with the CollectionPeriod that should be applied to these
 // metrics.
 type Schedule struct {
-	InclusionPatterns []Pattern
-	ExclusionPatterns []Pattern
-	Period            CollectionPeriod
+	InclusionPatterns []Pattern        `yaml:"InclusionPatterns,omitempty" json:",omitempty"`
+	ExclusionPatterns []Pattern        `yaml:"ExclusionPatterns,omitempty" json:",omitempty"`
+	Period            CollectionPeriod `yaml:"Period"`
 }
 
 // Proto generates a MetricConfigResponse_Schedule pointer from the Schedule.
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type operator int
+
+const (
+	opEquals operator = iota
+	opNotEquals
+	opIn
+	opNotIn
+	opExists
+	opNotExists
+	opRegex
+	opNotRegex
+)
+
+// A selector is a single requirement on a resource label, parsed from one of
+// the forms listed in Config.Match. Values may be surrounded by double quotes.
+type selector struct {
+	key    string
+	op     operator
+	values map[string]bool
+	regex  *regexp.Regexp
+}
+
+// selectorOperators are the binary operators of a selector, ordered so that
+// two-character operators are tried before "=" and ":".
+var selectorOperators = []struct {
+	token string
+	op    operator
+}{
+	{"=~", opRegex},
+	{"!~", opNotRegex},
+	{"!=", opNotEquals},
+	{"==", opEquals},
+	{"=", opEquals},
+	{":", opEquals},
+}
+
+var setSelectorRegex = regexp.MustCompile(`^\s*([^\s!=~:(),]+)\s+(in|notin|not\s+in)\s*\((.*)\)\s*$`)
+
+func parseSelector(text string) (*selector, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("empty resource selector")
+	}
+
+	if match := setSelectorRegex.FindStringSubmatch(text); match != nil {
+		sel := &selector{
+			key:    match[1],
+			op:     opIn,
+			values: make(map[string]bool),
+		}
+		if match[2] != "in" {
+			sel.op = opNotIn
+		}
+
+		for _, value := range strings.Split(match[3], ",") {
+			sel.values[unquote(value)] = true
+		}
+
+		return sel, nil
+	}
+
+	for idx := range text {
+		for _, candidate := range selectorOperators {
+			if !strings.HasPrefix(text[idx:], candidate.token) {
+				continue
+			}
+
+			key := strings.TrimSpace(text[:idx])
+			value := unquote(text[idx+len(candidate.token):])
+			if key == "" {
+				return nil, fmt.Errorf("missing label key in resource selector %q", text)
+			}
+
+			sel := &selector{key: key, op: candidate.op}
+			if candidate.op == opRegex || candidate.op == opNotRegex {
+				regex, err := regexp.Compile("^(?:" + value + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("invalid regex in resource selector %q: %w", text, err)
+				}
+
+				sel.regex = regex
+			} else {
+				sel.values = map[string]bool{value: true}
+			}
+
+			return sel, nil
+		}
+	}
+
+	if strings.HasPrefix(text, "!") {
+		key := strings.TrimSpace(text[1:])
+		if key == "" {
+			return nil, fmt.Errorf("missing label key in resource selector %q", text)
+		}
+
+		return &selector{key: key, op: opNotExists}, nil
+	}
+
+	if strings.ContainsAny(text, " ()") {
+		return nil, fmt.Errorf("cannot parse resource selector %q", text)
+	}
+
+	return &selector{key: text, op: opExists}, nil
+}
+
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+// matches reports whether the selector is satisfied by the given resource
+// labels, keyed by label name.
+func (sel *selector) matches(labels map[string]string) bool {
+	value, exists := labels[sel.key]
+
+	switch sel.op {
+	case opExists:
+		return exists
+	case opNotExists:
+		return !exists
+	case opEquals, opIn:
+		return exists && sel.values[value]
+	case opNotEquals, opNotIn:
+		return !exists || !sel.values[value]
+	case opRegex:
+		return exists && sel.regex.MatchString(value)
+	case opNotRegex:
+		return !exists || !sel.regex.MatchString(value)
+	}
+
+	return false
+}
+
+func parseSelectors(texts []string) ([]*selector, error) {
+	selectors := make([]*selector, len(texts))
+	for i, text := range texts {
+		sel, err := parseSelector(text)
+		if err != nil {
+			return nil, err
+		}
+
+		selectors[i] = sel
+	}
+
+	return selectors, nil
+}
@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	labels := map[string]string{
+		"service.name": "checkout-api",
+		"env":          "prod",
+		"zone":         "us-east-1a",
+	}
+
+	tests := []struct {
+		selector string
+		matches  bool
+	}{
+		{`service.name=~"checkout-.*"`, true},
+		{`service.name=~checkout`, false},
+		{`service.name!~"cart-.*"`, true},
+		{`env!=dev`, true},
+		{`env != prod`, false},
+		{`env=prod`, true},
+		{`env==prod`, true},
+		{`env:prod`, true},
+		{`env in (prod, staging)`, true},
+		{`env in ("dev")`, false},
+		{`env notin (dev, staging)`, true},
+		{`env not in (prod)`, false},
+		{`missing notin (prod)`, true},
+		{`zone`, true},
+		{`missing`, false},
+		{`!missing`, true},
+		{`!zone`, false},
+		{`zone=~us-east-.*`, true},
+	}
+
+	for _, test := range tests {
+		sel, err := parseSelector(test.selector)
+		if err != nil {
+			t.Errorf("fail to parse selector %q: %v", test.selector, err)
+			continue
+		}
+
+		if sel.matches(labels) != test.matches {
+			t.Errorf("selector %q: expected match=%v", test.selector, test.matches)
+		}
+	}
+}
+
+func TestParseBadSelector(t *testing.T) {
+	badSelectors := []string{
+		"",
+		"=value",
+		"!",
+		"key=~(",
+		"key in",
+	}
+
+	for _, text := range badSelectors {
+		if sel, err := parseSelector(text); err == nil {
+			t.Errorf("expected selector %q to fail parsing, built: %v", text, sel)
+		}
+	}
+}
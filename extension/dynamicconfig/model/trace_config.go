@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Contains common models for the dynamic config service. The corresponding
+// Proto() methods convert the model representation to a usable struct for
+// protobuf marshaling.
+
+package model
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
+)
+
+// A TraceConfig holds the trace parameters that should be applied by an SDK:
+// the sampler to use, and the limits placed on span attributes, events and
+// links. At most one of ConstantSampler, SamplingRatio and RateLimitQPS may be
+// specified. Limits left at zero are considered unset.
+type TraceConfig struct {
+	// ConstantSampler is one of "ALWAYS_ON", "ALWAYS_OFF" or "ALWAYS_PARENT".
+	ConstantSampler string `yaml:"ConstantSampler,omitempty" json:",omitempty"`
+	// SamplingRatio is the ratio of traces to sample, within [0.0, 1.0].
+	SamplingRatio *float64 `yaml:"SamplingRatio,omitempty" json:",omitempty"`
+	// RateLimitQPS is the number of traces to sample per second.
+	RateLimitQPS int64 `yaml:"RateLimitQPS,omitempty" json:",omitempty"`
+
+	MaxNumberOfAttributes              int64 `yaml:"MaxNumberOfAttributes,omitempty" json:",omitempty"`
+	MaxNumberOfTimedEvents             int64 `yaml:"MaxNumberOfTimedEvents,omitempty" json:",omitempty"`
+	MaxNumberOfAttributesPerTimedEvent int64 `yaml:"MaxNumberOfAttributesPerTimedEvent,omitempty" json:",omitempty"`
+	MaxNumberOfLinks                   int64 `yaml:"MaxNumberOfLinks,omitempty" json:",omitempty"`
+	MaxNumberOfAttributesPerLink       int64 `yaml:"MaxNumberOfAttributesPerLink,omitempty" json:",omitempty"`
+}
+
+// Proto converts the TraceConfig into a TraceParams pointer. A nil
+// TraceConfig yields nil TraceParams. If more than one sampler is specified,
+// or if any value is out of range, then an error is returned.
+func (tc *TraceConfig) Proto() (*pb.TraceParams, error) {
+	if tc == nil {
+		return nil, nil
+	}
+
+	if tc.samplerCount() > 1 {
+		return nil, fmt.Errorf("only specify one of ConstantSampler, SamplingRatio or RateLimitQPS")
+	}
+
+	params := &pb.TraceParams{
+		MaxNumberOfAttributes:              tc.MaxNumberOfAttributes,
+		MaxNumberOfTimedEvents:             tc.MaxNumberOfTimedEvents,
+		MaxNumberOfAttributesPerTimedEvent: tc.MaxNumberOfAttributesPerTimedEvent,
+		MaxNumberOfLinks:                   tc.MaxNumberOfLinks,
+		MaxNumberOfAttributesPerLink:       tc.MaxNumberOfAttributesPerLink,
+	}
+
+	for _, limit := range []int64{
+		tc.MaxNumberOfAttributes,
+		tc.MaxNumberOfTimedEvents,
+		tc.MaxNumberOfAttributesPerTimedEvent,
+		tc.MaxNumberOfLinks,
+		tc.MaxNumberOfAttributesPerLink,
+	} {
+		if limit < 0 {
+			return nil, fmt.Errorf("cannot process negative span limit: %v", limit)
+		}
+	}
+
+	switch {
+	case tc.ConstantSampler != "":
+		decision, ok := pb.ConstantSampler_ConstantDecision_value[tc.ConstantSampler]
+		if !ok {
+			return nil, fmt.Errorf("unknown constant sampler: %v", tc.ConstantSampler)
+		}
+
+		params.Sampler = &pb.TraceParams_ConstantSampler{
+			ConstantSampler: &pb.ConstantSampler{
+				Decision: pb.ConstantSampler_ConstantDecision(decision),
+			},
+		}
+
+	case tc.SamplingRatio != nil:
+		ratio := *tc.SamplingRatio
+		if ratio < 0 || ratio > 1 {
+			return nil, fmt.Errorf("sampling ratio must be within [0.0, 1.0], got: %v", ratio)
+		}
+
+		params.Sampler = &pb.TraceParams_TraceIdRatioBased{
+			TraceIdRatioBased: &pb.TraceIdRatioBased{
+				SamplingRatio: ratio,
+			},
+		}
+
+	case tc.RateLimitQPS != 0:
+		if tc.RateLimitQPS < 0 {
+			return nil, fmt.Errorf("cannot process negative rate limit: %v", tc.RateLimitQPS)
+		}
+
+		params.Sampler = &pb.TraceParams_RateLimitingSampler{
+			RateLimitingSampler: &pb.RateLimitingSampler{
+				Qps: tc.RateLimitQPS,
+			},
+		}
+	}
+
+	return params, nil
+}
+
+func (tc *TraceConfig) samplerCount() int {
+	count := 0
+	if tc.ConstantSampler != "" {
+		count++
+	}
+
+	if tc.SamplingRatio != nil {
+		count++
+	}
+
+	if tc.RateLimitQPS != 0 {
+		count++
+	}
+
+	return count
+}
+
+// Hash computes an FNVa 64 bit hash of the TraceConfig. If the TraceConfig is
+// nil, then zero is returned.
+func (tc *TraceConfig) Hash() []byte {
+	if tc == nil {
+		return []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	}
+
+	hasher := fnv.New64a()
+	bs := make([]byte, 8)
+	writeInt := func(name string, value int64) {
+		binary.LittleEndian.PutUint64(bs, uint64(value))
+		hasher.Write([]byte(name))
+		hasher.Write(bs)
+	}
+
+	hasher.Write([]byte("ConstantSampler"))
+	hasher.Write([]byte(tc.ConstantSampler))
+
+	if tc.SamplingRatio != nil {
+		writeInt("SamplingRatio", int64(math.Float64bits(*tc.SamplingRatio)))
+	}
+
+	writeInt("RateLimitQPS", tc.RateLimitQPS)
+	writeInt("MaxNumberOfAttributes", tc.MaxNumberOfAttributes)
+	writeInt("MaxNumberOfTimedEvents", tc.MaxNumberOfTimedEvents)
+	writeInt("MaxNumberOfAttributesPerTimedEvent", tc.MaxNumberOfAttributesPerTimedEvent)
+	writeInt("MaxNumberOfLinks", tc.MaxNumberOfLinks)
+	writeInt("MaxNumberOfAttributesPerLink", tc.MaxNumberOfAttributesPerLink)
+
+	return hasher.Sum(nil)
+}
+
+// Merge overlays the fields set in another TraceConfig onto this TraceConfig.
+// If the other TraceConfig specifies a sampler, it replaces the sampler of
+// this TraceConfig. Limits are overridden individually, when set.
+func (tc *TraceConfig) Merge(other *TraceConfig) {
+	if other == nil {
+		return
+	}
+
+	if other.samplerCount() > 0 {
+		tc.ConstantSampler = other.ConstantSampler
+		tc.SamplingRatio = other.SamplingRatio
+		tc.RateLimitQPS = other.RateLimitQPS
+	}
+
+	mergeLimit(&tc.MaxNumberOfAttributes, other.MaxNumberOfAttributes)
+	mergeLimit(&tc.MaxNumberOfTimedEvents, other.MaxNumberOfTimedEvents)
+	mergeLimit(&tc.MaxNumberOfAttributesPerTimedEvent, other.MaxNumberOfAttributesPerTimedEvent)
+	mergeLimit(&tc.MaxNumberOfLinks, other.MaxNumberOfLinks)
+	mergeLimit(&tc.MaxNumberOfAttributesPerLink, other.MaxNumberOfAttributesPerLink)
+}
+
+func mergeLimit(dst *int64, src int64) {
+	if src != 0 {
+		*dst = src
+	}
+}
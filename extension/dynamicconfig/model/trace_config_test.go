@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
+)
+
+func TestTraceConfigProto(t *testing.T) {
+	ratio := 0.25
+	tc := TraceConfig{
+		SamplingRatio:         &ratio,
+		MaxNumberOfAttributes: 16,
+	}
+
+	params, err := tc.Proto()
+	if err != nil {
+		t.Fatalf("improper conversion to proto: %v", err)
+	}
+
+	sampler, ok := params.Sampler.(*pb.TraceParams_TraceIdRatioBased)
+	if !ok || sampler.TraceIdRatioBased.SamplingRatio != 0.25 {
+		t.Errorf("expected sampling ratio 0.25, found: %v", params.Sampler)
+	}
+
+	if params.MaxNumberOfAttributes != 16 {
+		t.Errorf("expected 16 max attributes, found: %v", params.MaxNumberOfAttributes)
+	}
+
+	tc = TraceConfig{ConstantSampler: "ALWAYS_PARENT"}
+	params, err = tc.Proto()
+	if err != nil {
+		t.Fatalf("improper conversion to proto: %v", err)
+	}
+
+	constant, ok := params.Sampler.(*pb.TraceParams_ConstantSampler)
+	if !ok || constant.ConstantSampler.Decision != pb.ConstantSampler_ALWAYS_PARENT {
+		t.Errorf("expected ALWAYS_PARENT sampler, found: %v", params.Sampler)
+	}
+
+	var nilConfig *TraceConfig
+	if params, err := nilConfig.Proto(); params != nil || err != nil {
+		t.Errorf("expected nil params from nil config, found: %v: %v", params, err)
+	}
+}
+
+func TestTraceConfigBadProto(t *testing.T) {
+	ratio := 1.5
+	badConfigs := []TraceConfig{
+		{ConstantSampler: "ALWAYS_ON", RateLimitQPS: 10},
+		{ConstantSampler: "SOMETIMES"},
+		{SamplingRatio: &ratio},
+		{RateLimitQPS: -1},
+		{MaxNumberOfLinks: -1},
+	}
+
+	for _, tc := range badConfigs {
+		if params, err := tc.Proto(); err == nil {
+			t.Errorf("expected Proto() to fail, built: %v", params)
+		}
+	}
+}
+
+func TestTraceConfigHash(t *testing.T) {
+	ratioA, ratioB := 0.5, 0.5
+	configA := &TraceConfig{SamplingRatio: &ratioA, MaxNumberOfLinks: 4}
+	configB := &TraceConfig{SamplingRatio: &ratioB, MaxNumberOfLinks: 4}
+	configC := &TraceConfig{RateLimitQPS: 4}
+	configD := &TraceConfig{MaxNumberOfAttributes: 4}
+
+	if !bytes.Equal(configA.Hash(), configB.Hash()) {
+		t.Errorf("identical configs with different hashes")
+	}
+
+	if bytes.Equal(configA.Hash(), configC.Hash()) {
+		t.Errorf("different configs with identical hashes")
+	}
+
+	if bytes.Equal(configC.Hash(), configD.Hash()) {
+		t.Errorf("different configs with identical hashes")
+	}
+
+	var nilConfig *TraceConfig
+	if !bytes.Equal(nilConfig.Hash(), []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}) {
+		t.Errorf("expected all zeros, got: %v", nilConfig.Hash())
+	}
+}
+
+func TestTraceConfigMerge(t *testing.T) {
+	ratio := 0.1
+	tc := &TraceConfig{
+		SamplingRatio:         &ratio,
+		MaxNumberOfAttributes: 8,
+		MaxNumberOfLinks:      2,
+	}
+
+	tc.Merge(&TraceConfig{
+		ConstantSampler:       "ALWAYS_ON",
+		MaxNumberOfAttributes: 32,
+	})
+
+	if tc.ConstantSampler != "ALWAYS_ON" || tc.SamplingRatio != nil {
+		t.Errorf("expected sampler to be replaced, found: %v", tc)
+	}
+
+	if tc.MaxNumberOfAttributes != 32 || tc.MaxNumberOfLinks != 2 {
+		t.Errorf("expected limits 32 and 2, found: %v and %v",
+			tc.MaxNumberOfAttributes, tc.MaxNumberOfLinks)
+	}
+
+	tc.Merge(nil)
+	if tc.ConstantSampler != "ALWAYS_ON" {
+		t.Errorf("merging nil config should not alter config: %v", tc)
+	}
+}
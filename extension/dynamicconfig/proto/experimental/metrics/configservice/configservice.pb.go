@@ -26,6 +26,38 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+// How spans should be sampled:
+// - Always off
+// - Always on
+// - Always follow the parent Span's decision (off if no parent).
+type ConstantSampler_ConstantDecision int32
+
+const (
+	ConstantSampler_ALWAYS_OFF    ConstantSampler_ConstantDecision = 0
+	ConstantSampler_ALWAYS_ON     ConstantSampler_ConstantDecision = 1
+	ConstantSampler_ALWAYS_PARENT ConstantSampler_ConstantDecision = 2
+)
+
+var ConstantSampler_ConstantDecision_name = map[int32]string{
+	0: "ALWAYS_OFF",
+	1: "ALWAYS_ON",
+	2: "ALWAYS_PARENT",
+}
+
+var ConstantSampler_ConstantDecision_value = map[string]int32{
+	"ALWAYS_OFF":    0,
+	"ALWAYS_ON":     1,
+	"ALWAYS_PARENT": 2,
+}
+
+func (x ConstantSampler_ConstantDecision) String() string {
+	return proto.EnumName(ConstantSampler_ConstantDecision_name, int32(x))
+}
+
+func (ConstantSampler_ConstantDecision) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{5, 0}
+}
+
 type MetricConfigRequest struct {
 	// Required. The resource for which configuration should be returned.
 	Resource *v1.Resource `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
@@ -221,6 +253,8 @@ type MetricConfigResponse_Schedule_Pattern struct {
 	// Types that are valid to be assigned to Match:
 	//	*MetricConfigResponse_Schedule_Pattern_Equals
 	//	*MetricConfigResponse_Schedule_Pattern_StartsWith
+	//	*MetricConfigResponse_Schedule_Pattern_Regex
+	//	*MetricConfigResponse_Schedule_Pattern_Glob
 	Match                isMetricConfigResponse_Schedule_Pattern_Match `protobuf_oneof:"match"`
 	XXX_NoUnkeyedLiteral struct{}                                      `json:"-"`
 	XXX_unrecognized     []byte                                        `json:"-"`
@@ -261,11 +295,19 @@ type MetricConfigResponse_Schedule_Pattern_Equals struct {
 type MetricConfigResponse_Schedule_Pattern_StartsWith struct {
 	StartsWith string `protobuf:"bytes,2,opt,name=starts_with,json=startsWith,proto3,oneof" json:"starts_with,omitempty"`
 }
+type MetricConfigResponse_Schedule_Pattern_Regex struct {
+	Regex string `protobuf:"bytes,3,opt,name=regex,proto3,oneof" json:"regex,omitempty"`
+}
+type MetricConfigResponse_Schedule_Pattern_Glob struct {
+	Glob string `protobuf:"bytes,4,opt,name=glob,proto3,oneof" json:"glob,omitempty"`
+}
 
 func (*MetricConfigResponse_Schedule_Pattern_Equals) isMetricConfigResponse_Schedule_Pattern_Match() {
 }
 func (*MetricConfigResponse_Schedule_Pattern_StartsWith) isMetricConfigResponse_Schedule_Pattern_Match() {
 }
+func (*MetricConfigResponse_Schedule_Pattern_Regex) isMetricConfigResponse_Schedule_Pattern_Match() {}
+func (*MetricConfigResponse_Schedule_Pattern_Glob) isMetricConfigResponse_Schedule_Pattern_Match()  {}
 
 func (m *MetricConfigResponse_Schedule_Pattern) GetMatch() isMetricConfigResponse_Schedule_Pattern_Match {
 	if m != nil {
@@ -288,19 +330,410 @@ func (m *MetricConfigResponse_Schedule_Pattern) GetStartsWith() string {
 	return ""
 }
 
+func (m *MetricConfigResponse_Schedule_Pattern) GetRegex() string {
+	if x, ok := m.GetMatch().(*MetricConfigResponse_Schedule_Pattern_Regex); ok {
+		return x.Regex
+	}
+	return ""
+}
+
+func (m *MetricConfigResponse_Schedule_Pattern) GetGlob() string {
+	if x, ok := m.GetMatch().(*MetricConfigResponse_Schedule_Pattern_Glob); ok {
+		return x.Glob
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*MetricConfigResponse_Schedule_Pattern) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*MetricConfigResponse_Schedule_Pattern_Equals)(nil),
 		(*MetricConfigResponse_Schedule_Pattern_StartsWith)(nil),
+		(*MetricConfigResponse_Schedule_Pattern_Regex)(nil),
+		(*MetricConfigResponse_Schedule_Pattern_Glob)(nil),
+	}
+}
+
+type TraceConfigRequest struct {
+	// Required. The resource for which configuration should be returned.
+	Resource *v1.Resource `protobuf:"bytes,1,opt,name=resource,proto3" json:"resource,omitempty"`
+	// Optional. The value of TraceConfigResponse.fingerprint for the last
+	// configuration that the caller received and successfully applied.
+	LastKnownFingerprint []byte   `protobuf:"bytes,2,opt,name=last_known_fingerprint,json=lastKnownFingerprint,proto3" json:"last_known_fingerprint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceConfigRequest) Reset()         { *m = TraceConfigRequest{} }
+func (m *TraceConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*TraceConfigRequest) ProtoMessage()    {}
+func (*TraceConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{2}
+}
+func (m *TraceConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceConfigRequest.Unmarshal(m, b)
+}
+func (m *TraceConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *TraceConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceConfigRequest.Merge(m, src)
+}
+func (m *TraceConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_TraceConfigRequest.Size(m)
+}
+func (m *TraceConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceConfigRequest proto.InternalMessageInfo
+
+func (m *TraceConfigRequest) GetResource() *v1.Resource {
+	if m != nil {
+		return m.Resource
+	}
+	return nil
+}
+
+func (m *TraceConfigRequest) GetLastKnownFingerprint() []byte {
+	if m != nil {
+		return m.LastKnownFingerprint
+	}
+	return nil
+}
+
+type TraceConfigResponse struct {
+	// Optional. The fingerprint associated with this TraceConfigResponse. It
+	// follows the same semantics as MetricConfigResponse.fingerprint.
+	Fingerprint []byte `protobuf:"bytes,1,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	// Optional. The trace parameters to apply to the resource. If absent, the
+	// SDK should keep its current (or default) trace parameters.
+	TraceParams *TraceParams `protobuf:"bytes,2,opt,name=trace_params,json=traceParams,proto3" json:"trace_params,omitempty"`
+	// Optional. The client is suggested to wait this long (in seconds) before
+	// pinging the configuration service again.
+	SuggestedWaitTimeSec int32    `protobuf:"varint,3,opt,name=suggested_wait_time_sec,json=suggestedWaitTimeSec,proto3" json:"suggested_wait_time_sec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceConfigResponse) Reset()         { *m = TraceConfigResponse{} }
+func (m *TraceConfigResponse) String() string { return proto.CompactTextString(m) }
+func (*TraceConfigResponse) ProtoMessage()    {}
+func (*TraceConfigResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{3}
+}
+func (m *TraceConfigResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceConfigResponse.Unmarshal(m, b)
+}
+func (m *TraceConfigResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceConfigResponse.Marshal(b, m, deterministic)
+}
+func (m *TraceConfigResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceConfigResponse.Merge(m, src)
+}
+func (m *TraceConfigResponse) XXX_Size() int {
+	return xxx_messageInfo_TraceConfigResponse.Size(m)
+}
+func (m *TraceConfigResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceConfigResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceConfigResponse proto.InternalMessageInfo
+
+func (m *TraceConfigResponse) GetFingerprint() []byte {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return nil
+}
+
+func (m *TraceConfigResponse) GetTraceParams() *TraceParams {
+	if m != nil {
+		return m.TraceParams
+	}
+	return nil
+}
+
+func (m *TraceConfigResponse) GetSuggestedWaitTimeSec() int32 {
+	if m != nil {
+		return m.SuggestedWaitTimeSec
+	}
+	return 0
+}
+
+// Global configuration of the trace service. All fields must be specified, or
+// the default (zero) values will be used for each type.
+type TraceParams struct {
+	// The global default sampler used to make decisions on span sampling.
+	//
+	// Types that are valid to be assigned to Sampler:
+	//	*TraceParams_ConstantSampler
+	//	*TraceParams_TraceIdRatioBased
+	//	*TraceParams_RateLimitingSampler
+	Sampler isTraceParams_Sampler `protobuf_oneof:"sampler"`
+	// The global default max number of attributes per span.
+	MaxNumberOfAttributes int64 `protobuf:"varint,4,opt,name=max_number_of_attributes,json=maxNumberOfAttributes,proto3" json:"max_number_of_attributes,omitempty"`
+	// The global default max number of annotation events per span.
+	MaxNumberOfTimedEvents int64 `protobuf:"varint,5,opt,name=max_number_of_timed_events,json=maxNumberOfTimedEvents,proto3" json:"max_number_of_timed_events,omitempty"`
+	// The global default max number of attributes per timed event.
+	MaxNumberOfAttributesPerTimedEvent int64 `protobuf:"varint,6,opt,name=max_number_of_attributes_per_timed_event,json=maxNumberOfAttributesPerTimedEvent,proto3" json:"max_number_of_attributes_per_timed_event,omitempty"`
+	// The global default max number of link entries per span.
+	MaxNumberOfLinks int64 `protobuf:"varint,7,opt,name=max_number_of_links,json=maxNumberOfLinks,proto3" json:"max_number_of_links,omitempty"`
+	// The global default max number of attributes per link.
+	MaxNumberOfAttributesPerLink int64    `protobuf:"varint,8,opt,name=max_number_of_attributes_per_link,json=maxNumberOfAttributesPerLink,proto3" json:"max_number_of_attributes_per_link,omitempty"`
+	XXX_NoUnkeyedLiteral         struct{} `json:"-"`
+	XXX_unrecognized             []byte   `json:"-"`
+	XXX_sizecache                int32    `json:"-"`
+}
+
+func (m *TraceParams) Reset()         { *m = TraceParams{} }
+func (m *TraceParams) String() string { return proto.CompactTextString(m) }
+func (*TraceParams) ProtoMessage()    {}
+func (*TraceParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{4}
+}
+func (m *TraceParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceParams.Unmarshal(m, b)
+}
+func (m *TraceParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceParams.Marshal(b, m, deterministic)
+}
+func (m *TraceParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceParams.Merge(m, src)
+}
+func (m *TraceParams) XXX_Size() int {
+	return xxx_messageInfo_TraceParams.Size(m)
+}
+func (m *TraceParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceParams proto.InternalMessageInfo
+
+type isTraceParams_Sampler interface {
+	isTraceParams_Sampler()
+}
+
+type TraceParams_ConstantSampler struct {
+	ConstantSampler *ConstantSampler `protobuf:"bytes,1,opt,name=constant_sampler,json=constantSampler,proto3,oneof" json:"constant_sampler,omitempty"`
+}
+type TraceParams_TraceIdRatioBased struct {
+	TraceIdRatioBased *TraceIdRatioBased `protobuf:"bytes,2,opt,name=trace_id_ratio_based,json=traceIdRatioBased,proto3,oneof" json:"trace_id_ratio_based,omitempty"`
+}
+type TraceParams_RateLimitingSampler struct {
+	RateLimitingSampler *RateLimitingSampler `protobuf:"bytes,3,opt,name=rate_limiting_sampler,json=rateLimitingSampler,proto3,oneof" json:"rate_limiting_sampler,omitempty"`
+}
+
+func (*TraceParams_ConstantSampler) isTraceParams_Sampler()     {}
+func (*TraceParams_TraceIdRatioBased) isTraceParams_Sampler()   {}
+func (*TraceParams_RateLimitingSampler) isTraceParams_Sampler() {}
+
+func (m *TraceParams) GetSampler() isTraceParams_Sampler {
+	if m != nil {
+		return m.Sampler
+	}
+	return nil
+}
+
+func (m *TraceParams) GetConstantSampler() *ConstantSampler {
+	if x, ok := m.GetSampler().(*TraceParams_ConstantSampler); ok {
+		return x.ConstantSampler
+	}
+	return nil
+}
+
+func (m *TraceParams) GetTraceIdRatioBased() *TraceIdRatioBased {
+	if x, ok := m.GetSampler().(*TraceParams_TraceIdRatioBased); ok {
+		return x.TraceIdRatioBased
+	}
+	return nil
+}
+
+func (m *TraceParams) GetRateLimitingSampler() *RateLimitingSampler {
+	if x, ok := m.GetSampler().(*TraceParams_RateLimitingSampler); ok {
+		return x.RateLimitingSampler
+	}
+	return nil
+}
+
+func (m *TraceParams) GetMaxNumberOfAttributes() int64 {
+	if m != nil {
+		return m.MaxNumberOfAttributes
+	}
+	return 0
+}
+
+func (m *TraceParams) GetMaxNumberOfTimedEvents() int64 {
+	if m != nil {
+		return m.MaxNumberOfTimedEvents
+	}
+	return 0
+}
+
+func (m *TraceParams) GetMaxNumberOfAttributesPerTimedEvent() int64 {
+	if m != nil {
+		return m.MaxNumberOfAttributesPerTimedEvent
+	}
+	return 0
+}
+
+func (m *TraceParams) GetMaxNumberOfLinks() int64 {
+	if m != nil {
+		return m.MaxNumberOfLinks
+	}
+	return 0
+}
+
+func (m *TraceParams) GetMaxNumberOfAttributesPerLink() int64 {
+	if m != nil {
+		return m.MaxNumberOfAttributesPerLink
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*TraceParams) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*TraceParams_ConstantSampler)(nil),
+		(*TraceParams_TraceIdRatioBased)(nil),
+		(*TraceParams_RateLimitingSampler)(nil),
+	}
+}
+
+// Sampler that always makes a constant decision on span sampling.
+type ConstantSampler struct {
+	Decision             ConstantSampler_ConstantDecision `protobuf:"varint,1,opt,name=decision,proto3,enum=opentelemetry.proto.experimental.metrics.configservice.ConstantSampler_ConstantDecision" json:"decision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *ConstantSampler) Reset()         { *m = ConstantSampler{} }
+func (m *ConstantSampler) String() string { return proto.CompactTextString(m) }
+func (*ConstantSampler) ProtoMessage()    {}
+func (*ConstantSampler) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{5}
+}
+func (m *ConstantSampler) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConstantSampler.Unmarshal(m, b)
+}
+func (m *ConstantSampler) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConstantSampler.Marshal(b, m, deterministic)
+}
+func (m *ConstantSampler) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConstantSampler.Merge(m, src)
+}
+func (m *ConstantSampler) XXX_Size() int {
+	return xxx_messageInfo_ConstantSampler.Size(m)
+}
+func (m *ConstantSampler) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConstantSampler.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConstantSampler proto.InternalMessageInfo
+
+func (m *ConstantSampler) GetDecision() ConstantSampler_ConstantDecision {
+	if m != nil {
+		return m.Decision
+	}
+	return ConstantSampler_ALWAYS_OFF
+}
+
+// Sampler that tries to uniformly sample traces with a given ratio.
+// The ratio of sampling a trace is equal to that of the specified ratio.
+type TraceIdRatioBased struct {
+	// The desired ratio of sampling. Must be within [0.0, 1.0].
+	SamplingRatio        float64  `protobuf:"fixed64,1,opt,name=samplingRatio,proto3" json:"samplingRatio,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceIdRatioBased) Reset()         { *m = TraceIdRatioBased{} }
+func (m *TraceIdRatioBased) String() string { return proto.CompactTextString(m) }
+func (*TraceIdRatioBased) ProtoMessage()    {}
+func (*TraceIdRatioBased) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{6}
+}
+func (m *TraceIdRatioBased) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceIdRatioBased.Unmarshal(m, b)
+}
+func (m *TraceIdRatioBased) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceIdRatioBased.Marshal(b, m, deterministic)
+}
+func (m *TraceIdRatioBased) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceIdRatioBased.Merge(m, src)
+}
+func (m *TraceIdRatioBased) XXX_Size() int {
+	return xxx_messageInfo_TraceIdRatioBased.Size(m)
+}
+func (m *TraceIdRatioBased) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceIdRatioBased.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceIdRatioBased proto.InternalMessageInfo
+
+func (m *TraceIdRatioBased) GetSamplingRatio() float64 {
+	if m != nil {
+		return m.SamplingRatio
+	}
+	return 0
+}
+
+// Sampler that tries to sample with a rate per time window.
+type RateLimitingSampler struct {
+	// Rate per second.
+	Qps                  int64    `protobuf:"varint,1,opt,name=qps,proto3" json:"qps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RateLimitingSampler) Reset()         { *m = RateLimitingSampler{} }
+func (m *RateLimitingSampler) String() string { return proto.CompactTextString(m) }
+func (*RateLimitingSampler) ProtoMessage()    {}
+func (*RateLimitingSampler) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b84003fbcd8d3179, []int{7}
+}
+func (m *RateLimitingSampler) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RateLimitingSampler.Unmarshal(m, b)
+}
+func (m *RateLimitingSampler) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RateLimitingSampler.Marshal(b, m, deterministic)
+}
+func (m *RateLimitingSampler) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RateLimitingSampler.Merge(m, src)
+}
+func (m *RateLimitingSampler) XXX_Size() int {
+	return xxx_messageInfo_RateLimitingSampler.Size(m)
+}
+func (m *RateLimitingSampler) XXX_DiscardUnknown() {
+	xxx_messageInfo_RateLimitingSampler.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RateLimitingSampler proto.InternalMessageInfo
+
+func (m *RateLimitingSampler) GetQps() int64 {
+	if m != nil {
+		return m.Qps
 	}
+	return 0
 }
 
 func init() {
+	proto.RegisterEnum("opentelemetry.proto.experimental.metrics.configservice.ConstantSampler_ConstantDecision", ConstantSampler_ConstantDecision_name, ConstantSampler_ConstantDecision_value)
 	proto.RegisterType((*MetricConfigRequest)(nil), "opentelemetry.proto.experimental.metrics.configservice.MetricConfigRequest")
 	proto.RegisterType((*MetricConfigResponse)(nil), "opentelemetry.proto.experimental.metrics.configservice.MetricConfigResponse")
 	proto.RegisterType((*MetricConfigResponse_Schedule)(nil), "opentelemetry.proto.experimental.metrics.configservice.MetricConfigResponse.Schedule")
 	proto.RegisterType((*MetricConfigResponse_Schedule_Pattern)(nil), "opentelemetry.proto.experimental.metrics.configservice.MetricConfigResponse.Schedule.Pattern")
+	proto.RegisterType((*TraceConfigRequest)(nil), "opentelemetry.proto.experimental.metrics.configservice.TraceConfigRequest")
+	proto.RegisterType((*TraceConfigResponse)(nil), "opentelemetry.proto.experimental.metrics.configservice.TraceConfigResponse")
+	proto.RegisterType((*TraceParams)(nil), "opentelemetry.proto.experimental.metrics.configservice.TraceParams")
+	proto.RegisterType((*ConstantSampler)(nil), "opentelemetry.proto.experimental.metrics.configservice.ConstantSampler")
+	proto.RegisterType((*TraceIdRatioBased)(nil), "opentelemetry.proto.experimental.metrics.configservice.TraceIdRatioBased")
+	proto.RegisterType((*RateLimitingSampler)(nil), "opentelemetry.proto.experimental.metrics.configservice.RateLimitingSampler")
 }
 
 func init() {
@@ -308,39 +741,68 @@ func init() {
 }
 
 var fileDescriptor_b84003fbcd8d3179 = []byte{
-	// 504 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x94, 0xcb, 0x6e, 0xd3, 0x40,
-	0x14, 0x86, 0x99, 0x86, 0x5e, 0x72, 0x52, 0x09, 0x31, 0x44, 0x60, 0x45, 0x42, 0x0a, 0x5d, 0x85,
-	0x45, 0xc7, 0x6a, 0xb8, 0x48, 0x6c, 0x83, 0xb8, 0x88, 0x82, 0x88, 0x1c, 0x50, 0x24, 0x24, 0x64,
-	0xb9, 0xce, 0xa9, 0x3d, 0xc2, 0x9e, 0x71, 0x3d, 0xc7, 0x49, 0x79, 0x07, 0x56, 0xac, 0x78, 0x08,
-	0x16, 0xbc, 0x00, 0xcf, 0x06, 0xf2, 0xa5, 0x8e, 0x2d, 0xb2, 0x40, 0x55, 0x61, 0xe7, 0xf9, 0xff,
-	0x39, 0xdf, 0xf9, 0xe7, 0x68, 0x3c, 0xf0, 0x4a, 0x27, 0xa8, 0x08, 0x23, 0x8c, 0x91, 0xd2, 0xcf,
-	0x76, 0x92, 0x6a, 0xd2, 0x36, 0x9e, 0x27, 0x98, 0xca, 0x18, 0x15, 0x79, 0x91, 0x9d, 0x1b, 0xd2,
-	0x37, 0xb6, 0xaf, 0xd5, 0xa9, 0x0c, 0x0c, 0xa6, 0x4b, 0xe9, 0x63, 0x7b, 0x25, 0x8a, 0x32, 0xfe,
-	0xb8, 0xc5, 0x2a, 0x45, 0xd1, 0x64, 0x89, 0x8a, 0x25, 0x5a, 0xd5, 0x03, 0xb1, 0x29, 0x43, 0x8a,
-	0x46, 0x67, 0xa9, 0x8f, 0xf6, 0xf2, 0xa8, 0xfe, 0x2e, 0x91, 0x07, 0x5f, 0x19, 0xdc, 0x7a, 0x53,
-	0x90, 0x9e, 0x16, 0x1c, 0x07, 0xcf, 0x32, 0x34, 0xc4, 0x9f, 0xc1, 0xde, 0xc5, 0x4e, 0x8b, 0x0d,
-	0xd9, 0xa8, 0x37, 0xbe, 0x2f, 0x36, 0x45, 0xaa, 0x71, 0xcb, 0x23, 0xe1, 0x54, 0xdf, 0x4e, 0x5d,
-	0xca, 0x1f, 0xc2, 0xed, 0xc8, 0x33, 0xe4, 0x7e, 0x52, 0x7a, 0xa5, 0xdc, 0x53, 0xa9, 0x02, 0x4c,
-	0x93, 0x54, 0x2a, 0xb2, 0xb6, 0x86, 0x6c, 0xb4, 0xef, 0xf4, 0x73, 0xf7, 0x38, 0x37, 0x9f, 0xaf,
-	0xbd, 0x83, 0x5f, 0xd7, 0xa1, 0xdf, 0x0e, 0x65, 0x12, 0xad, 0x0c, 0xf2, 0x21, 0xf4, 0x9a, 0x0c,
-	0x56, 0x30, 0x9a, 0x12, 0x37, 0xd0, 0x35, 0x7e, 0x88, 0x8b, 0x2c, 0x42, 0x63, 0x6d, 0x0d, 0x3b,
-	0xa3, 0xde, 0xf8, 0xbd, 0xb8, 0xdc, 0x2c, 0xc5, 0xa6, 0x08, 0x62, 0x56, 0xd1, 0x9d, 0x75, 0x1f,
-	0xfe, 0x08, 0xee, 0x98, 0x2c, 0x08, 0xd0, 0x10, 0x2e, 0xdc, 0x95, 0x27, 0xc9, 0x25, 0x19, 0xa3,
-	0x6b, 0xd0, 0xb7, 0x3a, 0x43, 0x36, 0xda, 0x76, 0xfa, 0xb5, 0x3d, 0xf7, 0x24, 0xbd, 0x93, 0x31,
-	0xce, 0xd0, 0x1f, 0x7c, 0xeb, 0xc0, 0xde, 0x05, 0x8e, 0x7f, 0x61, 0xc0, 0xf1, 0xdc, 0x8f, 0x32,
-	0x23, 0xb5, 0x72, 0x13, 0x8f, 0x08, 0x53, 0x65, 0x2c, 0x56, 0x1c, 0xe1, 0xe3, 0x3f, 0x39, 0x82,
-	0x98, 0x96, 0x5d, 0x9c, 0x9b, 0x75, 0xe3, 0x4a, 0x31, 0x45, 0x1c, 0xa9, 0xfe, 0x88, 0xb3, 0xf5,
-	0x5f, 0xe2, 0xd4, 0x8d, 0xeb, 0x38, 0x77, 0x01, 0x72, 0xb8, 0x5e, 0x34, 0x86, 0xda, 0x2d, 0x95,
-	0x7c, 0x92, 0x6f, 0x61, 0xb7, 0xda, 0xca, 0x2d, 0xd8, 0xc1, 0xb3, 0xcc, 0x8b, 0x4c, 0x71, 0x3b,
-	0xba, 0x2f, 0xaf, 0x39, 0xd5, 0x9a, 0xdf, 0x83, 0x9e, 0x21, 0x2f, 0x25, 0xe3, 0xae, 0x24, 0x85,
-	0xc5, 0x05, 0xcc, 0x6d, 0x28, 0xc5, 0xb9, 0xa4, 0x70, 0xb2, 0x0b, 0xdb, 0xb1, 0x47, 0x7e, 0x38,
-	0xfe, 0xc9, 0x60, 0xbf, 0x19, 0x96, 0x7f, 0x67, 0x70, 0xe3, 0x05, 0x52, 0x4b, 0x3b, 0xbe, 0x9a,
-	0x31, 0x14, 0x3f, 0xdc, 0xe0, 0xf5, 0x55, 0xce, 0x74, 0xf2, 0x83, 0xc1, 0x13, 0xa9, 0x2f, 0x89,
-	0x9c, 0x58, 0x4d, 0xe6, 0xac, 0x14, 0xa7, 0x79, 0xf1, 0x94, 0x7d, 0x98, 0x07, 0x92, 0xc2, 0xec,
-	0x44, 0xf8, 0x3a, 0xb6, 0x73, 0xfc, 0xe1, 0xfa, 0xb1, 0x69, 0x75, 0x3b, 0x2c, 0x9f, 0x9e, 0x00,
-	0x95, 0x1d, 0xfc, 0xcd, 0x2b, 0x78, 0xb2, 0x53, 0x14, 0x3c, 0xf8, 0x1d, 0x00, 0x00, 0xff, 0xff,
-	0xaa, 0x7b, 0x5f, 0x2c, 0x46, 0x05, 0x00, 0x00,
+	// 976 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x97, 0x5b, 0x6f, 0xe3, 0x44,
+	0x14, 0xc7, 0x3b, 0xc9, 0xa6, 0x97, 0x93, 0x5e, 0xd2, 0x69, 0xb7, 0x58, 0x15, 0x48, 0xdd, 0x08,
+	0x89, 0xf2, 0x50, 0x67, 0x37, 0xdc, 0xb4, 0xbc, 0xb5, 0xdd, 0x5e, 0x76, 0x5b, 0xba, 0x91, 0x53,
+	0x54, 0x40, 0x42, 0xd6, 0xc4, 0x39, 0x71, 0x46, 0xeb, 0x5b, 0x67, 0x26, 0x6d, 0x90, 0xe0, 0x81,
+	0x77, 0x5e, 0xe0, 0x5b, 0x20, 0x01, 0x82, 0x2f, 0xc1, 0x1b, 0x4f, 0x48, 0x7c, 0x08, 0xde, 0xf8,
+	0x06, 0xc8, 0x63, 0xd7, 0xb1, 0xdb, 0x2c, 0x5a, 0x65, 0x0b, 0x62, 0xdf, 0x3c, 0xff, 0xe3, 0xf3,
+	0x3b, 0xff, 0x39, 0x73, 0xb1, 0x0c, 0x4f, 0xc2, 0x08, 0x03, 0x85, 0x1e, 0xfa, 0xa8, 0xc4, 0x17,
+	0x8d, 0x48, 0x84, 0x2a, 0x6c, 0xe0, 0x30, 0x42, 0xc1, 0x7d, 0x0c, 0x14, 0xf3, 0x1a, 0x71, 0x80,
+	0x3b, 0xb2, 0xe1, 0x84, 0x41, 0x8f, 0xbb, 0x12, 0xc5, 0x05, 0x77, 0xb0, 0x38, 0x32, 0x75, 0x1a,
+	0x7d, 0xbf, 0xc0, 0x4a, 0x44, 0x33, 0xcf, 0x32, 0x53, 0x96, 0x59, 0xc8, 0x5e, 0x37, 0xc7, 0x79,
+	0x10, 0x28, 0xc3, 0x81, 0x70, 0xb0, 0x71, 0xf1, 0x20, 0x7b, 0x4e, 0x90, 0xf5, 0xef, 0x08, 0xac,
+	0x7c, 0xa4, 0x49, 0xbb, 0x9a, 0x63, 0xe1, 0xf9, 0x00, 0xa5, 0xa2, 0x7b, 0x30, 0x7b, 0xf5, 0xa6,
+	0x41, 0x36, 0xc8, 0x66, 0xb5, 0xf9, 0xb6, 0x39, 0xce, 0x52, 0x86, 0xbb, 0x78, 0x60, 0x5a, 0xe9,
+	0xb3, 0x95, 0xa5, 0xd2, 0x77, 0x61, 0xcd, 0x63, 0x52, 0xd9, 0xcf, 0x82, 0xf0, 0x32, 0xb0, 0x7b,
+	0x3c, 0x70, 0x51, 0x44, 0x82, 0x07, 0xca, 0x28, 0x6d, 0x90, 0xcd, 0x79, 0x6b, 0x35, 0x8e, 0x1e,
+	0xc5, 0xc1, 0xfd, 0x51, 0xac, 0xfe, 0x63, 0x05, 0x56, 0x8b, 0xa6, 0x64, 0x14, 0x06, 0x12, 0xe9,
+	0x06, 0x54, 0xf3, 0x0c, 0xa2, 0x19, 0x79, 0x89, 0x4a, 0x98, 0x93, 0x4e, 0x1f, 0xbb, 0x03, 0x0f,
+	0xa5, 0x51, 0xda, 0x28, 0x6f, 0x56, 0x9b, 0x1f, 0x9b, 0x93, 0xf5, 0xd2, 0x1c, 0x67, 0xc1, 0x6c,
+	0xa7, 0x74, 0x6b, 0x54, 0x87, 0xbe, 0x07, 0xaf, 0xc9, 0x81, 0xeb, 0xa2, 0x54, 0xd8, 0xb5, 0x2f,
+	0x19, 0x57, 0xb6, 0xe2, 0x3e, 0xda, 0x12, 0x1d, 0xa3, 0xbc, 0x41, 0x36, 0x2b, 0xd6, 0x6a, 0x16,
+	0x3e, 0x63, 0x5c, 0x9d, 0x72, 0x1f, 0xdb, 0xe8, 0xac, 0xff, 0x5a, 0x86, 0xd9, 0x2b, 0x1c, 0xfd,
+	0x86, 0x00, 0xc5, 0xa1, 0xe3, 0x0d, 0x24, 0x0f, 0x03, 0x3b, 0x62, 0x4a, 0xa1, 0x08, 0xa4, 0x41,
+	0xf4, 0x14, 0x3e, 0xff, 0x57, 0xa6, 0x60, 0xb6, 0x92, 0x2a, 0xd6, 0x72, 0x56, 0x38, 0x55, 0xa4,
+	0xb6, 0xc3, 0x83, 0x1b, 0x76, 0x4a, 0xff, 0x89, 0x9d, 0xac, 0x70, 0x66, 0xe7, 0x0d, 0x80, 0x18,
+	0x1e, 0x76, 0x73, 0x4d, 0x9d, 0x4b, 0x94, 0xb8, 0x93, 0x5f, 0xc1, 0x4c, 0xfa, 0x2a, 0x35, 0x60,
+	0x1a, 0xcf, 0x07, 0xcc, 0x93, 0x7a, 0x77, 0xcc, 0x1d, 0x4e, 0x59, 0xe9, 0x98, 0xde, 0x83, 0xaa,
+	0x54, 0x4c, 0x28, 0x69, 0x5f, 0x72, 0xd5, 0x37, 0x4a, 0x69, 0x18, 0x12, 0xf1, 0x8c, 0xab, 0x3e,
+	0x5d, 0x83, 0x8a, 0x40, 0x17, 0x87, 0x46, 0x39, 0x0d, 0x26, 0x43, 0xba, 0x0a, 0x77, 0x5c, 0x2f,
+	0xec, 0x18, 0x77, 0x52, 0x59, 0x8f, 0x76, 0x66, 0xa0, 0xe2, 0x33, 0xe5, 0xf4, 0xeb, 0xdf, 0x12,
+	0xa0, 0xa7, 0x82, 0x39, 0xf8, 0x3f, 0x3a, 0x43, 0x7f, 0x10, 0x58, 0x29, 0x78, 0x7a, 0xe1, 0x23,
+	0xd4, 0x83, 0x79, 0x15, 0x27, 0xda, 0x11, 0x13, 0xcc, 0x97, 0xba, 0x4a, 0xb5, 0xb9, 0x3b, 0xe9,
+	0x9a, 0x6b, 0x13, 0x2d, 0x8d, 0xb2, 0xaa, 0x6a, 0x34, 0x98, 0xf0, 0xd4, 0xd4, 0x7f, 0xaf, 0x40,
+	0x35, 0xc7, 0xa4, 0x0a, 0x6a, 0x4e, 0x18, 0x48, 0xc5, 0x02, 0x65, 0x4b, 0xe6, 0x47, 0x1e, 0x8a,
+	0xb4, 0xdb, 0x07, 0x93, 0x5a, 0xde, 0x4d, 0x79, 0xed, 0x04, 0x77, 0x38, 0x65, 0x2d, 0x39, 0x45,
+	0x89, 0x7e, 0x09, 0xab, 0x49, 0x93, 0x78, 0xd7, 0x16, 0x4c, 0xf1, 0xd0, 0xee, 0x30, 0x89, 0xdd,
+	0xb4, 0x59, 0x8f, 0x5f, 0xaa, 0x59, 0x8f, 0xbb, 0x56, 0x4c, 0xdc, 0x89, 0x81, 0x87, 0x53, 0xd6,
+	0xb2, 0xba, 0x2e, 0xd2, 0xaf, 0x09, 0xdc, 0x15, 0x4c, 0xa1, 0xed, 0x71, 0x9f, 0x2b, 0x1e, 0xb8,
+	0xd9, 0xcc, 0xcb, 0xba, 0xfe, 0xd1, 0xa4, 0xf5, 0x2d, 0xa6, 0xf0, 0x38, 0x65, 0x8e, 0x66, 0xbf,
+	0x22, 0x6e, 0xca, 0xf4, 0x03, 0x30, 0x7c, 0x36, 0xb4, 0x83, 0x81, 0xdf, 0x41, 0x61, 0x87, 0x3d,
+	0x9b, 0x29, 0x25, 0x78, 0x67, 0xa0, 0x50, 0xea, 0x73, 0x52, 0xb6, 0xee, 0xfa, 0x6c, 0x78, 0xa2,
+	0xc3, 0x4f, 0x7b, 0xdb, 0x59, 0x90, 0x7e, 0x08, 0xeb, 0xc5, 0xc4, 0x78, 0xd9, 0xbb, 0x36, 0x5e,
+	0x60, 0xa0, 0xa4, 0x51, 0xd1, 0xa9, 0x6b, 0xb9, 0xd4, 0x78, 0xe1, 0xbb, 0x7b, 0x3a, 0x4a, 0x4f,
+	0x61, 0xf3, 0x79, 0x45, 0xed, 0x08, 0x45, 0x1e, 0x65, 0x4c, 0x6b, 0x52, 0x7d, 0xac, 0x89, 0x16,
+	0x8a, 0x11, 0x96, 0x6e, 0xc1, 0x4a, 0x91, 0xea, 0xf1, 0xe0, 0x99, 0x34, 0x66, 0x34, 0xa0, 0x96,
+	0x03, 0x1c, 0xc7, 0x3a, 0x3d, 0x80, 0x7b, 0xff, 0x68, 0x22, 0xce, 0x36, 0x66, 0x75, 0xf2, 0xeb,
+	0xcf, 0xab, 0x1e, 0x93, 0x76, 0xe6, 0x60, 0x26, 0x5d, 0xb7, 0xfa, 0x6f, 0x04, 0x96, 0xae, 0x6d,
+	0x3b, 0xaa, 0x60, 0xb6, 0x8b, 0x0e, 0x8f, 0x2f, 0x42, 0xbd, 0xa3, 0x17, 0x9b, 0x9f, 0xdc, 0xd2,
+	0x8e, 0xce, 0xc6, 0x8f, 0x52, 0xbe, 0x95, 0x55, 0xaa, 0x3f, 0x82, 0xda, 0xf5, 0x28, 0x5d, 0x04,
+	0xd8, 0x3e, 0x3e, 0xdb, 0xfe, 0xb4, 0x6d, 0x3f, 0xdd, 0xdf, 0xaf, 0x4d, 0xd1, 0x05, 0x98, 0xbb,
+	0x1a, 0x9f, 0xd4, 0x08, 0x5d, 0x86, 0x85, 0x74, 0xd8, 0xda, 0xb6, 0xf6, 0x4e, 0x4e, 0x6b, 0xa5,
+	0xfa, 0x43, 0x58, 0xbe, 0xb1, 0x97, 0xe9, 0x9b, 0xb0, 0xa0, 0xe7, 0xcb, 0x03, 0x57, 0xab, 0x7a,
+	0x56, 0xc4, 0x2a, 0x8a, 0xf5, 0xb7, 0x60, 0x65, 0xcc, 0x36, 0xa4, 0x35, 0x28, 0x9f, 0x47, 0xc9,
+	0xad, 0x5e, 0xb6, 0xe2, 0xc7, 0xe6, 0x5f, 0x25, 0x98, 0xcf, 0x7f, 0x51, 0xe8, 0x0f, 0x04, 0x96,
+	0x0e, 0x50, 0x15, 0xb4, 0xa3, 0xdb, 0xf9, 0x56, 0xe9, 0x1b, 0x7d, 0xfd, 0xf8, 0x36, 0x3f, 0x7c,
+	0xf4, 0x17, 0x02, 0xb4, 0xad, 0x04, 0x32, 0xff, 0x15, 0x71, 0x7c, 0x9f, 0x34, 0xff, 0x2c, 0xa5,
+	0xb7, 0x6f, 0x6a, 0xf6, 0x7b, 0x02, 0x8b, 0x07, 0xa8, 0xf2, 0xd2, 0x93, 0x97, 0xba, 0xfc, 0x8a,
+	0xf6, 0x8f, 0x6e, 0x85, 0x95, 0xf6, 0xfb, 0x27, 0x02, 0xcb, 0x49, 0xbf, 0x5f, 0x05, 0xbb, 0xf7,
+	0xc9, 0xce, 0xcf, 0x04, 0x1e, 0xf2, 0x70, 0x42, 0xe6, 0x8e, 0x91, 0x5f, 0xc2, 0x76, 0x22, 0xb6,
+	0xe2, 0xe4, 0x16, 0xf9, 0xec, 0xcc, 0xe5, 0xaa, 0x3f, 0xe8, 0x98, 0x4e, 0xe8, 0x37, 0x62, 0xfc,
+	0xd6, 0xe8, 0x97, 0xa1, 0x50, 0x6d, 0x2b, 0xf9, 0x81, 0x70, 0x31, 0x68, 0xb8, 0x2f, 0xf2, 0x2f,
+	0xd3, 0x99, 0xd6, 0x09, 0xef, 0xfc, 0x3d, 0x00, 0x1c, 0xc4, 0x5b, 0x6f, 0x0c, 0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -356,6 +818,11 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type MetricConfigClient interface {
 	GetMetricConfig(ctx context.Context, in *MetricConfigRequest, opts ...grpc.CallOption) (*MetricConfigResponse, error)
+	// StreamMetricConfig sends a MetricConfigResponse as soon as the call is
+	// made, and a new MetricConfigResponse each time the configuration for the
+	// resource changes. Clients that do not support streaming may keep polling
+	// GetMetricConfig instead.
+	StreamMetricConfig(ctx context.Context, in *MetricConfigRequest, opts ...grpc.CallOption) (MetricConfig_StreamMetricConfigClient, error)
 }
 
 type metricConfigClient struct {
@@ -375,9 +842,46 @@ func (c *metricConfigClient) GetMetricConfig(ctx context.Context, in *MetricConf
 	return out, nil
 }
 
+func (c *metricConfigClient) StreamMetricConfig(ctx context.Context, in *MetricConfigRequest, opts ...grpc.CallOption) (MetricConfig_StreamMetricConfigClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MetricConfig_serviceDesc.Streams[0], "/opentelemetry.proto.experimental.metrics.configservice.MetricConfig/StreamMetricConfig", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metricConfigStreamMetricConfigClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MetricConfig_StreamMetricConfigClient interface {
+	Recv() (*MetricConfigResponse, error)
+	grpc.ClientStream
+}
+
+type metricConfigStreamMetricConfigClient struct {
+	grpc.ClientStream
+}
+
+func (x *metricConfigStreamMetricConfigClient) Recv() (*MetricConfigResponse, error) {
+	m := new(MetricConfigResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MetricConfigServer is the server API for MetricConfig service.
 type MetricConfigServer interface {
 	GetMetricConfig(context.Context, *MetricConfigRequest) (*MetricConfigResponse, error)
+	// StreamMetricConfig sends a MetricConfigResponse as soon as the call is
+	// made, and a new MetricConfigResponse each time the configuration for the
+	// resource changes. Clients that do not support streaming may keep polling
+	// GetMetricConfig instead.
+	StreamMetricConfig(*MetricConfigRequest, MetricConfig_StreamMetricConfigServer) error
 }
 
 // UnimplementedMetricConfigServer can be embedded to have forward compatible implementations.
@@ -387,6 +891,9 @@ type UnimplementedMetricConfigServer struct {
 func (*UnimplementedMetricConfigServer) GetMetricConfig(ctx context.Context, req *MetricConfigRequest) (*MetricConfigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMetricConfig not implemented")
 }
+func (*UnimplementedMetricConfigServer) StreamMetricConfig(req *MetricConfigRequest, srv MetricConfig_StreamMetricConfigServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMetricConfig not implemented")
+}
 
 func RegisterMetricConfigServer(s *grpc.Server, srv MetricConfigServer) {
 	s.RegisterService(&_MetricConfig_serviceDesc, srv)
@@ -410,6 +917,27 @@ func _MetricConfig_GetMetricConfig_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetricConfig_StreamMetricConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MetricConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetricConfigServer).StreamMetricConfig(m, &metricConfigStreamMetricConfigServer{stream})
+}
+
+type MetricConfig_StreamMetricConfigServer interface {
+	Send(*MetricConfigResponse) error
+	grpc.ServerStream
+}
+
+type metricConfigStreamMetricConfigServer struct {
+	grpc.ServerStream
+}
+
+func (x *metricConfigStreamMetricConfigServer) Send(m *MetricConfigResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _MetricConfig_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "opentelemetry.proto.experimental.metrics.configservice.MetricConfig",
 	HandlerType: (*MetricConfigServer)(nil),
@@ -419,6 +947,152 @@ var _MetricConfig_serviceDesc = grpc.ServiceDesc{
 			Handler:    _MetricConfig_GetMetricConfig_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMetricConfig",
+			Handler:       _MetricConfig_StreamMetricConfig_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "opentelemetry/proto/experimental/metrics/configservice/configservice.proto",
+}
+
+// TraceConfigClient is the client API for TraceConfig service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TraceConfigClient interface {
+	GetTraceConfig(ctx context.Context, in *TraceConfigRequest, opts ...grpc.CallOption) (*TraceConfigResponse, error)
+	// StreamTraceConfig is the streaming counterpart of GetTraceConfig, following
+	// the same semantics as MetricConfig.StreamMetricConfig.
+	StreamTraceConfig(ctx context.Context, in *TraceConfigRequest, opts ...grpc.CallOption) (TraceConfig_StreamTraceConfigClient, error)
+}
+
+type traceConfigClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTraceConfigClient(cc *grpc.ClientConn) TraceConfigClient {
+	return &traceConfigClient{cc}
+}
+
+func (c *traceConfigClient) GetTraceConfig(ctx context.Context, in *TraceConfigRequest, opts ...grpc.CallOption) (*TraceConfigResponse, error) {
+	out := new(TraceConfigResponse)
+	err := c.cc.Invoke(ctx, "/opentelemetry.proto.experimental.metrics.configservice.TraceConfig/GetTraceConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *traceConfigClient) StreamTraceConfig(ctx context.Context, in *TraceConfigRequest, opts ...grpc.CallOption) (TraceConfig_StreamTraceConfigClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TraceConfig_serviceDesc.Streams[0], "/opentelemetry.proto.experimental.metrics.configservice.TraceConfig/StreamTraceConfig", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &traceConfigStreamTraceConfigClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TraceConfig_StreamTraceConfigClient interface {
+	Recv() (*TraceConfigResponse, error)
+	grpc.ClientStream
+}
+
+type traceConfigStreamTraceConfigClient struct {
+	grpc.ClientStream
+}
+
+func (x *traceConfigStreamTraceConfigClient) Recv() (*TraceConfigResponse, error) {
+	m := new(TraceConfigResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TraceConfigServer is the server API for TraceConfig service.
+type TraceConfigServer interface {
+	GetTraceConfig(context.Context, *TraceConfigRequest) (*TraceConfigResponse, error)
+	// StreamTraceConfig is the streaming counterpart of GetTraceConfig, following
+	// the same semantics as MetricConfig.StreamMetricConfig.
+	StreamTraceConfig(*TraceConfigRequest, TraceConfig_StreamTraceConfigServer) error
+}
+
+// UnimplementedTraceConfigServer can be embedded to have forward compatible implementations.
+type UnimplementedTraceConfigServer struct {
+}
+
+func (*UnimplementedTraceConfigServer) GetTraceConfig(ctx context.Context, req *TraceConfigRequest) (*TraceConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTraceConfig not implemented")
+}
+func (*UnimplementedTraceConfigServer) StreamTraceConfig(req *TraceConfigRequest, srv TraceConfig_StreamTraceConfigServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTraceConfig not implemented")
+}
+
+func RegisterTraceConfigServer(s *grpc.Server, srv TraceConfigServer) {
+	s.RegisterService(&_TraceConfig_serviceDesc, srv)
+}
+
+func _TraceConfig_GetTraceConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TraceConfigServer).GetTraceConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/opentelemetry.proto.experimental.metrics.configservice.TraceConfig/GetTraceConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TraceConfigServer).GetTraceConfig(ctx, req.(*TraceConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TraceConfig_StreamTraceConfig_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceConfigRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TraceConfigServer).StreamTraceConfig(m, &traceConfigStreamTraceConfigServer{stream})
+}
+
+type TraceConfig_StreamTraceConfigServer interface {
+	Send(*TraceConfigResponse) error
+	grpc.ServerStream
+}
+
+type traceConfigStreamTraceConfigServer struct {
+	grpc.ServerStream
+}
+
+func (x *traceConfigStreamTraceConfigServer) Send(m *TraceConfigResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TraceConfig_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "opentelemetry.proto.experimental.metrics.configservice.TraceConfig",
+	HandlerType: (*TraceConfigServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTraceConfig",
+			Handler:    _TraceConfig_GetTraceConfig_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTraceConfig",
+			Handler:       _TraceConfig_StreamTraceConfig_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "opentelemetry/proto/experimental/metrics/configservice/configservice.proto",
 }
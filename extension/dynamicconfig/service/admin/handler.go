@@ -0,0 +1,288 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin implements an HTTP/JSON API to inspect and edit the config
+// blocks served by the dynamic config service at runtime.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/model"
+	com "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/common/v1"
+	res "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/resource/v1"
+)
+
+const (
+	blocksPath = "/v1/blocks"
+	matchPath  = "/v1/match"
+)
+
+// Editor is implemented by config backends whose config blocks can be
+// inspected and edited at runtime, such as the file backend.
+type Editor interface {
+	// Config returns the config blocks currently served. The returned Config
+	// must not be modified.
+	Config() *model.Config
+	// UpdateConfig applies the update to a copy of the current config, and
+	// serves the result if the update succeeds and the result is valid.
+	UpdateConfig(update func(*model.Config) error) error
+}
+
+// BlockResponse is the JSON representation of a single config block,
+// together with its index in the list of config blocks.
+type BlockResponse struct {
+	Index       int
+	ConfigBlock *model.ConfigBlock
+}
+
+// MatchRequest is the JSON request body used to resolve the configuration of
+// a resource, given as a map of resource labels.
+type MatchRequest struct {
+	Resource map[string]string
+}
+
+// MatchResponse lists the indices of the config blocks matching a resource,
+// in the order they are combined, and the resulting combined config block.
+type MatchResponse struct {
+	MatchedBlocks []int
+	ConfigBlock   *model.ConfigBlock
+}
+
+type errorResponse struct {
+	Error string
+}
+
+// errUnauthorized is returned to requests without the expected bearer token.
+var errUnauthorized = errors.New("missing or invalid bearer token")
+
+// errNotFound is returned from updates referring to a nonexistent block.
+var errNotFound = errors.New("config block not found")
+
+type handler struct {
+	editor Editor
+	logger *zap.Logger
+}
+
+// NewHandler returns an http.Handler serving the admin API for the given
+// editor. The following routes are supported:
+//    * GET /v1/blocks lists all config blocks
+//    * POST /v1/blocks appends a new config block
+//    * GET /v1/blocks/{index} returns a single config block
+//    * PUT /v1/blocks/{index} replaces a config block
+//    * DELETE /v1/blocks/{index} removes a config block
+//    * POST /v1/match resolves the config block served to a resource
+// New and replaced config blocks are validated before they are served.
+func NewHandler(editor Editor, logger *zap.Logger) http.Handler {
+	h := &handler{editor: editor, logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(blocksPath, h.handleBlocks)
+	mux.HandleFunc(blocksPath+"/", h.handleBlock)
+	mux.HandleFunc(matchPath, h.handleMatch)
+
+	return mux
+}
+
+// RequireBearerToken returns an http.Handler rejecting requests whose
+// Authorization header does not carry the given bearer token with 401
+// Unauthorized, and passing all other requests on to next.
+func RequireBearerToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(actual, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *handler) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, h.editor.Config())
+
+	case http.MethodPost:
+		block, err := decodeBlock(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		var index int
+		err = h.editor.UpdateConfig(func(config *model.Config) error {
+			index = len(config.ConfigBlocks)
+			config.ConfigBlocks = append(config.ConfigBlocks, block)
+			return nil
+		})
+		if err != nil {
+			writeUpdateError(w, err)
+			return
+		}
+
+		h.logger.Info("Added config block", zap.Int("index", index))
+		writeJSON(w, http.StatusCreated, &BlockResponse{Index: index, ConfigBlock: block})
+
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (h *handler) handleBlock(w http.ResponseWriter, r *http.Request) {
+	index, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, blocksPath+"/"))
+	if err != nil || index < 0 {
+		writeError(w, http.StatusNotFound, errNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		blocks := h.editor.Config().ConfigBlocks
+		if index >= len(blocks) {
+			writeError(w, http.StatusNotFound, errNotFound)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, &BlockResponse{Index: index, ConfigBlock: blocks[index]})
+
+	case http.MethodPut:
+		block, err := decodeBlock(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		err = h.editor.UpdateConfig(func(config *model.Config) error {
+			if index >= len(config.ConfigBlocks) {
+				return errNotFound
+			}
+
+			config.ConfigBlocks[index] = block
+			return nil
+		})
+		if err != nil {
+			writeUpdateError(w, err)
+			return
+		}
+
+		h.logger.Info("Replaced config block", zap.Int("index", index))
+		writeJSON(w, http.StatusOK, &BlockResponse{Index: index, ConfigBlock: block})
+
+	case http.MethodDelete:
+		err := h.editor.UpdateConfig(func(config *model.Config) error {
+			if index >= len(config.ConfigBlocks) {
+				return errNotFound
+			}
+
+			config.ConfigBlocks = append(config.ConfigBlocks[:index], config.ConfigBlocks[index+1:]...)
+			return nil
+		})
+		if err != nil {
+			writeUpdateError(w, err)
+			return
+		}
+
+		h.logger.Info("Deleted config block", zap.Int("index", index))
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeMethodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (h *handler) handleMatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req MatchRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resource := &res.Resource{}
+	for key, value := range req.Resource {
+		resource.Attributes = append(resource.Attributes, &com.KeyValue{
+			Key:   key,
+			Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: value}},
+		})
+	}
+
+	config := h.editor.Config()
+	writeJSON(w, http.StatusOK, &MatchResponse{
+		MatchedBlocks: config.MatchingBlocks(resource),
+		ConfigBlock:   config.Match(resource),
+	})
+}
+
+func decodeBlock(r *http.Request) (*model.ConfigBlock, error) {
+	block := &model.ConfigBlock{}
+	if err := decode(r, block); err != nil {
+		return nil, err
+	}
+
+	if err := block.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config block: %w", err)
+	}
+
+	return block, nil
+}
+
+func decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("failed to decode request body: %w", err)
+	}
+
+	return nil
+}
+
+func writeUpdateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNotFound) {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeError(w, http.StatusInternalServerError, err)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/model"
+)
+
+// fakeEditor keeps config blocks in memory, like the file backend without
+// persistence.
+type fakeEditor struct {
+	mu     sync.Mutex
+	config *model.Config
+	err    error // returned from UpdateConfig, if set
+}
+
+func (e *fakeEditor) Config() *model.Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return &model.Config{ConfigBlocks: append([]*model.ConfigBlock(nil), e.config.ConfigBlocks...)}
+}
+
+func (e *fakeEditor) UpdateConfig(update func(*model.Config) error) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	config := e.Config()
+	if err := update(config); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config = config
+	return nil
+}
+
+func newTestHandler() (*fakeEditor, http.Handler) {
+	editor := &fakeEditor{
+		config: &model.Config{
+			ConfigBlocks: []*model.ConfigBlock{
+				{
+					Schedules: []*model.Schedule{{Period: "MIN_5"}},
+				},
+				{
+					Selectors: []string{"env=prod"},
+					Priority:  1,
+					Schedules: []*model.Schedule{{Period: "MIN_1"}},
+				},
+			},
+		},
+	}
+
+	return editor, NewHandler(editor, zap.NewNop())
+}
+
+func serve(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestListBlocks(t *testing.T) {
+	_, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodGet, "/v1/blocks", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var config model.Config
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config))
+	require.Len(t, config.ConfigBlocks, 2)
+	assert.Equal(t, []string{"env=prod"}, config.ConfigBlocks[1].Selectors)
+}
+
+func TestGetBlock(t *testing.T) {
+	_, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodGet, "/v1/blocks/1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp BlockResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Index)
+	assert.Equal(t, 1, resp.ConfigBlock.Priority)
+
+	for _, path := range []string{"/v1/blocks/2", "/v1/blocks/-1", "/v1/blocks/first"} {
+		rec = serve(handler, http.MethodGet, path, "")
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+}
+
+func TestAddBlock(t *testing.T) {
+	editor, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodPost, "/v1/blocks",
+		`{"Resource": ["service.name:db"], "Schedules": [{"Period": "SEC_10"}]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	var resp BlockResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Index)
+
+	blocks := editor.Config().ConfigBlocks
+	require.Len(t, blocks, 3)
+	assert.Equal(t, model.CollectionPeriod("SEC_10"), blocks[2].Schedules[0].Period)
+}
+
+func TestAddInvalidBlock(t *testing.T) {
+	editor, handler := newTestHandler()
+
+	for _, body := range []string{
+		`{"Schedules": [{"Period": "EVERY_NOW_AND_THEN"}]}`,
+		`{"Selectors": ["env in (prod"]}`,
+		`{"TraceConfig": {"SamplingRatio": 2}}`,
+		`{"Schedule": []}`,
+		`not json`,
+	} {
+		rec := serve(handler, http.MethodPost, "/v1/blocks", body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	assert.Len(t, editor.Config().ConfigBlocks, 2)
+}
+
+func TestReplaceBlock(t *testing.T) {
+	editor, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodPut, "/v1/blocks/0", `{"Schedules": [{"Period": "HR_1"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, model.CollectionPeriod("HR_1"), editor.Config().ConfigBlocks[0].Schedules[0].Period)
+
+	rec = serve(handler, http.MethodPut, "/v1/blocks/5", `{"Schedules": [{"Period": "HR_1"}]}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDeleteBlock(t *testing.T) {
+	editor, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodDelete, "/v1/blocks/0", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	blocks := editor.Config().ConfigBlocks
+	require.Len(t, blocks, 1)
+	assert.Equal(t, []string{"env=prod"}, blocks[0].Selectors)
+
+	rec = serve(handler, http.MethodDelete, "/v1/blocks/1", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestUpdateFailure(t *testing.T) {
+	editor, handler := newTestHandler()
+	editor.err = errors.New("disk full")
+
+	rec := serve(handler, http.MethodDelete, "/v1/blocks/0", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "disk full")
+}
+
+func TestMatch(t *testing.T) {
+	_, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodPost, "/v1/match", `{"Resource": {"env": "prod"}}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp MatchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []int{0, 1}, resp.MatchedBlocks)
+	require.Len(t, resp.ConfigBlock.Schedules, 2)
+	assert.Equal(t, []string{"env:prod"}, resp.ConfigBlock.Resource)
+
+	rec = serve(handler, http.MethodPost, "/v1/match", `{"Resource": {"env": "dev"}}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []int{0}, resp.MatchedBlocks)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	_, handler := newTestHandler()
+
+	rec := serve(handler, http.MethodDelete, "/v1/blocks", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+
+	rec = serve(handler, http.MethodGet, "/v1/match", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	editor, handler := newTestHandler()
+	handler = RequireBearerToken("secret", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{name: "add", method: http.MethodPost, path: "/v1/blocks", body: `{"Schedules": [{"Period": "SEC_1"}]}`},
+		{name: "replace", method: http.MethodPut, path: "/v1/blocks/0", body: `{"Schedules": [{"Period": "SEC_1"}]}`},
+		{name: "delete", method: http.MethodDelete, path: "/v1/blocks/0"},
+		{name: "list", method: http.MethodGet, path: "/v1/blocks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, authorization := range []string{"", "Bearer wrong", "secret"} {
+				req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+				if authorization != "" {
+					req.Header.Set("Authorization", authorization)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	blocks := editor.Config().ConfigBlocks
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "MIN_5", string(blocks[0].Schedules[0].Period))
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/blocks/0", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, editor.Config().ConfigBlocks, 1)
+}
@@ -16,12 +16,15 @@ package file
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/collector/config"
+	"gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/model"
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
@@ -29,15 +32,17 @@ import (
 )
 
 // A Backend is a ConfigBackend that uses a local file to determine what
-// schedules to change. The file is read live, so changes to it will reflect
-// immediately in the configs.
+// schedules and trace parameters to change. The file is read live, so changes
+// to it will reflect immediately in the configs.
 type Backend struct {
 	viper *viper.Viper
 
 	mu          sync.Mutex
 	configModel *model.Config // protected by mutex
+	updated     chan struct{} // protected by mutex; closed on each update
 
 	waitTime int32
+	persist  bool          // write changes made by UpdateConfig to the file
 	updateCh chan struct{} // syncs updates; meant for testing
 }
 
@@ -45,6 +50,7 @@ func NewBackend(configFile string) (*Backend, error) {
 	backend := &Backend{
 		viper:    config.NewViper(),
 		waitTime: 30,
+		updated:  make(chan struct{}),
 	}
 	backend.viper.SetConfigFile(configFile)
 
@@ -76,13 +82,100 @@ func (backend *Backend) updateConfig() error {
 		return fmt.Errorf("file backend failed to decode config: %w", err)
 	}
 
+	if err := configModel.Validate(); err != nil {
+		return fmt.Errorf("file backend found invalid config: %w", err)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	backend.setConfig(&configModel)
+	return nil
+}
+
+// setConfig replaces the config model and notifies waiting streams. The
+// caller must hold the mutex.
+func (backend *Backend) setConfig(configModel *model.Config) {
+	backend.configModel = configModel
+	close(backend.updated)
+	backend.updated = make(chan struct{})
+}
+
+// Config returns the config blocks currently served by the backend. The
+// blocks are shared with the backend and must not be modified; use
+// UpdateConfig instead.
+func (backend *Backend) Config() *model.Config {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	return backend.copyConfig()
+}
+
+// UpdateConfig applies the given update to a copy of the current config, and
+// serves the result if the update succeeds and the result is valid. The update
+// may add, replace or remove config blocks, but must not modify existing
+// blocks in place. If persistence is enabled, the result is also written to
+// the config file; otherwise it is served until the file next changes.
+func (backend *Backend) UpdateConfig(update func(*model.Config) error) error {
 	backend.mu.Lock()
 	defer backend.mu.Unlock()
 
-	backend.configModel = &configModel
+	configModel := backend.copyConfig()
+	if err := update(configModel); err != nil {
+		return err
+	}
+
+	if err := configModel.Validate(); err != nil {
+		return err
+	}
+
+	if backend.persist {
+		if err := backend.writeConfig(configModel); err != nil {
+			return err
+		}
+	}
+
+	backend.setConfig(configModel)
+	return nil
+}
+
+// copyConfig makes a shallow copy of the config model. The caller must hold
+// the mutex.
+func (backend *Backend) copyConfig() *model.Config {
+	blocks := make([]*model.ConfigBlock, len(backend.configModel.ConfigBlocks))
+	copy(blocks, backend.configModel.ConfigBlocks)
+
+	return &model.Config{ConfigBlocks: blocks}
+}
+
+func (backend *Backend) writeConfig(configModel *model.Config) error {
+	data, err := yaml.Marshal(configModel)
+	if err != nil {
+		return fmt.Errorf("file backend failed to encode config: %w", err)
+	}
+
+	filename := backend.viper.ConfigFileUsed()
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode()
+	}
+
+	if err := ioutil.WriteFile(filename, data, mode); err != nil {
+		return fmt.Errorf("file backend failed to write config: %w", err)
+	}
+
 	return nil
 }
 
+// Updated returns a channel that is closed the next time the config file is
+// successfully reloaded.
+func (backend *Backend) Updated() <-chan struct{} {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	return backend.updated
+}
+
 // BuildConfigResponse builds a MetricConfigResponse based on the config data
 // and backend settings.
 func (backend *Backend) BuildConfigResponse(resource *res.Resource) (*pb.MetricConfigResponse, error) {
@@ -104,6 +197,27 @@ func (backend *Backend) BuildConfigResponse(resource *res.Resource) (*pb.MetricC
 	return resp, nil
 }
 
+// BuildTraceConfigResponse builds a TraceConfigResponse based on the config
+// data and backend settings.
+func (backend *Backend) BuildTraceConfigResponse(resource *res.Resource) (*pb.TraceConfigResponse, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	configBlock := backend.configModel.Match(resource)
+	paramsProto, err := configBlock.TraceConfig.Proto()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.TraceConfigResponse{
+		Fingerprint:          configBlock.TraceConfig.Hash(),
+		TraceParams:          paramsProto,
+		SuggestedWaitTimeSec: backend.waitTime,
+	}
+
+	return resp, nil
+}
+
 func (backend *Backend) GetWaitTime() int32 {
 	return backend.waitTime
 }
@@ -114,6 +228,12 @@ func (backend *Backend) SetWaitTime(waitTime int32) {
 	}
 }
 
+// SetPersist specifies whether changes made by UpdateConfig are written back
+// to the config file.
+func (backend *Backend) SetPersist(persist bool) {
+	backend.persist = persist
+}
+
 func (backend *Backend) Close() error {
 	// TODO: need to cleanup Viper resources?
 	return nil
@@ -20,6 +20,8 @@ import (
 	"os"
 	"testing"
 	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/model"
 )
 
 func TestNewFileConfig(t *testing.T) {
@@ -31,6 +33,10 @@ func TestNewFileConfig(t *testing.T) {
 		t.Errorf("failed to catch improper config file, built config: %v", config)
 	}
 
+	if config, err := NewBackend("../../testdata/schedules_bad_selector.yaml"); err == nil {
+		t.Errorf("failed to catch invalid selector, built config: %v", config)
+	}
+
 	if _, err := NewBackend("../../testdata/schedules.yaml"); err != nil {
 		t.Fatalf("failed to read config file")
 	}
@@ -81,6 +87,102 @@ func TestUpdateConfig(t *testing.T) {
 	}
 }
 
+func TestEditConfig(t *testing.T) {
+	tmpfile := newTmpSchedule(t)
+	defer os.Remove(tmpfile.Name())
+
+	writeString(t, tmpfile, `ConfigBlocks:
+    - Schedules:
+        - Period: MIN_5`)
+
+	backend, err := NewBackend(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("fail to create backend: %v", err)
+	}
+	defer backend.Close()
+
+	updated := backend.Updated()
+	err = backend.UpdateConfig(func(config *model.Config) error {
+		config.ConfigBlocks = append(config.ConfigBlocks, &model.ConfigBlock{
+			Resource:  []string{"service.name:db"},
+			Schedules: []*model.Schedule{{Period: "MIN_1"}},
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to update config: %v", err)
+	}
+
+	select {
+	case <-updated:
+	default:
+		t.Errorf("update did not notify streams")
+	}
+
+	if blocks := backend.Config().ConfigBlocks; len(blocks) != 2 || blocks[1].Schedules[0].Period != "MIN_1" {
+		t.Errorf("update incorrect: got blocks: %v", blocks)
+	}
+
+	err = backend.UpdateConfig(func(config *model.Config) error {
+		config.ConfigBlocks[0] = &model.ConfigBlock{Selectors: []string{"env in (prod"}}
+		return nil
+	})
+	if err == nil {
+		t.Errorf("failed to catch invalid selector")
+	}
+
+	if blocks := backend.Config().ConfigBlocks; len(blocks) != 2 || blocks[0].Selectors != nil {
+		t.Errorf("invalid update was applied: got blocks: %v", blocks)
+	}
+
+	data, err := ioutil.ReadFile(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("cannot read schedule: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("service.name:db")) {
+		t.Errorf("update persisted without persistence enabled: %s", data)
+	}
+}
+
+func TestEditConfigPersist(t *testing.T) {
+	tmpfile := newTmpSchedule(t)
+	defer os.Remove(tmpfile.Name())
+
+	writeString(t, tmpfile, `ConfigBlocks:
+    - Schedules:
+        - Period: MIN_5`)
+
+	backend, err := NewBackend(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("fail to create backend: %v", err)
+	}
+	defer backend.Close()
+	backend.SetPersist(true)
+
+	err = backend.UpdateConfig(func(config *model.Config) error {
+		config.ConfigBlocks[0] = &model.ConfigBlock{
+			Selectors: []string{"env=prod"},
+			Schedules: []*model.Schedule{{Period: "MIN_1"}},
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fail to update config: %v", err)
+	}
+
+	reloaded, err := NewBackend(tmpfile.Name())
+	if err != nil {
+		t.Fatalf("fail to reload persisted config: %v", err)
+	}
+	defer reloaded.Close()
+
+	blocks := reloaded.Config().ConfigBlocks
+	if len(blocks) != 1 || blocks[0].Selectors[0] != "env=prod" || blocks[0].Schedules[0].Period != "MIN_1" {
+		t.Errorf("persisted config incorrect: got blocks: %v", blocks)
+	}
+}
+
 func newTmpSchedule(t *testing.T) *os.File {
 	tmpfile, err := ioutil.TempFile("", "schedule.*.yaml")
 	if err != nil {
@@ -157,6 +259,36 @@ func TestBuildConfigResponse(t *testing.T) {
 	}
 }
 
+func TestBuildTraceConfigResponse(t *testing.T) {
+	backend, err := NewBackend("../../testdata/schedules.yaml")
+	if err != nil {
+		t.Fatalf("failed to read config file")
+	}
+
+	resp, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil {
+		t.Errorf("fail to build trace config response: %v", err)
+	}
+
+	if resp.Fingerprint == nil || resp.TraceParams == nil || resp.SuggestedWaitTimeSec == 0 {
+		t.Errorf("trace config response incomplete: %v", resp)
+	}
+
+	if resp.TraceParams.MaxNumberOfAttributes != 32 {
+		t.Errorf("expected 32 max attributes, got: %v", resp.TraceParams.MaxNumberOfAttributes)
+	}
+
+	fingerprint := backend.configModel.ConfigBlocks[0].TraceConfig.Hash()
+	if !bytes.Equal(fingerprint, resp.Fingerprint) {
+		t.Errorf("fingerprint inconsistent: expected %v, got %v",
+			fingerprint, resp.Fingerprint)
+	}
+
+	if err := backend.Close(); err != nil {
+		t.Errorf("fail to close backend: %v", err)
+	}
+}
+
 func TestBuildConfigResponseWithDuplicateInPattern(t *testing.T) {
 	backend, err := NewBackend("../../testdata/schedules_improper_pattern.yaml")
 	if err != nil {
@@ -29,11 +29,15 @@ var GlobalFingerprint = []byte("There once was a cat named Gretchen")
 var GlobalResponse = &pb.MetricConfigResponse{
 	Fingerprint: GlobalFingerprint,
 }
+var GlobalTraceResponse = &pb.TraceConfigResponse{
+	Fingerprint: GlobalFingerprint,
+}
 var GlobalError error
 
 func AlterFingerprint(newFingerprint []byte) {
 	GlobalFingerprint = newFingerprint
 	GlobalResponse.Fingerprint = GlobalFingerprint
+	GlobalTraceResponse.Fingerprint = GlobalFingerprint
 }
 
 func ResetError() {
@@ -50,18 +54,27 @@ func (*Backend) BuildConfigResponse(*res.Resource) (*pb.MetricConfigResponse, er
 	return GlobalResponse, GlobalError
 }
 
+func (*Backend) BuildTraceConfigResponse(*res.Resource) (*pb.TraceConfigResponse, error) {
+	return GlobalTraceResponse, GlobalError
+}
+
 func (*Backend) Close() error {
 	return GlobalError
 }
 
 type Service struct {
 	pb.UnimplementedMetricConfigServer
+	pb.UnimplementedTraceConfigServer
 }
 
 func (*Service) GetMetricConfig(context.Context, *pb.MetricConfigRequest) (*pb.MetricConfigResponse, error) {
 	return GlobalResponse, GlobalError
 }
 
+func (*Service) GetTraceConfig(context.Context, *pb.TraceConfigRequest) (*pb.TraceConfigResponse, error) {
+	return GlobalTraceResponse, GlobalError
+}
+
 // startServer is a test utility to start a quick-n-dirty gRPC server using the
 // mock backend.
 func StartServer(t *testing.T, quit <-chan struct{}, done chan<- struct{}) string {
@@ -75,6 +88,7 @@ func StartServer(t *testing.T, quit <-chan struct{}, done chan<- struct{}) strin
 	server := grpc.NewServer()
 	configService := &Service{}
 	pb.RegisterMetricConfigServer(server, configService)
+	pb.RegisterTraceConfigServer(server, configService)
 
 	go func() {
 		done <- struct{}{}
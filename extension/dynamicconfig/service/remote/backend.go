@@ -18,7 +18,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -26,22 +29,105 @@ import (
 	res "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/resource/v1"
 )
 
+const (
+	// defaultRequestTimeout bounds each call to an upstream service, so that
+	// a hung upstream does not prevent the failover or the fallback to the
+	// last known response.
+	defaultRequestTimeout = 10 * time.Second
+
+	// cacheEntryGracePeriod is how long a cache entry is kept after the cache
+	// TTL once it is no longer requested. Resources carry per-instance
+	// attributes, so entries of instances that went away are evicted.
+	cacheEntryGracePeriod = 10 * time.Minute
+)
+
 // A Backend is a ConfigBackend that communicates with an upstream config
-// service to obtain config data.
+// service to obtain config data. Responses are cached per resource. When
+// several upstream addresses are given, they are tried in order until one
+// answers, and when none answers the last known good response for the
+// resource is served.
 type Backend struct {
-	remoteConfigAddress string
-	conn                *grpc.ClientConn
-	client              pb.MetricConfigClient
+	upstreams      []*upstream
+	dialOptions    []grpc.DialOption
+	cacheTTL       time.Duration
+	requestTimeout time.Duration
+	gracePeriod    time.Duration
+
+	mu        sync.Mutex
+	active    int                    // protected by mutex; index of the last healthy upstream
+	cache     map[string]*cacheEntry // protected by mutex; keyed by resource
+	lastEvict time.Time              // protected by mutex; time of the last eviction sweep
+}
+
+type upstream struct {
+	address     string
+	conn        *grpc.ClientConn
+	client      pb.MetricConfigClient
+	traceClient pb.TraceConfigClient
+}
+
+// A cacheEntry holds the last responses received for a resource, along with
+// the time each was last confirmed by an upstream service.
+type cacheEntry struct {
+	resp          *pb.MetricConfigResponse
+	respTime      time.Time
+	traceResp     *pb.TraceConfigResponse
+	traceRespTime time.Time
+	lastUsed      time.Time
+}
+
+// Option configures optional settings of a Backend.
+type Option func(*Backend)
 
-	mu   sync.Mutex
-	resp *pb.MetricConfigResponse // protected by mutex
+// WithFailover adds upstream config services, tried in the given order when
+// the primary upstream service cannot be reached.
+func WithFailover(addresses ...string) Option {
+	return func(backend *Backend) {
+		for _, address := range addresses {
+			backend.upstreams = append(backend.upstreams, &upstream{address: address})
+		}
+	}
+}
+
+// WithDialOptions specifies the options used to connect to the upstream
+// services, e.g. transport and per-RPC credentials. By default, connections
+// are insecure.
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(backend *Backend) {
+		backend.dialOptions = append(backend.dialOptions, opts...)
+	}
+}
+
+// WithCacheTTL specifies how long a response is served from the cache before
+// the upstream service is queried again for the same resource. By default,
+// every request is forwarded upstream.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(backend *Backend) {
+		backend.cacheTTL = ttl
+	}
+}
+
+// WithRequestTimeout specifies how long a call to an upstream service may
+// take before the next upstream service is tried. Defaults to 10 seconds.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(backend *Backend) {
+		if timeout > 0 {
+			backend.requestTimeout = timeout
+		}
+	}
 }
 
-func NewBackend(remoteConfigAddress string) (*Backend, error) {
+func NewBackend(remoteConfigAddress string, opts ...Option) (*Backend, error) {
 	backend := &Backend{
-		remoteConfigAddress: remoteConfigAddress,
-		conn:                nil,
-		client:              nil,
+		upstreams:      []*upstream{{address: remoteConfigAddress}},
+		requestTimeout: defaultRequestTimeout,
+		gracePeriod:    cacheEntryGracePeriod,
+		cache:          make(map[string]*cacheEntry),
+		lastEvict:      time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(backend)
 	}
 
 	if err := backend.initConn(); err != nil {
@@ -52,62 +138,209 @@ func NewBackend(remoteConfigAddress string) (*Backend, error) {
 }
 
 func (backend *Backend) initConn() error {
-	conn, err := grpc.Dial(
-		backend.remoteConfigAddress,
-		grpc.WithInsecure(), // TODO: consider security implications
-	)
-	if err != nil {
-		return fmt.Errorf("remote config backend fail to connect: %w", err)
+	dialOptions := backend.dialOptions
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{grpc.WithInsecure()}
+	}
+
+	for _, up := range backend.upstreams {
+		conn, err := grpc.Dial(up.address, dialOptions...)
+		if err != nil {
+			backend.Close()
+			return fmt.Errorf("remote config backend fail to connect: %w", err)
+		}
+
+		up.conn = conn
+		up.client = pb.NewMetricConfigClient(conn)
+		up.traceClient = pb.NewTraceConfigClient(conn)
 	}
 
-	backend.conn = conn
-	backend.client = pb.NewMetricConfigClient(conn)
 	return nil
 }
 
 // BuildConfigResponse builds a MetricConfigResponse based on responses from the
 // upstream config server.
 func (backend *Backend) BuildConfigResponse(resource *res.Resource) (*pb.MetricConfigResponse, error) {
-	if err := backend.syncRemote(resource); err != nil {
+	key := resourceKey(resource)
+
+	backend.mu.Lock()
+	entry := backend.entry(key)
+	cached, cachedTime := entry.resp, entry.respTime
+	backend.mu.Unlock()
+
+	if cached != nil && time.Since(cachedTime) < backend.cacheTTL {
+		return cached, nil
+	}
+
+	req := &pb.MetricConfigRequest{Resource: resource}
+	if cached != nil {
+		req.LastKnownFingerprint = cached.Fingerprint
+	}
+
+	var resp *pb.MetricConfigResponse
+	err := backend.invoke(func(ctx context.Context, up *upstream) error {
+		var err error
+		resp, err = up.client.GetMetricConfig(ctx, req)
+		return err
+	})
+	if err != nil {
+		if cached != nil {
+			return cached, nil
+		}
+
 		return nil, fmt.Errorf("fail to build config resp: %w", err)
 	}
 
 	backend.mu.Lock()
 	defer backend.mu.Unlock()
 
-	resp := backend.resp
-	return resp, nil
+	if cached == nil || !bytes.Equal(cached.Fingerprint, resp.Fingerprint) {
+		entry.resp = resp
+	}
+	entry.respTime = time.Now()
+
+	return entry.resp, nil
 }
 
-func (backend *Backend) syncRemote(resource *res.Resource) error {
+// BuildTraceConfigResponse builds a TraceConfigResponse based on responses
+// from the upstream config server.
+func (backend *Backend) BuildTraceConfigResponse(resource *res.Resource) (*pb.TraceConfigResponse, error) {
+	key := resourceKey(resource)
+
 	backend.mu.Lock()
-	defer backend.mu.Unlock()
+	entry := backend.entry(key)
+	cached, cachedTime := entry.traceResp, entry.traceRespTime
+	backend.mu.Unlock()
 
-	var lastKnownFingerprint []byte
-	if backend.resp != nil {
-		lastKnownFingerprint = backend.resp.Fingerprint
+	if cached != nil && time.Since(cachedTime) < backend.cacheTTL {
+		return cached, nil
 	}
 
-	req := &pb.MetricConfigRequest{
-		Resource:             resource,
-		LastKnownFingerprint: lastKnownFingerprint,
+	req := &pb.TraceConfigRequest{Resource: resource}
+	if cached != nil {
+		req.LastKnownFingerprint = cached.Fingerprint
 	}
 
-	resp, err := backend.client.GetMetricConfig(context.Background(), req)
-	if err != nil {
+	var resp *pb.TraceConfigResponse
+	err := backend.invoke(func(ctx context.Context, up *upstream) error {
+		var err error
+		resp, err = up.traceClient.GetTraceConfig(ctx, req)
 		return err
+	})
+	if err != nil {
+		if cached != nil {
+			return cached, nil
+		}
+
+		return nil, fmt.Errorf("fail to build trace config resp: %w", err)
 	}
 
-	if backend.resp == nil || !bytes.Equal(backend.resp.Fingerprint, resp.Fingerprint) {
-		backend.resp = resp
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	if cached == nil || !bytes.Equal(cached.Fingerprint, resp.Fingerprint) {
+		entry.traceResp = resp
+	}
+	entry.traceRespTime = time.Now()
+
+	return entry.traceResp, nil
+}
+
+// entry returns the cache entry for a resource key, creating it if needed,
+// and evicts the entries that are no longer requested. The caller must hold
+// the mutex.
+func (backend *Backend) entry(key string) *cacheEntry {
+	now := time.Now()
+	backend.evict(now)
+
+	entry, ok := backend.cache[key]
+	if !ok {
+		entry = &cacheEntry{}
+		backend.cache[key] = entry
+	}
+	entry.lastUsed = now
+
+	return entry
+}
+
+// evict removes the entries that were not requested for longer than the
+// cache TTL and the grace period. Entries are swept at most once per grace
+// period. The caller must hold the mutex.
+func (backend *Backend) evict(now time.Time) {
+	if now.Sub(backend.lastEvict) < backend.gracePeriod {
+		return
+	}
+	backend.lastEvict = now
+
+	for key, entry := range backend.cache {
+		if now.Sub(entry.lastUsed) > backend.cacheTTL+backend.gracePeriod {
+			delete(backend.cache, key)
+		}
 	}
-	return nil
 }
 
-// Close shuts down the connection to the upstream config server.
+// invoke calls the given function against each upstream service, starting
+// with the last healthy one, until a call succeeds. Each call is bounded by
+// the request timeout. The error of the last call is returned if all calls
+// fail.
+func (backend *Backend) invoke(call func(context.Context, *upstream) error) error {
+	backend.mu.Lock()
+	start := backend.active
+	backend.mu.Unlock()
+
+	var err error
+	for i := range backend.upstreams {
+		idx := (start + i) % len(backend.upstreams)
+		if err = backend.call(call, backend.upstreams[idx]); err == nil {
+			backend.mu.Lock()
+			backend.active = idx
+			backend.mu.Unlock()
+
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (backend *Backend) call(call func(context.Context, *upstream) error, up *upstream) error {
+	ctx, cancel := context.WithTimeout(context.Background(), backend.requestTimeout)
+	defer cancel()
+
+	return call(ctx, up)
+}
+
+// resourceKey builds a string uniquely identifying a resource, regardless
+// of the order of its attributes.
+func resourceKey(resource *res.Resource) string {
+	if resource == nil {
+		return ""
+	}
+
+	attrs := make([]string, len(resource.Attributes))
+	for i, attr := range resource.Attributes {
+		attrs[i] = attr.String()
+	}
+	sort.Strings(attrs)
+
+	return strings.Join(attrs, "\n")
+}
+
+// Close shuts down the connections to the upstream config servers.
 func (backend *Backend) Close() error {
-	if err := backend.conn.Close(); err != nil {
-		return fmt.Errorf("remote config backend fail to close connection: %w", err)
+	var errs []string
+	for _, up := range backend.upstreams {
+		if up.conn == nil {
+			continue
+		}
+
+		if err := up.conn.Close(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", up.address, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("remote config backend fail to close connection: %s", strings.Join(errs, "; "))
 	}
 
 	return nil
@@ -16,10 +16,28 @@ package remote
 
 import (
 	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
+	"math/big"
+	"net"
 	"testing"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	com "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/common/v1"
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
+	res "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/resource/v1"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/service/mock"
 )
 
@@ -73,6 +91,20 @@ func TestBuildConfigResponseRemote(t *testing.T) {
 	}
 }
 
+func TestBuildTraceConfigResponseRemote(t *testing.T) {
+	backend, quit, done := SetUpServer(t)
+	defer TearDownServer(t, backend, quit, done)
+
+	resp, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil {
+		t.Errorf("fail to build trace config response: %v", err)
+	}
+
+	if !bytes.Equal(resp.Fingerprint, mock.GlobalTraceResponse.Fingerprint) {
+		t.Errorf("expected resp %v, got %v", mock.GlobalTraceResponse, resp)
+	}
+}
+
 func buildResp(t *testing.T, backend *Backend) *pb.MetricConfigResponse {
 	resp, err := backend.BuildConfigResponse(nil)
 	if err != nil {
@@ -102,3 +134,301 @@ func TestDoubleClose(t *testing.T) {
 		t.Errorf("should have failed to close backend, since it's already closed")
 	}
 }
+
+func TestFailover(t *testing.T) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	address := mock.StartServer(t, quit, done)
+	<-done
+
+	// nothing listens on the primary address
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("fail to listen: %v", err)
+	}
+	deadAddress := listen.Addr().String()
+	listen.Close()
+
+	backend, err := NewBackend(deadAddress, WithFailover(address))
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+	defer TearDownServer(t, backend, quit, done)
+
+	resp := buildResp(t, backend)
+	if resp == nil || !bytes.Equal(resp.Fingerprint, mock.GlobalResponse.Fingerprint) {
+		t.Errorf("expected resp %v, got %v", mock.GlobalResponse, resp)
+	}
+
+	if backend.active != 1 {
+		t.Errorf("expected failover upstream to become active, got: %v", backend.active)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	address := mock.StartServer(t, quit, done)
+	<-done
+
+	backend, err := NewBackend(address, WithCacheTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+	defer TearDownServer(t, backend, quit, done)
+
+	defer mock.AlterFingerprint(mock.GlobalFingerprint)
+	mock.AlterFingerprint([]byte("first"))
+	resp := buildResp(t, backend)
+
+	mock.AlterFingerprint([]byte("second"))
+	if cached := buildResp(t, backend); !bytes.Equal(cached.Fingerprint, resp.Fingerprint) {
+		t.Errorf("expected cached fingerprint %s, got %s", resp.Fingerprint, cached.Fingerprint)
+	}
+
+	// a different resource is not served from the same cache entry
+	other := &res.Resource{
+		Attributes: []*com.KeyValue{
+			{Key: "service", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: "other"}}},
+		},
+	}
+	otherResp, err := backend.BuildConfigResponse(other)
+	if err != nil || string(otherResp.Fingerprint) != "second" {
+		t.Errorf("expected fresh fingerprint for other resource, got: %v: %v", otherResp, err)
+	}
+}
+
+func TestLastKnownGood(t *testing.T) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	address := mock.StartServer(t, quit, done)
+	<-done
+
+	backend, err := NewBackend(address)
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+
+	resp := buildResp(t, backend)
+	traceResp, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil {
+		t.Fatalf("fail to build trace config response: %v", err)
+	}
+
+	quit <- struct{}{}
+	<-done
+
+	if stale := buildResp(t, backend); !bytes.Equal(stale.Fingerprint, resp.Fingerprint) {
+		t.Errorf("expected last known fingerprint %s, got %s", resp.Fingerprint, stale.Fingerprint)
+	}
+
+	staleTrace, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil || !bytes.Equal(staleTrace.Fingerprint, traceResp.Fingerprint) {
+		t.Errorf("expected last known trace response %v, got %v: %v", traceResp, staleTrace, err)
+	}
+
+	if err := backend.Close(); err != nil {
+		t.Errorf("fail to close backend: %v", err)
+	}
+}
+
+// hangingServer is an upstream config service that never responds.
+type hangingServer struct {
+	pb.UnimplementedMetricConfigServer
+	pb.UnimplementedTraceConfigServer
+}
+
+func (*hangingServer) GetMetricConfig(ctx context.Context, _ *pb.MetricConfigRequest) (*pb.MetricConfigResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (*hangingServer) GetTraceConfig(ctx context.Context, _ *pb.TraceConfigRequest) (*pb.TraceConfigResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func startHangingServer(t *testing.T) (string, func()) {
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("fail to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterMetricConfigServer(server, &hangingServer{})
+	pb.RegisterTraceConfigServer(server, &hangingServer{})
+	go server.Serve(listen)
+
+	return listen.Addr().String(), server.Stop
+}
+
+func TestRequestTimeout(t *testing.T) {
+	hangingAddress, stop := startHangingServer(t)
+	defer stop()
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	address := mock.StartServer(t, quit, done)
+	<-done
+
+	backend, err := NewBackend(hangingAddress, WithFailover(address), WithRequestTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+
+	start := time.Now()
+	resp := buildResp(t, backend)
+	if resp == nil || !bytes.Equal(resp.Fingerprint, mock.GlobalResponse.Fingerprint) {
+		t.Errorf("expected resp %v, got %v", mock.GlobalResponse, resp)
+	}
+	traceResp, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil {
+		t.Fatalf("fail to build trace config response: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the hung upstream to time out, took %v", elapsed)
+	}
+	if backend.active != 1 {
+		t.Errorf("expected failover upstream to become active, got: %v", backend.active)
+	}
+
+	// only the hung upstream is left: the last known responses are served.
+	quit <- struct{}{}
+	<-done
+	backend.active = 0
+
+	if stale := buildResp(t, backend); !bytes.Equal(stale.Fingerprint, resp.Fingerprint) {
+		t.Errorf("expected last known fingerprint %s, got %s", resp.Fingerprint, stale.Fingerprint)
+	}
+	staleTrace, err := backend.BuildTraceConfigResponse(nil)
+	if err != nil || !bytes.Equal(staleTrace.Fingerprint, traceResp.Fingerprint) {
+		t.Errorf("expected last known trace response %v, got %v: %v", traceResp, staleTrace, err)
+	}
+
+	if err := backend.Close(); err != nil {
+		t.Errorf("fail to close backend: %v", err)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	backend, quit, done := SetUpServer(t)
+	defer TearDownServer(t, backend, quit, done)
+
+	buildResp(t, backend)
+	if len(backend.cache) != 1 {
+		t.Fatalf("expected 1 cache entry, got %d", len(backend.cache))
+	}
+
+	// the entry has not been requested for longer than the grace period.
+	backend.mu.Lock()
+	backend.cache[""].lastUsed = time.Now().Add(-2 * backend.gracePeriod)
+	backend.lastEvict = time.Now().Add(-2 * backend.gracePeriod)
+	backend.mu.Unlock()
+
+	other := &res.Resource{
+		Attributes: []*com.KeyValue{
+			{Key: "service", Value: &com.AnyValue{Value: &com.AnyValue_StringValue{StringValue: "other"}}},
+		},
+	}
+	if _, err := backend.BuildConfigResponse(other); err != nil {
+		t.Fatalf("fail to build config response: %v", err)
+	}
+
+	if _, ok := backend.cache[""]; ok || len(backend.cache) != 1 {
+		t.Errorf("expected the idle entry to be evicted, got %d entries", len(backend.cache))
+	}
+}
+
+func TestTLSAndBearerToken(t *testing.T) {
+	const token = "secret-token"
+	cert, pool := newSelfSignedCert(t)
+
+	listen, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("fail to listen: %v", err)
+	}
+
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewServerTLSFromCert(&cert)),
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer "+token {
+				return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+			}
+
+			return handler(ctx, req)
+		}),
+	)
+	pb.RegisterMetricConfigServer(server, &mock.Service{})
+	go server.Serve(listen)
+	defer server.Stop()
+
+	tlsCreds := grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool}))
+	backend, err := NewBackend(listen.Addr().String(), WithDialOptions(tlsCreds))
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+
+	if _, err := backend.BuildConfigResponse(nil); err == nil {
+		t.Errorf("expected request without bearer token to fail")
+	}
+	backend.Close()
+
+	backend, err = NewBackend(listen.Addr().String(),
+		WithDialOptions(tlsCreds, grpc.WithPerRPCCredentials(bearerToken(token))))
+	if err != nil {
+		t.Fatalf("fail to init remote config backend: %v", err)
+	}
+	defer backend.Close()
+
+	resp := buildResp(t, backend)
+	if resp == nil || !bytes.Equal(resp.Fingerprint, mock.GlobalResponse.Fingerprint) {
+		t.Errorf("expected resp %v, got %v", mock.GlobalResponse, resp)
+	}
+}
+
+type bearerToken string
+
+func (token bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(token)}, nil
+}
+
+func (bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+func newSelfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("fail to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("fail to create certificate: %v", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("fail to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
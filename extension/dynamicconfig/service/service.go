@@ -21,6 +21,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
 
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
 	res "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/resource/v1"
@@ -33,14 +37,32 @@ import (
 // configuration data from.
 type ConfigBackend interface {
 	BuildConfigResponse(*res.Resource) (*pb.MetricConfigResponse, error)
+	BuildTraceConfigResponse(*res.Resource) (*pb.TraceConfigResponse, error)
 	Close() error
 }
 
-// ConfigService implements the server side of the gRPC service for config
-// updates.
+// UpdateNotifier is implemented by ConfigBackends that are able to signal when
+// their configuration data may have changed. Streaming clients of a backend
+// that does not implement UpdateNotifier are instead refreshed once per
+// suggested wait time.
+type UpdateNotifier interface {
+	// Updated returns a channel that is closed on the next update.
+	Updated() <-chan struct{}
+}
+
+// defaultStreamWaitTime is the refresh period, in seconds, used for streaming
+// clients when the backend neither notifies updates nor suggests a wait time.
+const defaultStreamWaitTime = 30
+
+// ConfigService implements the server side of the gRPC services for metric
+// and trace config updates.
 type ConfigService struct {
 	pb.UnimplementedMetricConfigServer // for forward compatibility
+	pb.UnimplementedTraceConfigServer  // for forward compatibility
 	backend                            ConfigBackend
+
+	quit     chan struct{} // closed on Stop, ending all open streams
+	stopOnce sync.Once
 }
 
 func NewConfigService(opts ...Option) (*ConfigService, error) {
@@ -54,13 +76,23 @@ func NewConfigService(opts ...Option) (*ConfigService, error) {
 		return nil, err
 	}
 
-	return &ConfigService{backend: backend}, nil
+	return &ConfigService{
+		backend: backend,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// Backend returns the backend that the service reads configuration data from.
+func (service *ConfigService) Backend() ConfigBackend {
+	return service.backend
 }
 
 type serviceBuilder struct {
 	remoteConfigAddress string
+	remoteOptions       []remote.Option
 	filepath            string
 	waitTime            int32
+	persist             bool
 
 	// overrides build() to use this given backend.
 	// NOTE: intended for testing only!
@@ -73,7 +105,7 @@ func (builder *serviceBuilder) build() (ConfigBackend, error) {
 	}
 
 	if builder.remoteConfigAddress != "" {
-		backend, err := remote.NewBackend(builder.remoteConfigAddress)
+		backend, err := remote.NewBackend(builder.remoteConfigAddress, builder.remoteOptions...)
 		if err != nil {
 			return nil, err
 		}
@@ -91,6 +123,8 @@ func (builder *serviceBuilder) build() (ConfigBackend, error) {
 			backend.SetWaitTime(builder.waitTime)
 		}
 
+		backend.SetPersist(builder.persist)
+
 		return backend, nil
 
 	}
@@ -111,6 +145,43 @@ func WithRemoteConfig(remoteConfigAddress string) Option {
 	}
 }
 
+// WithRemoteFailover specifies further upstream config services to use, in
+// order, when the one given to WithRemoteConfig cannot be reached. This option
+// is only used with the remote backend.
+func WithRemoteFailover(addresses ...string) Option {
+	return func(builder *serviceBuilder) {
+		builder.remoteOptions = append(builder.remoteOptions, remote.WithFailover(addresses...))
+	}
+}
+
+// WithRemoteDialOptions specifies the gRPC dial options, such as TLS and
+// per-RPC credentials, used to connect to the upstream config services. By
+// default, the connections are insecure. This option is only used with the
+// remote backend.
+func WithRemoteDialOptions(opts ...grpc.DialOption) Option {
+	return func(builder *serviceBuilder) {
+		builder.remoteOptions = append(builder.remoteOptions, remote.WithDialOptions(opts...))
+	}
+}
+
+// WithRemoteCacheTTL specifies how long responses from the upstream config
+// services are cached for each resource. By default, every request is
+// forwarded upstream. This option is only used with the remote backend.
+func WithRemoteCacheTTL(ttl time.Duration) Option {
+	return func(builder *serviceBuilder) {
+		builder.remoteOptions = append(builder.remoteOptions, remote.WithCacheTTL(ttl))
+	}
+}
+
+// WithRemoteRequestTimeout specifies how long a call to an upstream config
+// service may take before the next one is tried. Defaults to 10 seconds. This
+// option is only used with the remote backend.
+func WithRemoteRequestTimeout(timeout time.Duration) Option {
+	return func(builder *serviceBuilder) {
+		builder.remoteOptions = append(builder.remoteOptions, remote.WithRequestTimeout(timeout))
+	}
+}
+
 // WithFileConfig instantiates a ConfigService that uses a local file backend
 // that monitors a file for configuration data.  If both WithRemoteConfig and
 // WithFileConfig are specified, WithRemoteConfig will take precedence and a
@@ -131,6 +202,15 @@ func WithWaitTime(time int32) Option {
 	}
 }
 
+// WithPersist specifies whether changes made to the configuration at runtime,
+// for instance through the admin API, are written back to the config file.
+// This option is only used with the local file backend.
+func WithPersist(persist bool) Option {
+	return func(builder *serviceBuilder) {
+		builder.persist = persist
+	}
+}
+
 // NOTE: intended for testing only!
 func WithMockBackend() Option {
 	return func(builder *serviceBuilder) {
@@ -153,10 +233,140 @@ func (service *ConfigService) GetMetricConfig(ctx context.Context, req *pb.Metri
 	return resp, nil
 }
 
-// Stop cleans up all resources and connections, and stops the extension from
-// serving new MetricConfigRequests.
+// StreamMetricConfig is the server-side streaming gRPC call that sends the
+// metric schedules corresponding to a particular MetricConfigRequest, followed
+// by a new response each time the fingerprint for the resource changes.
+func (service *ConfigService) StreamMetricConfig(req *pb.MetricConfigRequest, stream pb.MetricConfig_StreamMetricConfigServer) error {
+	lastFingerprint := req.LastKnownFingerprint
+	first := true
+
+	return service.streamUpdates(stream.Context(), func() (int32, error) {
+		resp, err := service.backend.BuildConfigResponse(req.Resource)
+		if err != nil {
+			return 0, fmt.Errorf("backend failed to build config response: %w", err)
+		}
+
+		changed := !bytes.Equal(resp.Fingerprint, lastFingerprint)
+		if first && !changed {
+			resp = &pb.MetricConfigResponse{Fingerprint: resp.Fingerprint}
+		}
+
+		if first || changed {
+			if err := stream.Send(resp); err != nil {
+				return 0, err
+			}
+		}
+
+		first = false
+		lastFingerprint = resp.Fingerprint
+		return resp.SuggestedWaitTimeSec, nil
+	})
+}
+
+// GetTraceConfig is the server-side gRPC call that returns the trace parameters
+// corresponding to a particular TraceConfigRequest.
+func (service *ConfigService) GetTraceConfig(ctx context.Context, req *pb.TraceConfigRequest) (*pb.TraceConfigResponse, error) {
+	resp, err := service.backend.BuildTraceConfigResponse(req.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("backend failed to build trace config response: %w", err)
+	}
+
+	if bytes.Equal(resp.Fingerprint, req.LastKnownFingerprint) {
+		resp = &pb.TraceConfigResponse{Fingerprint: resp.Fingerprint}
+	}
+
+	return resp, nil
+}
+
+// StreamTraceConfig is the server-side streaming gRPC call that sends the
+// trace parameters corresponding to a particular TraceConfigRequest, followed
+// by a new response each time the fingerprint for the resource changes.
+func (service *ConfigService) StreamTraceConfig(req *pb.TraceConfigRequest, stream pb.TraceConfig_StreamTraceConfigServer) error {
+	lastFingerprint := req.LastKnownFingerprint
+	first := true
+
+	return service.streamUpdates(stream.Context(), func() (int32, error) {
+		resp, err := service.backend.BuildTraceConfigResponse(req.Resource)
+		if err != nil {
+			return 0, fmt.Errorf("backend failed to build trace config response: %w", err)
+		}
+
+		changed := !bytes.Equal(resp.Fingerprint, lastFingerprint)
+		if first && !changed {
+			resp = &pb.TraceConfigResponse{Fingerprint: resp.Fingerprint}
+		}
+
+		if first || changed {
+			if err := stream.Send(resp); err != nil {
+				return 0, err
+			}
+		}
+
+		first = false
+		lastFingerprint = resp.Fingerprint
+		return resp.SuggestedWaitTimeSec, nil
+	})
+}
+
+// streamUpdates calls sendUpdate once, and then again each time the backend
+// may have changed, until the stream is closed or the service is stopped.
+// sendUpdate returns the suggested wait time of the response it built, which
+// is used as the refresh period when the backend cannot notify updates.
+func (service *ConfigService) streamUpdates(ctx context.Context, sendUpdate func() (int32, error)) error {
+	notifier, canNotify := service.backend.(UpdateNotifier)
+
+	for {
+		// Obtain the update channel before building the response, so that
+		// no update between the two can be missed.
+		var updated <-chan struct{}
+		if canNotify {
+			updated = notifier.Updated()
+		}
+
+		waitTime, err := sendUpdate()
+		if err != nil {
+			return err
+		}
+
+		if !service.waitForUpdate(ctx, updated, waitTime) {
+			return nil
+		}
+	}
+}
+
+// waitForUpdate blocks until the updated channel is closed or, if it is nil,
+// until waitTime seconds have passed. It returns false if the stream was closed
+// or the service was stopped in the meantime.
+func (service *ConfigService) waitForUpdate(ctx context.Context, updated <-chan struct{}, waitTime int32) bool {
+	var refresh <-chan time.Time
+	if updated == nil {
+		if waitTime <= 0 {
+			waitTime = defaultStreamWaitTime
+		}
+
+		timer := time.NewTimer(time.Duration(waitTime) * time.Second)
+		defer timer.Stop()
+		refresh = timer.C
+	}
+
+	select {
+	case <-updated:
+	case <-refresh:
+	case <-ctx.Done():
+		return false
+	case <-service.quit:
+		return false
+	}
+
+	return true
+}
+
+// Stop cleans up all resources and connections, ends all open streams, and
+// stops the extension from serving new config requests.
 func (service *ConfigService) Stop() error {
 	if service != nil {
+		service.stopOnce.Do(func() { close(service.quit) })
+
 		if err := service.backend.Close(); err != nil {
 			return fmt.Errorf("fail to stop config service: %w", err)
 		}
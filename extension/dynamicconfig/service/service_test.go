@@ -17,7 +17,12 @@ package service
 import (
 	"bytes"
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc"
 
 	pb "github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/proto/experimental/metrics/configservice"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/dynamicconfig/service/file"
@@ -109,6 +114,33 @@ func TestGetMetricConfig(t *testing.T) {
 	}
 }
 
+func TestGetTraceConfig(t *testing.T) {
+	service, err := NewConfigService(WithMockBackend())
+	if err != nil {
+		t.Errorf("failed to initialize service: %v", err)
+	}
+
+	sameFingerprintReq := pb.TraceConfigRequest{LastKnownFingerprint: mock.GlobalFingerprint}
+	resp, err := service.GetTraceConfig(context.Background(), &sameFingerprintReq)
+	if err != nil {
+		t.Errorf("failed to get trace config: %v", err)
+	}
+
+	if !bytes.Equal(resp.Fingerprint, mock.GlobalFingerprint) {
+		t.Errorf("expected fingerprint to equal %v, got %v", mock.GlobalFingerprint, resp.Fingerprint)
+	}
+
+	blankReq := pb.TraceConfigRequest{}
+	resp, err = service.GetTraceConfig(context.Background(), &blankReq)
+	if err != nil {
+		t.Errorf("failed to get trace config: %v", err)
+	}
+
+	if !bytes.Equal(resp.Fingerprint, mock.GlobalFingerprint) {
+		t.Errorf("expected fingerprint to equal %v, got %v", mock.GlobalFingerprint, resp.Fingerprint)
+	}
+}
+
 func TestBackendWithBadSchedules(t *testing.T) {
 	service, err := NewConfigService(
 		WithFileConfig("../testdata/schedules_improper_pattern.yaml"),
@@ -122,3 +154,115 @@ func TestBackendWithBadSchedules(t *testing.T) {
 		t.Errorf("should have failed to build config response with bad schedules")
 	}
 }
+
+type fakeMetricStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	resps chan *pb.MetricConfigResponse
+}
+
+func (stream *fakeMetricStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *fakeMetricStream) Send(resp *pb.MetricConfigResponse) error {
+	stream.resps <- resp
+	return nil
+}
+
+func newFakeMetricStream(ctx context.Context) *fakeMetricStream {
+	return &fakeMetricStream{
+		ctx:   ctx,
+		resps: make(chan *pb.MetricConfigResponse, 10),
+	}
+}
+
+func receive(t *testing.T, resps <-chan *pb.MetricConfigResponse) *pb.MetricConfigResponse {
+	select {
+	case resp := <-resps:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for streamed response")
+	}
+
+	return nil
+}
+
+func TestStreamMetricConfig(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "schedule.*.yaml")
+	if err != nil {
+		t.Fatalf("cannot open tempfile: %v", err)
+	}
+	defer os.Remove(tmpfile.Name())
+
+	writeSchedule := func(period string) {
+		if err := ioutil.WriteFile(tmpfile.Name(), []byte("ConfigBlocks:\n    Schedules:\n        - Period: "+period), 0600); err != nil {
+			t.Fatalf("cannot write schedule: %v", err)
+		}
+	}
+	writeSchedule("MIN_5")
+
+	service, err := NewConfigService(WithFileConfig(tmpfile.Name()))
+	if err != nil {
+		t.Fatalf("failed to initialize service: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newFakeMetricStream(ctx)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- service.StreamMetricConfig(&pb.MetricConfigRequest{}, stream)
+	}()
+
+	resp := receive(t, stream.resps)
+	if len(resp.Schedules) != 1 || resp.Schedules[0].PeriodSec != 300 {
+		t.Errorf("expected a single schedule with period 300, got: %v", resp)
+	}
+
+	writeSchedule("MIN_1")
+	resp = receive(t, stream.resps)
+	if len(resp.Schedules) != 1 || resp.Schedules[0].PeriodSec != 60 {
+		t.Errorf("expected a single schedule with period 60, got: %v", resp)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("stream ended with error: %v", err)
+	}
+
+	if err := service.Stop(); err != nil {
+		t.Errorf("fail to stop service")
+	}
+}
+
+func TestStreamMetricConfigStop(t *testing.T) {
+	service, err := NewConfigService(WithMockBackend())
+	if err != nil {
+		t.Fatalf("failed to initialize service: %v", err)
+	}
+
+	stream := newFakeMetricStream(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		req := &pb.MetricConfigRequest{LastKnownFingerprint: mock.GlobalFingerprint}
+		errCh <- service.StreamMetricConfig(req, stream)
+	}()
+
+	resp := receive(t, stream.resps)
+	if !bytes.Equal(resp.Fingerprint, mock.GlobalFingerprint) || resp.Schedules != nil {
+		t.Errorf("expected fingerprint-only response, got: %v", resp)
+	}
+
+	if err := service.Stop(); err != nil {
+		t.Errorf("fail to stop service")
+	}
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("stream ended with error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("stream not ended by service stop")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for docker observer.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// Endpoint is the address of the Docker daemon, for instance
+	// "unix:///var/run/docker.sock" or "tcp://localhost:2375".
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Timeout is the maximum duration of each request to the Docker daemon.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// DockerAPIVersion is the version of the Docker Engine API to use. It
+	// must be quoted in YAML so that it is not parsed as a number.
+	DockerAPIVersion string `mapstructure:"api_version"`
+
+	// RefreshInterval determines how frequently the observer lists the
+	// running containers.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// ExcludedImages is a list of image names, with or without tags, whose
+	// containers are ignored. Names may contain "*" and "?" wildcards.
+	ExcludedImages []string `mapstructure:"excluded_images"`
+
+	// UseHostBindings determines whether endpoints target the host address
+	// and port that container ports are published to, instead of the
+	// container address and exposed port. Ports that are not published keep
+	// targeting the container.
+	UseHostBindings bool `mapstructure:"use_host_bindings"`
+}
@@ -0,0 +1,276 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	docker "github.com/docker/docker/client"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+type dockerObserver struct {
+	observer.EndpointsWatcher
+	client *docker.Client
+}
+
+type endpointsLister struct {
+	logger       *zap.Logger
+	observerName string
+	config       *Config
+	client       *docker.Client
+}
+
+var _ component.ServiceExtension = (*dockerObserver)(nil)
+
+func newObserver(logger *zap.Logger, config *Config) (component.ServiceExtension, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("\"endpoint\" is required when using the \"docker_observer\" extension")
+	}
+
+	client, err := docker.NewClientWithOpts(
+		docker.WithHost(config.Endpoint),
+		docker.WithVersion(config.DockerAPIVersion),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	d := &dockerObserver{
+		EndpointsWatcher: observer.EndpointsWatcher{
+			RefreshInterval: config.RefreshInterval,
+			Endpointslister: endpointsLister{
+				logger:       logger,
+				observerName: config.Name(),
+				config:       config,
+				client:       client,
+			},
+		},
+		client: client,
+	}
+
+	return d, nil
+}
+
+func (d *dockerObserver) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (d *dockerObserver) Shutdown(context.Context) error {
+	d.StopListAndWatch()
+	return d.client.Close()
+}
+
+func (e endpointsLister) ListEndpoints() []observer.Endpoint {
+	ctx, cancel := context.WithTimeout(context.Background(), e.config.Timeout)
+	defer cancel()
+
+	containers, err := e.client.ContainerList(ctx, types.ContainerListOptions{})
+	if err != nil {
+		e.logger.Error("Could not list docker containers", zap.Error(err))
+		return nil
+	}
+
+	return e.collectEndpoints(containers)
+}
+
+func (e endpointsLister) collectEndpoints(containers []types.Container) []observer.Endpoint {
+	var endpoints []observer.Endpoint
+	for i := range containers {
+		c := &containers[i]
+		if e.isExcluded(c.Image) {
+			continue
+		}
+
+		network, host := containerNetwork(c)
+		image, tag := splitImage(c.Image)
+		for _, p := range uniquePorts(c.Ports) {
+			details := observer.Container{
+				Name:        containerName(c),
+				Image:       image,
+				Tag:         tag,
+				ContainerID: c.ID,
+				Command:     c.Command,
+				Labels:      c.Labels,
+				Network:     network,
+				Host:        host,
+				Port:        p.PrivatePort,
+				HostPort:    p.PublicPort,
+				Transport:   portTypeToProtocol(p.Type),
+			}
+
+			target := fmt.Sprintf("%s:%d", host, p.PrivatePort)
+			if e.config.UseHostBindings && p.PublicPort != 0 {
+				target = fmt.Sprintf("%s:%d", hostBindingIP(p.IP), p.PublicPort)
+			} else if host == "" {
+				e.logger.Debug("Container port has no reachable address (skipping)",
+					zap.String("container", details.Name), zap.Uint16("port", p.PrivatePort),
+				)
+				continue
+			}
+
+			id := observer.EndpointID(
+				fmt.Sprintf(
+					"(%s)%s-%d-%s", e.observerName, c.ID, p.PrivatePort, details.Transport,
+				),
+			)
+
+			endpoints = append(endpoints, observer.Endpoint{
+				ID:      id,
+				Target:  target,
+				Details: details,
+			})
+		}
+	}
+
+	return endpoints
+}
+
+// isExcluded reports whether containers of the given image are ignored.
+// Patterns match the image name, with or without its tag.
+func (e endpointsLister) isExcluded(image string) bool {
+	name, _ := splitImage(image)
+	for _, pattern := range e.config.ExcludedImages {
+		for _, candidate := range []string{image, name} {
+			if matched, _ := path.Match(pattern, candidate); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// containerName returns the primary name of the container, without the
+// leading slash added by the Docker daemon.
+func containerName(c *types.Container) string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// containerNetwork returns the name of the network the container is reached
+// on, and the IP address of the container on that network. The network given
+// as network mode is preferred; otherwise networks are picked by name, for
+// stable results across refreshes.
+func containerNetwork(c *types.Container) (string, string) {
+	if c.NetworkSettings == nil || len(c.NetworkSettings.Networks) == 0 {
+		return "", ""
+	}
+
+	networks := c.NetworkSettings.Networks
+	mode := c.HostConfig.NetworkMode
+	if mode == "default" {
+		mode = "bridge"
+	}
+
+	if settings, ok := networks[mode]; ok && settings != nil && settings.IPAddress != "" {
+		return mode, settings.IPAddress
+	}
+
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if settings := networks[name]; settings != nil && settings.IPAddress != "" {
+			return name, settings.IPAddress
+		}
+	}
+
+	return "", ""
+}
+
+// uniquePorts drops the duplicate entries that the Docker daemon lists for
+// ports published on several host addresses (e.g. both IPv4 and IPv6),
+// keeping the first IPv4 binding.
+func uniquePorts(ports []types.Port) []types.Port {
+	type portKey struct {
+		port      uint16
+		transport string
+	}
+
+	var unique []types.Port
+	seen := make(map[portKey]int)
+	for _, p := range ports {
+		key := portKey{p.PrivatePort, p.Type}
+		if i, ok := seen[key]; ok {
+			if strings.Contains(unique[i].IP, ":") && !strings.Contains(p.IP, ":") {
+				unique[i] = p
+			}
+			continue
+		}
+
+		seen[key] = len(unique)
+		unique = append(unique, p)
+	}
+
+	return unique
+}
+
+// splitImage splits an image reference into its name and tag. Digests are
+// dropped from the name.
+func splitImage(image string) (string, string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	// A colon before the last slash separates a registry host from its port.
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+
+	return image, ""
+}
+
+func hostBindingIP(ip string) string {
+	// A binding to all interfaces includes localhost, so use that since we
+	// can't actually connect to 0.0.0.0.
+	switch ip {
+	case "", "0.0.0.0":
+		return "127.0.0.1"
+	case "::":
+		return "[::1]"
+	}
+
+	if strings.Contains(ip, ":") {
+		return "[" + ip + "]"
+	}
+
+	return ip
+}
+
+func portTypeToProtocol(t string) observer.Transport {
+	switch t {
+	case "tcp":
+		return observer.ProtocolTCP
+	case "udp":
+		return observer.ProtocolUDP
+	}
+	return observer.ProtocolUnknown
+}
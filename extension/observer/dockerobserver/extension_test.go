@@ -0,0 +1,324 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// fakeDockerAPI serves the container list of the Docker Engine API.
+type fakeDockerAPI struct {
+	mu         sync.Mutex
+	containers []types.Container
+	fail       bool
+}
+
+func (f *fakeDockerAPI) setContainers(containers ...types.Container) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers = containers
+}
+
+func (f *fakeDockerAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasSuffix(r.URL.Path, "/containers/json") || f.fail {
+		http.Error(w, `{"message": "unavailable"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(f.containers)
+}
+
+func startFakeDockerAPI(t *testing.T) (*fakeDockerAPI, string) {
+	api := &fakeDockerAPI{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+
+	return api, "tcp://" + srv.Listener.Addr().String()
+}
+
+func redisContainer() types.Container {
+	c := types.Container{
+		ID:      "5e1f",
+		Names:   []string{"/cache"},
+		Image:   "redis:6.0",
+		Command: "redis-server",
+		Labels:  map[string]string{"app": "cache"},
+		Ports: []types.Port{
+			{IP: "0.0.0.0", PrivatePort: 6379, PublicPort: 16379, Type: "tcp"},
+			{IP: "::", PrivatePort: 6379, PublicPort: 16379, Type: "tcp"},
+		},
+		NetworkSettings: &types.SummaryNetworkSettings{
+			Networks: map[string]*network.EndpointSettings{
+				"bridge": {IPAddress: "172.17.0.2"},
+			},
+		},
+	}
+	c.HostConfig.NetworkMode = "default"
+
+	return c
+}
+
+func newTestConfig(endpoint string) *Config {
+	config := createDefaultConfig().(*Config)
+	config.NameVal = "docker_observer/1"
+	config.Endpoint = endpoint
+	config.RefreshInterval = 50 * time.Millisecond
+
+	return config
+}
+
+type mockNotifier struct {
+	mu           sync.Mutex
+	endpointsMap map[observer.EndpointID]observer.Endpoint
+}
+
+func (m *mockNotifier) OnAdd(added []observer.Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range added {
+		m.endpointsMap[e.ID] = e
+	}
+}
+
+func (m *mockNotifier) OnRemove(removed []observer.Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range removed {
+		delete(m.endpointsMap, e.ID)
+	}
+}
+
+func (m *mockNotifier) OnChange(changed []observer.Endpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range changed {
+		m.endpointsMap[e.ID] = e
+	}
+}
+
+func (m *mockNotifier) endpoint(id observer.EndpointID) (observer.Endpoint, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.endpointsMap[id]
+	return e, ok
+}
+
+func TestDockerObserver(t *testing.T) {
+	api, endpoint := startFakeDockerAPI(t)
+	api.setContainers(redisContainer())
+
+	ext, err := newObserver(zap.NewNop(), newTestConfig(endpoint))
+	require.NoError(t, err)
+	d := ext.(*dockerObserver)
+
+	ctx := context.Background()
+	require.NoError(t, d.Start(ctx, componenttest.NewNopHost()))
+
+	mn := &mockNotifier{endpointsMap: map[observer.EndpointID]observer.Endpoint{}}
+	d.ListAndWatch(mn)
+
+	id := observer.EndpointID("(docker_observer/1)5e1f-6379-TCP")
+	e, ok := mn.endpoint(id)
+	require.True(t, ok, "expected endpoint ID not found. ID: %v", id)
+	assert.Equal(t, observer.Endpoint{
+		ID:     id,
+		Target: "172.17.0.2:6379",
+		Details: observer.Container{
+			Name:        "cache",
+			Image:       "redis",
+			Tag:         "6.0",
+			ContainerID: "5e1f",
+			Command:     "redis-server",
+			Labels:      map[string]string{"app": "cache"},
+			Network:     "bridge",
+			Host:        "172.17.0.2",
+			Port:        6379,
+			HostPort:    16379,
+			Transport:   observer.ProtocolTCP,
+		},
+	}, e)
+
+	changed := redisContainer()
+	changed.Labels = map[string]string{"app": "session-cache"}
+	api.setContainers(changed)
+	require.Eventually(t, func() bool {
+		e, ok := mn.endpoint(id)
+		return ok && e.Details.(observer.Container).Labels["app"] == "session-cache"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	api.setContainers()
+	require.Eventually(t, func() bool {
+		_, ok := mn.endpoint(id)
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, d.Shutdown(ctx))
+}
+
+func TestListEndpointsFailure(t *testing.T) {
+	api, endpoint := startFakeDockerAPI(t)
+	api.setContainers(redisContainer())
+	api.fail = true
+
+	ext, err := newObserver(zap.NewNop(), newTestConfig(endpoint))
+	require.NoError(t, err)
+
+	lister := ext.(*dockerObserver).Endpointslister
+	assert.Empty(t, lister.ListEndpoints())
+}
+
+func TestNewObserverInvalidEndpoint(t *testing.T) {
+	_, err := newObserver(zap.NewNop(), newTestConfig(""))
+	assert.Error(t, err)
+
+	_, err = newObserver(zap.NewNop(), newTestConfig("not a docker endpoint"))
+	assert.Error(t, err)
+}
+
+func TestCollectEndpoints(t *testing.T) {
+	hostNetwork := redisContainer()
+	hostNetwork.Ports = []types.Port{{PrivatePort: 6379, Type: "tcp"}}
+	hostNetwork.HostConfig.NetworkMode = "host"
+	hostNetwork.NetworkSettings.Networks = map[string]*network.EndpointSettings{"host": {}}
+
+	multiNetwork := redisContainer()
+	multiNetwork.HostConfig.NetworkMode = "backend"
+	multiNetwork.NetworkSettings.Networks = map[string]*network.EndpointSettings{
+		"monitoring": {IPAddress: "10.0.1.2"},
+		"backend":    {IPAddress: "10.0.0.2"},
+	}
+
+	unpublished := redisContainer()
+	unpublished.ID = "7a2b"
+	unpublished.Image = "registry.local:5000/redis@sha256:abcd"
+	unpublished.Ports = []types.Port{{PrivatePort: 6379, Type: "tcp"}, {PrivatePort: 8125, Type: "udp"}}
+
+	tests := []struct {
+		name       string
+		config     func(*Config)
+		containers []types.Container
+		want       map[observer.EndpointID]string
+	}{
+		{
+			name:       "container address",
+			containers: []types.Container{redisContainer()},
+			want:       map[observer.EndpointID]string{"(docker_observer)5e1f-6379-TCP": "172.17.0.2:6379"},
+		},
+		{
+			name:       "host bindings",
+			config:     func(config *Config) { config.UseHostBindings = true },
+			containers: []types.Container{redisContainer(), unpublished},
+			want: map[observer.EndpointID]string{
+				"(docker_observer)5e1f-6379-TCP": "127.0.0.1:16379",
+				"(docker_observer)7a2b-6379-TCP": "172.17.0.2:6379",
+				"(docker_observer)7a2b-8125-UDP": "172.17.0.2:8125",
+			},
+		},
+		{
+			name:       "network mode",
+			containers: []types.Container{multiNetwork},
+			want:       map[observer.EndpointID]string{"(docker_observer)5e1f-6379-TCP": "10.0.0.2:6379"},
+		},
+		{
+			name:       "no container address",
+			containers: []types.Container{hostNetwork},
+			want:       map[observer.EndpointID]string{},
+		},
+		{
+			name:       "excluded image",
+			config:     func(config *Config) { config.ExcludedImages = []string{"redis"} },
+			containers: []types.Container{redisContainer()},
+			want:       map[observer.EndpointID]string{},
+		},
+		{
+			name:       "excluded image with tag",
+			config:     func(config *Config) { config.ExcludedImages = []string{"redis:6.*"} },
+			containers: []types.Container{redisContainer(), unpublished},
+			want: map[observer.EndpointID]string{
+				"(docker_observer)7a2b-6379-TCP": "172.17.0.2:6379",
+				"(docker_observer)7a2b-8125-UDP": "172.17.0.2:8125",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			if tt.config != nil {
+				tt.config(config)
+			}
+
+			e := endpointsLister{
+				logger:       zap.NewNop(),
+				observerName: config.Name(),
+				config:       config,
+			}
+
+			got := map[observer.EndpointID]string{}
+			for _, endpoint := range e.collectEndpoints(tt.containers) {
+				got[endpoint.ID] = endpoint.Target
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{image: "redis", name: "redis"},
+		{image: "redis:6.0", name: "redis", tag: "6.0"},
+		{image: "registry.local:5000/redis", name: "registry.local:5000/redis"},
+		{image: "registry.local:5000/redis:6.0", name: "registry.local:5000/redis", tag: "6.0"},
+		{image: "redis@sha256:abcd", name: "redis"},
+	}
+
+	for _, tt := range tests {
+		name, tag := splitImage(tt.image)
+		assert.Equal(t, tt.name, name, tt.image)
+		assert.Equal(t, tt.tag, tag, tt.image)
+	}
+}
+
+func TestHostBindingIP(t *testing.T) {
+	assert.Equal(t, "127.0.0.1", hostBindingIP("0.0.0.0"))
+	assert.Equal(t, "127.0.0.1", hostBindingIP(""))
+	assert.Equal(t, "[::1]", hostBindingIP("::"))
+	assert.Equal(t, "192.168.1.5", hostBindingIP("192.168.1.5"))
+	assert.Equal(t, "[fe80::1]", hostBindingIP("fe80::1"))
+}
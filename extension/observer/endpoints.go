@@ -36,12 +36,57 @@ func (e *Endpoint) String() string {
 type Pod struct {
 	// Name of the pod.
 	Name string
+	// Namespace of the pod.
+	Namespace string
+	// UID is the unique ID of the pod in the cluster.
+	UID string
 	// Labels is a map of user-specified metadata.
 	Labels map[string]string
 	// Annotations is a map of user-specified metadata.
 	Annotations map[string]string
 }
 
+// Service is a discovered k8s service port.
+type Service struct {
+	// Name of the service.
+	Name string
+	// Namespace of the service.
+	Namespace string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// ClusterIP is the cluster-internal IP address of the service.
+	ClusterIP string
+	// ServiceType is the type of the service (e.g. ClusterIP or NodePort).
+	ServiceType string
+	// PortName is the name of the service port.
+	PortName string
+	// Port number of the endpoint.
+	Port uint16
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+}
+
+// Node is a discovered k8s node, targeting its kubelet endpoint.
+type Node struct {
+	// Name of the node.
+	Name string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// Hostname of the node, as reported in its status.
+	Hostname string
+	// InternalIP is the IP address of the node within the cluster.
+	InternalIP string
+	// ExternalIP is the IP address of the node reachable from outside the
+	// cluster, if any.
+	ExternalIP string
+	// KubeletEndpointPort is the port the kubelet of the node listens on.
+	KubeletEndpointPort uint16
+}
+
 // Port is an endpoint that has a target as well as a port.
 type Port struct {
 	// Name is the name of the container port.
@@ -62,6 +107,16 @@ type HostPort struct {
 	Name string
 	// Command used to invoke the process using the Endpoint.
 	Command string
+	// Args is the full argument vector of the process, including the
+	// executable itself at the beginning.
+	Args []string
+	// Executable is the path of the executable of the process.
+	Executable string
+	// Username is the name of the user owning the process.
+	Username string
+	// ContainerID is the ID of the container running the process, derived
+	// from its cgroups. It is empty if the process is not containerized.
+	ContainerID string
 	// Port number of the endpoint.
 	Port uint16
 	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
@@ -70,13 +125,43 @@ type HostPort struct {
 	IsIPv6 bool
 }
 
+// Container is a discovered Docker container port.
+type Container struct {
+	// Name is the primary name of the container.
+	Name string
+	// Image is the name of the container image, without its tag.
+	Image string
+	// Tag is the tag of the container image.
+	Tag string
+	// ContainerID is the ID of the container.
+	ContainerID string
+	// Command used to invoke the process running in the container.
+	Command string
+	// Labels is a map of user-specified metadata on the container.
+	Labels map[string]string
+	// Network is the name of the network the container port is reached on.
+	Network string
+	// Host is the IP address of the container on Network.
+	Host string
+	// Port is the port exposed by the container.
+	Port uint16
+	// HostPort is the port on the host the exposed port is mapped to, or 0 if
+	// the port is not published.
+	HostPort uint16
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+}
+
 type EndpointEnv map[string]interface{}
 
 // EndpointToEnv converts an endpoint into a map suitable for expr evaluation.
 func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 	ruleTypes := map[string]interface{}{
-		"port": false,
-		"pod":  false,
+		"port":      false,
+		"pod":       false,
+		"container": false,
+		"service":   false,
+		"node":      false,
 	}
 
 	switch o := endpoint.Details.(type) {
@@ -86,9 +171,39 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 			"type":        ruleTypes,
 			"endpoint":    endpoint.Target,
 			"name":        o.Name,
+			"namespace":   o.Namespace,
+			"uid":         o.UID,
 			"labels":      o.Labels,
 			"annotations": o.Annotations,
 		}, nil
+	case Service:
+		ruleTypes["service"] = true
+		return map[string]interface{}{
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"namespace":    o.Namespace,
+			"labels":       o.Labels,
+			"annotations":  o.Annotations,
+			"cluster_ip":   o.ClusterIP,
+			"service_type": o.ServiceType,
+			"port_name":    o.PortName,
+			"port":         o.Port,
+			"transport":    o.Transport,
+		}, nil
+	case Node:
+		ruleTypes["node"] = true
+		return map[string]interface{}{
+			"type":                  ruleTypes,
+			"endpoint":              endpoint.Target,
+			"name":                  o.Name,
+			"labels":                o.Labels,
+			"annotations":           o.Annotations,
+			"hostname":              o.Hostname,
+			"internal_ip":           o.InternalIP,
+			"external_ip":           o.ExternalIP,
+			"kubelet_endpoint_port": o.KubeletEndpointPort,
+		}, nil
 	case Port:
 		ruleTypes["port"] = true
 		return map[string]interface{}{
@@ -98,6 +213,8 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 			"port":     o.Port,
 			"pod": map[string]interface{}{
 				"name":        o.Pod.Name,
+				"namespace":   o.Pod.Namespace,
+				"uid":         o.Pod.UID,
 				"labels":      o.Pod.Labels,
 				"annotations": o.Pod.Annotations,
 			},
@@ -106,13 +223,34 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 	case HostPort:
 		ruleTypes["port"] = true
 		return map[string]interface{}{
-			"type":      ruleTypes,
-			"endpoint":  endpoint.Target,
-			"name":      o.Name,
-			"command":   o.Command,
-			"is_ipv6":   o.IsIPv6,
-			"port":      o.Port,
-			"transport": o.Transport,
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"command":      o.Command,
+			"args":         o.Args,
+			"executable":   o.Executable,
+			"username":     o.Username,
+			"container_id": o.ContainerID,
+			"is_ipv6":      o.IsIPv6,
+			"port":         o.Port,
+			"transport":    o.Transport,
+		}, nil
+	case Container:
+		ruleTypes["container"] = true
+		return map[string]interface{}{
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"image":        o.Image,
+			"tag":          o.Tag,
+			"container_id": o.ContainerID,
+			"command":      o.Command,
+			"labels":       o.Labels,
+			"network":      o.Network,
+			"host":         o.Host,
+			"port":         o.Port,
+			"host_port":    o.HostPort,
+			"transport":    o.Transport,
 		}, nil
 
 	default:
@@ -32,7 +32,9 @@ func TestEndpointToEnv(t *testing.T) {
 				ID:     EndpointID("pod_id"),
 				Target: "192.68.73.2",
 				Details: Pod{
-					Name: "pod_name",
+					Name:      "pod_name",
+					Namespace: "pod_namespace",
+					UID:       "pod_uid",
 					Labels: map[string]string{
 						"label_key": "label_val",
 					},
@@ -43,11 +45,16 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": false,
-					"pod":  true,
+					"port":      false,
+					"pod":       true,
+					"container": false,
+					"service":   false,
+					"node":      false,
 				},
-				"endpoint": "192.68.73.2",
-				"name":     "pod_name",
+				"endpoint":  "192.68.73.2",
+				"name":      "pod_name",
+				"namespace": "pod_namespace",
+				"uid":       "pod_uid",
 				"labels": map[string]string{
 					"label_key": "label_val",
 				},
@@ -65,7 +72,9 @@ func TestEndpointToEnv(t *testing.T) {
 				Details: Port{
 					Name: "port_name",
 					Pod: Pod{
-						Name: "pod_name",
+						Name:      "pod_name",
+						Namespace: "pod_namespace",
+						UID:       "pod_uid",
 						Labels: map[string]string{
 							"label_key": "label_val",
 						},
@@ -79,14 +88,19 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
+					"port":      true,
+					"pod":       false,
+					"container": false,
+					"service":   false,
+					"node":      false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "port_name",
 				"port":     uint16(2379),
 				"pod": map[string]interface{}{
-					"name": "pod_name",
+					"name":      "pod_name",
+					"namespace": "pod_namespace",
+					"uid":       "pod_uid",
 					"labels": map[string]string{
 						"label_key": "label_val",
 					},
@@ -104,24 +118,169 @@ func TestEndpointToEnv(t *testing.T) {
 				ID:     EndpointID("port_id"),
 				Target: "127.0.0.1",
 				Details: HostPort{
-					Name:      "process_name",
-					Command:   "./cmd --config config.yaml",
-					Port:      2379,
-					Transport: ProtocolUDP,
-					IsIPv6:    true,
+					Name:        "process_name",
+					Command:     "./cmd --config config.yaml",
+					Args:        []string{"./cmd", "--config", "config.yaml"},
+					Executable:  "/usr/local/bin/cmd",
+					Username:    "otel",
+					ContainerID: "5e1f",
+					Port:        2379,
+					Transport:   ProtocolUDP,
+					IsIPv6:      true,
 				},
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
-				},
-				"endpoint":  "127.0.0.1",
-				"name":      "process_name",
-				"command":   "./cmd --config config.yaml",
-				"is_ipv6":   true,
-				"port":      uint16(2379),
-				"transport": ProtocolUDP,
+					"port":      true,
+					"pod":       false,
+					"container": false,
+					"service":   false,
+					"node":      false,
+				},
+				"endpoint":     "127.0.0.1",
+				"name":         "process_name",
+				"command":      "./cmd --config config.yaml",
+				"args":         []string{"./cmd", "--config", "config.yaml"},
+				"executable":   "/usr/local/bin/cmd",
+				"username":     "otel",
+				"container_id": "5e1f",
+				"is_ipv6":      true,
+				"port":         uint16(2379),
+				"transport":    ProtocolUDP,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Container",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_id"),
+				Target: "172.17.0.2:6379",
+				Details: Container{
+					Name:        "cache",
+					Image:       "redis",
+					Tag:         "6.0",
+					ContainerID: "6f1e2d3c4b5a",
+					Command:     "redis-server",
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+					Network:   "bridge",
+					Host:      "172.17.0.2",
+					Port:      6379,
+					HostPort:  16379,
+					Transport: ProtocolTCP,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"container": true,
+					"service":   false,
+					"node":      false,
+				},
+				"endpoint":     "172.17.0.2:6379",
+				"name":         "cache",
+				"image":        "redis",
+				"tag":          "6.0",
+				"container_id": "6f1e2d3c4b5a",
+				"command":      "redis-server",
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+				"network":   "bridge",
+				"host":      "172.17.0.2",
+				"port":      uint16(6379),
+				"host_port": uint16(16379),
+				"transport": ProtocolTCP,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Service",
+			endpoint: Endpoint{
+				ID:     EndpointID("service_id"),
+				Target: "10.96.0.12:6379",
+				Details: Service{
+					Name:      "redis",
+					Namespace: "default",
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+					Annotations: map[string]string{
+						"annotation_1": "value_1",
+					},
+					ClusterIP:   "10.96.0.12",
+					ServiceType: "ClusterIP",
+					PortName:    "redis",
+					Port:        6379,
+					Transport:   ProtocolTCP,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"container": false,
+					"service":   true,
+					"node":      false,
+				},
+				"endpoint":  "10.96.0.12:6379",
+				"name":      "redis",
+				"namespace": "default",
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+				"annotations": map[string]string{
+					"annotation_1": "value_1",
+				},
+				"cluster_ip":   "10.96.0.12",
+				"service_type": "ClusterIP",
+				"port_name":    "redis",
+				"port":         uint16(6379),
+				"transport":    ProtocolTCP,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Node",
+			endpoint: Endpoint{
+				ID:     EndpointID("node_id"),
+				Target: "10.0.0.5:10250",
+				Details: Node{
+					Name: "node-1",
+					Labels: map[string]string{
+						"label_key": "label_val",
+					},
+					Annotations: map[string]string{
+						"annotation_1": "value_1",
+					},
+					Hostname:            "node-1.internal",
+					InternalIP:          "10.0.0.5",
+					ExternalIP:          "34.1.2.3",
+					KubeletEndpointPort: 10250,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"container": false,
+					"service":   false,
+					"node":      true,
+				},
+				"endpoint": "10.0.0.5:10250",
+				"name":     "node-1",
+				"labels": map[string]string{
+					"label_key": "label_val",
+				},
+				"annotations": map[string]string{
+					"annotation_1": "value_1",
+				},
+				"hostname":              "node-1.internal",
+				"internal_ip":           "10.0.0.5",
+				"external_ip":           "34.1.2.3",
+				"kubelet_endpoint_port": uint16(10250),
 			},
 			wantErr: false,
 		},
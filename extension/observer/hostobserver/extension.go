@@ -15,9 +15,16 @@
 package hostobserver
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/shirou/gopsutil/net"
@@ -167,10 +174,14 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 				ID:     id,
 				Target: cd.target,
 				Details: observer.HostPort{
-					Name:      pd.name,
-					Command:   pd.args,
-					Port:      cd.port,
-					Transport: cd.transport,
+					Name:        pd.name,
+					Command:     pd.args,
+					Args:        pd.argv,
+					Executable:  pd.exe,
+					Username:    pd.username,
+					ContainerID: pd.containerID,
+					Port:        cd.port,
+					Transport:   cd.transport,
 					// TODO: Move this field to observer.Endpoint and
 					// update receiver_creator to filter IPv4/IPv6.
 					IsIPv6: cd.isIPv6,
@@ -221,8 +232,12 @@ func collectConnectionDetails(c *net.ConnectionStat) connectionDetails {
 }
 
 type processDetails struct {
-	name string
-	args string
+	name        string
+	args        string
+	argv        []string
+	exe         string
+	username    string
+	containerID string
 }
 
 func collectProcessDetails(proc *process.Process) (*processDetails, error) {
@@ -236,12 +251,80 @@ func collectProcessDetails(proc *process.Process) (*processDetails, error) {
 		return nil, fmt.Errorf("could not get process args: %v", err)
 	}
 
+	argv, err := proc.CmdlineSlice()
+	if err != nil {
+		return nil, fmt.Errorf("could not get process args: %v", err)
+	}
+
+	// The following details may not be available without additional
+	// privileges, so they are left empty rather than failing.
+	exe, _ := proc.Exe()
+	username, _ := proc.Username()
+
 	return &processDetails{
-		name: name,
-		args: args,
+		name:        name,
+		args:        args,
+		argv:        argv,
+		exe:         exe,
+		username:    username,
+		containerID: readContainerID(proc.Pid),
 	}, nil
 }
 
+// readContainerID returns the ID of the container running the process with
+// the given pid, based on its cgroups. It returns an empty string if the
+// process is not containerized, or if its cgroups cannot be read (e.g. on
+// platforms other than linux).
+func readContainerID(pid int32) string {
+	f, err := os.Open(filepath.Join(hostProc(), strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseContainerID(f)
+}
+
+// hostProc returns the root of the procfs, honoring the HOST_PROC
+// environment variable like gopsutil does.
+func hostProc() string {
+	if proc := os.Getenv("HOST_PROC"); proc != "" {
+		return proc
+	}
+	return "/proc"
+}
+
+var containerIDRe = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// parseContainerID finds a container ID in the contents of a
+// /proc/<pid>/cgroup file. Each line has the form
+// "hierarchy-ID:controller-list:cgroup-path", and container runtimes end
+// the cgroup path with the container ID, possibly wrapped in a systemd scope
+// such as "docker-<id>.scope" or "cri-containerd-<id>.scope".
+func parseContainerID(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		segments := strings.Split(fields[2], "/")
+		for i := len(segments) - 1; i >= 0; i-- {
+			segment := strings.TrimSuffix(segments[i], ".scope")
+			if j := strings.LastIndex(segment, "-"); j >= 0 {
+				segment = segment[j+1:]
+			}
+
+			if containerIDRe.MatchString(segment) {
+				return segment
+			}
+		}
+	}
+
+	return ""
+}
+
 func portTypeToProtocol(t uint32) observer.Transport {
 	switch t {
 	case syscall.SOCK_STREAM:
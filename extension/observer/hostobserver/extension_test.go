@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"os/user"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -115,6 +117,7 @@ func TestHostObserver(t *testing.T) {
 				details, ok := actualEndpoint.Details.(observer.HostPort)
 				assert.True(t, ok, "failed to get Endpoint.Details")
 				assert.Equal(t, filepath.Base(exe), details.Name)
+				assert.Equal(t, os.Args, details.Args)
 				assert.Equal(t, tt.protocol, details.Transport)
 				assert.Equal(t, isIPv6, details.IsIPv6)
 
@@ -432,6 +435,73 @@ func TestCollectConnectionDetails(t *testing.T) {
 	}
 }
 
+func TestCollectProcessDetails(t *testing.T) {
+	proc, err := process.NewProcess(int32(selfPid))
+	require.NoError(t, err)
+
+	pd, err := collectProcessDetails(proc)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Base(exe), pd.name)
+	assert.Equal(t, os.Args, pd.argv)
+	assert.Equal(t, strings.Join(os.Args, " "), pd.args)
+
+	if runtime.GOOS == "linux" {
+		assert.Equal(t, exe, pd.exe)
+
+		self, err := user.Current()
+		require.NoError(t, err)
+		assert.Equal(t, self.Username, pd.username)
+	}
+}
+
+func TestParseContainerID(t *testing.T) {
+	const id = "7be92808767a667f35c8505cbf40d14e931ef6db5b0210329cf193b15ba9d605"
+
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{
+			name:   "not containerized",
+			cgroup: "12:pids:/user.slice/user-1000.slice/session-2.scope\n0::/user.slice/user-1000.slice/session-2.scope\n",
+			want:   "",
+		},
+		{
+			name:   "docker",
+			cgroup: "12:pids:/docker/" + id + "\n11:memory:/docker/" + id + "\n",
+			want:   id,
+		},
+		{
+			name:   "docker with systemd driver",
+			cgroup: "0::/system.slice/docker-" + id + ".scope\n",
+			want:   id,
+		},
+		{
+			name:   "kubernetes",
+			cgroup: "4:cpu,cpuacct:/kubepods/burstable/pod8fd52bb2-d2a1-4e39-a5a5-0d9d6a40b9f5/" + id + "\n",
+			want:   id,
+		},
+		{
+			name:   "containerd",
+			cgroup: "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod8fd52bb2.slice/cri-containerd-" + id + ".scope\n",
+			want:   id,
+		},
+		{
+			name:   "malformed",
+			cgroup: "garbage\n",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseContainerID(strings.NewReader(tt.cgroup)))
+		})
+	}
+}
+
 func TestCollectEndpoints(t *testing.T) {
 	tests := []struct {
 		name        string
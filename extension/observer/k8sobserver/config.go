@@ -36,4 +36,18 @@ type Config struct {
 	//
 	// Then set this value to ${K8S_NODE_NAME} in the configuration.
 	Node string `mapstructure:"node"`
+
+	// ObservePods determines whether to report endpoints for pods and their
+	// container ports. Defaults to true.
+	ObservePods bool `mapstructure:"observe_pods"`
+
+	// ObserveServices determines whether to report endpoints for the ports of
+	// cluster services, targeting their cluster IP. Services are not limited
+	// by Node. Defaults to false.
+	ObserveServices bool `mapstructure:"observe_services"`
+
+	// ObserveNodes determines whether to report endpoints for nodes,
+	// targeting their kubelet. If Node is set, only that node is reported.
+	// Defaults to false.
+	ObserveNodes bool `mapstructure:"observe_nodes"`
 }
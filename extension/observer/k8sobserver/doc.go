@@ -12,5 +12,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package k8sobserver implements a k8s observer extension for monitoring pods.
+// Package k8sobserver implements a k8s observer extension for monitoring pods,
+// services and nodes.
 package k8sobserver
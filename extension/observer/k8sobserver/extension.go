@@ -20,20 +20,27 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
 type k8sObserver struct {
-	logger   *zap.Logger
-	informer cache.SharedInformer
-	stop     chan struct{}
-	config   *Config
+	logger    *zap.Logger
+	informers []cache.SharedInformer // one for each observed kind of resource
+	stop      chan struct{}
+	config    *Config
 }
 
 func (k *k8sObserver) Start(ctx context.Context, host component.Host) error {
-	go k.informer.Run(k.stop)
+	for _, informer := range k.informers {
+		go informer.Run(k.stop)
+	}
 	return nil
 }
 
@@ -46,11 +53,60 @@ var _ (component.ServiceExtension) = (*k8sObserver)(nil)
 
 // ListAndWatch notifies watcher with the current state and sends subsequent state changes.
 func (k *k8sObserver) ListAndWatch(listener observer.Notify) {
-	k.informer.AddEventHandler(&handler{watcher: listener, idNamespace: k.config.Name()})
+	h := &handler{watcher: listener, idNamespace: k.config.Name()}
+	for _, informer := range k.informers {
+		informer.AddEventHandler(h)
+	}
 }
 
 // newObserver creates a new k8s observer extension.
-func newObserver(logger *zap.Logger, config *Config, listWatch cache.ListerWatcher) (component.ServiceExtension, error) {
-	informer := cache.NewSharedInformer(listWatch, &v1.Pod{}, 0)
-	return &k8sObserver{logger: logger, informer: informer, stop: make(chan struct{}), config: config}, nil
+func newObserver(logger *zap.Logger, config *Config, clientset kubernetes.Interface) (component.ServiceExtension, error) {
+	var informers []cache.SharedInformer
+	if config.ObservePods {
+		selector := fields.OneTermEqualSelector("spec.nodeName", config.Node)
+		listWatch := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = selector.String()
+				return clientset.CoreV1().Pods(v1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = selector.String()
+				return clientset.CoreV1().Pods(v1.NamespaceAll).Watch(context.Background(), options)
+			},
+		}
+		informers = append(informers, cache.NewSharedInformer(listWatch, &v1.Pod{}, 0))
+	}
+
+	if config.ObserveServices {
+		listWatch := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return clientset.CoreV1().Services(v1.NamespaceAll).List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return clientset.CoreV1().Services(v1.NamespaceAll).Watch(context.Background(), options)
+			},
+		}
+		informers = append(informers, cache.NewSharedInformer(listWatch, &v1.Service{}, 0))
+	}
+
+	if config.ObserveNodes {
+		selector := fields.Everything()
+		if config.Node != "" {
+			selector = fields.OneTermEqualSelector("metadata.name", config.Node)
+		}
+
+		listWatch := &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.FieldSelector = selector.String()
+				return clientset.CoreV1().Nodes().List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = selector.String()
+				return clientset.CoreV1().Nodes().Watch(context.Background(), options)
+			},
+		}
+		informers = append(informers, cache.NewSharedInformer(listWatch, &v1.Node{}, 0))
+	}
+
+	return &k8sObserver{logger: logger, informers: informers, stop: make(chan struct{}), config: config}, nil
 }
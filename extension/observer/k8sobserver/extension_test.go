@@ -22,28 +22,30 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.uber.org/zap"
-	framework "k8s.io/client-go/tools/cache/testing"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
 func TestNewExtension(t *testing.T) {
-	listWatch := framework.NewFakeControllerSource()
+	clientset := fake.NewSimpleClientset()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), clientset)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
 
 func TestExtensionObserve(t *testing.T) {
-	listWatch := framework.NewFakeControllerSource()
+	clientset := fake.NewSimpleClientset()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), clientset)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 	obs := ext.(*k8sObserver)
 
-	listWatch.Add(pod1V1)
+	_, err = clientset.CoreV1().Pods(pod1V1.Namespace).Create(context.Background(), pod1V1, metav1.CreateOptions{})
+	require.NoError(t, err)
 
 	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
 
@@ -58,14 +60,23 @@ func TestExtensionObserve(t *testing.T) {
 		ID:     "k8s_observer/pod1-UID",
 		Target: "1.2.3.4",
 		Details: observer.Pod{
-			Name: "pod1",
+			Name:      "pod1",
+			Namespace: "default",
+			UID:       "pod1-UID",
 			Labels: map[string]string{
 				"env": "prod",
 			},
 		},
 	}, sink.added[0])
 
-	listWatch.Delete(pod1V2)
+	_, err = clientset.CoreV1().Pods(pod1V2.Namespace).Update(context.Background(), pod1V2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.changed) == 1
+	})
+
+	require.NoError(t, clientset.CoreV1().Pods(pod1V2.Namespace).Delete(context.Background(), pod1V2.Name, metav1.DeleteOptions{}))
 
 	assertSink(t, sink, func() bool {
 		return len(sink.removed) == 1
@@ -75,7 +86,9 @@ func TestExtensionObserve(t *testing.T) {
 		ID:     "k8s_observer/pod1-UID",
 		Target: "1.2.3.4",
 		Details: observer.Pod{
-			Name: "pod1",
+			Name:      "pod1",
+			Namespace: "default",
+			UID:       "pod1-UID",
 			Labels: map[string]string{
 				"env":         "prod",
 				"pod-version": "2",
@@ -85,3 +98,56 @@ func TestExtensionObserve(t *testing.T) {
 
 	require.NoError(t, ext.Shutdown(context.Background()))
 }
+
+func TestExtensionObserveServicesAndNodes(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	factory := &Factory{}
+	config := factory.CreateDefaultConfig().(*Config)
+	config.ObservePods = false
+	config.ObserveServices = true
+	config.ObserveNodes = true
+	ext, err := newObserver(zap.NewNop(), config, clientset)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	obs := ext.(*k8sObserver)
+	require.Len(t, obs.informers, 2)
+
+	_, err = clientset.CoreV1().Services(serviceWithPorts.Namespace).Create(context.Background(), serviceWithPorts, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = clientset.CoreV1().Nodes().Create(context.Background(), node1, metav1.CreateOptions{})
+	require.NoError(t, err)
+	// Pods are not observed.
+	_, err = clientset.CoreV1().Pods(pod1V1.Namespace).Create(context.Background(), pod1V1, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.added) == 3
+	})
+
+	var ids []observer.EndpointID
+	sink.Lock()
+	for _, e := range sink.added {
+		ids = append(ids, e.ID)
+	}
+	sink.Unlock()
+	assert.ElementsMatch(t, []observer.EndpointID{
+		"k8s_observer/redis-UID/redis(6379)",
+		"k8s_observer/redis-UID/sentinel(26379)",
+		"k8s_observer/node1-UID",
+	}, ids)
+
+	require.NoError(t, clientset.CoreV1().Nodes().Delete(context.Background(), node1.Name, metav1.DeleteOptions{}))
+
+	assertSink(t, sink, func() bool {
+		return len(sink.removed) == 1
+	})
+	assert.Equal(t, observer.EndpointID("k8s_observer/node1-UID"), sink.removed[0].ID)
+	assert.IsType(t, observer.Node{}, sink.removed[0].Details)
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
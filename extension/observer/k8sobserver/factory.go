@@ -16,14 +16,12 @@ package k8sobserver
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
-	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/k8sconfig"
 )
@@ -54,7 +52,8 @@ func (f *Factory) CreateDefaultConfig() configmodels.Extension {
 			TypeVal: typeStr,
 			NameVal: string(typeStr),
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods: true,
 	}
 }
 
@@ -65,17 +64,16 @@ func (f *Factory) CreateExtension(
 	cfg configmodels.Extension,
 ) (component.ServiceExtension, error) {
 	config := cfg.(*Config)
+	if !config.ObservePods && !config.ObserveServices && !config.ObserveNodes {
+		return nil, errors.New("at least one of \"observe_pods\", \"observe_services\" or \"observe_nodes\" must be enabled")
+	}
 
 	clientset, err := f.createK8sClientset(config.APIConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	listWatch := cache.NewListWatchFromClient(
-		clientset.CoreV1().RESTClient(), "pods", v1.NamespaceAll,
-		fields.OneTermEqualSelector("spec.nodeName", config.Node))
-
-	return newObserver(params.Logger, config, listWatch)
+	return newObserver(params.Logger, config, clientset)
 }
 
 // NewFactory should be called to create a factory with default values.
@@ -47,7 +47,8 @@ func TestFactory_CreateDefaultConfig(t *testing.T) {
 			TypeVal: typeStr,
 			NameVal: string(typeStr),
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods: true,
 	},
 		cfg)
 
@@ -66,6 +67,16 @@ func TestFactory_CreateExtension(t *testing.T) {
 	require.NotNil(t, ext)
 }
 
+func TestFactory_CreateExtensionNothingObserved(t *testing.T) {
+	factory := Factory{createK8sClientset: nilClient}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ObservePods = false
+
+	ext, err := factory.CreateExtension(context.Background(), component.ExtensionCreateParams{Logger: zap.NewNop()}, cfg)
+	require.Error(t, err)
+	require.Nil(t, ext)
+}
+
 func TestNewFactory(t *testing.T) {
 	f := NewFactory()
 	require.IsType(t, f, &Factory{})
@@ -32,13 +32,25 @@ type handler struct {
 	watcher observer.Notify
 }
 
-// OnAdd is called in response to a pod being added.
+// OnAdd is called in response to a pod, service or node being added.
 func (h *handler) OnAdd(obj interface{}) {
-	pod, ok := obj.(*v1.Pod)
-	if !ok {
-		return
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnAdd(endpoints)
 	}
-	h.watcher.OnAdd(h.convertPodToEndpoints(pod))
+}
+
+// convertToEndpoints converts a pod, service or node into a slice of
+// endpoints. Other objects yield no endpoints.
+func (h *handler) convertToEndpoints(obj interface{}) []observer.Endpoint {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		return h.convertPodToEndpoints(o)
+	case *v1.Service:
+		return h.convertServiceToEndpoints(o)
+	case *v1.Node:
+		return h.convertNodeToEndpoints(o)
+	}
+	return nil
 }
 
 // convertPodToEndpoints converts a pod instance into a slice of endpoints. The endpoints
@@ -52,6 +64,8 @@ func (h *handler) convertPodToEndpoints(pod *v1.Pod) []observer.Endpoint {
 		Annotations: pod.Annotations,
 		Labels:      pod.Labels,
 		Name:        pod.Name,
+		Namespace:   pod.Namespace,
+		UID:         string(pod.UID),
 	}
 
 	endpoints := []observer.Endpoint{{
@@ -97,6 +111,85 @@ func (h *handler) convertPodToEndpoints(pod *v1.Pod) []observer.Endpoint {
 	return endpoints
 }
 
+// convertServiceToEndpoints converts a service into an endpoint for each of
+// its ports, targeting the cluster IP of the service. Headless and
+// ExternalName services have no cluster IP, and so yield no endpoints.
+func (h *handler) convertServiceToEndpoints(svc *v1.Service) []observer.Endpoint {
+	clusterIP := svc.Spec.ClusterIP
+	if clusterIP == "" || clusterIP == v1.ClusterIPNone {
+		return nil
+	}
+
+	serviceID := observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, svc.UID))
+
+	endpoints := make([]observer.Endpoint, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		endpointID := observer.EndpointID(
+			fmt.Sprintf(
+				"%s/%s(%d)", serviceID, port.Name, port.Port,
+			),
+		)
+		endpoints = append(endpoints, observer.Endpoint{
+			ID:     endpointID,
+			Target: fmt.Sprintf("%s:%d", clusterIP, port.Port),
+			Details: observer.Service{
+				Name:        svc.Name,
+				Namespace:   svc.Namespace,
+				Labels:      svc.Labels,
+				Annotations: svc.Annotations,
+				ClusterIP:   clusterIP,
+				ServiceType: string(svc.Spec.Type),
+				PortName:    port.Name,
+				Port:        uint16(port.Port),
+				Transport:   getTransport(port.Protocol),
+			},
+		})
+	}
+
+	return endpoints
+}
+
+// convertNodeToEndpoints converts a node into an endpoint targeting its
+// kubelet, preferably on the internal IP of the node.
+func (h *handler) convertNodeToEndpoints(node *v1.Node) []observer.Endpoint {
+	details := observer.Node{
+		Name:                node.Name,
+		Labels:              node.Labels,
+		Annotations:         node.Annotations,
+		KubeletEndpointPort: uint16(node.Status.DaemonEndpoints.KubeletEndpoint.Port),
+	}
+
+	for _, address := range node.Status.Addresses {
+		switch address.Type {
+		case v1.NodeHostName:
+			details.Hostname = address.Address
+		case v1.NodeInternalIP:
+			details.InternalIP = address.Address
+		case v1.NodeExternalIP:
+			details.ExternalIP = address.Address
+		}
+	}
+
+	host := details.InternalIP
+	if host == "" {
+		host = details.Hostname
+	}
+	if host == "" {
+		host = node.Name
+	}
+
+	target := host
+	if details.KubeletEndpointPort != 0 {
+		target = fmt.Sprintf("%s:%d", host, details.KubeletEndpointPort)
+	}
+
+	return []observer.Endpoint{{
+		ID:      observer.EndpointID(fmt.Sprintf("%s/%s", h.idNamespace, node.UID)),
+		Target:  target,
+		Details: details,
+	}}
+}
+
 func getTransport(protocol v1.Protocol) observer.Transport {
 	switch protocol {
 	case v1.ProtocolTCP:
@@ -107,32 +200,23 @@ func getTransport(protocol v1.Protocol) observer.Transport {
 	return observer.ProtocolUnknown
 }
 
-// OnUpdate is called in response to an existing pod changing.
+// OnUpdate is called in response to an existing pod, service or node changing.
 func (h *handler) OnUpdate(oldObj, newObj interface{}) {
-	oldPod, ok := oldObj.(*v1.Pod)
-	if !ok {
-		return
-	}
-	newPod, ok := newObj.(*v1.Pod)
-	if !ok {
-		return
-	}
-
 	oldEndpoints := map[observer.EndpointID]observer.Endpoint{}
 	newEndpoints := map[observer.EndpointID]observer.Endpoint{}
 
-	// Convert pods to endpoints and map by ID for easier lookup.
-	for _, e := range h.convertPodToEndpoints(oldPod) {
+	// Convert objects to endpoints and map by ID for easier lookup.
+	for _, e := range h.convertToEndpoints(oldObj) {
 		oldEndpoints[e.ID] = e
 	}
-	for _, e := range h.convertPodToEndpoints(newPod) {
+	for _, e := range h.convertToEndpoints(newObj) {
 		newEndpoints[e.ID] = e
 	}
 
 	var removedEndpoints, updatedEndpoints, addedEndpoints []observer.Endpoint
 
-	// Find endpoints that are present in oldPod and newPod and see if they've
-	// changed. Otherwise if it wasn't in oldPod it's a new endpoint.
+	// Find endpoints that are present in oldObj and newObj and see if they've
+	// changed. Otherwise if it wasn't in oldObj it's a new endpoint.
 	for _, e := range newEndpoints {
 		if existing, ok := oldEndpoints[e.ID]; ok {
 			if !reflect.DeepEqual(existing, e) {
@@ -143,7 +227,7 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 		}
 	}
 
-	// If an endpoint is present in the oldPod but not in the newPod then
+	// If an endpoint is present in the oldObj but not in the newObj then
 	// send as removed.
 	for _, e := range oldEndpoints {
 		if _, ok := newEndpoints[e.ID]; !ok {
@@ -169,19 +253,18 @@ func (h *handler) OnUpdate(oldObj, newObj interface{}) {
 	// they are all cleaned up.
 }
 
-// OnDelete is called in response to a pod being deleted.
+// OnDelete is called in response to a pod, service or node being deleted.
 func (h *handler) OnDelete(obj interface{}) {
-	var pod *v1.Pod
 	switch o := obj.(type) {
 	case *cache.DeletedFinalStateUnknown:
-		// Assuming we never saw the pod state where new endpoints would have been created
+		// Assuming we never saw the object state where new endpoints would have been created
 		// to begin with it seems that we can't leak endpoints here.
-		pod = o.Obj.(*v1.Pod)
-	case *v1.Pod:
-		pod = o
+		obj = o.Obj
+	case cache.DeletedFinalStateUnknown:
+		obj = o.Obj
 	}
-	if pod == nil {
-		return
+
+	if endpoints := h.convertToEndpoints(obj); len(endpoints) > 0 {
+		h.watcher.OnRemove(endpoints)
 	}
-	h.watcher.OnRemove(h.convertPodToEndpoints(pod))
 }
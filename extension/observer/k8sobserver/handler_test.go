@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -34,8 +35,10 @@ func TestEndpointsAdded(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod"},
+				Name:      "pod-2",
+				Namespace: "default",
+				UID:       "pod-2-UID",
+				Labels:    map[string]string{"env": "prod"},
 			},
 		}, {
 			ID:     "test-1/pod-2-UID/https(443)",
@@ -43,8 +46,10 @@ func TestEndpointsAdded(t *testing.T) {
 			Details: observer.Port{
 				Name: "https",
 				Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod"},
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod"},
 				},
 				Port:      443,
 				Transport: observer.ProtocolTCP,
@@ -66,8 +71,10 @@ func TestEndpointsRemoved(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod"},
+				Name:      "pod-2",
+				Namespace: "default",
+				UID:       "pod-2-UID",
+				Labels:    map[string]string{"env": "prod"},
 			},
 		}, {
 			ID:     "test-1/pod-2-UID/https(443)",
@@ -75,8 +82,10 @@ func TestEndpointsRemoved(t *testing.T) {
 			Details: observer.Port{
 				Name: "https",
 				Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod"},
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod"},
 				},
 				Port:      443,
 				Transport: observer.ProtocolTCP,
@@ -121,16 +130,115 @@ func TestEndpointsChanged(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod", "updated-label": "true"}}},
+				Name:      "pod-2",
+				Namespace: "default",
+				UID:       "pod-2-UID",
+				Labels:    map[string]string{"env": "prod", "updated-label": "true"}}},
 		{
 			ID:     "test-1/pod-2-UID/https(443)",
 			Target: "1.2.3.4:443",
 			Details: observer.Port{
 				Name: "https", Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod", "updated-label": "true"}},
+					Name:      "pod-2",
+					Namespace: "default",
+					UID:       "pod-2-UID",
+					Labels:    map[string]string{"env": "prod", "updated-label": "true"}},
 				Port:      443,
 				Transport: observer.ProtocolTCP}},
 	}, sink.changed)
 }
+
+func TestServiceEndpointsAdded(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(serviceWithPorts)
+	details := observer.Service{
+		Name:        "redis",
+		Namespace:   "default",
+		Labels:      map[string]string{"app": "redis"},
+		ClusterIP:   "10.96.0.12",
+		ServiceType: "ClusterIP",
+		Transport:   observer.ProtocolTCP,
+	}
+	redis, sentinel := details, details
+	redis.PortName, redis.Port = "redis", 6379
+	sentinel.PortName, sentinel.Port = "sentinel", 26379
+	assert.ElementsMatch(t, []observer.Endpoint{
+		{
+			ID:      "test-1/redis-UID/redis(6379)",
+			Target:  "10.96.0.12:6379",
+			Details: redis,
+		}, {
+			ID:      "test-1/redis-UID/sentinel(26379)",
+			Target:  "10.96.0.12:26379",
+			Details: sentinel,
+		}}, sink.added)
+	assert.Nil(t, sink.removed)
+	assert.Nil(t, sink.changed)
+
+	// Headless services cannot be targeted.
+	sink = endpointSink{}
+	h.OnAdd(headlessService)
+	assert.Nil(t, sink.added)
+}
+
+func TestServiceEndpointsChanged(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+
+	// One port removed.
+	updatedService := serviceWithPorts.DeepCopy()
+	updatedService.Spec.Ports = updatedService.Spec.Ports[:1]
+	h.OnUpdate(serviceWithPorts, updatedService)
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.changed)
+	assert.Len(t, sink.removed, 1)
+	assert.Equal(t, observer.EndpointID("test-1/redis-UID/sentinel(26379)"), sink.removed[0].ID)
+}
+
+func TestNodeEndpointsAdded(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(node1)
+	assert.Equal(t, []observer.Endpoint{
+		{
+			ID:     "test-1/node1-UID",
+			Target: "10.0.0.5:10250",
+			Details: observer.Node{
+				Name:                "node1",
+				Labels:              map[string]string{"zone": "west-1a"},
+				Hostname:            "node1.internal",
+				InternalIP:          "10.0.0.5",
+				ExternalIP:          "34.1.2.3",
+				KubeletEndpointPort: 10250,
+			},
+		}}, sink.added)
+
+	// Without an internal IP, the hostname is targeted.
+	sink = endpointSink{}
+	nodeWithoutIP := node1.DeepCopy()
+	nodeWithoutIP.Status.Addresses = nodeWithoutIP.Status.Addresses[:1]
+	h.OnAdd(nodeWithoutIP)
+	assert.Equal(t, "node1.internal:10250", sink.added[0].Target)
+}
+
+func TestNodeEndpointsRemovedFinalStateUnknown(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: "node1", Obj: node1})
+	assert.Len(t, sink.removed, 1)
+	assert.Equal(t, observer.EndpointID("test-1/node1-UID"), sink.removed[0].ID)
+	assert.Nil(t, sink.added)
+}
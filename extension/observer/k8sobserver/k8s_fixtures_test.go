@@ -110,3 +110,50 @@ var podWithNamedPorts = func() *v1.Pod {
 	}
 	return pod
 }()
+
+var serviceWithPorts = &v1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "redis",
+		UID:       types.UID("redis-UID"),
+		Labels: map[string]string{
+			"app": "redis",
+		},
+	},
+	Spec: v1.ServiceSpec{
+		Type:      v1.ServiceTypeClusterIP,
+		ClusterIP: "10.96.0.12",
+		Ports: []v1.ServicePort{
+			{Name: "redis", Port: 6379, Protocol: v1.ProtocolTCP},
+			{Name: "sentinel", Port: 26379, Protocol: v1.ProtocolTCP},
+		},
+	},
+}
+
+var headlessService = func() *v1.Service {
+	svc := serviceWithPorts.DeepCopy()
+	svc.Name = "redis-headless"
+	svc.UID = types.UID("redis-headless-UID")
+	svc.Spec.ClusterIP = v1.ClusterIPNone
+	return svc
+}()
+
+var node1 = &v1.Node{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "node1",
+		UID:  types.UID("node1-UID"),
+		Labels: map[string]string{
+			"zone": "west-1a",
+		},
+	},
+	Status: v1.NodeStatus{
+		Addresses: []v1.NodeAddress{
+			{Type: v1.NodeHostName, Address: "node1.internal"},
+			{Type: v1.NodeInternalIP, Address: "10.0.0.5"},
+			{Type: v1.NodeExternalIP, Address: "34.1.2.3"},
+		},
+		DaemonEndpoints: v1.NodeDaemonEndpoints{
+			KubeletEndpoint: v1.DaemonEndpoint{Port: 10250},
+		},
+	},
+}
@@ -40,4 +40,11 @@ const (
 
 	// AWSXRayTracedAttribute is the `traced` field in an X-Ray subsegment
 	AWSXRayTracedAttribute = "aws.xray.traced"
+
+	// AWSXRayRetriesAttribute is the `retries` field in the `aws` object of an X-Ray segment
+	AWSXRayRetriesAttribute = "aws.xray.retries"
+
+	// AWSXRayMetadataAttributePrefix prefixes the `<namespace>.<key>` entries of the
+	// `metadata` field in an X-Ray segment
+	AWSXRayMetadataAttributePrefix = "aws.xray.metadata."
 )
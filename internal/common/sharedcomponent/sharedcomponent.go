@@ -0,0 +1,66 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sharedcomponent lets a factory share one component between the
+// pipelines of different data types that use the same configuration.
+package sharedcomponent
+
+import "sync"
+
+// Components maps configurations to the components created for them. The
+// factory is asked for a component once per data type, e.g. by
+// CreateMetricsReceiver() and CreateLogsReceiver(), but components such as
+// receivers listening on a port must not be created more than once per
+// configuration. Components is safe for concurrent use.
+type Components struct {
+	mu    sync.Mutex
+	comps map[interface{}]interface{}
+}
+
+// NewComponents returns an empty Components.
+func NewComponents() *Components {
+	return &Components{comps: map[interface{}]interface{}{}}
+}
+
+// GetOrAdd returns the component created for key, calling create to create it
+// if there is none. Nothing is added if create fails.
+func (c *Components) GetOrAdd(key interface{}, create func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if comp, ok := c.comps[key]; ok {
+		return comp, nil
+	}
+
+	comp, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.comps[key] = comp
+	return comp, nil
+}
+
+// Remove forgets comp, so that the next call to GetOrAdd for its configuration
+// creates a new component. Components call it when they are shut down, since
+// they cannot be started again.
+func (c *Components) Remove(comp interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, existing := range c.comps {
+		if existing == comp {
+			delete(c.comps, key)
+		}
+	}
+}
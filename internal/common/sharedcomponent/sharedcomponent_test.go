@@ -0,0 +1,109 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharedcomponent
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type component struct {
+	name string
+}
+
+func TestGetOrAdd(t *testing.T) {
+	comps := NewComponents()
+	key := &struct{}{}
+	created := 0
+	create := func() (interface{}, error) {
+		created++
+		return &component{name: "first"}, nil
+	}
+
+	first, err := comps.GetOrAdd(key, create)
+	require.NoError(t, err)
+	second, err := comps.GetOrAdd(key, create)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, created)
+
+	other, err := comps.GetOrAdd(&struct{ x int }{}, create)
+	require.NoError(t, err)
+	assert.NotSame(t, first, other)
+	assert.Equal(t, 2, created)
+}
+
+func TestGetOrAddError(t *testing.T) {
+	comps := NewComponents()
+	key := &struct{}{}
+
+	comp, err := comps.GetOrAdd(key, func() (interface{}, error) {
+		return nil, errors.New("invalid config")
+	})
+	assert.EqualError(t, err, "invalid config")
+	assert.Nil(t, comp)
+
+	comp, err = comps.GetOrAdd(key, func() (interface{}, error) {
+		return &component{}, nil
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, comp)
+}
+
+func TestRemove(t *testing.T) {
+	comps := NewComponents()
+	key := &struct{}{}
+
+	first, err := comps.GetOrAdd(key, func() (interface{}, error) {
+		return &component{name: "first"}, nil
+	})
+	require.NoError(t, err)
+
+	comps.Remove(first)
+	// Removing twice, as when a component is shut down twice, is a no-op.
+	comps.Remove(first)
+
+	second, err := comps.GetOrAdd(key, func() (interface{}, error) {
+		return &component{name: "second"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "second", second.(*component).name)
+}
+
+func TestConcurrentGetOrAdd(t *testing.T) {
+	comps := NewComponents()
+	key := &struct{}{}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = comps.GetOrAdd(key, func() (interface{}, error) {
+				return &component{}, nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, comp := range results {
+		assert.Same(t, results[0], comp)
+	}
+}
@@ -15,8 +15,10 @@
 package splunk
 
 const (
-	SFxAccessTokenHeader = "X-Sf-Token"
-	SFxAccessTokenLabel  = "com.splunk.signalfx.access_token"
+	SFxAccessTokenHeader  = "X-Sf-Token"
+	SFxAccessTokenLabel   = "com.splunk.signalfx.access_token"
+	SFxEventCategoryKey   = "com.splunk.signalfx.event_category"
+	SFxEventPropertiesKey = "com.splunk.signalfx.event_properties"
 )
 
 type AccessTokenPassthroughConfig struct {
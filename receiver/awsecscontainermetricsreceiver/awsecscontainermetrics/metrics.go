@@ -0,0 +1,324 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// Prefixes of task and container metric names.
+const (
+	taskPrefix      = "ecs.task."
+	containerPrefix = "container."
+)
+
+// Resource attributes set on task and container metrics.
+const (
+	attributeECSCluster      = "aws.ecs.cluster.name"
+	attributeECSTaskARN      = "aws.ecs.task.arn"
+	attributeECSTaskFamily   = "aws.ecs.task.family"
+	attributeECSTaskRevision = "aws.ecs.task.revision"
+	attributeECSLaunchType   = "aws.ecs.launchtype"
+	attributeECSDockerName   = "aws.ecs.docker.name"
+
+	resourceTypeTask      = "aws.ecs.task"
+	resourceTypeContainer = "aws.ecs.container"
+)
+
+const (
+	bytesInMiB       = 1024 * 1024
+	cpuUnitsInVCPU   = 1024
+	blkioReadOp      = "Read"
+	blkioWriteOp     = "Write"
+	percentageFactor = 100
+)
+
+// usage holds the values of the metrics of a container, or of a task as the sum of
+// the values of its containers.
+type usage struct {
+	memoryUsage    uint64
+	memoryMaxUsage uint64
+	memoryLimit    uint64
+	memoryReserved uint64
+
+	cpuTotal    uint64
+	cpuKernel   uint64
+	cpuUser     uint64
+	cpuUtilized float64
+	cpuReserved float64
+
+	rxBytes   uint64
+	rxPackets uint64
+	rxErrors  uint64
+	rxDropped uint64
+	txBytes   uint64
+	txPackets uint64
+	txErrors  uint64
+	txDropped uint64
+
+	storageRead  uint64
+	storageWrite uint64
+
+	// restarts is nil when the restart count is not reported.
+	restarts *uint64
+}
+
+// MetricsData converts the metadata and container stats of an ECS task into metrics
+// collected at now. It returns the metrics of the task followed by the metrics of each
+// container that has stats, each with their own resource.
+func MetricsData(metadata TaskMetadata, stats map[string]*ContainerStats, now time.Time) []consumerdata.MetricsData {
+	ts := timestampProto(now)
+
+	var task usage
+	var taskStart time.Time
+	var containerMDs []consumerdata.MetricsData
+
+	for _, container := range metadata.Containers {
+		containerStats := stats[container.DockerID]
+		if containerStats == nil {
+			continue
+		}
+
+		cu := containerUsage(container, containerStats)
+		task.add(cu)
+
+		start := containerStartTime(container)
+		if !start.IsZero() && (taskStart.IsZero() || start.Before(taskStart)) {
+			taskStart = start
+		}
+
+		containerMDs = append(containerMDs, consumerdata.MetricsData{
+			Resource: containerResource(metadata, container),
+			Metrics:  cu.metrics(containerPrefix, timestampProto(start), ts),
+		})
+	}
+
+	// Task limits take precedence over the sum of the limits of its containers.
+	if metadata.Limits.Memory != nil {
+		task.memoryReserved = *metadata.Limits.Memory
+	}
+	if metadata.Limits.CPU != nil {
+		task.cpuReserved = *metadata.Limits.CPU
+	}
+	// The memory limit of containers without a hard limit is the memory of the host, so
+	// their sum is meaningless for the task.
+	task.memoryLimit = 0
+
+	taskMD := consumerdata.MetricsData{
+		Resource: taskResource(metadata),
+		Metrics:  task.metrics(taskPrefix, timestampProto(taskStart), ts),
+	}
+	return append([]consumerdata.MetricsData{taskMD}, containerMDs...)
+}
+
+// containerUsage computes the metric values of a container from its metadata and stats.
+func containerUsage(container ContainerMetadata, stats *ContainerStats) usage {
+	var u usage
+
+	if container.Limits.Memory != nil {
+		u.memoryReserved = *container.Limits.Memory
+	}
+	if container.Limits.CPU != nil {
+		u.cpuReserved = *container.Limits.CPU / cpuUnitsInVCPU
+	}
+	if container.RestartCount != nil && *container.RestartCount >= 0 {
+		restarts := uint64(*container.RestartCount)
+		u.restarts = &restarts
+	}
+
+	if m := stats.Memory; m != nil {
+		u.memoryUsage = value(m.Usage)
+		u.memoryMaxUsage = value(m.MaxUsage)
+		u.memoryLimit = value(m.Limit)
+	}
+
+	if cpu := stats.CPU; cpu != nil && cpu.CPUUsage != nil {
+		u.cpuTotal = value(cpu.CPUUsage.TotalUsage)
+		u.cpuKernel = value(cpu.CPUUsage.UsageInKernelmode)
+		u.cpuUser = value(cpu.CPUUsage.UsageInUserMode)
+		u.cpuUtilized = cpuUtilized(cpu, stats.PreviousCPU)
+	}
+
+	for _, n := range stats.Network {
+		u.rxBytes += value(n.RxBytes)
+		u.rxPackets += value(n.RxPackets)
+		u.rxErrors += value(n.RxErrors)
+		u.rxDropped += value(n.RxDropped)
+		u.txBytes += value(n.TxBytes)
+		u.txPackets += value(n.TxPackets)
+		u.txErrors += value(n.TxErrors)
+		u.txDropped += value(n.TxDropped)
+	}
+
+	if stats.Disk != nil {
+		for _, io := range stats.Disk.IoServiceBytesRecursives {
+			switch io.Op {
+			case blkioReadOp:
+				u.storageRead += value(io.Value)
+			case blkioWriteOp:
+				u.storageWrite += value(io.Value)
+			}
+		}
+	}
+
+	return u
+}
+
+// cpuUtilized returns the percentage of the CPU of the host used by a container since
+// the previous stats, where 100% is a single fully used CPU.
+func cpuUtilized(cpu, previous *CPUStats) float64 {
+	if previous == nil || previous.CPUUsage == nil {
+		return 0
+	}
+
+	total, previousTotal := value(cpu.CPUUsage.TotalUsage), value(previous.CPUUsage.TotalUsage)
+	system, previousSystem := value(cpu.SystemCPUUsage), value(previous.SystemCPUUsage)
+	if total <= previousTotal || system <= previousSystem {
+		return 0
+	}
+
+	onlineCpus := value(cpu.OnlineCpus)
+	if onlineCpus == 0 {
+		onlineCpus = uint64(len(cpu.CPUUsage.PerCPUUsage))
+	}
+
+	cpuDelta := float64(total - previousTotal)
+	systemDelta := float64(system - previousSystem)
+	return cpuDelta / systemDelta * float64(onlineCpus) * percentageFactor
+}
+
+// add sums the values of a container into u.
+func (u *usage) add(c usage) {
+	u.memoryUsage += c.memoryUsage
+	u.memoryMaxUsage += c.memoryMaxUsage
+	u.memoryLimit += c.memoryLimit
+	u.memoryReserved += c.memoryReserved
+	u.cpuTotal += c.cpuTotal
+	u.cpuKernel += c.cpuKernel
+	u.cpuUser += c.cpuUser
+	u.cpuUtilized += c.cpuUtilized
+	u.cpuReserved += c.cpuReserved
+	u.rxBytes += c.rxBytes
+	u.rxPackets += c.rxPackets
+	u.rxErrors += c.rxErrors
+	u.rxDropped += c.rxDropped
+	u.txBytes += c.txBytes
+	u.txPackets += c.txPackets
+	u.txErrors += c.txErrors
+	u.txDropped += c.txDropped
+	u.storageRead += c.storageRead
+	u.storageWrite += c.storageWrite
+	if c.restarts != nil {
+		restarts := *c.restarts
+		if u.restarts != nil {
+			restarts += *u.restarts
+		}
+		u.restarts = &restarts
+	}
+}
+
+// metrics returns the metrics of u with names starting with prefix.
+func (u *usage) metrics(prefix string, start, ts *timestamp.Timestamp) []*metricspb.Metric {
+	metrics := []*metricspb.Metric{
+		intGauge(prefix+"memory.usage", unitBytes, u.memoryUsage, ts),
+		intGauge(prefix+"memory.usage.max", unitBytes, u.memoryMaxUsage, ts),
+		intGauge(prefix+"memory.reserved", unitMegabytes, u.memoryReserved, ts),
+
+		intCumulative(prefix+"cpu.usage.total", unitNanoseconds, u.cpuTotal, start, ts),
+		intCumulative(prefix+"cpu.usage.kernelmode", unitNanoseconds, u.cpuKernel, start, ts),
+		intCumulative(prefix+"cpu.usage.usermode", unitNanoseconds, u.cpuUser, start, ts),
+		doubleGauge(prefix+"cpu.utilized", unitPercent, u.cpuUtilized, ts),
+		doubleGauge(prefix+"cpu.reserved", unitVCPU, u.cpuReserved, ts),
+
+		intCumulative(prefix+"network.io.usage.rx_bytes", unitBytes, u.rxBytes, start, ts),
+		intCumulative(prefix+"network.io.usage.rx_packets", unitCount, u.rxPackets, start, ts),
+		intCumulative(prefix+"network.io.usage.rx_errors", unitCount, u.rxErrors, start, ts),
+		intCumulative(prefix+"network.io.usage.rx_dropped", unitCount, u.rxDropped, start, ts),
+		intCumulative(prefix+"network.io.usage.tx_bytes", unitBytes, u.txBytes, start, ts),
+		intCumulative(prefix+"network.io.usage.tx_packets", unitCount, u.txPackets, start, ts),
+		intCumulative(prefix+"network.io.usage.tx_errors", unitCount, u.txErrors, start, ts),
+		intCumulative(prefix+"network.io.usage.tx_dropped", unitCount, u.txDropped, start, ts),
+
+		intCumulative(prefix+"storage.read_bytes", unitBytes, u.storageRead, start, ts),
+		intCumulative(prefix+"storage.write_bytes", unitBytes, u.storageWrite, start, ts),
+	}
+
+	if u.memoryLimit > 0 {
+		metrics = append(metrics, intGauge(prefix+"memory.usage.limit", unitBytes, u.memoryLimit, ts))
+	}
+	if u.restarts != nil {
+		metrics = append(metrics, intCumulative(prefix+"restarts", unitCount, *u.restarts, start, ts))
+	}
+	return metrics
+}
+
+func taskResource(metadata TaskMetadata) *resourcepb.Resource {
+	labels := map[string]string{
+		conventions.AttributeCloudProvider: "aws",
+	}
+	putLabel(labels, attributeECSCluster, metadata.Cluster)
+	putLabel(labels, attributeECSTaskARN, metadata.TaskARN)
+	putLabel(labels, attributeECSTaskFamily, metadata.Family)
+	putLabel(labels, attributeECSTaskRevision, metadata.Revision)
+	putLabel(labels, attributeECSLaunchType, metadata.LaunchType)
+	putLabel(labels, conventions.AttributeCloudZone, metadata.AvailabilityZone)
+
+	return &resourcepb.Resource{
+		Type:   resourceTypeTask,
+		Labels: labels,
+	}
+}
+
+func containerResource(metadata TaskMetadata, container ContainerMetadata) *resourcepb.Resource {
+	resource := taskResource(metadata)
+	resource.Type = resourceTypeContainer
+	putLabel(resource.Labels, conventions.AttributeContainerName, container.Name)
+	putLabel(resource.Labels, conventions.AttributeContainerID, container.DockerID)
+	putLabel(resource.Labels, conventions.AttributeContainerImage, container.Image)
+	putLabel(resource.Labels, attributeECSDockerName, container.DockerName)
+	return resource
+}
+
+// containerStartTime returns the time a container started at, or was created at if it
+// has not started, or the zero time if neither is known.
+func containerStartTime(container ContainerMetadata) time.Time {
+	switch {
+	case container.StartedAt != nil:
+		return *container.StartedAt
+	case container.CreatedAt != nil:
+		return *container.CreatedAt
+	default:
+		return time.Time{}
+	}
+}
+
+func putLabel(labels map[string]string, key, val string) {
+	if val != "" {
+		labels[key] = val
+	}
+}
+
+func value(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
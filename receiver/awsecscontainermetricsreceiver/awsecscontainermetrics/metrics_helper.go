@@ -15,33 +15,77 @@
 package awsecscontainermetrics
 
 import (
-	"strconv"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
-	"go.opentelemetry.io/collector/testutil/metricstestutil"
+	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
-// GenerateDummyMetrics generates some dummy metrics
-func GenerateDummyMetrics() consumerdata.MetricsData {
-	md := consumerdata.MetricsData{}
-	ts := time.Now()
-	for i := 0; i < 5; i++ {
-		md.Metrics = append(md.Metrics,
-			metricstestutil.Gauge(
-				"test_"+strconv.Itoa(i),
-				[]string{"k0", "k1"},
-				metricstestutil.Timeseries(
-					time.Now(),
-					[]string{"v0", "v1"},
-					&metricspb.Point{
-						Timestamp: metricstestutil.Timestamp(ts),
-						Value:     &metricspb.Point_Int64Value{Int64Value: int64(i)},
-					},
-				),
-			),
-		)
+// Units of the metrics.
+const (
+	unitBytes       = "Bytes"
+	unitMegabytes   = "Megabytes"
+	unitNanoseconds = "Nanoseconds"
+	unitPercent     = "Percent"
+	unitVCPU        = "vCPU"
+	unitCount       = "Count"
+)
+
+func timestampProto(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return &timestamp.Timestamp{
+		Seconds: t.Unix(),
+		Nanos:   int32(t.Nanosecond()),
+	}
+}
+
+func intGauge(name, unit string, value uint64, ts *timestamp.Timestamp) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: name,
+			Unit: unit,
+			Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{
+				Timestamp: ts,
+				Value:     &metricspb.Point_Int64Value{Int64Value: int64(value)},
+			}},
+		}},
+	}
+}
+
+func doubleGauge(name, unit string, value float64, ts *timestamp.Timestamp) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: name,
+			Unit: unit,
+			Type: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{
+				Timestamp: ts,
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: value},
+			}},
+		}},
+	}
+}
+
+func intCumulative(name, unit string, value uint64, start, ts *timestamp.Timestamp) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: name,
+			Unit: unit,
+			Type: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			StartTimestamp: start,
+			Points: []*metricspb.Point{{
+				Timestamp: ts,
+				Value:     &metricspb.Point_Int64Value{Int64Value: int64(value)},
+			}},
+		}},
 	}
-	return md
 }
@@ -0,0 +1,155 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metricValues(metrics []*metricspb.Metric) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, m := range metrics {
+		switch v := m.Timeseries[0].Points[0].Value.(type) {
+		case *metricspb.Point_Int64Value:
+			values[m.MetricDescriptor.Name] = v.Int64Value
+		case *metricspb.Point_DoubleValue:
+			values[m.MetricDescriptor.Name] = v.DoubleValue
+		}
+	}
+	return values
+}
+
+func TestMetricsData(t *testing.T) {
+	server := newTaskMetadataServer(t)
+	defer server.Close()
+
+	client := NewRestClient(server.URL, server.Client())
+	metadata, err := client.TaskMetadata(context.Background())
+	require.NoError(t, err)
+	stats, err := client.TaskStats(context.Background())
+	require.NoError(t, err)
+
+	now := time.Date(2020, 10, 2, 0, 51, 13, 0, time.UTC)
+	mds := MetricsData(metadata, stats, now)
+
+	// The task followed by the two containers with stats.
+	require.Len(t, mds, 3)
+
+	task := mds[0]
+	assert.Equal(t, "aws.ecs.task", task.Resource.Type)
+	assert.Equal(t, map[string]string{
+		"cloud.provider":        "aws",
+		"cloud.zone":            "us-west-2d",
+		"aws.ecs.cluster.name":  "arn:aws:ecs:us-west-2:111122223333:cluster/default",
+		"aws.ecs.task.arn":      "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c",
+		"aws.ecs.task.family":   "curltest",
+		"aws.ecs.task.revision": "26",
+		"aws.ecs.launchtype":    "EC2",
+	}, task.Resource.Labels)
+	assert.Equal(t, map[string]interface{}{
+		"ecs.task.memory.usage":                int64(6500352),
+		"ecs.task.memory.usage.max":            int64(12189696),
+		"ecs.task.memory.reserved":             int64(1024),
+		"ecs.task.cpu.usage.total":             int64(2026712260),
+		"ecs.task.cpu.usage.kernelmode":        int64(510000000),
+		"ecs.task.cpu.usage.usermode":          int64(1410000000),
+		"ecs.task.cpu.utilized":                50.0,
+		"ecs.task.cpu.reserved":                0.5,
+		"ecs.task.network.io.usage.rx_bytes":   int64(5820),
+		"ecs.task.network.io.usage.rx_packets": int64(52),
+		"ecs.task.network.io.usage.rx_errors":  int64(0),
+		"ecs.task.network.io.usage.rx_dropped": int64(2),
+		"ecs.task.network.io.usage.tx_bytes":   int64(3616),
+		"ecs.task.network.io.usage.tx_packets": int64(38),
+		"ecs.task.network.io.usage.tx_errors":  int64(1),
+		"ecs.task.network.io.usage.tx_dropped": int64(0),
+		"ecs.task.storage.read_bytes":          int64(638976),
+		"ecs.task.storage.write_bytes":         int64(4096),
+		"ecs.task.restarts":                    int64(2),
+	}, metricValues(task.Metrics))
+
+	curl := mds[2]
+	assert.Equal(t, "aws.ecs.container", curl.Resource.Type)
+	assert.Equal(t, "curl", curl.Resource.Labels["container.name"])
+	assert.Equal(t, "ee08638adaaf009d78c248913f629e38299471d45fe7dc944d1039077e3424ca", curl.Resource.Labels["container.id"])
+	assert.Equal(t, "111122223333.dkr.ecr.us-west-2.amazonaws.com/curltest:latest", curl.Resource.Labels["container.image.name"])
+	assert.Equal(t, "ecs-curltest-26-curl-a0e7dba5aca6d8cb2e00", curl.Resource.Labels["aws.ecs.docker.name"])
+	assert.Equal(t, "curltest", curl.Resource.Labels["aws.ecs.task.family"])
+	assert.Equal(t, map[string]interface{}{
+		"container.memory.usage":                int64(5066752),
+		"container.memory.usage.max":            int64(9027584),
+		"container.memory.usage.limit":          int64(536870912),
+		"container.memory.reserved":             int64(512),
+		"container.cpu.usage.total":             int64(2000000000),
+		"container.cpu.usage.kernelmode":        int64(500000000),
+		"container.cpu.usage.usermode":          int64(1400000000),
+		"container.cpu.utilized":                50.0,
+		"container.cpu.reserved":                0.25,
+		"container.network.io.usage.rx_bytes":   int64(5736),
+		"container.network.io.usage.rx_packets": int64(50),
+		"container.network.io.usage.rx_errors":  int64(0),
+		"container.network.io.usage.rx_dropped": int64(2),
+		"container.network.io.usage.tx_bytes":   int64(3532),
+		"container.network.io.usage.tx_packets": int64(36),
+		"container.network.io.usage.tx_errors":  int64(1),
+		"container.network.io.usage.tx_dropped": int64(0),
+		"container.storage.read_bytes":          int64(638976),
+		"container.storage.write_bytes":         int64(4096),
+		"container.restarts":                    int64(2),
+	}, metricValues(curl.Metrics))
+
+	// Cumulative metrics start when the container started.
+	for _, m := range curl.Metrics {
+		ts := m.Timeseries[0]
+		assert.Equal(t, now.Unix(), ts.Points[0].Timestamp.Seconds)
+		if m.MetricDescriptor.Type == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+			assert.Equal(t, metadata.Containers[1].StartedAt.Unix(), ts.StartTimestamp.Seconds, m.MetricDescriptor.Name)
+		}
+	}
+
+	// The task starts with its first container.
+	for _, m := range task.Metrics {
+		if m.MetricDescriptor.Type == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+			assert.Equal(t, metadata.Containers[0].StartedAt.Unix(), m.Timeseries[0].StartTimestamp.Seconds, m.MetricDescriptor.Name)
+		}
+	}
+}
+
+func TestMetricsDataWithoutTaskLimits(t *testing.T) {
+	cpu, memory := 512.0, uint64(256)
+	metadata := TaskMetadata{
+		Containers: []ContainerMetadata{{
+			DockerID: "1",
+			Limits:   Limits{CPU: &cpu, Memory: &memory},
+		}},
+	}
+	stats := map[string]*ContainerStats{"1": {}}
+
+	mds := MetricsData(metadata, stats, time.Now())
+	require.Len(t, mds, 2)
+
+	values := metricValues(mds[0].Metrics)
+	assert.Equal(t, int64(256), values["ecs.task.memory.reserved"])
+	assert.Equal(t, 0.5, values["ecs.task.cpu.reserved"])
+	assert.Equal(t, 0.0, values["ecs.task.cpu.utilized"])
+	assert.NotContains(t, values, "ecs.task.restarts")
+	assert.NotContains(t, values, "ecs.task.memory.usage.limit")
+}
@@ -0,0 +1,89 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	taskMetadataPath = "/task"
+	taskStatsPath    = "/task/stats"
+)
+
+// RestClient fetches task metadata and container stats from the Task Metadata Endpoint v4.
+type RestClient interface {
+	// TaskMetadata returns the metadata of the task and its containers.
+	TaskMetadata(ctx context.Context) (TaskMetadata, error)
+	// TaskStats returns the docker stats of the containers of the task by docker ID.
+	// Containers that are not running have nil stats.
+	TaskStats(ctx context.Context) (map[string]*ContainerStats, error)
+}
+
+type restClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+var _ RestClient = (*restClient)(nil)
+
+// NewRestClient creates a RestClient for the Task Metadata Endpoint at endpoint, as set in
+// the ECS_CONTAINER_METADATA_URI_V4 environment variable of the containers of a task.
+func NewRestClient(endpoint string, client *http.Client) RestClient {
+	return &restClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+	}
+}
+
+func (c *restClient) TaskMetadata(ctx context.Context) (TaskMetadata, error) {
+	var metadata TaskMetadata
+	err := c.get(ctx, taskMetadataPath, &metadata)
+	return metadata, err
+}
+
+func (c *restClient) TaskStats(ctx context.Context) (map[string]*ContainerStats, error) {
+	var stats map[string]*ContainerStats
+	err := c.get(ctx, taskStatsPath, &stats)
+	return stats, err
+}
+
+// get decodes the JSON response of the endpoint for path into v.
+func (c *restClient) get(ctx context.Context, path string, v interface{}) error {
+	url := c.endpoint + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed with status %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTaskMetadataServer returns a stub of the Task Metadata Endpoint v4 serving the
+// task metadata and stats in testdata.
+func newTaskMetadataServer(t *testing.T) *httptest.Server {
+	metadata, err := ioutil.ReadFile(path.Join("testdata", "task_metadata.json"))
+	require.NoError(t, err)
+	stats, err := ioutil.ReadFile(path.Join("testdata", "task_stats.json"))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/task", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(metadata)
+	})
+	mux.HandleFunc("/task/stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(stats)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRestClient(t *testing.T) {
+	server := newTaskMetadataServer(t)
+	defer server.Close()
+
+	client := NewRestClient(server.URL+"/", server.Client())
+
+	metadata, err := client.TaskMetadata(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:ecs:us-west-2:111122223333:cluster/default", metadata.Cluster)
+	assert.Equal(t, "curltest", metadata.Family)
+	assert.Equal(t, "26", metadata.Revision)
+	require.Len(t, metadata.Containers, 3)
+	assert.Equal(t, "curl", metadata.Containers[1].Name)
+	assert.Equal(t, int64(2), *metadata.Containers[1].RestartCount)
+	assert.Equal(t, 256.0, *metadata.Containers[1].Limits.CPU)
+
+	stats, err := client.TaskStats(context.Background())
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+	assert.Nil(t, stats["2c7f8ee4b4f4c9c1a4a0c7d0c6e3e5b3a77a1e9d3d0f8f4a63a1e1b2d7c5e8f1"])
+	curl := stats["ee08638adaaf009d78c248913f629e38299471d45fe7dc944d1039077e3424ca"]
+	require.NotNil(t, curl)
+	assert.Equal(t, uint64(5066752), *curl.Memory.Usage)
+	assert.Len(t, curl.Network, 2)
+	assert.Len(t, curl.Disk.IoServiceBytesRecursives, 5)
+}
+
+func TestRestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/task" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("{"))
+	}))
+	defer server.Close()
+
+	client := NewRestClient(server.URL, server.Client())
+
+	_, err := client.TaskMetadata(context.Background())
+	assert.EqualError(t, err, "request to "+server.URL+"/task failed with status 503")
+
+	_, err = client.TaskStats(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode response of "+server.URL+"/task/stats")
+
+	server.Close()
+	_, err = client.TaskMetadata(context.Background())
+	assert.Error(t, err)
+}
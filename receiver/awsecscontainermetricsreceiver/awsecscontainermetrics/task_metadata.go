@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import "time"
+
+// TaskMetadata is the metadata of an ECS task returned by the /task path of the
+// Task Metadata Endpoint v4.
+type TaskMetadata struct {
+	Cluster          string              `json:"Cluster,omitempty"`
+	TaskARN          string              `json:"TaskARN,omitempty"`
+	Family           string              `json:"Family,omitempty"`
+	Revision         string              `json:"Revision,omitempty"`
+	AvailabilityZone string              `json:"AvailabilityZone,omitempty"`
+	LaunchType       string              `json:"LaunchType,omitempty"`
+	KnownStatus      string              `json:"KnownStatus,omitempty"`
+	Limits           Limits              `json:"Limits,omitempty"`
+	Containers       []ContainerMetadata `json:"Containers,omitempty"`
+}
+
+// ContainerMetadata is the metadata of a container of an ECS task.
+type ContainerMetadata struct {
+	DockerID    string            `json:"DockerId,omitempty"`
+	Name        string            `json:"Name,omitempty"`
+	DockerName  string            `json:"DockerName,omitempty"`
+	Image       string            `json:"Image,omitempty"`
+	ImageID     string            `json:"ImageID,omitempty"`
+	Labels      map[string]string `json:"Labels,omitempty"`
+	KnownStatus string            `json:"KnownStatus,omitempty"`
+	Limits      Limits            `json:"Limits,omitempty"`
+	CreatedAt   *time.Time        `json:"CreatedAt,omitempty"`
+	StartedAt   *time.Time        `json:"StartedAt,omitempty"`
+	// RestartCount is only reported for containers with a restart policy.
+	RestartCount *int64 `json:"RestartCount,omitempty"`
+}
+
+// Limits are the CPU and memory limits of a task or container. CPU is expressed in
+// vCPUs for tasks and in CPU units (1024 per vCPU) for containers, memory in MiB.
+type Limits struct {
+	CPU    *float64 `json:"CPU,omitempty"`
+	Memory *uint64  `json:"Memory,omitempty"`
+}
@@ -16,12 +16,14 @@ package awsecscontainermetricsreceiver
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
@@ -29,6 +31,10 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
+// endpointEnvKey is the environment variable ECS sets to the Task Metadata Endpoint v4
+// in the containers of a task.
+const endpointEnvKey = "ECS_CONTAINER_METADATA_URI_V4"
+
 var _ component.MetricsReceiver = (*awsEcsContainerMetricsReceiver)(nil)
 
 // awsEcsContainerMetricsReceiver implements the component.MetricsReceiver for aws ecs container metrics.
@@ -37,6 +43,7 @@ type awsEcsContainerMetricsReceiver struct {
 	nextConsumer consumer.MetricsConsumer
 	config       *Config
 	cancel       context.CancelFunc
+	restClient   awsecscontainermetrics.RestClient
 }
 
 // newAwsEcsContainerMetricsReceiver creates the aws ecs container metrics receiver with the given parameters.
@@ -58,6 +65,12 @@ func newAwsEcsContainerMetricsReceiver(
 
 // Start begins collecting metrics from Amazon ECS task metadata endpoint.
 func (aecmr *awsEcsContainerMetricsReceiver) Start(ctx context.Context, host component.Host) error {
+	endpoint := os.Getenv(endpointEnvKey)
+	if endpoint == "" {
+		return fmt.Errorf("%s environment variable is not set, the receiver must run in an ECS task", endpointEnvKey)
+	}
+	aecmr.restClient = awsecscontainermetrics.NewRestClient(endpoint, &http.Client{Timeout: aecmr.config.CollectionInterval})
+
 	ctx, aecmr.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, "http", aecmr.config.Name()))
 	go func() {
 		ticker := time.NewTicker(aecmr.config.CollectionInterval)
@@ -66,7 +79,9 @@ func (aecmr *awsEcsContainerMetricsReceiver) Start(ctx context.Context, host com
 		for {
 			select {
 			case <-ticker.C:
-				aecmr.collectDataFromEndpoint(ctx)
+				if err := aecmr.collectDataFromEndpoint(ctx); err != nil {
+					aecmr.logger.Error("failed to collect metrics from task metadata endpoint", zap.Error(err))
+				}
 			case <-ctx.Done():
 				return
 			}
@@ -77,15 +92,24 @@ func (aecmr *awsEcsContainerMetricsReceiver) Start(ctx context.Context, host com
 
 // Shutdown stops the awsecscontainermetricsreceiver receiver.
 func (aecmr *awsEcsContainerMetricsReceiver) Shutdown(context.Context) error {
-	aecmr.cancel()
+	if aecmr.cancel != nil {
+		aecmr.cancel()
+	}
 	return nil
 }
 
-// collectDataFromEndpoint collects container stats from Amazon ECS Task Metadata Endpoint
-// TODO: Replace with acutal logic.
+// collectDataFromEndpoint collects task metadata and container stats from Amazon ECS
+// Task Metadata Endpoint and sends them as metrics to the next consumer.
 func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context.Context) error {
-	md := awsecscontainermetrics.GenerateDummyMetrics()
+	metadata, err := aecmr.restClient.TaskMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	stats, err := aecmr.restClient.TaskStats(ctx)
+	if err != nil {
+		return err
+	}
 
-	err := aecmr.nextConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
-	return err
+	mds := awsecscontainermetrics.MetricsData(metadata, stats, time.Now())
+	return aecmr.nextConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData(mds))
 }
@@ -16,15 +16,47 @@ package awsecscontainermetricsreceiver
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
+// newTaskMetadataServer returns a stub of the Task Metadata Endpoint v4 serving the
+// task metadata and stats in testdata.
+func newTaskMetadataServer(t *testing.T) *httptest.Server {
+	metadata, err := ioutil.ReadFile(path.Join("awsecscontainermetrics", "testdata", "task_metadata.json"))
+	require.NoError(t, err)
+	stats, err := ioutil.ReadFile(path.Join("awsecscontainermetrics", "testdata", "task_stats.json"))
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/task", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(metadata)
+	})
+	mux.HandleFunc("/task/stats", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(stats)
+	})
+	return httptest.NewServer(mux)
+}
+
 func TestReceiver(t *testing.T) {
+	server := newTaskMetadataServer(t)
+	defer server.Close()
+	os.Setenv(endpointEnvKey, server.URL)
+	defer os.Unsetenv(endpointEnvKey)
+
 	cfg := createDefaultConfig().(*Config)
 	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
 		zap.NewNop(),
@@ -45,20 +77,67 @@ func TestReceiver(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReceiverWithoutEndpoint(t *testing.T) {
+	os.Unsetenv(endpointEnvKey)
+
+	cfg := createDefaultConfig().(*Config)
+	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
+		zap.NewNop(),
+		cfg,
+		exportertest.NewNopMetricsExporter(),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = metricsReceiver.Start(ctx, componenttest.NewNopHost())
+	require.EqualError(t, err, "ECS_CONTAINER_METADATA_URI_V4 environment variable is not set, the receiver must run in an ECS task")
+	require.NoError(t, metricsReceiver.Shutdown(ctx))
+}
+
 func TestCollectDataFromEndpoint(t *testing.T) {
+	server := newTaskMetadataServer(t)
+	defer server.Close()
+
 	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
 	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
 		zap.NewNop(),
 		cfg,
-		new(exportertest.SinkMetricsExporter),
+		sink,
 	)
 
 	require.NoError(t, err)
 	require.NotNil(t, metricsReceiver)
 
 	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	r.restClient = awsecscontainermetrics.NewRestClient(server.URL, server.Client())
 	ctx := context.Background()
 
 	err = r.collectDataFromEndpoint(ctx)
 	require.NoError(t, err)
+
+	// Metrics of the task and of its two containers with stats.
+	require.Len(t, sink.AllMetrics(), 1)
+	mds := pdatautil.MetricsToMetricsData(sink.AllMetrics()[0])
+	require.Len(t, mds, 3)
+	assert.Equal(t, "curltest", mds[0].Resource.Labels["aws.ecs.task.family"])
+	assert.Equal(t, "~internal~ecs~pause", mds[1].Resource.Labels["container.name"])
+	assert.Equal(t, "curl", mds[2].Resource.Labels["container.name"])
+}
+
+func TestCollectDataFromEndpointError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
+	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	r.restClient = awsecscontainermetrics.NewRestClient(server.URL, server.Client())
+
+	err = r.collectDataFromEndpoint(context.Background())
+	require.Error(t, err)
+	assert.Empty(t, sink.AllMetrics())
 }
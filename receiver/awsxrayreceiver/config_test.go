@@ -26,6 +26,8 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/config/configtls"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -60,9 +62,9 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint:  "0.0.0.0:5678",
 				Transport: "udp",
 			},
-			ProxyServer: &proxyServer{
+			ProxyServer: &proxy.Config{
 				TCPAddr: confignet.TCPAddr{
-					Endpoint: "0.0.0.0:2000",
+					Endpoint: "127.0.0.1:2000",
 				},
 				ProxyAddress: "",
 				TLSSetting: configtls.TLSClientSetting{
@@ -89,7 +91,7 @@ func TestLoadConfig(t *testing.T) {
 				Endpoint:  "0.0.0.0:2000",
 				Transport: "udp",
 			},
-			ProxyServer: &proxyServer{
+			ProxyServer: &proxy.Config{
 				TCPAddr: confignet.TCPAddr{
 					Endpoint: "0.0.0.0:1234",
 				},
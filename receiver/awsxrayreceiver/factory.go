@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
 
@@ -51,9 +52,12 @@ func createDefaultConfig() configmodels.Receiver {
 			Endpoint:  "0.0.0.0:2000",
 			Transport: udppoller.Transport,
 		},
-		ProxyServer: &proxyServer{
+		ProxyServer: &proxy.Config{
+			// The proxy relays requests signed with the collector's
+			// credentials, so it only listens on the loopback interface
+			// by default, like the X-Ray daemon.
 			TCPAddr: confignet.TCPAddr{
-				Endpoint: "0.0.0.0:2000",
+				Endpoint: "127.0.0.1:2000",
 			},
 			ProxyAddress: "",
 			TLSSetting: configtls.TLSClientSetting{
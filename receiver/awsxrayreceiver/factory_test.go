@@ -51,12 +51,13 @@ func TestCreateDefaultConfig(t *testing.T) {
 	cfg := factory.CreateDefaultConfig()
 	assert.NotNil(t, cfg, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
+	// The signing proxy must not be reachable from other hosts by default.
+	assert.Equal(t, "127.0.0.1:2000", cfg.(*Config).ProxyServer.Endpoint)
 
 	assert.Equal(t, configmodels.Type(typeStr), factory.Type())
 }
 
 func TestCreateTraceReceiver(t *testing.T) {
-	// TODO: Create proper tests after CreateTraceReceiver is implemented.
 	factory := NewFactory()
 	_, err := factory.CreateTraceReceiver(
 		context.Background(),
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"go.uber.org/zap"
+)
+
+const (
+	// service is the name of the X-Ray service in AWS endpoints and signatures.
+	service = "xray"
+
+	// the environment variables the region can be read from, the former taking
+	// precedence as in the AWS SDKs.
+	regionEnvVar        = "AWS_REGION"
+	regionEnvVarDefault = "AWS_DEFAULT_REGION"
+
+	// idleConnTimeout and remoteProxyMaxIdleConnsPerHost follow the X-Ray daemon settings:
+	// https://github.com/aws/aws-xray-daemon/blob/master/pkg/conn/conn.go
+	idleConnTimeout                = 30 * time.Second
+	remoteProxyMaxIdleConnsPerHost = 2
+)
+
+// ErrNoRegion is returned when the region X-Ray requests are forwarded to can not be resolved.
+var ErrNoRegion = errors.New("could not fetch the region from the config, the AWS_REGION or AWS_DEFAULT_REGION environment variables, or the EC2 instance metadata")
+
+// newHTTPTransport creates the transport of the requests forwarded to X-Ray, going
+// through the configured proxy if any.
+func newHTTPTransport(cfg *Config) (*http.Transport, error) {
+	tlsCfg, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg == nil {
+		// the TLS settings return no TLS config when insecure as in gRPC, insecure
+		// only disables the verification of certificates when forwarding over HTTPS.
+		tlsCfg = &tls.Config{}
+	}
+	tlsCfg.InsecureSkipVerify = cfg.TLSSetting.Insecure
+	tlsCfg.ServerName = cfg.TLSSetting.ServerName
+
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: remoteProxyMaxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		TLSClientConfig:     tlsCfg,
+	}
+
+	if cfg.ProxyAddress != "" {
+		proxyURL, err := url.Parse(cfg.ProxyAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q: %w", cfg.ProxyAddress, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// newAWSSession creates the session used to sign the forwarded requests, resolving the
+// region and assuming the configured role.
+func newAWSSession(cfg *Config, transport *http.Transport, logger *zap.Logger) (*session.Session, error) {
+	awsCfg := &aws.Config{
+		HTTPClient: &http.Client{Transport: transport},
+	}
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := getRegion(cfg, sess, logger)
+	if err != nil {
+		return nil, err
+	}
+	sess.Config.Region = aws.String(region)
+
+	if cfg.RoleARN != "" {
+		logger.Debug("Assuming role to forward requests to X-Ray", zap.String("role_arn", cfg.RoleARN))
+		sess.Config.Credentials = stscreds.NewCredentials(sess, cfg.RoleARN)
+	}
+	return sess, nil
+}
+
+// getRegion returns the configured region, falling back to the environment variables
+// and, unless in local mode, to the EC2 instance metadata.
+func getRegion(cfg *Config, sess *session.Session, logger *zap.Logger) (string, error) {
+	if cfg.Region != "" {
+		return cfg.Region, nil
+	}
+
+	for _, envVar := range []string{regionEnvVar, regionEnvVarDefault} {
+		if region := os.Getenv(envVar); region != "" {
+			logger.Debug("Fetched region from environment variable", zap.String("env", envVar), zap.String("region", region))
+			return region, nil
+		}
+	}
+
+	if cfg.LocalMode == nil || !*cfg.LocalMode {
+		region, err := ec2metadata.New(sess).Region()
+		if err == nil {
+			logger.Debug("Fetched region from EC2 instance metadata", zap.String("region", region))
+			return region, nil
+		}
+		logger.Debug("Unable to fetch region from EC2 instance metadata", zap.Error(err))
+	}
+	return "", ErrNoRegion
+}
+
+// getServiceEndpoint returns the configured X-Ray endpoint, or the endpoint of X-Ray in
+// the region.
+func getServiceEndpoint(cfg *Config, region string) (*url.URL, error) {
+	endpoint := cfg.AWSEndpoint
+	if endpoint == "" {
+		resolved, err := endpoints.DefaultResolver().EndpointFor(service, region)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = resolved.URL
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Ray endpoint %q: %w", endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid X-Ray endpoint %q: scheme and host are required", endpoint)
+	}
+	return u, nil
+}
@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.uber.org/zap"
+)
+
+func TestGetRegion(t *testing.T) {
+	sess, err := session.NewSession()
+	require.NoError(t, err)
+	localMode := &Config{LocalMode: aws.Bool(true)}
+	os.Unsetenv(regionEnvVar)
+	os.Unsetenv(regionEnvVarDefault)
+
+	region, err := getRegion(&Config{Region: "us-east-1", LocalMode: aws.Bool(true)}, sess, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", region)
+
+	os.Setenv(regionEnvVarDefault, "eu-west-1")
+	region, err = getRegion(localMode, sess, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", region)
+
+	os.Setenv(regionEnvVar, "eu-west-2")
+	region, err = getRegion(localMode, sess, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-2", region, "AWS_REGION takes precedence over AWS_DEFAULT_REGION")
+
+	os.Unsetenv(regionEnvVar)
+	os.Unsetenv(regionEnvVarDefault)
+	_, err = getRegion(localMode, sess, zap.NewNop())
+	assert.Equal(t, ErrNoRegion, err, "the EC2 instance metadata is not queried in local mode")
+}
+
+func TestGetServiceEndpoint(t *testing.T) {
+	u, err := getServiceEndpoint(&Config{}, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://xray.us-west-2.amazonaws.com", u.String())
+
+	u, err = getServiceEndpoint(&Config{}, "cn-north-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://xray.cn-north-1.amazonaws.com.cn", u.String())
+
+	u, err = getServiceEndpoint(&Config{AWSEndpoint: "https://xray.example.com"}, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://xray.example.com", u.String())
+}
+
+func TestNewHTTPTransport(t *testing.T) {
+	transport, err := newHTTPTransport(&Config{
+		ProxyAddress: "https://proxy.example.com:8080",
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure:   true,
+			ServerName: "xray.example.com",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Equal(t, "xray.example.com", transport.TLSClientConfig.ServerName)
+
+	req, err := http.NewRequest(http.MethodPost, "https://xray.us-west-2.amazonaws.com/GetSamplingRules", nil)
+	require.NoError(t, err)
+	proxyURL, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "https://proxy.example.com:8080", proxyURL.String())
+
+	transport, err = newHTTPTransport(&Config{})
+	require.NoError(t, err)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	assert.Nil(t, transport.Proxy, "requests are sent directly without proxy address")
+}
+
+func TestNewAWSSessionAssumesRole(t *testing.T) {
+	defer setCredentials(t)()
+	transport, err := newHTTPTransport(&Config{})
+	require.NoError(t, err)
+
+	sess, err := newAWSSession(&Config{Region: "us-west-2", LocalMode: aws.Bool(true)}, transport, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, "us-west-2", *sess.Config.Region)
+	creds, err := sess.Config.Credentials.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "AKIDEXAMPLE", creds.AccessKeyID)
+
+	roleSess, err := newAWSSession(&Config{
+		Region:    "us-west-2",
+		RoleARN:   "arn:aws:iam::123456789012:role/xray",
+		LocalMode: aws.Bool(true),
+	}, transport, zap.NewNop())
+	require.NoError(t, err)
+	assert.NotSame(t, sess.Config.Credentials, roleSess.Config.Credentials,
+		"the credentials of the assumed role should be used")
+	assert.Same(t, transport, roleSess.Config.HTTPClient.Transport,
+		"calls to STS should go through the configured proxy")
+}
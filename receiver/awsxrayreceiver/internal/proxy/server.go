@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"go.uber.org/zap"
+)
+
+// The X-Ray APIs the SDKs call through the proxy. Requests to any other path are
+// rejected, so that the proxy does not relay arbitrary calls signed with the
+// collector's credentials.
+const (
+	getSamplingRulesPath = "/GetSamplingRules"
+	samplingTargetsPath  = "/SamplingTargets"
+)
+
+// Server is the local TCP proxy the X-Ray SDKs get sampling rules and report sampling
+// statistics through. It signs the requests with AWS Signature Version 4 and forwards
+// them to the X-Ray service.
+type Server interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+// NewServer creates a proxy server listening on the configured endpoint.
+func NewServer(cfg *Config, logger *zap.Logger) (Server, error) {
+	if _, err := net.ResolveTCPAddr("tcp", cfg.Endpoint); err != nil {
+		return nil, err
+	}
+
+	transport, err := newHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := newAWSSession(cfg, transport, logger)
+	if err != nil {
+		return nil, err
+	}
+	region := *sess.Config.Region
+	awsURL, err := getServiceEndpoint(cfg, region)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Forwarding X-Ray proxy requests",
+		zap.String("endpoint", awsURL.String()), zap.String("region", region))
+
+	proxy := &httputil.ReverseProxy{
+		Transport: &signingTransport{
+			transport: transport,
+			signer:    v4.NewSigner(sess.Config.Credentials),
+			region:    region,
+		},
+		Director: director(awsURL, logger),
+		ErrorLog: zap.NewStdLog(logger),
+	}
+
+	return &http.Server{
+		Addr:    cfg.Endpoint,
+		Handler: samplingAPIsOnly(proxy, logger),
+	}, nil
+}
+
+// samplingAPIsOnly passes the requests to the sampling APIs on to next, and
+// replies 404 to any other request.
+func samplingAPIsOnly(next http.Handler, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case getSamplingRulesPath, samplingTargetsPath:
+			next.ServeHTTP(w, req)
+		default:
+			logger.Debug("Rejected request to unsupported path on X-Ray receiver TCP proxy server",
+				zap.String("path", req.URL.Path))
+			http.NotFound(w, req)
+		}
+	})
+}
+
+// director rewrites the requests of the SDKs to the X-Ray endpoint.
+func director(awsURL *url.URL, logger *zap.Logger) func(*http.Request) {
+	return func(req *http.Request) {
+		logger.Debug("Received request on X-Ray receiver TCP proxy server", zap.String("URL", req.URL.String()))
+
+		req.URL.Scheme = awsURL.Scheme
+		req.URL.Host = awsURL.Host
+		req.Host = awsURL.Host
+	}
+}
+
+// signingTransport signs the requests with AWS Signature Version 4 before
+// forwarding them. Requests that can not be signed are not forwarded, the
+// reverse proxy replies to the SDK with a 502 instead.
+type signingTransport struct {
+	transport http.RoundTripper
+	signer    *v4.Signer
+	region    string
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the reverse proxy removes the hop-by-hop headers before the round trip,
+	// they are not part of the signature.
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body: %w", err)
+		}
+	}
+
+	// the signer sets the body of the request back.
+	if _, err := t.signer.Sign(req, bytes.NewReader(body), service, t.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("unable to sign request: %w", err)
+	}
+	return t.transport.RoundTrip(req)
+}
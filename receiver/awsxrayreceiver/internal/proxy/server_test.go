@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+// setCredentials sets static AWS credentials in the environment for the duration of a test.
+func setCredentials(t *testing.T) func() {
+	require.NoError(t, os.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE"))
+	require.NoError(t, os.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"))
+	return func() {
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+		os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+	}
+}
+
+// receivedRequest is what the fake X-Ray API records of the requests it receives.
+type receivedRequest struct {
+	method string
+	path   string
+	host   string
+	header http.Header
+	body   string
+}
+
+// newFakeXRay returns a fake X-Ray API recording the requests it receives in requests.
+func newFakeXRay(t *testing.T, requests chan<- receivedRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests <- receivedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			host:   r.Host,
+			header: r.Header,
+			body:   string(body),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"SamplingRuleRecords":[]}`))
+	}))
+}
+
+func TestProxyForwardsSignedRequests(t *testing.T) {
+	defer setCredentials(t)()
+
+	requests := make(chan receivedRequest, 1)
+	xray := newFakeXRay(t, requests)
+	defer xray.Close()
+
+	cfg := &Config{
+		TCPAddr:     confignet.TCPAddr{Endpoint: testutil.GetAvailableLocalAddress(t)},
+		Region:      "us-west-2",
+		AWSEndpoint: xray.URL,
+		LocalMode:   aws.Bool(true),
+	}
+	srv, err := NewServer(cfg, zap.NewNop())
+	require.NoError(t, err)
+	go func() {
+		assert.Equal(t, http.ErrServerClosed, srv.ListenAndServe())
+	}()
+	defer srv.Shutdown(context.Background())
+
+	var resp *http.Response
+	testutil.WaitFor(t, func() bool {
+		resp, err = http.Post("http://"+cfg.Endpoint+"/GetSamplingRules", "application/json",
+			strings.NewReader(`{"NextToken":null}`))
+		return err == nil
+	}, "proxy server should eventually accept requests")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"SamplingRuleRecords":[]}`, string(respBody))
+
+	var req receivedRequest
+	select {
+	case req = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request was not forwarded to X-Ray")
+	}
+	assert.Equal(t, http.MethodPost, req.method)
+	assert.Equal(t, "/GetSamplingRules", req.path)
+	assert.Equal(t, strings.TrimPrefix(xray.URL, "http://"), req.host)
+	assert.Equal(t, `{"NextToken":null}`, req.body)
+	assert.NotEmpty(t, req.header.Get("X-Amz-Date"))
+
+	auth := req.header.Get("Authorization")
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), auth)
+	assert.Contains(t, auth, "/us-west-2/xray/aws4_request")
+	assert.Contains(t, auth, "SignedHeaders=")
+	assert.Contains(t, auth, "Signature=")
+
+	other, err := http.Post("http://"+cfg.Endpoint+"/TraceSegments", "application/json",
+		strings.NewReader(`{"TraceSegmentDocuments":[]}`))
+	require.NoError(t, err)
+	defer other.Body.Close()
+	assert.Equal(t, http.StatusNotFound, other.StatusCode)
+	select {
+	case <-requests:
+		t.Fatal("requests to other APIs should not be forwarded to X-Ray")
+	default:
+	}
+}
+
+func TestNewServerInvalidConfig(t *testing.T) {
+	defer setCredentials(t)()
+
+	_, err := NewServer(&Config{
+		TCPAddr:   confignet.TCPAddr{Endpoint: "invalid_endpoint"},
+		Region:    "us-west-2",
+		LocalMode: aws.Bool(true),
+	}, zap.NewNop())
+	assert.Error(t, err, "the TCP endpoint must be valid")
+
+	_, err = NewServer(&Config{
+		TCPAddr:      confignet.TCPAddr{Endpoint: "localhost:0"},
+		Region:       "us-west-2",
+		ProxyAddress: "://invalid",
+		LocalMode:    aws.Bool(true),
+	}, zap.NewNop())
+	assert.Error(t, err, "the proxy address must be valid")
+
+	_, err = NewServer(&Config{
+		TCPAddr:     confignet.TCPAddr{Endpoint: "localhost:0"},
+		Region:      "us-west-2",
+		AWSEndpoint: "no-scheme",
+		LocalMode:   aws.Bool(true),
+	}, zap.NewNop())
+	assert.EqualError(t, err, `invalid X-Ray endpoint "no-scheme": scheme and host are required`)
+}
+
+// errReader is a request body failing to be read.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestProxyDoesNotForwardUnsignedRequests(t *testing.T) {
+	requests := make(chan receivedRequest, 1)
+	xray := newFakeXRay(t, requests)
+	defer xray.Close()
+	awsURL, err := url.Parse(xray.URL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		signer *v4.Signer
+		body   io.Reader
+	}{
+		{
+			name:   "signing_error",
+			signer: v4.NewSigner(credentials.NewStaticCredentials("", "", "")),
+			body:   strings.NewReader(`{"NextToken":null}`),
+		},
+		{
+			name:   "body_read_error",
+			signer: v4.NewSigner(credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", "")),
+			body:   errReader{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &httputil.ReverseProxy{
+				Transport: &signingTransport{transport: http.DefaultTransport, signer: tt.signer, region: "us-west-2"},
+				Director:  director(awsURL, zap.NewNop()),
+				ErrorLog:  zap.NewStdLog(zap.NewNop()),
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/GetSamplingRules", tt.body))
+
+			assert.Equal(t, http.StatusBadGateway, rec.Code)
+			select {
+			case <-requests:
+				t.Fatal("the request should not be forwarded to X-Ray")
+			default:
+			}
+		})
+	}
+}
+
+func TestProxyOnlyForwardsSamplingAPIs(t *testing.T) {
+	tests := []struct {
+		path      string
+		forwarded bool
+	}{
+		{path: "/GetSamplingRules", forwarded: true},
+		{path: "/SamplingTargets", forwarded: true},
+		{path: "/TraceSegments"},
+		{path: "/PutTraceSegments"},
+		{path: "/GetSamplingRules/extra"},
+		{path: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var forwarded bool
+			handler := samplingAPIsOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				forwarded = true
+			}), zap.NewNop())
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader("{}")))
+
+			assert.Equal(t, tt.forwarded, forwarded)
+			if !tt.forwarded {
+				assert.Equal(t, http.StatusNotFound, rec.Code)
+			}
+		})
+	}
+}
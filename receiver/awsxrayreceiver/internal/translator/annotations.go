@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+// addAnnotations converts the annotations, indexed by X-Ray, to span attributes.
+func addAnnotations(annos map[string]interface{}, attrs pdata.AttributeMap) {
+	for k, v := range annos {
+		switch v := v.(type) {
+		case string:
+			attrs.UpsertString(k, v)
+		case bool:
+			attrs.UpsertBool(k, v)
+		case float64:
+			attrs.UpsertDouble(k, v)
+		default:
+			// X-Ray only allows string, number and boolean annotations.
+			if b, err := json.Marshal(v); err == nil {
+				attrs.UpsertString(k, string(b))
+			}
+		}
+	}
+}
+
+// addMetadata converts the metadata, arbitrary JSON values grouped by namespace, to
+// span attributes holding the JSON encoding of the values.
+func addMetadata(meta map[string]map[string]interface{}, attrs pdata.AttributeMap) error {
+	for ns, kvs := range meta {
+		for k, v := range kvs {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			attrs.UpsertString(awsxray.AWSXRayMetadataAttributePrefix+ns+"."+k, string(b))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+// addAWSToResource sets the resource attributes describing where the segment was
+// emitted from.
+func addAWSToResource(aws *awsxray.AWSData, attrs pdata.AttributeMap) {
+	if aws == nil {
+		return
+	}
+
+	if aws.EC2 != nil || aws.ECS != nil || aws.Beanstalk != nil {
+		attrs.UpsertString(conventions.AttributeCloudProvider, "aws")
+	}
+
+	if ec2 := aws.EC2; ec2 != nil {
+		addString(ec2.InstanceID, conventions.AttributeHostID, attrs)
+		addString(ec2.AvailabilityZone, conventions.AttributeCloudZone, attrs)
+		addString(ec2.InstanceSize, conventions.AttributeHostType, attrs)
+		addString(ec2.AmiID, conventions.AttributeHostImageID, attrs)
+	}
+
+	if ecs := aws.ECS; ecs != nil {
+		addString(ecs.ContainerName, conventions.AttributeContainerName, attrs)
+	}
+
+	if bs := aws.Beanstalk; bs != nil {
+		addString(bs.Environment, conventions.AttributeServiceNamespace, attrs)
+		if bs.DeploymentID != nil {
+			attrs.UpsertString(conventions.AttributeServiceInstance, strconv.FormatInt(*bs.DeploymentID, 10))
+		}
+		// the version of the `service` field takes precedence.
+		if bs.VersionLabel != nil {
+			attrs.InsertString(conventions.AttributeServiceVersion, *bs.VersionLabel)
+		}
+	}
+
+	if xray := aws.XRay; xray != nil {
+		addString(xray.SDK, conventions.AttributeTelemetrySDKName, attrs)
+		addString(xray.SDKVersion, conventions.AttributeTelemetrySDKVersion, attrs)
+		if xray.AutoInstrumentation != nil && *xray.AutoInstrumentation {
+			addString(xray.SDKVersion, conventions.AttributeTelemetryAutoVersion, attrs)
+		}
+	}
+}
+
+// addAWSToSpan sets the span attributes describing the call made to an AWS service.
+func addAWSToSpan(aws *awsxray.AWSData, attrs pdata.AttributeMap) {
+	if aws == nil {
+		return
+	}
+
+	addString(aws.AccountID, awsxray.AWSAccountAttribute, attrs)
+	addString(aws.Operation, awsxray.AWSOperationAttribute, attrs)
+	addString(aws.RemoteRegion, awsxray.AWSRegionAttribute, attrs)
+	addString(aws.RequestID, awsxray.AWSRequestIDAttribute, attrs)
+	addString(aws.QueueURL, awsxray.AWSQueueURLAttribute, attrs)
+	addString(aws.TableName, awsxray.AWSTableNameAttribute, attrs)
+	if aws.Retries != nil {
+		attrs.UpsertInt(awsxray.AWSXRayRetriesAttribute, int64(*aws.Retries))
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+// addCause sets the status of the span from the fault, error and throttle flags, and
+// records the exceptions of the `cause` field as exception events.
+func addCause(seg *awsxray.Segment, span pdata.Span) {
+	isFault := seg.Fault != nil && *seg.Fault
+	isError := seg.Error != nil && *seg.Error
+	isThrottle := seg.Throttle != nil && *seg.Throttle
+	if !isFault && !isError && !isThrottle && seg.Cause == nil {
+		return
+	}
+
+	var httpStatus int64
+	if seg.HTTP != nil && seg.HTTP.Response != nil && seg.HTTP.Response.Status != nil {
+		httpStatus = *seg.HTTP.Response.Status
+	}
+
+	var code int32
+	switch {
+	case isFault:
+		// a server side error.
+		code = tracetranslator.OCInternal
+		if httpStatus >= 500 && httpStatus < 600 {
+			code = tracetranslator.OCStatusCodeFromHTTP(int32(httpStatus))
+		}
+	case isThrottle:
+		code = tracetranslator.OCResourceExhausted
+	case isError:
+		// a client side error.
+		code = tracetranslator.OCInvalidArgument
+		if httpStatus >= 400 && httpStatus < 500 {
+			code = tracetranslator.OCStatusCodeFromHTTP(int32(httpStatus))
+		}
+	}
+	if code != tracetranslator.OCOK {
+		span.Status().InitEmpty()
+		span.Status().SetCode(pdata.StatusCode(code))
+	}
+
+	// when the cause is an exception ID, the exception has been recorded by another
+	// segment of the trace.
+	if seg.Cause == nil || seg.Cause.Type != awsxray.CauseTypeObject {
+		return
+	}
+
+	timestamp := span.EndTime()
+	if timestamp == 0 {
+		// in progress segment.
+		timestamp = span.StartTime()
+	}
+
+	events := span.Events()
+	events.Resize(len(seg.Cause.Exceptions))
+	for i, excp := range seg.Cause.Exceptions {
+		event := events.At(i)
+		event.SetName(conventions.AttributeExceptionEventName)
+		event.SetTimestamp(timestamp)
+
+		attrs := event.Attributes()
+		attrs.InitEmptyWithCapacity(3)
+		addString(excp.Type, conventions.AttributeExceptionType, attrs)
+		addString(excp.Message, conventions.AttributeExceptionMessage, attrs)
+		if len(excp.Stack) > 0 {
+			attrs.UpsertString(conventions.AttributeExceptionStacktrace, stackTrace(excp))
+		}
+	}
+}
+
+// stackTrace formats the stack frames of an exception like a Java stack trace, the
+// format parsed by the X-Ray exporter.
+func stackTrace(excp awsxray.Exception) string {
+	var b strings.Builder
+	if excp.Type != nil {
+		b.WriteString(*excp.Type)
+	}
+	if excp.Message != nil {
+		b.WriteString(": ")
+		b.WriteString(*excp.Message)
+	}
+	b.WriteString("\n")
+	for _, frame := range excp.Stack {
+		b.WriteString("\tat ")
+		if frame.Label != nil {
+			b.WriteString(*frame.Label)
+		}
+		b.WriteString("(")
+		if frame.Path != nil {
+			b.WriteString(*frame.Path)
+		}
+		if frame.Line != nil {
+			b.WriteString(":")
+			b.WriteString(strconv.Itoa(*frame.Line))
+		}
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+func addHTTP(seg *awsxray.Segment, span pdata.Span) {
+	if seg.HTTP == nil {
+		return
+	}
+	attrs := span.Attributes()
+
+	if req := seg.HTTP.Request; req != nil {
+		addString(req.Method, conventions.AttributeHTTPMethod, attrs)
+		addString(req.URL, conventions.AttributeHTTPURL, attrs)
+		addString(req.UserAgent, conventions.AttributeHTTPUserAgent, attrs)
+
+		if req.XForwardedFor != nil && *req.XForwardedFor {
+			// the IP address comes from the X-Forwarded-For header and is not the
+			// address of the peer.
+			addString(req.ClientIP, conventions.AttributeHTTPClientIP, attrs)
+			attrs.UpsertBool(awsxray.AWSXRayXForwardedForAttribute, true)
+		} else {
+			addString(req.ClientIP, conventions.AttributeNetPeerIP, attrs)
+		}
+	}
+
+	if resp := seg.HTTP.Response; resp != nil {
+		addInt64(resp.Status, conventions.AttributeHTTPStatusCode, attrs)
+		addInt64(resp.ContentLength, conventions.AttributeHTTPResponseContentLength, attrs)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+// reSQLURL splits the `url` of the `sql` field, protocol://host[:port]/database, into
+// the connection string and the database name.
+var reSQLURL = regexp.MustCompile(`^(.+://[^/]+)/([^/?]+)$`)
+
+func addSQLToSpan(sql *awsxray.SQLData, attrs pdata.AttributeMap) error {
+	if sql == nil {
+		return nil
+	}
+
+	if sql.URL != nil {
+		matches := reSQLURL.FindStringSubmatch(*sql.URL)
+		if matches == nil {
+			return fmt.Errorf(`failed to parse out the database name in the "sql.url" field, rawUrl: %s`, *sql.URL)
+		}
+		attrs.UpsertString(conventions.AttributeDBConnectionString, matches[1])
+		attrs.UpsertString(conventions.AttributeDBName, matches[2])
+	}
+	if sql.DatabaseType != nil {
+		// db.system values are lowercase, e.g. postgresql for PostgreSQL.
+		attrs.UpsertString(conventions.AttributeDBSystem, strings.ToLower(*sql.DatabaseType))
+	}
+	addString(sql.SanitizedQuery, conventions.AttributeDBStatement, attrs)
+	addString(sql.User, conventions.AttributeDBUser, attrs)
+	return nil
+}
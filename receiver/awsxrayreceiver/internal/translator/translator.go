@@ -0,0 +1,270 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+const (
+	// validAWSNamespace is the namespace of subsegments representing calls to AWS services.
+	validAWSNamespace = "aws"
+	// validRemoteNamespace is the namespace of subsegments representing calls to other services.
+	validRemoteNamespace = "remote"
+
+	// subsegmentType is the `type` of independent subsegments.
+	subsegmentType = "subsegment"
+
+	// initAttrCapacity avoids reallocations of the attribute maps for most segments.
+	initAttrCapacity = 15
+)
+
+// ToTraces converts an X-Ray segment document, and all its embedded subsegments, to
+// OT traces. All the spans share a single resource built from the segment-level fields.
+// The returned count is the number of segments in the document, including embedded
+// subsegments, and is meant for the observability of the receiver whether the
+// conversion succeeds or not.
+func ToTraces(rawSeg []byte) (*pdata.Traces, int, error) {
+	var seg awsxray.Segment
+	err := json.Unmarshal(rawSeg, &seg)
+	if err != nil {
+		// the document can not be parsed so it is counted as a single segment.
+		return nil, 1, err
+	}
+	count := totalSegmentsCount(seg)
+
+	err = seg.Validate()
+	if err != nil {
+		return nil, count, err
+	}
+
+	traceID, err := toTraceID(*seg.TraceID)
+	if err != nil {
+		return nil, count, err
+	}
+
+	traces := pdata.NewTraces()
+	rspans := traces.ResourceSpans()
+	rspans.Resize(1)
+	rspan := rspans.At(0)
+
+	resource := rspan.Resource()
+	resource.InitEmpty()
+	populateResource(&seg, resource.Attributes())
+
+	// a segment document, with all its subsegments, is emitted by a single instrumentation
+	// library.
+	rspan.InstrumentationLibrarySpans().Resize(1)
+	spans := rspan.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(count)
+
+	var parentID pdata.SpanID
+	if seg.ParentID != nil {
+		if parentID, err = toSpanID(*seg.ParentID); err != nil {
+			return nil, count, err
+		}
+	}
+	isSubsegment := seg.Type != nil && *seg.Type == subsegmentType
+	if _, err = segToSpans(&seg, isSubsegment, traceID, parentID, spans, 0); err != nil {
+		return nil, count, err
+	}
+
+	return &traces, count, nil
+}
+
+// segToSpans populates the span at index next with seg, then recursively the spans
+// following it with the embedded subsegments of seg. Embedded subsegments do not carry
+// the trace ID nor the parent ID, they are taken from the enclosing segment. It returns
+// the index of the next span to populate.
+func segToSpans(seg *awsxray.Segment, isSubsegment bool, traceID pdata.TraceID, parentID pdata.SpanID,
+	spans pdata.SpanSlice, next int) (int, error) {
+	span := spans.At(next)
+	if err := populateSpan(seg, isSubsegment, traceID, parentID, span); err != nil {
+		return 0, err
+	}
+	next++
+
+	for i := range seg.Subsegments {
+		var err error
+		next, err = segToSpans(&seg.Subsegments[i], true, traceID, span.SpanID(), spans, next)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return next, nil
+}
+
+func populateSpan(seg *awsxray.Segment, isSubsegment bool, traceID pdata.TraceID, parentID pdata.SpanID,
+	span pdata.Span) error {
+	if err := validateSubsegment(seg); err != nil {
+		return err
+	}
+
+	spanID, err := toSpanID(*seg.ID)
+	if err != nil {
+		return err
+	}
+
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentID)
+	span.SetName(*seg.Name)
+	span.SetStartTime(toTimestamp(*seg.StartTime))
+
+	attrs := span.Attributes()
+	attrs.InitEmptyWithCapacity(initAttrCapacity)
+
+	inProgress := seg.InProgress != nil && *seg.InProgress
+	if inProgress {
+		// the segment has no end time yet.
+		attrs.UpsertBool(awsxray.AWSXRayInProgressAttribute, true)
+	} else {
+		span.SetEndTime(toTimestamp(*seg.EndTime))
+	}
+
+	switch {
+	case !isSubsegment:
+		span.SetKind(pdata.SpanKindSERVER)
+	case seg.Namespace == nil:
+		span.SetKind(pdata.SpanKindINTERNAL)
+	case *seg.Namespace == validAWSNamespace:
+		span.SetKind(pdata.SpanKindCLIENT)
+		attrs.UpsertString(awsxray.AWSServiceAttribute, *seg.Name)
+	case *seg.Namespace == validRemoteNamespace:
+		span.SetKind(pdata.SpanKindCLIENT)
+		attrs.UpsertString(conventions.AttributePeerService, *seg.Name)
+	default:
+		return fmt.Errorf("unexpected namespace: %s", *seg.Namespace)
+	}
+
+	addString(seg.User, conventions.AttributeEnduserID, attrs)
+	addString(seg.ResourceARN, awsxray.AWSXRayResourceARNAttribute, attrs)
+	addBool(seg.Traced, awsxray.AWSXRayTracedAttribute, attrs)
+
+	addHTTP(seg, span)
+	addAWSToSpan(seg.AWS, attrs)
+	if err = addSQLToSpan(seg.SQL, attrs); err != nil {
+		return err
+	}
+	addCause(seg, span)
+	addAnnotations(seg.Annotations, attrs)
+	if err = addMetadata(seg.Metadata, attrs); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSubsegment checks the fields required by embedded subsegments as well as
+// segments, awsxray.Segment.Validate requiring fields only present on the latter.
+func validateSubsegment(seg *awsxray.Segment) error {
+	if seg.Name == nil {
+		return errors.New(`segment "name" can not be nil`)
+	}
+	if seg.ID == nil {
+		return errors.New(`segment "id" can not be nil`)
+	}
+	if seg.StartTime == nil {
+		return errors.New(`segment "start_time" can not be nil`)
+	}
+	if seg.EndTime == nil && (seg.InProgress == nil || !*seg.InProgress) {
+		return errors.New(`segment "end_time" can not be nil unless "in_progress" is true`)
+	}
+	return nil
+}
+
+// populateResource sets the attributes shared by the segment and its embedded subsegments.
+func populateResource(seg *awsxray.Segment, attrs pdata.AttributeMap) {
+	attrs.InitEmptyWithCapacity(initAttrCapacity)
+
+	// the name of a segment is the name of the service that emitted it, it is
+	// not the case of independent subsegments.
+	if seg.Type == nil || *seg.Type != subsegmentType {
+		attrs.UpsertString(conventions.AttributeServiceName, *seg.Name)
+	}
+	if seg.Service != nil {
+		addString(seg.Service.Version, conventions.AttributeServiceVersion, attrs)
+	}
+	addAWSToResource(seg.AWS, attrs)
+}
+
+// totalSegmentsCount returns the number of segments in a segment document, including
+// all the embedded subsegments.
+func totalSegmentsCount(seg awsxray.Segment) int {
+	count := 1
+	for _, s := range seg.Subsegments {
+		count += totalSegmentsCount(s)
+	}
+	return count
+}
+
+// toTraceID converts an X-Ray trace ID, e.g. 1-58406520-a006649127e371903a2de979, to
+// an OT trace ID made of the epoch and the 96-bit identifier.
+func toTraceID(traceID string) (pdata.TraceID, error) {
+	parts := strings.Split(traceID, "-")
+	if len(parts) != 3 || parts[0] != "1" || len(parts[1]) != 8 || len(parts[2]) != 24 {
+		return nil, fmt.Errorf("invalid X-Ray trace ID: %s", traceID)
+	}
+	id, err := hex.DecodeString(parts[1] + parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Ray trace ID: %s: %w", traceID, err)
+	}
+	return id, nil
+}
+
+// toSpanID converts the 16 hexadecimal digits of an X-Ray segment ID to an OT span ID.
+func toSpanID(segmentID string) (pdata.SpanID, error) {
+	if len(segmentID) != 16 {
+		return nil, fmt.Errorf("invalid X-Ray segment ID: %s", segmentID)
+	}
+	id, err := hex.DecodeString(segmentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid X-Ray segment ID: %s: %w", segmentID, err)
+	}
+	return id, nil
+}
+
+// toTimestamp converts the epoch seconds of X-Ray segments to a timestamp.
+func toTimestamp(seconds float64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(seconds * float64(time.Second))
+}
+
+func addString(val *string, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertString(key, *val)
+	}
+}
+
+func addBool(val *bool, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertBool(key, *val)
+	}
+}
+
+func addInt64(val *int64, key string, attrs pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertInt(key, *val)
+	}
+}
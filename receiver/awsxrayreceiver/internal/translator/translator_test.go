@@ -0,0 +1,410 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+
+	exptranslator "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+)
+
+// the raw segments are shared with the tests of the segment model.
+var testdataDir = path.Join("..", "..", "..", "..", "internal", "common", "awsxray", "testdata")
+
+func readSegment(t *testing.T, name string) []byte {
+	content, err := ioutil.ReadFile(path.Join(testdataDir, name))
+	require.NoError(t, err)
+	return content
+}
+
+// spansByID returns the resource and the spans of traces, indexed by the X-Ray segment ID.
+func spansByID(t *testing.T, traces *pdata.Traces) (pdata.Resource, map[string]pdata.Span) {
+	require.Equal(t, 1, traces.ResourceSpans().Len())
+	rspan := traces.ResourceSpans().At(0)
+	require.Equal(t, 1, rspan.InstrumentationLibrarySpans().Len())
+	spans := rspan.InstrumentationLibrarySpans().At(0).Spans()
+
+	byID := map[string]pdata.Span{}
+	for i := 0; i < spans.Len(); i++ {
+		byID[hex.EncodeToString(spans.At(i).SpanID())] = spans.At(i)
+	}
+	require.Len(t, byID, spans.Len(), "span IDs must be unique")
+	return rspan.Resource(), byID
+}
+
+func assertAttributes(t *testing.T, expected map[string]pdata.AttributeValue, attrs pdata.AttributeMap) {
+	for k, v := range expected {
+		actual, ok := attrs.Get(k)
+		if assert.True(t, ok, "missing attribute %q", k) {
+			assert.True(t, v.Equal(actual), "attribute %q: expected %v, got %v", k, v, actual)
+		}
+	}
+}
+
+func TestTranslateInstrumentedApp(t *testing.T) {
+	traces, count, err := ToTraces(readSegment(t, "ddbSample.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, 18, count)
+	assert.Equal(t, 18, traces.SpanCount())
+
+	resource, spans := spansByID(t, traces)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeServiceName:         pdata.NewAttributeValueString("DDB"),
+		conventions.AttributeTelemetrySDKName:    pdata.NewAttributeValueString("X-Ray for Go"),
+		conventions.AttributeTelemetrySDKVersion: pdata.NewAttributeValueString("1.1.0"),
+	}, resource.Attributes())
+
+	root := spans["88ad1df59cd7a7be"]
+	assert.Equal(t, "DDB", root.Name())
+	assert.Equal(t, pdata.SpanKindSERVER, root.Kind())
+	assert.Equal(t, "5f29ab21d4ebf299219a65bd5c31d6da", hex.EncodeToString(root.TraceID()))
+	assert.Empty(t, root.ParentSpanID())
+	assert.Equal(t, pdata.TimestampUnixNano(1596566305535414000), root.StartTime()/1000*1000)
+	assert.Equal(t, pdata.StatusCode(tracetranslator.OCInternal), root.Status().Code())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeEnduserID: pdata.NewAttributeValueString("xraysegmentdump"),
+	}, root.Attributes())
+
+	require.Equal(t, 1, root.Events().Len())
+	event := root.Events().At(0)
+	assert.Equal(t, conventions.AttributeExceptionEventName, event.Name())
+	assert.Equal(t, root.EndTime(), event.Timestamp())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeExceptionType:    pdata.NewAttributeValueString("dynamodb.ResourceNotFoundException"),
+		conventions.AttributeExceptionMessage: pdata.NewAttributeValueString("ResourceNotFoundException: Requested resource not found"),
+		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString(
+			"dynamodb.ResourceNotFoundException: ResourceNotFoundException: Requested resource not found\n" +
+				"\tat main(runtime/proc.go:203)\n" +
+				"\tat goexit(runtime/asm_amd64.s:1373)\n"),
+	}, event.Attributes())
+
+	internal := spans["7df694142c905d8d"]
+	assert.Equal(t, pdata.SpanKindINTERNAL, internal.Kind())
+	assert.Equal(t, root.SpanID(), internal.ParentSpanID())
+	assert.Equal(t, root.TraceID(), internal.TraceID())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"DDB.DescribeExistingTableAndPutToMissingTable.Annotation":                                                   pdata.NewAttributeValueString("anno"),
+		awsxray.AWSXRayMetadataAttributePrefix + "default.DDB.DescribeExistingTableAndPutToMissingTable.AddMetadata": pdata.NewAttributeValueString(`"meta"`),
+	}, internal.Attributes())
+
+	ddb := spans["7318c46a385557f5"]
+	assert.Equal(t, pdata.SpanKindCLIENT, ddb.Kind())
+	assert.Equal(t, internal.SpanID(), ddb.ParentSpanID())
+	assert.True(t, ddb.Status().IsNil())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		awsxray.AWSServiceAttribute:                    pdata.NewAttributeValueString("dynamodb"),
+		awsxray.AWSOperationAttribute:                  pdata.NewAttributeValueString("DescribeTable"),
+		awsxray.AWSRegionAttribute:                     pdata.NewAttributeValueString("us-west-2"),
+		awsxray.AWSRequestIDAttribute:                  pdata.NewAttributeValueString("29P5V7QSAKHS4LNL56ECAJFF3BVV4KQNSO5AEMVJF66Q9ASUAAJG"),
+		awsxray.AWSTableNameAttribute:                  pdata.NewAttributeValueString("xray_sample_table"),
+		awsxray.AWSXRayRetriesAttribute:                pdata.NewAttributeValueInt(0),
+		conventions.AttributeHTTPStatusCode:            pdata.NewAttributeValueInt(200),
+		conventions.AttributeHTTPResponseContentLength: pdata.NewAttributeValueInt(713),
+	}, ddb.Attributes())
+
+	connect := spans["417b81b977b9563b"]
+	assert.Equal(t, "connect", connect.Name())
+	assert.Equal(t, spans["23cf5bb60e4f66b1"].SpanID(), connect.ParentSpanID())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		awsxray.AWSXRayMetadataAttributePrefix + "http.connection": pdata.NewAttributeValueString(`{"reused":false,"was_idle":false}`),
+	}, connect.Attributes())
+
+	putItem := spans["71631df3f58bdfc5"]
+	assert.Equal(t, pdata.StatusCode(tracetranslator.OCInternal), putItem.Status().Code())
+	assert.Equal(t, 1, putItem.Events().Len())
+}
+
+func TestTranslateServer(t *testing.T) {
+	traces, count, err := ToTraces(readSegment(t, "serverSample.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, spans := spansByID(t, traces)
+	span := spans["bda182a644eee9b3"]
+	assert.Equal(t, pdata.SpanKindSERVER, span.Kind())
+	assert.True(t, span.Status().IsNil())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeHTTPMethod:       pdata.NewAttributeValueString("GET"),
+		conventions.AttributeHTTPURL:          pdata.NewAttributeValueString("http://localhost:8000/"),
+		conventions.AttributeHTTPClientIP:     pdata.NewAttributeValueString("127.0.0.1"),
+		conventions.AttributeHTTPUserAgent:    pdata.NewAttributeValueString("Go-http-client/1.1"),
+		conventions.AttributeHTTPStatusCode:   pdata.NewAttributeValueInt(200),
+		awsxray.AWSXRayXForwardedForAttribute: pdata.NewAttributeValueBool(true),
+	}, span.Attributes())
+	_, ok := span.Attributes().Get(conventions.AttributeNetPeerIP)
+	assert.False(t, ok)
+}
+
+func TestTranslateAWSFields(t *testing.T) {
+	traces, _, err := ToTraces(readSegment(t, "awsValidAwsFields.txt"))
+	require.NoError(t, err)
+
+	resource, spans := spansByID(t, traces)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeCloudProvider:    pdata.NewAttributeValueString("aws"),
+		conventions.AttributeHostID:           pdata.NewAttributeValueString("i-075ad396f12bc325a"),
+		conventions.AttributeCloudZone:        pdata.NewAttributeValueString("us-west-2c"),
+		conventions.AttributeHostType:         pdata.NewAttributeValueString("m5.xlarge"),
+		conventions.AttributeHostImageID:      pdata.NewAttributeValueString("ami-003634241a8fcdec0"),
+		conventions.AttributeContainerName:    pdata.NewAttributeValueString("containerId1234"),
+		conventions.AttributeServiceNamespace: pdata.NewAttributeValueString("scorekeep"),
+		conventions.AttributeServiceInstance:  pdata.NewAttributeValueString("32"),
+		conventions.AttributeServiceVersion:   pdata.NewAttributeValueString("app-5a56-170119_190650-stage-170119_190650"),
+		conventions.AttributeTelemetrySDKName: pdata.NewAttributeValueString("X-Ray for Go"),
+		conventions.AttributeServiceName:      pdata.NewAttributeValueString("SampleServer"),
+	}, resource.Attributes())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		awsxray.AWSAccountAttribute: pdata.NewAttributeValueString("620297135128"),
+	}, spans["bda182a644eee9b3"].Attributes())
+}
+
+func TestTranslateIndependentSubsegment(t *testing.T) {
+	traces, count, err := ToTraces(readSegment(t, "indepSubsegment.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	resource, spans := spansByID(t, traces)
+	_, ok := resource.Attributes().Get(conventions.AttributeServiceName)
+	assert.False(t, ok, "the name of a subsegment is not a service name")
+
+	span := spans["53995c3f42cd8ad8"]
+	assert.Equal(t, pdata.SpanKindCLIENT, span.Kind())
+	assert.Equal(t, "581cf771a006649127e371903a2de979", hex.EncodeToString(span.TraceID()))
+	assert.Equal(t, "defdfd9912dc5a56", hex.EncodeToString(span.ParentSpanID()))
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributePeerService:               pdata.NewAttributeValueString("api.example.com"),
+		conventions.AttributeHTTPMethod:                pdata.NewAttributeValueString("POST"),
+		conventions.AttributeHTTPURL:                   pdata.NewAttributeValueString("https://api.example.com/health"),
+		conventions.AttributeHTTPResponseContentLength: pdata.NewAttributeValueInt(861),
+		awsxray.AWSXRayTracedAttribute:                 pdata.NewAttributeValueBool(true),
+	}, span.Attributes())
+}
+
+func TestTranslateSQL(t *testing.T) {
+	traces, _, err := ToTraces(readSegment(t, "indepSubsegmentWithSql.txt"))
+	require.NoError(t, err)
+
+	_, spans := spansByID(t, traces)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		conventions.AttributeDBConnectionString: pdata.NewAttributeValueString("jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432"),
+		conventions.AttributeDBName:             pdata.NewAttributeValueString("ebdb"),
+		conventions.AttributeDBSystem:           pdata.NewAttributeValueString("postgresql"),
+		conventions.AttributeDBUser:             pdata.NewAttributeValueString("dbuser"),
+		conventions.AttributeDBStatement:        pdata.NewAttributeValueString("SELECT  *  FROM  customers  WHERE  customer_id=?;"),
+	}, spans["3fd8634e78ca9560"].Attributes())
+}
+
+func TestTranslateMinimalSegments(t *testing.T) {
+	traces, _, err := ToTraces(readSegment(t, "minInProgress.txt"))
+	require.NoError(t, err)
+	_, spans := spansByID(t, traces)
+	span := spans["5cc4a447f5d4d696"]
+	assert.Zero(t, span.EndTime())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		awsxray.AWSXRayInProgressAttribute: pdata.NewAttributeValueBool(true),
+	}, span.Attributes())
+
+	traces, _, err = ToTraces(readSegment(t, "minOtherFields.txt"))
+	require.NoError(t, err)
+	_, spans = spansByID(t, traces)
+	span = spans["5cc4a447f5d4d696"]
+	assert.Equal(t, pdata.SpanKindINTERNAL, span.Kind())
+	assert.Equal(t, pdata.StatusCode(tracetranslator.OCResourceExhausted), span.Status().Code())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		awsxray.AWSXRayResourceARNAttribute: pdata.NewAttributeValueString("chicken"),
+	}, span.Attributes())
+
+	traces, _, err = ToTraces(readSegment(t, "minCauseIsExceptionId.txt"))
+	require.NoError(t, err)
+	_, spans = spansByID(t, traces)
+	span = spans["5cc4a447f5d4d696"]
+	assert.Equal(t, pdata.StatusCode(tracetranslator.OCInternal), span.Status().Code())
+	assert.Equal(t, 0, span.Events().Len())
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		file          string
+		expectedCount int
+		expectedErr   string
+	}{
+		{
+			file:          "minCauseIsInvalid.txt",
+			expectedCount: 1,
+			expectedErr:   "the value assigned to the `cause` field does not appear to be a string",
+		},
+		{
+			file:          "segmentValidationFailed.txt",
+			expectedCount: 1,
+			expectedErr:   `segment "start_time" can not be nil`,
+		},
+		{
+			file:          "invalidNamespace.txt",
+			expectedCount: 18,
+			expectedErr:   "unexpected namespace: invalidNs",
+		},
+		{
+			file:          "indepSubsegmentWithInvalidSqlUrl.txt",
+			expectedCount: 1,
+			expectedErr:   `failed to parse out the database name in the "sql.url" field`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			traces, count, err := ToTraces(readSegment(t, tt.file))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+			assert.Equal(t, tt.expectedCount, count)
+			assert.Nil(t, traces)
+		})
+	}
+}
+
+func TestTranslateInvalidIDs(t *testing.T) {
+	_, _, err := ToTraces([]byte(`{"trace_id": "1-5f2aebcc-b475d14618c51eaa", "id": "bda182a644eee9b3", "name": "a", "start_time": 1, "end_time": 2}`))
+	assert.EqualError(t, err, "invalid X-Ray trace ID: 1-5f2aebcc-b475d14618c51eaa")
+
+	_, _, err = ToTraces([]byte(`{"trace_id": "1-5f2aebcc-b475d14618c51eaa28753d37", "id": "bda182a644eee9", "name": "a", "start_time": 1, "end_time": 2}`))
+	assert.EqualError(t, err, "invalid X-Ray segment ID: bda182a644eee9")
+
+	_, _, err = ToTraces([]byte(`{"trace_id": "1-5f2aebcc-b475d14618c51eaa28753d37", "id": "bda182a644eee9b3", "name": "a", "start_time": 1}`))
+	assert.EqualError(t, err, `segment "end_time" can not be nil unless "in_progress" is true`)
+
+	_, count, err := ToTraces([]byte(`{"trace_id": "1-5f2aebcc-b475d14618c51eaa28753d37", "id": "bda182a644eee9b3", "name": "a", "start_time": 1, "end_time": 2, "subsegments": [{"name": "b", "start_time": 1, "end_time": 2}]}`))
+	assert.EqualError(t, err, `segment "id" can not be nil`)
+	assert.Equal(t, 2, count)
+}
+
+// flatten indexes a segment and all its embedded subsegments by ID, along with their
+// parent ID: embedded subsegments get it from the enclosing segment.
+func flatten(seg *awsxray.Segment, parentID string, byID map[string]*awsxray.Segment, parents map[string]string) {
+	byID[*seg.ID] = seg
+	parents[*seg.ID] = parentID
+	for i := range seg.Subsegments {
+		flatten(&seg.Subsegments[i], *seg.ID, byID, parents)
+	}
+}
+
+// TestRoundTrip converts the spans of a segment document back to X-Ray segments with
+// the X-Ray exporter and checks that the fields supported by both ends are preserved.
+func TestRoundTrip(t *testing.T) {
+	files := []string{
+		"ddbSample.txt",
+		"serverSample.txt",
+		"awsValidAwsFields.txt",
+		"indepSubsegment.txt",
+		"indepSubsegmentWithSql.txt",
+	}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			raw := readSegment(t, file)
+			traces, _, err := ToTraces(raw)
+			require.NoError(t, err)
+
+			var doc awsxray.Segment
+			require.NoError(t, json.Unmarshal(raw, &doc))
+			originals := map[string]*awsxray.Segment{}
+			parents := map[string]string{}
+			flatten(&doc, aws.StringValue(doc.ParentID), originals, parents)
+
+			resource, spans := spansByID(t, traces)
+			require.Len(t, spans, len(originals))
+			for id, span := range spans {
+				orig := originals[id]
+				require.NotNil(t, orig, "unexpected span %s", id)
+				seg := exptranslator.MakeSegment(span, resource)
+
+				assert.Equal(t, *orig.ID, *seg.ID)
+				// the exporter replaces the epoch of trace IDs older than 28 days so only
+				// the identifier is compared.
+				assert.Equal(t, (*doc.TraceID)[11:], (*seg.TraceID)[11:])
+				assert.Equal(t, *orig.Name, *seg.Name)
+				assert.Equal(t, parents[id], *seg.ParentID)
+				assert.InDelta(t, *orig.StartTime, *seg.StartTime, 1e-6)
+				assert.InDelta(t, aws.Float64Value(orig.EndTime), *seg.EndTime, 1e-6)
+				assert.Equal(t, aws.StringValue(orig.Namespace), *seg.Namespace)
+				assert.Equal(t, aws.StringValue(orig.User), *seg.User)
+				assert.Equal(t, aws.BoolValue(orig.Fault), *seg.Fault)
+				assert.Equal(t, aws.BoolValue(orig.Error), *seg.Error)
+
+				if orig.Cause != nil && orig.Cause.Type == awsxray.CauseTypeObject {
+					require.NotNil(t, seg.Cause)
+					require.Len(t, seg.Cause.Exceptions, len(orig.Cause.Exceptions))
+					for i, excp := range orig.Cause.Exceptions {
+						assert.Equal(t, *excp.Type, *seg.Cause.Exceptions[i].Type)
+						assert.Equal(t, *excp.Message, *seg.Cause.Exceptions[i].Message)
+					}
+				}
+
+				if orig.HTTP != nil {
+					require.NotNil(t, seg.HTTP)
+					if req := orig.HTTP.Request; req != nil {
+						assert.Equal(t, req.Method, seg.HTTP.Request.Method)
+						assert.Equal(t, req.URL, seg.HTTP.Request.URL)
+						assert.Equal(t, req.UserAgent, seg.HTTP.Request.UserAgent)
+						assert.Equal(t, req.ClientIP, seg.HTTP.Request.ClientIP)
+					}
+					if resp := orig.HTTP.Response; resp != nil {
+						assert.Equal(t, resp.Status, seg.HTTP.Response.Status)
+					}
+				}
+
+				if orig.AWS != nil {
+					require.NotNil(t, seg.AWS)
+					assert.Equal(t, aws.StringValue(orig.AWS.AccountID), *seg.AWS.AccountID)
+					assert.Equal(t, aws.StringValue(orig.AWS.Operation), *seg.AWS.Operation)
+					assert.Equal(t, aws.StringValue(orig.AWS.RemoteRegion), *seg.AWS.RemoteRegion)
+					assert.Equal(t, aws.StringValue(orig.AWS.RequestID), *seg.AWS.RequestID)
+					assert.Equal(t, aws.StringValue(orig.AWS.TableName), *seg.AWS.TableName)
+					assert.Equal(t, orig.AWS.EC2, seg.AWS.EC2)
+					assert.Equal(t, orig.AWS.ECS, seg.AWS.ECS)
+					assert.Equal(t, orig.AWS.Beanstalk, seg.AWS.Beanstalk)
+					if orig.AWS.XRay != nil {
+						assert.Equal(t, orig.AWS.XRay.SDK, seg.AWS.XRay.SDK)
+						assert.Equal(t, orig.AWS.XRay.SDKVersion, seg.AWS.XRay.SDKVersion)
+					}
+				}
+
+				if orig.SQL != nil {
+					require.NotNil(t, seg.SQL)
+					assert.Equal(t, orig.SQL.URL, seg.SQL.URL)
+					assert.Equal(t, orig.SQL.User, seg.SQL.User)
+					assert.Equal(t, orig.SQL.SanitizedQuery, seg.SQL.SanitizedQuery)
+					assert.True(t, strings.EqualFold(*orig.SQL.DatabaseType, *seg.SQL.DatabaseType))
+				}
+
+				for k, v := range orig.Annotations {
+					// the exporter replaces the characters X-Ray does not allow in keys.
+					key := strings.ReplaceAll(k, ".", "_")
+					assert.Equal(t, v, seg.Annotations[key], "annotation %q", k)
+				}
+			}
+		})
+	}
+}
@@ -16,15 +16,19 @@ package awsxrayreceiver
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
 
@@ -39,6 +43,8 @@ const (
 type xrayReceiver struct {
 	instanceName string
 	poller       udppoller.Poller
+	proxyConfig  *proxy.Config
+	server       proxy.Server
 	logger       *zap.Logger
 	consumer     consumer.TraceConsumer
 	longLivedCtx context.Context
@@ -72,6 +78,7 @@ func newReceiver(config *Config,
 	return &xrayReceiver{
 		instanceName: config.Name(),
 		poller:       poller,
+		proxyConfig:  config.ProxyServer,
 		logger:       logger,
 		consumer:     consumer,
 	}, nil
@@ -81,29 +88,81 @@ func (x *xrayReceiver) Start(ctx context.Context, host component.Host) error {
 	// TODO: Might want to pass `host` into read() below to report a fatal error
 	var err = componenterror.ErrAlreadyStarted
 	x.startOnce.Do(func() {
+		// the proxy server is created when starting as resolving the region may
+		// query the EC2 instance metadata.
+		if err = x.createProxyServer(); err != nil {
+			return
+		}
+
 		x.longLivedCtx = obsreport.ReceiverContext(ctx, x.instanceName, udppoller.Transport, "")
 		x.poller.Start(x.longLivedCtx)
 		go x.start()
+		if x.server != nil {
+			go func() {
+				if err := x.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					host.ReportFatalError(err)
+				}
+			}()
+		}
 		err = nil
 	})
 	return err
 }
 
-func (x *xrayReceiver) Shutdown(_ context.Context) error {
+// createProxyServer creates the proxy server, unless the configuration has no
+// proxy_server as configured in tests. The proxy server is disabled when the
+// region can not be resolved, so that segments are still received outside of AWS.
+func (x *xrayReceiver) createProxyServer() error {
+	if x.proxyConfig == nil {
+		return nil
+	}
+
+	server, err := proxy.NewServer(x.proxyConfig, x.logger)
+	if errors.Is(err, proxy.ErrNoRegion) {
+		x.logger.Warn("Unable to resolve the AWS region, the X-Ray proxy server is disabled", zap.Error(err))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	x.server = server
+	return nil
+}
+
+func (x *xrayReceiver) Shutdown(ctx context.Context) error {
 	var err = componenterror.ErrAlreadyStopped
 	x.stopOnce.Do(func() {
-		err = x.poller.Close()
+		var errs []error
+		if x.server != nil {
+			if serverErr := x.server.Shutdown(ctx); serverErr != nil {
+				errs = append(errs, serverErr)
+			}
+		}
+		if pollerErr := x.poller.Close(); pollerErr != nil {
+			errs = append(errs, pollerErr)
+		}
+		err = componenterror.CombineErrors(errs)
 	})
 	return err
 }
 
 func (x *xrayReceiver) start() {
 	incomingSegments := x.poller.SegmentsChan()
-	for range incomingSegments {
-		// TODO: Transform an incoming segment to consumer.ConsumeTraceData. For now
-		// we are just dropping the ingested X-Ray segments.
-		// TODO: use the context generated by obsreport.StartMetricsReceiveOp
-		x.consumer.ConsumeTraces(context.Background(), pdata.NewTraces())
-		// TODO: emit metrics here to indicate segment received
+	for seg := range incomingSegments {
+		ctx := obsreport.StartTraceDataReceiveOp(
+			x.longLivedCtx,
+			x.instanceName,
+			udppoller.Transport,
+			obsreport.WithLongLivedCtx())
+
+		traces, totalSpansCount, err := translator.ToTraces(seg.Payload)
+		if err != nil {
+			x.logger.Warn("X-Ray segment to OT traces conversion failed", zap.Error(err))
+			obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, totalSpansCount, err)
+			continue
+		}
+
+		err = x.consumer.ConsumeTraces(ctx, *traces)
+		obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, totalSpansCount, err)
 	}
 }
@@ -18,9 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
@@ -32,9 +39,12 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
 
+const segmentHeader = "{\"format\": \"json\", \"version\": 1}\n"
+
 func TestConsumerCantBeNil(t *testing.T) {
 	addr, err := net.ResolveUDPAddr(udppoller.Transport, "localhost:0")
 	assert.NoError(t, err, "should resolve UDP address")
@@ -140,15 +150,14 @@ func TestCantStopAnInstanceTwice(t *testing.T) {
 	assert.True(t, errors.Is(err, componenterror.ErrAlreadyStopped), "should not stop receiver instance twice")
 }
 
-// TODO: Update this test to assert on the format of traces
-// once the transformation from X-Ray segments -> OTLP is done.
 func TestSegmentsPassedToConsumer(t *testing.T) {
 	addr, rcvr, _ := createAndOptionallyStartReceiver(t, true)
 	defer rcvr.Shutdown(context.Background())
 
-	// valid header with invalid body (for now this is ok because we haven't
-	// implemented the X-Ray segment -> OT format conversion)
-	err := writePacket(t, addr, `{"format": "json", "version": 1}`+"\nBody")
+	content, err := ioutil.ReadFile(path.Join("..", "..", "internal", "common", "awsxray", "testdata", "ddbSample.txt"))
+	assert.NoError(t, err, "can not read raw segment")
+
+	err = writePacket(t, addr, segmentHeader+string(content))
 	assert.NoError(t, err, "can not write packet in the happy case")
 
 	sink := rcvr.(*xrayReceiver).consumer.(*exportertest.SinkTraceExporter)
@@ -157,6 +166,116 @@ func TestSegmentsPassedToConsumer(t *testing.T) {
 		got := sink.AllTraces()
 		return len(got) == 1
 	}, "consumer should eventually get the X-Ray span")
+
+	traces := sink.AllTraces()[0]
+	assert.Equal(t, 18, traces.SpanCount(), "all the segments should be converted to spans")
+}
+
+func TestTranslatorErrorsOut(t *testing.T) {
+	addr, rcvr, recordedLogs := createAndOptionallyStartReceiver(t, true)
+	defer rcvr.Shutdown(context.Background())
+
+	err := writePacket(t, addr, segmentHeader+"invalidSegment")
+	assert.NoError(t, err, "can not write packet in the "+udppoller.Transport+" case")
+
+	testutil.WaitFor(t, func() bool {
+		logs := recordedLogs.All()
+		return len(logs) > 0 && strings.Contains(logs[len(logs)-1].Message,
+			"X-Ray segment to OT traces conversion failed")
+	}, "poller should log warning because consumer errored out")
+
+	sink := rcvr.(*xrayReceiver).consumer.(*exportertest.SinkTraceExporter)
+	assert.Empty(t, sink.AllTraces(), "invalid segments should not be consumed")
+}
+
+func TestProxyServerForwardsRequests(t *testing.T) {
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+	defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+	paths := make(chan string, 1)
+	xray := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+		_, _ = w.Write([]byte(`{"SamplingTargetDocuments":[]}`))
+	}))
+	defer xray.Close()
+
+	udpAddr, err := findAvailableAddress()
+	assert.NoError(t, err, "there should be address available")
+	tcpAddr := testutil.GetAvailableLocalAddress(t)
+
+	rcvr, err := newReceiver(
+		&Config{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  udpAddr,
+				Transport: udppoller.Transport,
+			},
+			ProxyServer: &proxy.Config{
+				TCPAddr:     confignet.TCPAddr{Endpoint: tcpAddr},
+				Region:      "us-west-2",
+				AWSEndpoint: xray.URL,
+				LocalMode:   aws.Bool(true),
+			},
+		},
+		new(exportertest.SinkTraceExporter),
+		zap.NewNop(),
+	)
+	assert.NoError(t, err, "receiver should be created")
+	assert.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	var resp *http.Response
+	testutil.WaitFor(t, func() bool {
+		resp, err = http.Post("http://"+tcpAddr+"/SamplingTargets", "application/json", strings.NewReader("{}"))
+		return err == nil
+	}, "proxy server should eventually accept requests")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/SamplingTargets", <-paths)
+
+	assert.NoError(t, rcvr.Shutdown(context.Background()))
+	_, err = http.Post("http://"+tcpAddr+"/SamplingTargets", "application/json", strings.NewReader("{}"))
+	assert.Error(t, err, "proxy server should be stopped with the receiver")
+}
+
+func TestProxyServerDisabledWithoutRegion(t *testing.T) {
+	os.Unsetenv("AWS_REGION")
+	os.Unsetenv("AWS_DEFAULT_REGION")
+
+	addr, err := findAvailableAddress()
+	assert.NoError(t, err, "there should be address available")
+	logger, recordedLogs := logSetup()
+	rcvr, err := newReceiver(
+		&Config{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  addr,
+				Transport: udppoller.Transport,
+			},
+			ProxyServer: &proxy.Config{
+				TCPAddr:   confignet.TCPAddr{Endpoint: testutil.GetAvailableLocalAddress(t)},
+				LocalMode: aws.Bool(true),
+			},
+		},
+		new(exportertest.SinkTraceExporter),
+		logger,
+	)
+	assert.NoError(t, err, "receiver should be created without a region")
+	assert.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()),
+		"receiver should be started without a region")
+	defer rcvr.Shutdown(context.Background())
+
+	assert.Nil(t, rcvr.(*xrayReceiver).server, "the proxy server should be disabled")
+	assert.Equal(t, 1, recordedLogs.FilterMessage(
+		"Unable to resolve the AWS region, the X-Ray proxy server is disabled").Len())
+
+	content, err := ioutil.ReadFile(path.Join("..", "..", "internal", "common", "awsxray", "testdata", "ddbSample.txt"))
+	assert.NoError(t, err, "can not read raw segment")
+	assert.NoError(t, writePacket(t, addr, segmentHeader+string(content)))
+
+	sink := rcvr.(*xrayReceiver).consumer.(*exportertest.SinkTraceExporter)
+	testutil.WaitFor(t, func() bool {
+		return len(sink.AllTraces()) == 1
+	}, "consumer should eventually get the X-Ray span")
 }
 
 func createAndOptionallyStartReceiver(t *testing.T, start bool) (string, component.TraceReceiver, *observer.ObservedLogs) {
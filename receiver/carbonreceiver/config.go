@@ -37,7 +37,8 @@ type Config struct {
 	confignet.NetAddr `mapstructure:",squash"`
 
 	// TCPIdleTimeout is the timout for idle TCP connections, it is ignored
-	// if transport being used is UDP.
+	// if transport being used is UDP. It also applies to the "pickle"
+	// transport since it is carried over TCP.
 	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
 
 	// Parser specifies a parser and the respective configuration to be used
@@ -38,6 +38,25 @@ type Parser interface {
 	Parse(line string) (*metricspb.Metric, error)
 }
 
+// DataPoint is a Carbon data point that was already split in its parts, as
+// received by protocols that are not line based, e.g. pickle.
+type DataPoint struct {
+	// Path is the <metric_path> of the data point.
+	Path string
+	// Timestamp is the Unix time of when the measurement was made.
+	Timestamp int64
+	// Value of the measurement, either an int64 or a float64.
+	Value interface{}
+}
+
+// PointParser is implemented by parsers that can also transform a DataPoint
+// to the collector metric format, handling its path in the same way that it
+// is handled for plaintext lines.
+type PointParser interface {
+	// ParsePoint transforms the data point to the collector metric format.
+	ParsePoint(dp DataPoint) (*metricspb.Metric, error)
+}
+
 // Below a few helper functions useful to different parsers.
 func buildMetricForSinglePoint(
 	metricName string,
@@ -70,6 +70,7 @@ type PathParserHelper struct {
 }
 
 var _ (Parser) = (*PathParserHelper)(nil)
+var _ (PointParser) = (*PathParserHelper)(nil)
 
 // BuildParser creates a new Parser instance that receives plaintext
 // Carbon data.
@@ -118,36 +119,69 @@ func (pph *PathParserHelper) Parse(line string) (*metricspb.Metric, error) {
 		return nil, fmt.Errorf("invalid carbon metric time [%s]: %v", line, err)
 	}
 
+	var value interface{}
+	intVal, err := strconv.ParseInt(valueStr, 10, 64)
+	if err == nil {
+		value = intVal
+	} else {
+		dblVal, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid carbon metric value [%s]: %v", line, err)
+		}
+		value = dblVal
+	}
+
+	return buildMetricForDataPoint(&parsedPath, unixTime, value), nil
+}
+
+// ParsePoint receives a Carbon data point that was already split in its
+// parts, typically by a protocol that is not line based like pickle, and
+// transforms it to the collector metric format. The path of the data point
+// is handled by the same PathParser used for plaintext lines.
+func (pph *PathParserHelper) ParsePoint(dp DataPoint) (*metricspb.Metric, error) {
+	switch dp.Value.(type) {
+	case int64, float64:
+	default:
+		return nil, fmt.Errorf("invalid carbon metric value [%s]: unsupported type %T", dp.Path, dp.Value)
+	}
+
+	parsedPath := ParsedPath{}
+	err := pph.pathParser.ParsePath(dp.Path, &parsedPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid carbon metric [%s]: %v", dp.Path, err)
+	}
+
+	return buildMetricForDataPoint(&parsedPath, dp.Timestamp, dp.Value), nil
+}
+
+// buildMetricForDataPoint builds the metric for a single point of the given
+// parsed path. The value is expected to be either an int64 or a float64.
+func buildMetricForDataPoint(parsedPath *ParsedPath, unixTime int64, value interface{}) *metricspb.Metric {
 	var metricType metricspb.MetricDescriptor_Type
 	point := metricspb.Point{
 		Timestamp: convertUnixSec(unixTime),
 	}
-	intVal, err := strconv.ParseInt(valueStr, 10, 64)
-	if err == nil {
+	switch v := value.(type) {
+	case int64:
 		if parsedPath.MetricType == CumulativeMetricType {
 			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
 		} else {
 			metricType = metricspb.MetricDescriptor_GAUGE_INT64
 		}
-		point.Value = &metricspb.Point_Int64Value{Int64Value: intVal}
-	} else {
-		dblVal, err := strconv.ParseFloat(valueStr, 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid carbon metric value [%s]: %v", line, err)
-		}
+		point.Value = &metricspb.Point_Int64Value{Int64Value: v}
+	case float64:
 		if parsedPath.MetricType == CumulativeMetricType {
 			metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
 		} else {
 			metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
 		}
-		point.Value = &metricspb.Point_DoubleValue{DoubleValue: dblVal}
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: v}
 	}
 
-	metric := buildMetricForSinglePoint(
+	return buildMetricForSinglePoint(
 		parsedPath.MetricName,
 		metricType,
 		parsedPath.LabelKeys,
 		parsedPath.LabelValues,
 		&point)
-	return metric, nil
 }
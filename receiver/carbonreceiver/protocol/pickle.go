@@ -0,0 +1,132 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	ogórek "github.com/kisielk/og-rek"
+)
+
+var errPickleNotList = errors.New("pickled carbon data is not a list")
+
+// DecodePickle decodes the payload of a message of the Carbon pickle
+// protocol, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// The payload is a pickled list of data points in the format:
+//
+//	[(path, (timestamp, value)), ...]
+//
+// The returned items are expected to be converted with PickleItemToDataPoint.
+// Only data is unpickled, no Python code referenced by the payload is ever
+// run.
+func DecodePickle(payload []byte) ([]interface{}, error) {
+	decoded, err := ogórek.NewDecoder(bytes.NewReader(payload)).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("invalid pickled carbon data: %v", err)
+	}
+
+	items, ok := decoded.([]interface{})
+	if !ok {
+		return nil, errPickleNotList
+	}
+	return items, nil
+}
+
+// PickleItemToDataPoint converts one of the items returned by DecodePickle,
+// a (path, (timestamp, value)) tuple, to a DataPoint. Both the tuples and
+// lists are accepted since some relays pickle lists instead of tuples.
+func PickleItemToDataPoint(item interface{}) (DataPoint, error) {
+	dp := DataPoint{}
+
+	pathAndPoint, ok := pickleSequence(item)
+	if !ok || len(pathAndPoint) != 2 {
+		return dp, fmt.Errorf("invalid pickled carbon metric [%v]: expected (path, (timestamp, value))", item)
+	}
+
+	switch path := pathAndPoint[0].(type) {
+	case string:
+		dp.Path = path
+	case ogórek.Bytes:
+		dp.Path = string(path)
+	default:
+		return dp, fmt.Errorf("invalid pickled carbon metric path [%v]", pathAndPoint[0])
+	}
+
+	point, ok := pickleSequence(pathAndPoint[1])
+	if !ok || len(point) != 2 {
+		return dp, fmt.Errorf("invalid pickled carbon metric [%s]: expected (timestamp, value)", dp.Path)
+	}
+
+	timestamp, err := pickleNumber(point[0])
+	if err != nil {
+		return dp, fmt.Errorf("invalid pickled carbon metric time [%s]: %v", dp.Path, err)
+	}
+	// Carbon timestamps are in seconds and may be sent as floats, drop the
+	// fractional part as the plaintext protocol expects an integer.
+	switch ts := timestamp.(type) {
+	case int64:
+		dp.Timestamp = ts
+	case float64:
+		dp.Timestamp = int64(ts)
+	}
+
+	dp.Value, err = pickleNumber(point[1])
+	if err != nil {
+		return dp, fmt.Errorf("invalid pickled carbon metric value [%s]: %v", dp.Path, err)
+	}
+
+	return dp, nil
+}
+
+func pickleSequence(v interface{}) ([]interface{}, bool) {
+	switch seq := v.(type) {
+	case ogórek.Tuple:
+		return seq, true
+	case []interface{}:
+		return seq, true
+	}
+	return nil, false
+}
+
+// pickleNumber returns the given unpickled number as either an int64 or a
+// float64.
+func pickleNumber(v interface{}) (interface{}, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case float64:
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("unsupported value %v", n)
+		}
+		return n, nil
+	case *big.Int:
+		if !n.IsInt64() {
+			return nil, fmt.Errorf("value %v overflows int64", n)
+		}
+		return n.Int64(), nil
+	case bool:
+		// Python booleans are integers, they are pickled as such by protocol 0.
+		if n {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
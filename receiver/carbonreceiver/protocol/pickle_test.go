@@ -0,0 +1,252 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	ogórek "github.com/kisielk/og-rek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePickle(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload []byte
+		want    []DataPoint
+		wantErr bool
+	}{
+		{
+			// Python: pickle.dumps([("tst.dbl;k0=v_0", (1582230020, 1.5)), ("tst.int", (1582230020.9, 7))], protocol=2)
+			name:    "python_protocol_2",
+			payload: []byte("\x80\x02]q\x00(X\x0e\x00\x00\x00tst.dbl;k0=v_0q\x01J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x07\x00\x00\x00tst.intq\x04GA\xd7\x93\xba\x819\x99\x9aK\x07\x86q\x05\x86q\x06e."),
+			want: []DataPoint{
+				{Path: "tst.dbl;k0=v_0", Timestamp: 1582230020, Value: 1.5},
+				{Path: "tst.int", Timestamp: 1582230020, Value: int64(7)},
+			},
+		},
+		{
+			// Python: pickle.dumps([("tst.int", (1582230020, 7))], protocol=0)
+			name:    "python_protocol_0",
+			payload: []byte("(lp0\n(Vtst.int\np1\n(I1582230020\nI7\ntp2\ntp3\na."),
+			want: []DataPoint{
+				{Path: "tst.int", Timestamp: 1582230020, Value: int64(7)},
+			},
+		},
+		{
+			name:    "empty_list",
+			payload: encodePickle(t, []interface{}{}),
+			want:    []DataPoint{},
+		},
+		{
+			name:    "not_a_list",
+			payload: encodePickle(t, ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), int64(7)}}),
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			payload: []byte("\x80\x02]q\x00(X\x0e\x00\x00\x00tst.dbl"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := DecodePickle(tt.payload)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make([]DataPoint, 0, len(items))
+			for _, item := range items {
+				dp, err := PickleItemToDataPoint(item)
+				require.NoError(t, err)
+				got = append(got, dp)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPickleItemToDataPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    interface{}
+		want    DataPoint
+		wantErr bool
+	}{
+		{
+			name: "tuples",
+			item: ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), int64(7)}},
+			want: DataPoint{Path: "tst.int", Timestamp: 1582230020, Value: int64(7)},
+		},
+		{
+			name: "lists",
+			item: []interface{}{"tst.dbl", []interface{}{1582230020.5, 3.14}},
+			want: DataPoint{Path: "tst.dbl", Timestamp: 1582230020, Value: 3.14},
+		},
+		{
+			name: "bytes_path",
+			item: ogórek.Tuple{ogórek.Bytes("tst.int"), ogórek.Tuple{int64(1582230020), int64(7)}},
+			want: DataPoint{Path: "tst.int", Timestamp: 1582230020, Value: int64(7)},
+		},
+		{
+			name: "big_int_value",
+			item: ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), big.NewInt(42)}},
+			want: DataPoint{Path: "tst.int", Timestamp: 1582230020, Value: int64(42)},
+		},
+		{
+			name:    "big_int_overflow",
+			item:    ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), new(big.Int).Lsh(big.NewInt(1), 64)}},
+			wantErr: true,
+		},
+		{
+			name:    "not_a_tuple",
+			item:    "tst.int",
+			wantErr: true,
+		},
+		{
+			name:    "missing_point",
+			item:    ogórek.Tuple{"tst.int"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_path",
+			item:    ogórek.Tuple{int64(1), ogórek.Tuple{int64(1582230020), int64(7)}},
+			wantErr: true,
+		},
+		{
+			name:    "missing_value",
+			item:    ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_timestamp",
+			item:    ogórek.Tuple{"tst.int", ogórek.Tuple{"now", int64(7)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_value",
+			item:    ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), "seven"}},
+			wantErr: true,
+		},
+		{
+			name:    "none_value",
+			item:    ogórek.Tuple{"tst.int", ogórek.Tuple{int64(1582230020), ogórek.None{}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PickleItemToDataPoint(tt.item)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPathParserHelper_ParsePoint(t *testing.T) {
+	plaintext, err := (&PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	regex, err := (&RegexParserConfig{
+		Rules: []*RegexRule{
+			{
+				Regexp:     `(?P<key_svc>[^.]+)\.(?P<key_host>[^.]+)\.cpu\.seconds`,
+				NamePrefix: "cpu_seconds",
+				MetricType: string(CumulativeMetricType),
+			},
+		},
+	}).BuildParser()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		parser  Parser
+		dp      DataPoint
+		want    *metricspb.Metric
+		wantErr bool
+	}{
+		{
+			name:   "plaintext_tags",
+			parser: plaintext,
+			dp:     DataPoint{Path: "tst.int.2tags;k0=v_0;k1=v_1", Timestamp: 1582230020, Value: int64(128)},
+			want: buildMetric(
+				metricspb.MetricDescriptor_GAUGE_INT64,
+				"tst.int.2tags",
+				[]string{"k0", "k1"},
+				[]string{"v_0", "v_1"},
+				&metricspb.Point{
+					Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+					Value:     &metricspb.Point_Int64Value{Int64Value: 128},
+				},
+			),
+		},
+		{
+			name:   "regex_rule",
+			parser: regex,
+			dp:     DataPoint{Path: "svc_00.host00.cpu.seconds", Timestamp: 1582230020, Value: 1.23},
+			want: buildMetric(
+				metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+				"cpu_seconds",
+				[]string{"svc", "host"},
+				[]string{"svc_00", "host00"},
+				&metricspb.Point{
+					Timestamp: &timestamp.Timestamp{Seconds: 1582230020},
+					Value:     &metricspb.Point_DoubleValue{DoubleValue: 1.23},
+				},
+			),
+		},
+		{
+			name:    "invalid_path",
+			parser:  plaintext,
+			dp:      DataPoint{Path: ";invalid=path", Timestamp: 1582230020, Value: 1.23},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_value",
+			parser:  plaintext,
+			dp:      DataPoint{Path: "tst.int", Timestamp: 1582230020, Value: "xyz"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pp, ok := tt.parser.(PointParser)
+			require.True(t, ok)
+			got, err := pp.ParsePoint(tt.dp)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func encodePickle(t *testing.T, v interface{}) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, ogórek.NewEncoderWithConfig(&buf, &ogórek.EncoderConfig{Protocol: 2}).Encode(v))
+	return buf.Bytes()
+}
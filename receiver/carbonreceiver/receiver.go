@@ -106,6 +106,8 @@ func buildTransportServer(config Config, logger *zap.Logger) (transport.Server,
 		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
 	case "udp":
 		return transport.NewUDPServer(config.Endpoint)
+	case "pickle":
+		return transport.NewPickleServer(config.Endpoint, config.TCPIdleTimeout)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.Transport, config.Name())
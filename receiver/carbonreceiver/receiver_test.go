@@ -206,6 +206,19 @@ func Test_carbonreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "default_config_pickle",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.Transport = "pickle"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.Graphite {
+				c, err := client.NewGraphite(client.Pickle, host, port)
+				require.NoError(t, err)
+				return c
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -15,12 +15,16 @@
 package client
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
 	"strconv"
 	"strings"
 	"time"
+
+	ogórek "github.com/kisielk/og-rek"
 )
 
 // Graphite is a struct that defines the relevant properties of a graphite
@@ -34,6 +38,8 @@ type Graphite struct {
 	Port    int
 	Timeout time.Duration
 	Conn    io.Writer
+
+	transport Transport
 }
 
 // Transport is used as an enum to select the type of transport to be used.
@@ -42,9 +48,10 @@ type Transport int
 const (
 	defaultTimeout = 5
 
-	// Available transport options: TCP and UDP.
+	// Available transport options: TCP, UDP, and Pickle (over TCP).
 	TCP Transport = iota
 	UDP
+	Pickle
 )
 
 // NewGraphite is a method that's used to create a new Graphite instance.
@@ -53,7 +60,7 @@ const (
 // and modified for the needs of testing the Carbon receiver package and is not
 // intended/tested to be used in production.
 func NewGraphite(transport Transport, host string, port int) (*Graphite, error) {
-	graphite := &Graphite{Host: host, Port: port}
+	graphite := &Graphite{Host: host, Port: port, transport: transport}
 	err := graphite.connect(transport)
 	if err != nil {
 		return nil, err
@@ -68,14 +75,14 @@ func (g *Graphite) connect(transport Transport) error {
 		cl.Close()
 	}
 
-	address := fmt.Sprintf("%s:%d", g.Host, g.Port)
+	address := net.JoinHostPort(g.Host, strconv.Itoa(g.Port))
 	if g.Timeout == 0 {
 		g.Timeout = defaultTimeout * time.Second
 	}
 
 	var err error
 	switch transport {
-	case TCP:
+	case TCP, Pickle:
 		g.Conn, err = net.DialTimeout("tcp", address, g.Timeout)
 	case UDP:
 		var udpAddr *net.UDPAddr
@@ -106,6 +113,9 @@ func (g *Graphite) Disconnect() (err error) {
 // SendMetric method can be used to just pass a metric name and value and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetric(metric Metric) error {
+	if g.transport == Pickle {
+		return g.SendMetrics([]Metric{metric})
+	}
 	_, err := fmt.Fprint(g.Conn, metric.String())
 	if err != nil {
 		return err
@@ -116,6 +126,9 @@ func (g *Graphite) SendMetric(metric Metric) error {
 // SendMetrics method can be used to pass a set of metrics and
 // have it be sent to the Graphite host
 func (g *Graphite) SendMetrics(metrics []Metric) error {
+	if g.transport == Pickle {
+		return g.sendPickle(metrics)
+	}
 	sb := strings.Builder{}
 	for i, metric := range metrics {
 		if _, err := sb.WriteString(metric.String()); err != nil {
@@ -135,6 +148,30 @@ func (g *Graphite) SendMetrics(metrics []Metric) error {
 	return nil
 }
 
+// sendPickle sends the metrics as a single message of the pickle protocol:
+// a 4 bytes big-endian length header followed by the pickled list of
+// (path, (timestamp, value)) tuples.
+func (g *Graphite) sendPickle(metrics []Metric) error {
+	tuples := make([]interface{}, 0, len(metrics))
+	for _, metric := range metrics {
+		tuples = append(tuples, ogórek.Tuple{
+			metric.Name,
+			ogórek.Tuple{metric.Timestamp.Unix(), metric.Value},
+		})
+	}
+
+	var payload bytes.Buffer
+	enc := ogórek.NewEncoderWithConfig(&payload, &ogórek.EncoderConfig{Protocol: 2})
+	if err := enc.Encode(tuples); err != nil {
+		return err
+	}
+
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(payload.Len()))
+	_, err := g.Conn.Write(append(header, payload.Bytes()...))
+	return err
+}
+
 // Metric contains the metric fields expected by Graphite.
 type Metric struct {
 	Name      string
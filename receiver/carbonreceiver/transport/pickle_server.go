@@ -0,0 +1,175 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// PickleMaxMessageLength is the maximum accepted length of a pickle
+	// message, it is the same limit used by Carbon itself.
+	PickleMaxMessageLength = 1 << 20
+
+	pickleHeaderLength = 4
+)
+
+var errParserNotPointParser = errors.New(
+	"the pickle transport requires a parser that supports data points")
+
+// pickleServer receives messages of the Carbon pickle protocol over TCP. Each
+// message is framed by a 4 bytes big-endian header with the length of the
+// pickled payload that follows it.
+type pickleServer struct {
+	*tcpServer
+}
+
+var _ (Server) = (*pickleServer)(nil)
+
+// NewPickleServer creates a transport.Server using TCP as its transport and
+// the Carbon pickle protocol as its framing and encoding.
+func NewPickleServer(
+	addr string,
+	idleTimeout time.Duration,
+) (Server, error) {
+	svr, err := NewTCPServer(addr, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &pickleServer{tcpServer: svr.(*tcpServer)}, nil
+}
+
+func (p *pickleServer) ListenAndServe(
+	parser protocol.Parser,
+	nextConsumer consumer.MetricsConsumer,
+	reporter Reporter,
+) error {
+	if parser == nil || nextConsumer == nil || reporter == nil {
+		return errNilListenAndServeParameters
+	}
+
+	pointParser, ok := parser.(protocol.PointParser)
+	if !ok {
+		return errParserNotPointParser
+	}
+
+	return p.serve(reporter, func(c net.Conn) {
+		p.handleConnection(pointParser, nextConsumer, c)
+	})
+}
+
+func (p *pickleServer) handleConnection(
+	pp protocol.PointParser,
+	nextConsumer consumer.MetricsConsumer,
+	conn net.Conn,
+) {
+	defer conn.Close()
+	header := make([]byte, pickleHeaderLength)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(p.idleTimeout)); err != nil {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - conn.SetDeadLine error: %v",
+				p.ln.Addr(),
+				err)
+			return
+		}
+
+		// Any error reading the message, including timeouts and io.EOF, ends
+		// the connection: there is no way to resync the framing afterwards.
+		payload, err := readPickleMessage(conn, header)
+		if err != nil {
+			p.reporter.OnDebugf(
+				"Pickle Transport (%s) - error: %v",
+				p.ln.Addr(),
+				err)
+			return
+		}
+
+		ctx := p.reporter.OnDataReceived(context.Background())
+		items, err := protocol.DecodePickle(payload)
+		if err != nil {
+			// The whole message is unusable, similarly to Carbon drop the
+			// connection of the misbehaving client.
+			p.reporter.OnTranslationError(ctx, err)
+			p.reporter.OnMetricsProcessed(ctx, 0, 0, nil)
+			return
+		}
+
+		var numInvalidTimeSeries int
+		metrics := make([]*metricspb.Metric, 0, len(items))
+		for _, item := range items {
+			metric, err := parsePickleItem(pp, item)
+			if err != nil {
+				numInvalidTimeSeries++
+				p.reporter.OnTranslationError(ctx, err)
+				continue
+			}
+			metrics = append(metrics, metric)
+		}
+
+		if len(metrics) > 0 {
+			md := consumerdata.MetricsData{
+				Metrics: metrics,
+			}
+			err = nextConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
+		}
+		p.reporter.OnMetricsProcessed(ctx, len(items), numInvalidTimeSeries, err)
+		if err != nil {
+			// See the equivalent comment on the TCP transport.
+			return
+		}
+	}
+}
+
+func readPickleMessage(r io.Reader, header []byte) ([]byte, error) {
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header)
+	if length > PickleMaxMessageLength {
+		return nil, fmt.Errorf(
+			"pickle message length %d exceeds the maximum of %d bytes",
+			length,
+			PickleMaxMessageLength)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func parsePickleItem(pp protocol.PointParser, item interface{}) (*metricspb.Metric, error) {
+	dp, err := protocol.PickleItemToDataPoint(item)
+	if err != nil {
+		return nil, err
+	}
+	return pp.ParsePoint(dp)
+}
@@ -0,0 +1,108 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	ogórek "github.com/kisielk/og-rek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+func Test_pickleServer_InvalidData(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second)
+	require.NoError(t, err)
+
+	mc := new(exportertest.SinkMetricsExporter)
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	mr := NewMockReporter(1)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	runtime.Gosched()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// A batch with one invalid item still delivers its valid items.
+	var payload bytes.Buffer
+	enc := ogórek.NewEncoderWithConfig(&payload, &ogórek.EncoderConfig{Protocol: 2})
+	require.NoError(t, enc.Encode([]interface{}{
+		ogórek.Tuple{"invalid.value", ogórek.Tuple{int64(1582230020), "xyz"}},
+		ogórek.Tuple{"valid.value", ogórek.Tuple{int64(1582230020), 1.23}},
+	}))
+	_, err = conn.Write(pickleFrame(uint32(payload.Len()), payload.Bytes()))
+	require.NoError(t, err)
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	// A message larger than the maximum allowed closes the connection.
+	_, err = conn.Write(pickleFrame(PickleMaxMessageLength+1, nil))
+	require.NoError(t, err)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	assert.NoError(t, svr.Close())
+	wgListenAndServe.Wait()
+
+	mdd := mc.AllMetrics()
+	require.Len(t, mdd, 1)
+	ocmd := pdatautil.MetricsToMetricsData(mdd[0])
+	require.Len(t, ocmd, 1)
+	require.Len(t, ocmd[0].Metrics, 1)
+	assert.Equal(t, "valid.value", ocmd[0].Metrics[0].GetMetricDescriptor().GetName())
+}
+
+func Test_pickleServer_ParserWithoutPoints(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 0)
+	require.NoError(t, err)
+	defer svr.Close()
+
+	err = svr.ListenAndServe(lineOnlyParser{}, new(exportertest.SinkMetricsExporter), NewMockReporter(0))
+	assert.Equal(t, errParserNotPointParser, err)
+}
+
+func pickleFrame(length uint32, payload []byte) []byte {
+	frame := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, length)
+	return append(frame, payload...)
+}
+
+type lineOnlyParser struct {
+	protocol.Parser
+}
@@ -56,6 +56,15 @@ func Test_Server_ListenAndServe(t *testing.T) {
 				return client.NewGraphite(client.UDP, host, port)
 			},
 		},
+		{
+			name: "pickle",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewPickleServer(addr, 1*time.Second)
+			},
+			buildClientFn: func(host string, port int) (*client.Graphite, error) {
+				return client.NewGraphite(client.Pickle, host, port)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -81,6 +81,14 @@ func (t *tcpServer) ListenAndServe(
 		return errNilListenAndServeParameters
 	}
 
+	return t.serve(reporter, func(c net.Conn) {
+		t.handleConnection(parser, nextConsumer, c)
+	})
+}
+
+// serve accepts connections until the listener is closed, each connection is
+// handled on its own goroutine by the given handleConn function.
+func (t *tcpServer) serve(reporter Reporter, handleConn func(c net.Conn)) error {
 	acceptedConnMap := make(map[net.Conn]struct{})
 	connMapMtx := &sync.Mutex{}
 
@@ -94,7 +102,7 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				handleConn(c)
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
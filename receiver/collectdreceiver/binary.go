@@ -0,0 +1,424 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec required by the collectd encryption format
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Part types of the collectd binary network protocol, see
+// https://collectd.org/wiki/index.php/Binary_protocol.
+const (
+	partTypeHost           = 0x0000
+	partTypeTime           = 0x0001
+	partTypePlugin         = 0x0002
+	partTypePluginInstance = 0x0003
+	partTypeType           = 0x0004
+	partTypeTypeInstance   = 0x0005
+	partTypeValues         = 0x0006
+	partTypeInterval       = 0x0007
+	partTypeTimeHR         = 0x0008
+	partTypeIntervalHR     = 0x0009
+	partTypeMessage        = 0x0100
+	partTypeSeverity       = 0x0101
+	partTypeSignSHA256     = 0x0200
+	partTypeEncrAES256     = 0x0210
+)
+
+// Data source types used in the values part.
+const (
+	dsTypeCounter  = 0
+	dsTypeGauge    = 1
+	dsTypeDerive   = 2
+	dsTypeAbsolute = 3
+)
+
+const (
+	partHeaderLen = 4
+	// sign part: header, HMAC-SHA256 and the username.
+	signHashLen = sha256.Size
+	// encrypted part: header, username length, username, IV, SHA-1 of the
+	// payload and the payload.
+	encrIVLen   = aes.BlockSize
+	encrHashLen = sha1.Size
+	// hrTimeUnit is the resolution of TIME_HR and INTERVAL_HR, 2^-30 seconds.
+	hrTimeUnit = 1 << 30
+)
+
+// Security levels of the collectd network plugin. Packets that don't reach
+// the configured level are ignored.
+const (
+	securityLevelNone    = "none"
+	securityLevelSign    = "sign"
+	securityLevelEncrypt = "encrypt"
+)
+
+type securityLevel int
+
+const (
+	securityNone securityLevel = iota
+	securitySign
+	securityEncrypt
+)
+
+var (
+	errBinaryPartTooShort   = errors.New("collectd binary part is too short")
+	errBinaryInvalidString  = errors.New("collectd binary string part is not null terminated")
+	errBinaryInvalidNumeric = errors.New("collectd binary numeric part has an invalid length")
+	errBinaryInvalidValues  = errors.New("collectd binary values part has an invalid length")
+	errBinaryUnknownUser    = errors.New("collectd binary packet is from an unknown user")
+	errBinaryBadSignature   = errors.New("collectd binary packet signature does not match")
+	errBinaryBadChecksum    = errors.New("collectd binary packet checksum does not match after decryption")
+)
+
+func parseSecurityLevel(level string) (securityLevel, error) {
+	switch strings.ToLower(level) {
+	case "", securityLevelNone:
+		return securityNone, nil
+	case securityLevelSign:
+		return securitySign, nil
+	case securityLevelEncrypt:
+		return securityEncrypt, nil
+	}
+	return securityNone, fmt.Errorf("unsupported collectd security level %q", level)
+}
+
+// binaryParser decodes packets of the collectd binary network protocol into
+// collectDRecords, the same records received from the write_http plugin.
+type binaryParser struct {
+	securityLevel securityLevel
+	// passwords by username as read from the auth file.
+	passwords map[string]string
+}
+
+// parse decodes all value lists and notifications of the packet. Data parts
+// that don't meet the security level are skipped. On error the records
+// decoded before the error are still returned.
+func (bp *binaryParser) parse(packet []byte) ([]collectDRecord, error) {
+	return bp.parseParts(packet, securityNone)
+}
+
+// binaryState holds the values set by the previous parts of a packet, each
+// values or message part is dispatched using the current state.
+type binaryState struct {
+	host           string
+	plugin         string
+	pluginInstance string
+	typeS          string
+	typeInstance   string
+	time           float64
+	interval       float64
+	severity       uint64
+}
+
+func (bp *binaryParser) parseParts(buf []byte, achieved securityLevel) ([]collectDRecord, error) {
+	var records []collectDRecord
+	state := binaryState{}
+	for len(buf) > 0 {
+		if len(buf) < partHeaderLen {
+			return records, errBinaryPartTooShort
+		}
+		partType := binary.BigEndian.Uint16(buf[0:2])
+		partLen := int(binary.BigEndian.Uint16(buf[2:4]))
+		if partLen < partHeaderLen || partLen > len(buf) {
+			return records, errBinaryPartTooShort
+		}
+		part := buf[partHeaderLen:partLen]
+		rest := buf[partLen:]
+
+		var err error
+		switch partType {
+		case partTypeSignSHA256:
+			if bp.passwords == nil {
+				// Like collectd, without an auth file signed data is
+				// accepted unverified, and only if no security is required.
+				break
+			}
+			if err = bp.verifySignature(part, rest); err != nil {
+				return records, err
+			}
+			// The signature covers the remainder of the packet.
+			if achieved < securitySign {
+				achieved = securitySign
+			}
+		case partTypeEncrAES256:
+			if bp.passwords == nil {
+				// Encrypted data can't be read without an auth file.
+				break
+			}
+			var payload []byte
+			payload, err = bp.decrypt(part)
+			if err != nil {
+				return records, err
+			}
+			var encrypted []collectDRecord
+			encrypted, err = bp.parseParts(payload, securityEncrypt)
+			records = append(records, encrypted...)
+		case partTypeHost:
+			state.host, err = parseBinaryString(part)
+		case partTypePlugin:
+			state.plugin, err = parseBinaryString(part)
+		case partTypePluginInstance:
+			state.pluginInstance, err = parseBinaryString(part)
+		case partTypeType:
+			state.typeS, err = parseBinaryString(part)
+		case partTypeTypeInstance:
+			state.typeInstance, err = parseBinaryString(part)
+		case partTypeTime, partTypeInterval, partTypeTimeHR, partTypeIntervalHR:
+			err = state.setTime(partType, part)
+		case partTypeSeverity:
+			state.severity, err = parseBinaryNumber(part)
+		case partTypeValues:
+			var record collectDRecord
+			record, err = state.valuesRecord(part)
+			if err == nil && achieved >= bp.securityLevel {
+				records = append(records, record)
+			}
+		case partTypeMessage:
+			var message string
+			message, err = parseBinaryString(part)
+			if err == nil && achieved >= bp.securityLevel {
+				records = append(records, state.notificationRecord(message))
+			}
+		default:
+			// Unknown parts are skipped as done by collectd itself.
+		}
+		if err != nil {
+			return records, err
+		}
+
+		buf = rest
+	}
+
+	return records, nil
+}
+
+func (bp *binaryParser) verifySignature(part, rest []byte) error {
+	if len(part) < signHashLen {
+		return errBinaryPartTooShort
+	}
+	hash, username := part[:signHashLen], part[signHashLen:]
+	password, ok := bp.passwords[string(username)]
+	if !ok {
+		return errBinaryUnknownUser
+	}
+
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write(username)
+	mac.Write(rest)
+	if !hmac.Equal(hash, mac.Sum(nil)) {
+		return errBinaryBadSignature
+	}
+	return nil
+}
+
+func (bp *binaryParser) decrypt(part []byte) ([]byte, error) {
+	if len(part) < 2 {
+		return nil, errBinaryPartTooShort
+	}
+	usernameLen := int(binary.BigEndian.Uint16(part[0:2]))
+	part = part[2:]
+	if len(part) < usernameLen+encrIVLen+encrHashLen {
+		return nil, errBinaryPartTooShort
+	}
+	username := string(part[:usernameLen])
+	iv := part[usernameLen : usernameLen+encrIVLen]
+	encrypted := part[usernameLen+encrIVLen:]
+
+	password, ok := bp.passwords[username]
+	if !ok {
+		return nil, errBinaryUnknownUser
+	}
+
+	key := sha256.Sum256([]byte(password))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewOFB(block, iv).XORKeyStream(decrypted, encrypted)
+
+	hash, payload := decrypted[:encrHashLen], decrypted[encrHashLen:]
+	checksum := sha1.Sum(payload) // #nosec required by the collectd encryption format
+	if !hmac.Equal(hash, checksum[:]) {
+		return nil, errBinaryBadChecksum
+	}
+	return payload, nil
+}
+
+func (s *binaryState) setTime(partType uint16, part []byte) error {
+	v, err := parseBinaryNumber(part)
+	if err != nil {
+		return err
+	}
+	switch partType {
+	case partTypeTime:
+		s.time = float64(v)
+	case partTypeInterval:
+		s.interval = float64(v)
+	case partTypeTimeHR:
+		s.time = float64(v) / hrTimeUnit
+	case partTypeIntervalHR:
+		s.interval = float64(v) / hrTimeUnit
+	}
+	return nil
+}
+
+// valuesRecord builds the record for a values part. The binary protocol
+// doesn't carry the data source names, those are defined by the types.db of
+// the sender, so they follow the go-collectd convention: "value" for a single
+// value and the index of the value otherwise.
+func (s *binaryState) valuesRecord(part []byte) (collectDRecord, error) {
+	if len(part) < 2 {
+		return collectDRecord{}, errBinaryInvalidValues
+	}
+	n := int(binary.BigEndian.Uint16(part[0:2]))
+	part = part[2:]
+	if len(part) != n*9 {
+		return collectDRecord{}, errBinaryInvalidValues
+	}
+
+	record := s.baseRecord()
+	if s.interval != 0 {
+		record.Interval = floatPtr(s.interval)
+	}
+	record.Dsnames = make([]*string, n)
+	record.Dstypes = make([]*string, n)
+	record.Values = make([]*json.Number, n)
+	for i := 0; i < n; i++ {
+		dsName := "value"
+		if n > 1 {
+			dsName = strconv.Itoa(i)
+		}
+		record.Dsnames[i] = &dsName
+
+		raw := part[n+i*8 : n+(i+1)*8]
+		var dsType, value string
+		switch part[i] {
+		case dsTypeCounter:
+			dsType = collectDMetricCounter
+			value = strconv.FormatUint(binary.BigEndian.Uint64(raw), 10)
+		case dsTypeGauge:
+			// Gauges are the only values sent in little-endian.
+			dsType = collectDMetricGauge
+			v := math.Float64frombits(binary.LittleEndian.Uint64(raw))
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				value = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+		case dsTypeDerive:
+			dsType = collectDMetricDerive
+			value = strconv.FormatInt(int64(binary.BigEndian.Uint64(raw)), 10)
+		case dsTypeAbsolute:
+			dsType = collectDMetricAbsolute
+			value = strconv.FormatUint(binary.BigEndian.Uint64(raw), 10)
+		default:
+			return collectDRecord{}, fmt.Errorf("unknown collectd data source type %d", part[i])
+		}
+		record.Dstypes[i] = &dsType
+		if value != "" {
+			// NaN is skipped, the same as null values from write_http.
+			number := json.Number(value)
+			record.Values[i] = &number
+		}
+	}
+	return record, nil
+}
+
+func (s *binaryState) notificationRecord(message string) collectDRecord {
+	record := s.baseRecord()
+	// Notifications are identified as events by having a time.
+	record.Time = floatPtr(s.time)
+	severity := strconv.FormatUint(s.severity, 10)
+	record.Severity = &severity
+	record.Message = &message
+	return record
+}
+
+func (s *binaryState) baseRecord() collectDRecord {
+	host, plugin, pluginInstance := s.host, s.plugin, s.pluginInstance
+	typeS, typeInstance := s.typeS, s.typeInstance
+	record := collectDRecord{
+		Host:           &host,
+		Plugin:         &plugin,
+		PluginInstance: &pluginInstance,
+		TypeS:          &typeS,
+		TypeInstance:   &typeInstance,
+	}
+	if s.time != 0 {
+		record.Time = floatPtr(s.time)
+	}
+	return record
+}
+
+func parseBinaryString(part []byte) (string, error) {
+	if len(part) == 0 || part[len(part)-1] != 0 {
+		return "", errBinaryInvalidString
+	}
+	return string(part[:len(part)-1]), nil
+}
+
+func parseBinaryNumber(part []byte) (uint64, error) {
+	if len(part) != 8 {
+		return 0, errBinaryInvalidNumeric
+	}
+	return binary.BigEndian.Uint64(part), nil
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// loadAuthFile reads the collectd auth file, one "username: password" entry
+// per line, the same file used by the collectd network plugin.
+func loadAuthFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	passwords := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		idx := bytes.IndexByte(line, ':')
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid line in collectd auth file %q: %q", path, line)
+		}
+		username := string(bytes.TrimSpace(line[:idx]))
+		passwords[username] = string(bytes.TrimSpace(line[idx+1:]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return passwords, nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.uber.org/zap"
+)
+
+// maxBinaryPacketSize is the largest UDP payload, collectd itself sends
+// packets of 1452 bytes by default.
+const maxBinaryPacketSize = 65535
+
+var _ component.MetricsReceiver = (*collectdBinaryReceiver)(nil)
+
+// collectdBinaryReceiver receives the collectd binary protocol, as sent by
+// the collectd network plugin, over UDP.
+type collectdBinaryReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	addr         string
+	conn         net.PacketConn
+	parser       *binaryParser
+	nextConsumer consumer.MetricsConsumer
+	wg           sync.WaitGroup
+	done         chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func newCollectdBinaryReceiver(
+	logger *zap.Logger,
+	addr string,
+	securityLevel string,
+	authFile string,
+	nextConsumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	level, err := parseSecurityLevel(securityLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &binaryParser{securityLevel: level}
+	if authFile != "" {
+		parser.passwords, err = loadAuthFile(authFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading collectd auth file: %v", err)
+		}
+	} else if level != securityNone {
+		return nil, fmt.Errorf("collectd security level %q requires an auth file", securityLevel)
+	}
+
+	r := &collectdBinaryReceiver{
+		logger:       logger,
+		addr:         addr,
+		parser:       parser,
+		nextConsumer: nextConsumer,
+		done:         make(chan struct{}),
+	}
+	return r, nil
+}
+
+func (cdr *collectdBinaryReceiver) Start(_ context.Context, host component.Host) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	err := errAlreadyStarted
+	cdr.startOnce.Do(func() {
+		cdr.conn, err = net.ListenPacket("udp", cdr.addr)
+		if err != nil {
+			err = fmt.Errorf("error starting collectd receiver: %v", err)
+			return
+		}
+
+		cdr.wg.Add(1)
+		go func() {
+			defer cdr.wg.Done()
+			cdr.serve(host)
+		}()
+	})
+
+	return err
+}
+
+func (cdr *collectdBinaryReceiver) Shutdown(context.Context) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	var err = errAlreadyStopped
+	cdr.stopOnce.Do(func() {
+		err = nil
+		close(cdr.done)
+		if cdr.conn != nil {
+			err = cdr.conn.Close()
+			cdr.wg.Wait()
+		}
+	})
+	return err
+}
+
+func (cdr *collectdBinaryReceiver) serve(host component.Host) {
+	buf := make([]byte, maxBinaryPacketSize)
+	for {
+		n, _, err := cdr.conn.ReadFrom(buf)
+		if n > 0 {
+			cdr.handlePacket(buf[:n])
+		}
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			select {
+			case <-cdr.done:
+				// The error is the result of closing the connection.
+			default:
+				host.ReportFatalError(fmt.Errorf("error reading collectd packets: %v", err))
+			}
+			return
+		}
+	}
+}
+
+func (cdr *collectdBinaryReceiver) handlePacket(packet []byte) {
+	recordRequestReceived()
+
+	records, err := cdr.parser.parse(packet)
+	if err != nil {
+		// The records decoded before the error are still sent, similarly to
+		// collectd that dispatches the values it parsed before a bad part.
+		recordRequestErrors()
+		cdr.logger.Debug("unable to decode collectd binary packet", zap.Error(err))
+	}
+
+	md := consumerdata.MetricsData{}
+	for _, record := range records {
+		md.Metrics, err = record.appendToMetrics(md.Metrics, nil)
+		if err != nil {
+			recordRequestErrors()
+			cdr.logger.Error("unable to process metrics", zap.Error(err))
+			return
+		}
+	}
+	if len(md.Metrics) == 0 {
+		return
+	}
+
+	err = cdr.nextConsumer.ConsumeMetrics(context.Background(), pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
+	if err != nil {
+		recordRequestErrors()
+		cdr.logger.Error("unable to process metrics", zap.Error(err))
+	}
+}
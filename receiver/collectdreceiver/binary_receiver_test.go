@@ -0,0 +1,136 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestNewBinaryReceiver(t *testing.T) {
+	authFile := writeAuthFile(t)
+	defer os.Remove(authFile)
+
+	tests := []struct {
+		name          string
+		securityLevel string
+		authFile      string
+		nilConsumer   bool
+		wantErr       bool
+	}{
+		{
+			name: "happy_path",
+		},
+		{
+			name:          "encrypt_with_auth_file",
+			securityLevel: securityLevelEncrypt,
+			authFile:      authFile,
+		},
+		{
+			name:        "nil_next_consumer",
+			nilConsumer: true,
+			wantErr:     true,
+		},
+		{
+			name:          "unknown_security_level",
+			securityLevel: "paranoid",
+			wantErr:       true,
+		},
+		{
+			name:          "sign_without_auth_file",
+			securityLevel: securityLevelSign,
+			wantErr:       true,
+		},
+		{
+			name:     "missing_auth_file",
+			authFile: authFile + ".missing",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nextConsumer consumer.MetricsConsumer = exportertest.NewNopMetricsExporter()
+			if tt.nilConsumer {
+				nextConsumer = nil
+			}
+			_, err := newCollectdBinaryReceiver(zap.NewNop(), ":0", tt.securityLevel, tt.authFile, nextConsumer)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestCollectDBinaryServer(t *testing.T) {
+	authFile := writeAuthFile(t)
+	defer os.Remove(authFile)
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkMetricsExporter)
+	cdr, err := newCollectdBinaryReceiver(zap.NewNop(), addr, securityLevelSign, authFile, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, cdr.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, errAlreadyStarted, cdr.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	parts := concatParts(
+		stringPart(partTypeHost, "i-b13d1e5f"),
+		numberPart(partTypeTime, 1415062577),
+		stringPart(partTypePlugin, "memory"),
+		stringPart(partTypeType, "memory"),
+		stringPart(partTypeTypeInstance, "free"),
+		valuesPart(dsTypeGauge, 2.5),
+	)
+	// Unsigned data is ignored, the packet with the signed data is processed.
+	_, err = conn.Write(parts)
+	require.NoError(t, err)
+	_, err = conn.Write(signPacket(testUser, testPassword, parts))
+	require.NoError(t, err)
+
+	testutil.WaitFor(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	})
+	mds := pdatautil.MetricsToMetricsData(sink.AllMetrics()[0])
+	require.Len(t, mds, 1)
+	require.Len(t, mds[0].Metrics, 1)
+	assert.Equal(t, "memory.free", mds[0].Metrics[0].MetricDescriptor.Name)
+
+	assert.NoError(t, cdr.Shutdown(context.Background()))
+	assert.Equal(t, errAlreadyStopped, cdr.Shutdown(context.Background()))
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func writeAuthFile(t *testing.T) string {
+	f, err := ioutil.TempFile("", "collectd-auth")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString(testUser + ": " + testPassword + "\n")
+	require.NoError(t, err)
+	return f.Name()
+}
@@ -0,0 +1,423 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec required by the collectd encryption format
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUser     = "alice"
+	testPassword = "s3cr3t"
+)
+
+func TestBinaryParser_Parse(t *testing.T) {
+	// The same data as sent by the collectd memory and interface plugins.
+	memoryParts := concatParts(
+		stringPart(partTypeHost, "i-b13d1e5f"),
+		numberPart(partTypeTimeHR, 1415062577<<30|(1<<29)),
+		numberPart(partTypeIntervalHR, 10<<30),
+		stringPart(partTypePlugin, "memory"),
+		stringPart(partTypeType, "memory"),
+		stringPart(partTypeTypeInstance, "free"),
+		valuesPart(dsTypeGauge, 2.5),
+	)
+	interfaceParts := concatParts(
+		stringPart(partTypeHost, "i-b13d1e5f"),
+		numberPart(partTypeTime, 1415062577),
+		numberPart(partTypeInterval, 10),
+		stringPart(partTypePlugin, "interface"),
+		stringPart(partTypePluginInstance, "eth0"),
+		stringPart(partTypeType, "if_octets"),
+		stringPart(partTypeTypeInstance, ""),
+		valuesPart(dsTypeDerive, int64(-1), dsTypeCounter, uint64(2), dsTypeAbsolute, uint64(3)),
+	)
+	memoryRecord := collectDRecord{
+		Dsnames:        []*string{strPtr("value")},
+		Dstypes:        []*string{strPtr(collectDMetricGauge)},
+		Host:           strPtr("i-b13d1e5f"),
+		Interval:       floatPtr(10),
+		Plugin:         strPtr("memory"),
+		PluginInstance: strPtr(""),
+		Time:           floatPtr(1415062577.5),
+		TypeS:          strPtr("memory"),
+		TypeInstance:   strPtr("free"),
+		Values:         []*json.Number{numberPtr("2.5")},
+	}
+	interfaceRecord := collectDRecord{
+		Dsnames:        []*string{strPtr("0"), strPtr("1"), strPtr("2")},
+		Dstypes:        []*string{strPtr(collectDMetricDerive), strPtr(collectDMetricCounter), strPtr(collectDMetricAbsolute)},
+		Host:           strPtr("i-b13d1e5f"),
+		Interval:       floatPtr(10),
+		Plugin:         strPtr("interface"),
+		PluginInstance: strPtr("eth0"),
+		Time:           floatPtr(1415062577),
+		TypeS:          strPtr("if_octets"),
+		TypeInstance:   strPtr(""),
+		Values:         []*json.Number{numberPtr("-1"), numberPtr("2"), numberPtr("3")},
+	}
+	passwords := map[string]string{testUser: testPassword}
+
+	tests := []struct {
+		name          string
+		securityLevel securityLevel
+		passwords     map[string]string
+		packet        []byte
+		want          []collectDRecord
+		wantErr       error
+	}{
+		{
+			name:   "plain",
+			packet: concatParts(memoryParts, interfaceParts),
+			want:   []collectDRecord{memoryRecord, interfaceRecord},
+		},
+		{
+			name: "state_carried_between_values",
+			packet: concatParts(
+				memoryParts,
+				stringPart(partTypeTypeInstance, "used"),
+				valuesPart(dsTypeGauge, 2.5),
+			),
+			want: []collectDRecord{
+				memoryRecord,
+				func() collectDRecord {
+					r := memoryRecord
+					r.TypeInstance = strPtr("used")
+					return r
+				}(),
+			},
+		},
+		{
+			name: "nan_gauge",
+			packet: concatParts(
+				stringPart(partTypePlugin, "memory"),
+				valuesPart(dsTypeGauge, math.NaN()),
+			),
+			want: []collectDRecord{{
+				Dsnames:        []*string{strPtr("value")},
+				Dstypes:        []*string{strPtr(collectDMetricGauge)},
+				Host:           strPtr(""),
+				Plugin:         strPtr("memory"),
+				PluginInstance: strPtr(""),
+				TypeS:          strPtr(""),
+				TypeInstance:   strPtr(""),
+				Values:         []*json.Number{nil},
+			}},
+		},
+		{
+			name: "notification",
+			packet: concatParts(
+				stringPart(partTypeHost, "i-b13d1e5f"),
+				numberPart(partTypeTime, 1415062577),
+				numberPart(partTypeSeverity, 2),
+				stringPart(partTypeMessage, "disk full"),
+			),
+			want: []collectDRecord{{
+				Host:           strPtr("i-b13d1e5f"),
+				Plugin:         strPtr(""),
+				PluginInstance: strPtr(""),
+				TypeS:          strPtr(""),
+				TypeInstance:   strPtr(""),
+				Time:           floatPtr(1415062577),
+				Severity:       strPtr("2"),
+				Message:        strPtr("disk full"),
+			}},
+		},
+		{
+			name:   "unknown_parts_skipped",
+			packet: concatParts(stringPart(0x0fff, "ignored"), memoryParts),
+			want:   []collectDRecord{memoryRecord},
+		},
+		{
+			name:    "truncated_part",
+			packet:  concatParts(memoryParts, []byte{0x00, 0x06, 0x00, 0x20, 0x00}),
+			want:    []collectDRecord{memoryRecord},
+			wantErr: errBinaryPartTooShort,
+		},
+		{
+			name:    "string_not_null_terminated",
+			packet:  []byte{0x00, 0x00, 0x00, 0x06, 'h', 'i'},
+			wantErr: errBinaryInvalidString,
+		},
+		{
+			name:    "invalid_numeric",
+			packet:  []byte{0x00, 0x01, 0x00, 0x06, 0x00, 0x01},
+			wantErr: errBinaryInvalidNumeric,
+		},
+		{
+			name:    "invalid_values",
+			packet:  []byte{0x00, 0x06, 0x00, 0x07, 0x00, 0x01, dsTypeGauge},
+			wantErr: errBinaryInvalidValues,
+		},
+		{
+			name:      "signed",
+			passwords: passwords,
+			packet:    signPacket(testUser, testPassword, memoryParts),
+			want:      []collectDRecord{memoryRecord},
+		},
+		{
+			name:          "signed_required",
+			securityLevel: securitySign,
+			passwords:     passwords,
+			packet:        concatParts(signPacket(testUser, testPassword, memoryParts)),
+			want:          []collectDRecord{memoryRecord},
+		},
+		{
+			name:      "signed_without_auth_file",
+			packet:    signPacket(testUser, testPassword, memoryParts),
+			passwords: nil,
+			want:      []collectDRecord{memoryRecord},
+		},
+		{
+			name:      "signed_wrong_password",
+			passwords: passwords,
+			packet:    signPacket(testUser, "wrong", memoryParts),
+			wantErr:   errBinaryBadSignature,
+		},
+		{
+			name:      "signed_unknown_user",
+			passwords: passwords,
+			packet:    signPacket("bob", testPassword, memoryParts),
+			wantErr:   errBinaryUnknownUser,
+		},
+		{
+			name:      "signed_tampered",
+			passwords: passwords,
+			packet: func() []byte {
+				packet := signPacket(testUser, testPassword, memoryParts)
+				packet[len(packet)-1] ^= 0xff
+				return packet
+			}(),
+			wantErr: errBinaryBadSignature,
+		},
+		{
+			name:          "unsigned_when_sign_required",
+			securityLevel: securitySign,
+			passwords:     passwords,
+			packet:        memoryParts,
+		},
+		{
+			name:          "encrypted",
+			securityLevel: securityEncrypt,
+			passwords:     passwords,
+			packet:        encryptPacket(testUser, testPassword, memoryParts),
+			want:          []collectDRecord{memoryRecord},
+		},
+		{
+			name:          "encrypted_meets_sign",
+			securityLevel: securitySign,
+			passwords:     passwords,
+			packet:        encryptPacket(testUser, testPassword, memoryParts),
+			want:          []collectDRecord{memoryRecord},
+		},
+		{
+			name:          "signed_when_encrypt_required",
+			securityLevel: securityEncrypt,
+			passwords:     passwords,
+			packet:        signPacket(testUser, testPassword, memoryParts),
+		},
+		{
+			name:      "encrypted_wrong_password",
+			passwords: passwords,
+			packet:    encryptPacket(testUser, "wrong", memoryParts),
+			wantErr:   errBinaryBadChecksum,
+		},
+		{
+			name:      "encrypted_unknown_user",
+			passwords: passwords,
+			packet:    encryptPacket("bob", testPassword, memoryParts),
+			wantErr:   errBinaryUnknownUser,
+		},
+		{
+			name:   "encrypted_without_auth_file",
+			packet: concatParts(encryptPacket(testUser, testPassword, memoryParts), interfaceParts),
+			want:   []collectDRecord{interfaceRecord},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := &binaryParser{securityLevel: tt.securityLevel, passwords: tt.passwords}
+			got, err := bp.parse(tt.packet)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBinaryRecordsToMetrics(t *testing.T) {
+	bp := &binaryParser{}
+	records, err := bp.parse(concatParts(
+		stringPart(partTypeHost, "i-b13d1e5f"),
+		numberPart(partTypeTime, 1415062577),
+		stringPart(partTypePlugin, "interface"),
+		stringPart(partTypePluginInstance, "eth0"),
+		stringPart(partTypeType, "if_octets"),
+		valuesPart(dsTypeDerive, int64(1), dsTypeDerive, int64(2)),
+	))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	metrics, err := records[0].appendToMetrics(nil, nil)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "if_octets.0", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, "if_octets.1", metrics[1].MetricDescriptor.Name)
+	for _, metric := range metrics {
+		assert.Equal(t, "CUMULATIVE_INT64", metric.MetricDescriptor.Type.String())
+		assert.Equal(t, int64(1415062577), metric.Timeseries[0].Points[0].Timestamp.Seconds)
+	}
+}
+
+func TestParseSecurityLevel(t *testing.T) {
+	for level, want := range map[string]securityLevel{
+		"":        securityNone,
+		"None":    securityNone,
+		"sign":    securitySign,
+		"Encrypt": securityEncrypt,
+	} {
+		got, err := parseSecurityLevel(level)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := parseSecurityLevel("plaintext")
+	assert.Error(t, err)
+}
+
+func TestLoadAuthFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collectd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	valid := path.Join(dir, "valid")
+	require.NoError(t, ioutil.WriteFile(valid, []byte("# comment\nalice: s3cr3t\n\n  bob:p:ss  \n"), 0600))
+	passwords, err := loadAuthFile(valid)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"alice": "s3cr3t", "bob": "p:ss"}, passwords)
+
+	invalid := path.Join(dir, "invalid")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("alice\n"), 0600))
+	_, err = loadAuthFile(invalid)
+	assert.Error(t, err)
+
+	_, err = loadAuthFile(path.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func concatParts(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func partHeader(partType uint16, payloadLen int) []byte {
+	header := make([]byte, partHeaderLen)
+	binary.BigEndian.PutUint16(header[0:2], partType)
+	binary.BigEndian.PutUint16(header[2:4], uint16(partHeaderLen+payloadLen))
+	return header
+}
+
+func stringPart(partType uint16, s string) []byte {
+	return concatParts(partHeader(partType, len(s)+1), []byte(s), []byte{0})
+}
+
+func numberPart(partType uint16, v uint64) []byte {
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint64(payload, v)
+	return concatParts(partHeader(partType, len(payload)), payload)
+}
+
+// valuesPart builds a values part from pairs of data source type and value.
+func valuesPart(typesAndValues ...interface{}) []byte {
+	n := len(typesAndValues) / 2
+	payload := make([]byte, 2+n*9)
+	binary.BigEndian.PutUint16(payload[0:2], uint16(n))
+	for i := 0; i < n; i++ {
+		dsType := typesAndValues[2*i].(int)
+		payload[2+i] = byte(dsType)
+		raw := payload[2+n+i*8 : 2+n+(i+1)*8]
+		switch v := typesAndValues[2*i+1].(type) {
+		case float64:
+			binary.LittleEndian.PutUint64(raw, math.Float64bits(v))
+		case int64:
+			binary.BigEndian.PutUint64(raw, uint64(v))
+		case uint64:
+			binary.BigEndian.PutUint64(raw, v)
+		}
+	}
+	return concatParts(partHeader(partTypeValues, len(payload)), payload)
+}
+
+// signPacket signs the payload as done by collectd with "SecurityLevel Sign".
+func signPacket(username, password string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte(username))
+	mac.Write(payload)
+	return concatParts(
+		partHeader(partTypeSignSHA256, signHashLen+len(username)),
+		mac.Sum(nil),
+		[]byte(username),
+		payload,
+	)
+}
+
+// encryptPacket encrypts the payload as done by collectd with
+// "SecurityLevel Encrypt".
+func encryptPacket(username, password string, payload []byte) []byte {
+	iv := bytes.Repeat([]byte{0x2a}, encrIVLen)
+	checksum := sha1.Sum(payload) // #nosec required by the collectd encryption format
+	plaintext := concatParts(checksum[:], payload)
+
+	key := sha256.Sum256([]byte(password))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err)
+	}
+	encrypted := make([]byte, len(plaintext))
+	cipher.NewOFB(block, iv).XORKeyStream(encrypted, plaintext)
+
+	usernameLen := make([]byte, 2)
+	binary.BigEndian.PutUint16(usernameLen, uint16(len(username)))
+	return concatParts(
+		partHeader(partTypeEncrAES256, 2+len(username)+encrIVLen+len(encrypted)),
+		usernameLen,
+		[]byte(username),
+		iv,
+		encrypted,
+	)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func numberPtr(s string) *json.Number {
+	n := json.Number(s)
+	return &n
+}
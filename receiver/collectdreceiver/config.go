@@ -29,4 +29,12 @@ type Config struct {
 	Timeout          time.Duration `mapstructure:"timeout"`
 	AttributesPrefix string        `mapstructure:"attributes_prefix"`
 	Encoding         string        `mapstructure:"encoding"`
+
+	// SecurityLevel is the minimum security level of the packets accepted
+	// with the "binary" encoding: "none" (default), "sign" or "encrypt", the
+	// same levels of the collectd network plugin.
+	SecurityLevel string `mapstructure:"security_level"`
+	// AuthFile is the collectd auth file, with one "username: password" per
+	// line, used to verify signed and decrypt encrypted "binary" packets.
+	AuthFile string `mapstructure:"auth_file"`
 }
@@ -30,10 +30,14 @@ import (
 // This file implements factory for CollectD receiver.
 
 const (
-	typeStr               = "collectd"
-	defaultBindEndpoint   = "localhost:8081"
-	defaultTimeout        = time.Duration(time.Second * 30)
-	defaultEncodingFormat = "json"
+	typeStr             = "collectd"
+	defaultBindEndpoint = "localhost:8081"
+	// defaultBinaryBindEndpoint is used with the binary encoding instead of
+	// defaultBindEndpoint, it is the default port of the network plugin.
+	defaultBinaryBindEndpoint = "localhost:25826"
+	defaultTimeout            = time.Duration(time.Second * 30)
+	defaultEncodingFormat     = "json"
+	binaryEncodingFormat      = "binary"
 )
 
 // NewFactory creates a factory for collectd receiver.
@@ -65,13 +69,20 @@ func createMetricsReceiver(
 ) (component.MetricsReceiver, error) {
 	c := cfg.(*Config)
 	c.Encoding = strings.ToLower(c.Encoding)
-	// CollectD receiver only supports JSON encoding. We expose a config option
-	// to make it explicit and obvious to the users.
-	if c.Encoding != defaultEncodingFormat {
-		return nil, fmt.Errorf(
-			"CollectD only support JSON encoding format. %s is not supported",
-			c.Encoding,
-		)
+	// CollectD receiver supports the JSON encoding of the write_http plugin
+	// over HTTP, and the binary encoding of the network plugin over UDP.
+	switch c.Encoding {
+	case defaultEncodingFormat:
+		return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	case binaryEncodingFormat:
+		endpoint := c.Endpoint
+		if endpoint == "" || endpoint == defaultBindEndpoint {
+			endpoint = defaultBinaryBindEndpoint
+		}
+		return newCollectdBinaryReceiver(params.Logger, endpoint, c.SecurityLevel, c.AuthFile, nextConsumer)
 	}
-	return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	return nil, fmt.Errorf(
+		"CollectD only support JSON and binary encoding formats. %s is not supported",
+		c.Encoding,
+	)
 }
@@ -41,3 +41,35 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
+
+func TestCreateBinaryReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Encoding = "Binary"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	tReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.NoError(t, err)
+	assert.IsType(t, &collectdBinaryReceiver{}, tReceiver)
+	assert.Equal(t, "localhost:25826", tReceiver.(*collectdBinaryReceiver).addr,
+		"the binary encoding should default to the port of the network plugin")
+
+	cfg.Endpoint = "0.0.0.0:9999"
+	tReceiver, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.0.0:9999", tReceiver.(*collectdBinaryReceiver).addr)
+
+	cfg.SecurityLevel = "sign"
+	_, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+}
+
+func TestCreateReceiverUnsupportedEncoding(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Encoding = "protobuf"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+}
@@ -25,6 +25,8 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -46,18 +48,31 @@ const (
 	k8sKeyReplicationControllerUID = "k8s.replicationcontroller.uid"
 	k8sKeyHPAUID                   = "k8s.hpa.uid"
 	k8sKeyResourceQuotaUID         = "k8s.resourcequota.uid"
+	k8sKeyPVCUID                   = "k8s.persistentvolumeclaim.uid"
+	k8sKeyEndpointsUID             = "k8s.endpoints.uid"
+	k8sKeyIngressUID               = "k8s.ingress.uid"
+	k8sKeyPDBUID                   = "k8s.poddisruptionbudget.uid"
 
 	// Resource labels keys for Name.
 	k8sKeyNodeName                  = "k8s.node.name"
 	k8sKeyReplicationControllerName = "k8s.replicationcontroller.name"
 	k8sKeyHPAName                   = "k8s.hpa.name"
 	k8sKeyResourceQuotaName         = "k8s.resourcequota.name"
+	k8sKeyPVCName                   = "k8s.persistentvolumeclaim.name"
+	k8sKeyEndpointsName             = "k8s.endpoints.name"
+	k8sKeyIngressName               = "k8s.ingress.name"
+	k8sKeyPDBName                   = "k8s.poddisruptionbudget.name"
+	k8sKeyServiceName               = "k8s.service.name"
 
 	// Kubernetes resource kinds
 	k8sKindCronJob               = "CronJob"
 	k8sKindDaemonSet             = "DaemonSet"
 	k8sKindDeployment            = "Deployment"
+	k8sKindEndpoints             = "Endpoints"
+	k8sKindIngress               = "Ingress"
 	k8sKindJob                   = "Job"
+	k8sKindPDB                   = "PodDisruptionBudget"
+	k8sKindPVC                   = "PersistentVolumeClaim"
 	k8sKindReplicationController = "ReplicationController"
 	k8sKindReplicaSet            = "ReplicaSet"
 	k8sKindService               = "Service"
@@ -131,6 +146,10 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForReplicationController(o)
 	case *corev1.ResourceQuota:
 		rm = getMetricsForResourceQuota(o)
+	case *corev1.PersistentVolumeClaim:
+		rm = getMetricsForPVC(o)
+	case *corev1.Endpoints:
+		rm = getMetricsForEndpoints(o)
 	case *appsv1.Deployment:
 		rm = getMetricsForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -145,6 +164,10 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		rm = getMetricsForHPA(o)
+	case *networkingv1beta1.Ingress:
+		rm = getMetricsForIngress(o)
+	case *policyv1beta1.PodDisruptionBudget:
+		rm = getMetricsForPDB(o)
 	default:
 		return
 	}
@@ -166,6 +189,10 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForNode(o)
 	case *corev1.ReplicationController:
 		km = getMetadataForReplicationController(o)
+	case *corev1.PersistentVolumeClaim:
+		km = getMetadataForPVC(o)
+	case *corev1.Endpoints:
+		km = getMetadataForEndpoints(o)
 	case *appsv1.Deployment:
 		km = getMetadataForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -180,6 +207,10 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		km = getMetadataForHPA(o)
+	case *networkingv1beta1.Ingress:
+		km = getMetadataForIngress(o)
+	case *policyv1beta1.PodDisruptionBudget:
+		km = getMetadataForPDB(o)
 	}
 
 	return km
@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestEndpointsMetrics(t *testing.T) {
+	ep := newEndpoints("1")
+
+	actualResourceMetrics := getMetricsForEndpoints(ep)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, rm.resource, k8sType,
+		map[string]string{
+			"k8s.endpoints.uid":  "test-endpoints-1-uid",
+			"k8s.endpoints.name": "test-endpoints-1",
+			"k8s.service.name":   "test-endpoints-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, rm.metrics[0], "k8s/endpoints/ready_addresses",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+
+	testutils.AssertMetrics(t, rm.metrics[1], "k8s/endpoints/not_ready_addresses",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestEndpointsMetadata(t *testing.T) {
+	ep := newEndpoints("1")
+
+	actualMetadata := getMetadataForEndpoints(ep)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.endpoints.uid",
+			resourceID:    "test-endpoints-1-uid",
+			metadata: map[string]string{
+				"endpoints.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":                          "bar",
+				"k8s.service.name":             "test-endpoints-1",
+				"k8s.workload.kind":            "Endpoints",
+				"k8s.workload.name":            "test-endpoints-1",
+			},
+		},
+		*actualMetadata["test-endpoints-1-uid"],
+	)
+}
+
+func newEndpoints(id string) *corev1.Endpoints {
+	return &corev1.Endpoints{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-endpoints-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-endpoints-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses:         []corev1.EndpointAddress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}},
+				NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.3"}},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{{IP: "10.0.1.1"}},
+			},
+		},
+	}
+}
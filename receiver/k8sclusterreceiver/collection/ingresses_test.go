@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestIngressMetrics(t *testing.T) {
+	ing := newIngress("1")
+
+	actualResourceMetrics := getMetricsForIngress(ing)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 2, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, rm.resource, k8sType,
+		map[string]string{
+			"k8s.ingress.uid":    "test-ingress-1-uid",
+			"k8s.ingress.name":   "test-ingress-1",
+			"k8s.namespace.name": "test-namespace",
+			"k8s.cluster.name":   "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, rm.metrics[0], "k8s/ingress/rules",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+
+	testutils.AssertMetrics(t, rm.metrics[1], "k8s/ingress/load_balancer_ingresses",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+}
+
+func TestIngressMetadata(t *testing.T) {
+	ing := newIngress("1")
+
+	actualMetadata := getMetadataForIngress(ing)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.ingress.uid",
+			resourceID:    "test-ingress-1-uid",
+			metadata: map[string]string{
+				"ingress.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":                        "bar",
+				"ingress_class":              "nginx",
+				"hosts":                      "a.example.com,b.example.com",
+				"k8s.workload.kind":          "Ingress",
+				"k8s.workload.name":          "test-ingress-1",
+			},
+		},
+		*actualMetadata["test-ingress-1-uid"],
+	)
+}
+
+func newIngress(id string) *networkingv1beta1.Ingress {
+	class := "nginx"
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-ingress-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-ingress-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			IngressClassName: &class,
+			Rules: []networkingv1beta1.IngressRule{
+				{Host: "b.example.com"},
+				{Host: "a.example.com"},
+				{Host: "b.example.com"},
+			},
+		},
+		Status: networkingv1beta1.IngressStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			},
+		},
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume claim metadata.
+	pvcKeyStorageClass = "storage_class"
+	pvcKeyVolumeName   = "volume_name"
+	pvcKeyAccessModes  = "access_modes"
+)
+
+var pvcPhaseMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/phase",
+	Description: "Current phase of the persistent volume claim (1 - Pending, 2 - Bound, 3 - Lost)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var pvcCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/capacity",
+	Description: "Storage capacity of the volume bound to the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var pvcRequestMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/request",
+	Description: "Storage requested by the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPVC(pvc *corev1.PersistentVolumeClaim) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: pvcPhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pvcPhaseToInt(pvc.Status.Phase))),
+			},
+		},
+	}
+
+	// Capacity is only known once the claim is bound.
+	if v, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: pvcCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(v.Value()),
+			},
+		})
+	}
+
+	if v, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: pvcRequestMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(v.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPVC(pvc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func pvcPhaseToInt(phase corev1.PersistentVolumeClaimPhase) int32 {
+	switch phase {
+	case corev1.ClaimPending:
+		return 1
+	case corev1.ClaimBound:
+		return 2
+	case corev1.ClaimLost:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func getResourceForPVC(pvc *corev1.PersistentVolumeClaim) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPVCUID:                      string(pvc.UID),
+			k8sKeyPVCName:                     pvc.Name,
+			conventions.AttributeK8sNamespace: pvc.Namespace,
+			conventions.AttributeK8sCluster:   pvc.ClusterName,
+		},
+	}
+}
+
+func getMetadataForPVC(pvc *corev1.PersistentVolumeClaim) map[ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pvc.ObjectMeta, k8sKindPVC)
+	if pvc.Spec.StorageClassName != nil {
+		rm.metadata[pvcKeyStorageClass] = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeName != "" {
+		rm.metadata[pvcKeyVolumeName] = pvc.Spec.VolumeName
+	}
+	if len(pvc.Spec.AccessModes) > 0 {
+		modes := make([]string, len(pvc.Spec.AccessModes))
+		for i, m := range pvc.Spec.AccessModes {
+			modes[i] = string(m)
+		}
+		rm.metadata[pvcKeyAccessModes] = strings.Join(modes, ",")
+	}
+	return map[ResourceID]*KubernetesMetadata{ResourceID(pvc.UID): rm}
+}
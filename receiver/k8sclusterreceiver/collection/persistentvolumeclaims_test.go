@@ -0,0 +1,120 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestPVCMetrics(t *testing.T) {
+	pvc := newPVC("1")
+
+	actualResourceMetrics := getMetricsForPVC(pvc)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 3, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, rm.resource, k8sType,
+		map[string]string{
+			"k8s.persistentvolumeclaim.uid":  "test-pvc-1-uid",
+			"k8s.persistentvolumeclaim.name": "test-pvc-1",
+			"k8s.namespace.name":             "test-namespace",
+			"k8s.cluster.name":               "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, rm.metrics[0], "k8s/persistentvolumeclaim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2)
+
+	testutils.AssertMetrics(t, rm.metrics[1], "k8s/persistentvolumeclaim/capacity",
+		metricspb.MetricDescriptor_GAUGE_INT64, 2*1024*1024*1024)
+
+	testutils.AssertMetrics(t, rm.metrics[2], "k8s/persistentvolumeclaim/request",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1024*1024*1024)
+
+	// Claims that are not bound yet have no capacity.
+	pvc.Status = corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending}
+	rm = getMetricsForPVC(pvc)[0]
+	require.Equal(t, 2, len(rm.metrics))
+	testutils.AssertMetrics(t, rm.metrics[0], "k8s/persistentvolumeclaim/phase",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+	testutils.AssertMetrics(t, rm.metrics[1], "k8s/persistentvolumeclaim/request",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1024*1024*1024)
+}
+
+func TestPVCMetadata(t *testing.T) {
+	pvc := newPVC("1")
+
+	actualMetadata := getMetadataForPVC(pvc)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.persistentvolumeclaim.uid",
+			resourceID:    "test-pvc-1-uid",
+			metadata: map[string]string{
+				"persistentvolumeclaim.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":               "bar",
+				"storage_class":     "standard",
+				"volume_name":       "test-volume",
+				"access_modes":      "ReadWriteOnce,ReadOnlyMany",
+				"k8s.workload.kind": "PersistentVolumeClaim",
+				"k8s.workload.name": "test-pvc-1",
+			},
+		},
+		*actualMetadata["test-pvc-1-uid"],
+	)
+}
+
+func newPVC(id string) *corev1.PersistentVolumeClaim {
+	storageClass := "standard"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pvc-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-pvc-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadOnlyMany},
+			StorageClassName: &storageClass,
+			VolumeName:       "test-volume",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("1Gi"),
+				},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+			Capacity: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("2Gi"),
+			},
+		},
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for pod disruption budget metadata.
+	pdbKeyMinAvailable   = "min_available"
+	pdbKeyMaxUnavailable = "max_unavailable"
+)
+
+var pdbCurrentHealthyMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/poddisruptionbudget/current_healthy",
+	Description: "Current number of healthy pods selected by the pod disruption budget",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var pdbDesiredHealthyMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/poddisruptionbudget/desired_healthy",
+	Description: "Minimum number of healthy pods required by the pod disruption budget",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var pdbDisruptionsAllowedMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/poddisruptionbudget/disruptions_allowed",
+	Description: "Number of pod disruptions that are currently allowed",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var pdbExpectedPodsMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/poddisruptionbudget/expected_pods",
+	Description: "Total number of pods counted by the pod disruption budget",
+	Unit:        "1",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPDB(pdb *policyv1beta1.PodDisruptionBudget) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: pdbCurrentHealthyMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pdb.Status.CurrentHealthy)),
+			},
+		},
+		{
+			MetricDescriptor: pdbDesiredHealthyMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pdb.Status.DesiredHealthy)),
+			},
+		},
+		{
+			MetricDescriptor: pdbDisruptionsAllowedMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pdb.Status.DisruptionsAllowed)),
+			},
+		},
+		{
+			MetricDescriptor: pdbExpectedPodsMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(pdb.Status.ExpectedPods)),
+			},
+		},
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPDB(pdb),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPDB(pdb *policyv1beta1.PodDisruptionBudget) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPDBUID:                      string(pdb.UID),
+			k8sKeyPDBName:                     pdb.Name,
+			conventions.AttributeK8sNamespace: pdb.Namespace,
+			conventions.AttributeK8sCluster:   pdb.ClusterName,
+		},
+	}
+}
+
+func getMetadataForPDB(pdb *policyv1beta1.PodDisruptionBudget) map[ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pdb.ObjectMeta, k8sKindPDB)
+	if pdb.Spec.MinAvailable != nil {
+		rm.metadata[pdbKeyMinAvailable] = pdb.Spec.MinAvailable.String()
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		rm.metadata[pdbKeyMaxUnavailable] = pdb.Spec.MaxUnavailable.String()
+	}
+	return map[ResourceID]*KubernetesMetadata{ResourceID(pdb.UID): rm}
+}
@@ -0,0 +1,105 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestPDBMetrics(t *testing.T) {
+	pdb := newPDB("1")
+
+	actualResourceMetrics := getMetricsForPDB(pdb)
+
+	require.Equal(t, 1, len(actualResourceMetrics))
+	require.Equal(t, 4, len(actualResourceMetrics[0].metrics))
+
+	rm := actualResourceMetrics[0]
+	testutils.AssertResource(t, rm.resource, k8sType,
+		map[string]string{
+			"k8s.poddisruptionbudget.uid":  "test-pdb-1-uid",
+			"k8s.poddisruptionbudget.name": "test-pdb-1",
+			"k8s.namespace.name":           "test-namespace",
+			"k8s.cluster.name":             "test-cluster",
+		},
+	)
+
+	testutils.AssertMetrics(t, rm.metrics[0], "k8s/poddisruptionbudget/current_healthy",
+		metricspb.MetricDescriptor_GAUGE_INT64, 4)
+
+	testutils.AssertMetrics(t, rm.metrics[1], "k8s/poddisruptionbudget/desired_healthy",
+		metricspb.MetricDescriptor_GAUGE_INT64, 3)
+
+	testutils.AssertMetrics(t, rm.metrics[2], "k8s/poddisruptionbudget/disruptions_allowed",
+		metricspb.MetricDescriptor_GAUGE_INT64, 1)
+
+	testutils.AssertMetrics(t, rm.metrics[3], "k8s/poddisruptionbudget/expected_pods",
+		metricspb.MetricDescriptor_GAUGE_INT64, 5)
+}
+
+func TestPDBMetadata(t *testing.T) {
+	pdb := newPDB("1")
+
+	actualMetadata := getMetadataForPDB(pdb)
+
+	require.Equal(t, 1, len(actualMetadata))
+	require.Equal(t,
+		KubernetesMetadata{
+			resourceIDKey: "k8s.poddisruptionbudget.uid",
+			resourceID:    "test-pdb-1-uid",
+			metadata: map[string]string{
+				"poddisruptionbudget.creation_timestamp": "0001-01-01T00:00:00Z",
+				"foo":                                    "bar",
+				"min_available":                          "60%",
+				"k8s.workload.kind":                      "PodDisruptionBudget",
+				"k8s.workload.name":                      "test-pdb-1",
+			},
+		},
+		*actualMetadata["test-pdb-1-uid"],
+	)
+}
+
+func newPDB(id string) *policyv1beta1.PodDisruptionBudget {
+	minAvailable := intstr.FromString("60%")
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "test-pdb-" + id,
+			Namespace:   "test-namespace",
+			UID:         types.UID("test-pdb-" + id + "-uid"),
+			ClusterName: "test-cluster",
+			Labels: map[string]string{
+				"foo": "bar",
+			},
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+		},
+		Status: policyv1beta1.PodDisruptionBudgetStatus{
+			CurrentHealthy:     4,
+			DesiredHealthy:     3,
+			DisruptionsAllowed: 1,
+			ExpectedPods:       5,
+		},
+	}
+}
@@ -15,6 +15,8 @@
 package k8sclusterreceiver
 
 import (
+	"errors"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -37,11 +39,81 @@ type Config struct {
 	// List of exporters to which metadata from this receiver should be forwarded to.
 	MetadataExporters []string `mapstructure:"metadata_exporters"`
 
+	// Settings of the collection of Kubernetes events, emitted as log records
+	// when the receiver is part of a logs pipeline.
+	Events EventsConfig `mapstructure:"events"`
+
+	// Settings of the leader election among replicas of the receiver.
+	LeaderElection LeaderElectionConfig `mapstructure:"leader_election"`
+
 	// For mocking.
 	makeClient func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
 }
 
+// EventsConfig defines which Kubernetes events are collected.
+type EventsConfig struct {
+	// Namespaces to collect events from. Events of all namespaces are
+	// collected when empty.
+	Namespaces []string `mapstructure:"namespaces"`
+	// Types of events to collect, e.g. "Warning". Events of all types are
+	// collected when empty.
+	Types []string `mapstructure:"types"`
+}
+
+// LeaderElectionConfig defines how replicas of the receiver elect the one
+// that collects data, using a Lease object.
+type LeaderElectionConfig struct {
+	// Whether to only collect data on the elected replica.
+	Enabled bool `mapstructure:"enabled"`
+	// Name of the Lease object.
+	LeaseName string `mapstructure:"lease_name"`
+	// Namespace of the Lease object.
+	LeaseNamespace string `mapstructure:"lease_namespace"`
+	// Identity of this replica, the hostname is used when empty.
+	Identity string `mapstructure:"identity"`
+	// How long followers wait before taking over a lease that is not renewed.
+	LeaseDuration time.Duration `mapstructure:"lease_duration"`
+	// How long the leader retries renewing the lease before giving up.
+	RenewDeadline time.Duration `mapstructure:"renew_deadline"`
+	// How long to wait between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration `mapstructure:"retry_period"`
+}
+
+func (cfg *Config) getLeaderElectionOptions() (*leaderElectionOptions, error) {
+	le := cfg.LeaderElection
+	if !le.Enabled {
+		return nil, nil
+	}
+
+	if le.LeaseName == "" || le.LeaseNamespace == "" {
+		return nil, errors.New("leader_election: lease_name and lease_namespace must be set")
+	}
+
+	identity := le.Identity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		identity = hostname
+	}
+
+	return &leaderElectionOptions{
+		leaseName:      le.LeaseName,
+		leaseNamespace: le.LeaseNamespace,
+		identity:       identity,
+		leaseDuration:  le.LeaseDuration,
+		renewDeadline:  le.RenewDeadline,
+		retryPeriod:    le.RetryPeriod,
+	}, nil
+}
+
 func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
+	leOptions, err := cfg.getLeaderElectionOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	if cfg.makeClient == nil {
 		cfg.makeClient = k8sconfig.MakeClient
 	}
@@ -56,5 +128,8 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		collectionInterval:         cfg.CollectionInterval,
 		nodeConditionTypesToReport: cfg.NodeConditionTypesToReport,
 		metadataExporters:          cfg.MetadataExporters,
+		eventNamespaces:            cfg.Events.Namespaces,
+		eventTypes:                 cfg.Events.Types,
+		leaderElection:             leOptions,
 	}, nil
 }
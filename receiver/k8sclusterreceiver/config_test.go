@@ -57,6 +57,19 @@ func TestLoadConfig(t *testing.T) {
 			CollectionInterval:         30 * time.Second,
 			NodeConditionTypesToReport: []string{"Ready", "MemoryPressure"},
 			MetadataExporters:          []string{"exampleexporter"},
+			Events: EventsConfig{
+				Namespaces: []string{"default", "kube-system"},
+				Types:      []string{"Warning"},
+			},
+			LeaderElection: LeaderElectionConfig{
+				Enabled:        true,
+				LeaseName:      "otel-collector",
+				LeaseNamespace: "monitoring",
+				Identity:       "collector-0",
+				LeaseDuration:  30 * time.Second,
+				RenewDeadline:  20 * time.Second,
+				RetryPeriod:    5 * time.Second,
+			},
 			APIConfig: k8sconfig.APIConfig{
 				AuthType: k8sconfig.AuthTypeServiceAccount,
 			},
@@ -74,5 +87,12 @@ func TestLoadConfig(t *testing.T) {
 			APIConfig: k8sconfig.APIConfig{
 				AuthType: k8sconfig.AuthTypeServiceAccount,
 			},
+			LeaderElection: LeaderElectionConfig{
+				LeaseName:      defaultLeaseName,
+				LeaseNamespace: defaultLeaseNamespace,
+				LeaseDuration:  defaultLeaseDuration,
+				RenewDeadline:  defaultRenewDeadline,
+				RetryPeriod:    defaultRetryPeriod,
+			},
 		})
 }
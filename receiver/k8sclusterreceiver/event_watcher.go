@@ -0,0 +1,248 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Keys for event log record attributes.
+const (
+	k8sKeyObjectKind           = "k8s.object.kind"
+	k8sKeyObjectName           = "k8s.object.name"
+	k8sKeyObjectUID            = "k8s.object.uid"
+	k8sKeyEventName            = "k8s.event.name"
+	k8sKeyEventUID             = "k8s.event.uid"
+	k8sKeyEventReason          = "k8s.event.reason"
+	k8sKeyEventType            = "k8s.event.type"
+	k8sKeyEventCount           = "k8s.event.count"
+	k8sKeyEventSourceComponent = "k8s.event.source.component"
+	k8sKeyEventSourceHost      = "k8s.event.source.host"
+)
+
+// eventWatcher watches Kubernetes events and emits each occurrence of an
+// event as a log record.
+type eventWatcher struct {
+	client     kubernetes.Interface
+	logger     *zap.Logger
+	consumer   consumer.LogsConsumer
+	namespaces []string
+	types      map[string]bool
+	// Events that happened before the watcher was started are only recorded,
+	// the informers list all events still stored by the API server on start.
+	startTime time.Time
+
+	ctx context.Context
+
+	mu sync.Mutex
+	// Last reported count of every known event. Events are updated in place
+	// by the API server when they recur, only updates that bump the count
+	// are reported again.
+	reported map[types.UID]int32
+}
+
+func newEventWatcher(logger *zap.Logger, rOptions *receiverOptions, lc consumer.LogsConsumer) *eventWatcher {
+	ew := &eventWatcher{
+		client:     rOptions.client,
+		logger:     logger,
+		consumer:   lc,
+		namespaces: rOptions.eventNamespaces,
+		startTime:  time.Now().Truncate(time.Second),
+		reported:   map[types.UID]int32{},
+	}
+	if len(rOptions.eventTypes) > 0 {
+		ew.types = make(map[string]bool, len(rOptions.eventTypes))
+		for _, t := range rOptions.eventTypes {
+			ew.types[strings.ToLower(t)] = true
+		}
+	}
+	return ew
+}
+
+// startWatchingEvents starts up event informers, one for every configured
+// namespace or a single one for all namespaces. They are stopped when ctx
+// is done.
+func (ew *eventWatcher) startWatchingEvents(ctx context.Context) {
+	ew.ctx = ctx
+
+	namespaces := ew.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	for _, ns := range namespaces {
+		factory := informers.NewSharedInformerFactoryWithOptions(ew.client, 0, informers.WithNamespace(ns))
+		factory.Core().V1().Events().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    ew.onAdd,
+			UpdateFunc: ew.onUpdate,
+			DeleteFunc: ew.onDelete,
+		})
+		factory.Start(ctx.Done())
+	}
+}
+
+func (ew *eventWatcher) onAdd(obj interface{}) {
+	if ev, ok := obj.(*corev1.Event); ok {
+		ew.handleEvent(ev)
+	}
+}
+
+func (ew *eventWatcher) onUpdate(_, newObj interface{}) {
+	if ev, ok := newObj.(*corev1.Event); ok {
+		ew.handleEvent(ev)
+	}
+}
+
+func (ew *eventWatcher) onDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	ew.mu.Lock()
+	delete(ew.reported, ev.UID)
+	ew.mu.Unlock()
+}
+
+func (ew *eventWatcher) handleEvent(ev *corev1.Event) {
+	if ew.types != nil && !ew.types[strings.ToLower(ev.Type)] {
+		return
+	}
+
+	count := eventCount(ev)
+
+	ew.mu.Lock()
+	last, seen := ew.reported[ev.UID]
+	if seen && count <= last {
+		ew.mu.Unlock()
+		return
+	}
+	ew.reported[ev.UID] = count
+	ew.mu.Unlock()
+
+	// Record events that occurred before start, but do not report them.
+	ts := eventTime(ev)
+	if !seen && ts.Before(ew.startTime) {
+		return
+	}
+
+	ctx := ew.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ew.consumer.ConsumeLogs(ctx, eventToLogs(ev, ts, count)); err != nil {
+		ew.logger.Error("Failed to consume Kubernetes event",
+			zap.String("event", ev.Namespace+"/"+ev.Name), zap.Error(err))
+	}
+}
+
+// eventCount returns how many times the event occurred so far.
+func eventCount(ev *corev1.Event) int32 {
+	count := ev.Count
+	if ev.Series != nil && ev.Series.Count > count {
+		count = ev.Series.Count
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+// eventTime returns the time of the last occurrence of the event.
+func eventTime(ev *corev1.Event) time.Time {
+	switch {
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		return ev.Series.LastObservedTime.Time
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.Time
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.Time
+	case !ev.FirstTimestamp.IsZero():
+		return ev.FirstTimestamp.Time
+	}
+	return ev.CreationTimestamp.Time
+}
+
+func eventToLogs(ev *corev1.Event, ts time.Time, count int32) pdata.Logs {
+	logs := pdata.NewLogs()
+	rls := logs.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	lrs := ills.At(0).Logs()
+	lrs.Resize(1)
+	lr := lrs.At(0)
+
+	lr.SetName(ev.Reason)
+	lr.SetTimestamp(pdata.TimestampUnixNano(uint64(ts.UnixNano())))
+	lr.Body().SetStringVal(ev.Message)
+	lr.SetSeverityText(ev.Type)
+	if ev.Type == corev1.EventTypeWarning {
+		lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	} else {
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	}
+
+	attrs := lr.Attributes()
+	attrs.InitEmptyWithCapacity(11)
+	attrs.InsertString(k8sKeyObjectKind, ev.InvolvedObject.Kind)
+	attrs.InsertString(k8sKeyObjectName, ev.InvolvedObject.Name)
+	attrs.InsertString(k8sKeyObjectUID, string(ev.InvolvedObject.UID))
+	namespace := ev.InvolvedObject.Namespace
+	if namespace == "" {
+		namespace = ev.Namespace
+	}
+	attrs.InsertString(conventions.AttributeK8sNamespace, namespace)
+	attrs.InsertString(k8sKeyEventName, ev.Name)
+	attrs.InsertString(k8sKeyEventUID, string(ev.UID))
+	attrs.InsertString(k8sKeyEventReason, ev.Reason)
+	attrs.InsertString(k8sKeyEventType, ev.Type)
+	attrs.InsertInt(k8sKeyEventCount, int64(count))
+
+	component, host := ev.Source.Component, ev.Source.Host
+	if component == "" {
+		component = ev.ReportingController
+	}
+	if host == "" {
+		host = ev.ReportingInstance
+	}
+	if component != "" {
+		attrs.InsertString(k8sKeyEventSourceComponent, component)
+	}
+	if host != "" {
+		attrs.InsertString(k8sKeyEventSourceHost, host)
+	}
+
+	return logs
+}
@@ -0,0 +1,192 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestEvent(namespace, name, eventType string, count int32, ts time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			UID:       types.UID(namespace + "-" + name),
+			Name:      name,
+			Namespace: namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "pod-1",
+			Namespace: namespace,
+			UID:       "pod-uid",
+		},
+		Reason:        "BackOff",
+		Message:       "Back-off restarting failed container",
+		Type:          eventType,
+		Count:         count,
+		LastTimestamp: v1.NewTime(ts),
+		Source:        corev1.EventSource{Component: "kubelet", Host: "node-1"},
+	}
+}
+
+func setupEventWatcher(t *testing.T, client *fake.Clientset, namespaces, eventTypes []string) *exportertest.SinkLogsExporter {
+	sink := &exportertest.SinkLogsExporter{}
+	ew := newEventWatcher(zap.NewNop(), &receiverOptions{
+		client:          client,
+		eventNamespaces: namespaces,
+		eventTypes:      eventTypes,
+	}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	ew.startWatchingEvents(ctx)
+	return sink
+}
+
+func createEvent(t *testing.T, client *fake.Clientset, ev *corev1.Event) {
+	_, err := client.CoreV1().Events(ev.Namespace).Create(context.Background(), ev, v1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+func updateEvent(t *testing.T, client *fake.Clientset, ev *corev1.Event) {
+	_, err := client.CoreV1().Events(ev.Namespace).Update(context.Background(), ev, v1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func waitForLogRecords(t *testing.T, sink *exportertest.SinkLogsExporter, count int) {
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == count
+	}, 5*time.Second, 10*time.Millisecond)
+	// Make sure nothing else is reported.
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, count, sink.LogRecordsCount())
+}
+
+func TestEventWatcher(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := setupEventWatcher(t, client, nil, nil)
+
+	now := time.Now().Add(time.Second)
+	ev := newTestEvent("test", "event-1", corev1.EventTypeWarning, 1, now)
+	createEvent(t, client, ev)
+	waitForLogRecords(t, sink, 1)
+
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "BackOff", lr.Name())
+	assert.Equal(t, "Back-off restarting failed container", lr.Body().StringVal())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(uint64(now.UnixNano())), lr.Timestamp())
+
+	expected := map[string]pdata.AttributeValue{
+		"k8s.object.kind":            pdata.NewAttributeValueString("Pod"),
+		"k8s.object.name":            pdata.NewAttributeValueString("pod-1"),
+		"k8s.object.uid":             pdata.NewAttributeValueString("pod-uid"),
+		"k8s.namespace.name":         pdata.NewAttributeValueString("test"),
+		"k8s.event.name":             pdata.NewAttributeValueString("event-1"),
+		"k8s.event.uid":              pdata.NewAttributeValueString("test-event-1"),
+		"k8s.event.reason":           pdata.NewAttributeValueString("BackOff"),
+		"k8s.event.type":             pdata.NewAttributeValueString("Warning"),
+		"k8s.event.count":            pdata.NewAttributeValueInt(1),
+		"k8s.event.source.component": pdata.NewAttributeValueString("kubelet"),
+		"k8s.event.source.host":      pdata.NewAttributeValueString("node-1"),
+	}
+	assert.Equal(t, len(expected), lr.Attributes().Len())
+	for k, v := range expected {
+		got, ok := lr.Attributes().Get(k)
+		require.True(t, ok, k)
+		assert.True(t, v.Equal(got), k)
+	}
+
+	// Updates that do not bump the count are not reported again.
+	ev.Message = "updated"
+	updateEvent(t, client, ev)
+	waitForLogRecords(t, sink, 1)
+
+	// Recurrences are.
+	ev.Count = 2
+	updateEvent(t, client, ev)
+	waitForLogRecords(t, sink, 2)
+
+	lr = sink.AllLogs()[1].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	count, ok := lr.Attributes().Get("k8s.event.count")
+	require.True(t, ok)
+	assert.EqualValues(t, 2, count.IntVal())
+}
+
+func TestEventWatcherFilters(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := setupEventWatcher(t, client, []string{"test", "other"}, []string{"warning"})
+
+	now := time.Now().Add(time.Second)
+	createEvent(t, client, newTestEvent("test", "normal", corev1.EventTypeNormal, 1, now))
+	createEvent(t, client, newTestEvent("ignored", "warning", corev1.EventTypeWarning, 1, now))
+	createEvent(t, client, newTestEvent("test", "warning", corev1.EventTypeWarning, 1, now))
+	createEvent(t, client, newTestEvent("other", "warning", corev1.EventTypeWarning, 1, now))
+	waitForLogRecords(t, sink, 2)
+
+	var names []string
+	for _, l := range sink.AllLogs() {
+		lr := l.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+		ns, _ := lr.Attributes().Get("k8s.namespace.name")
+		names = append(names, ns.StringVal())
+	}
+	assert.ElementsMatch(t, []string{"test", "other"}, names)
+}
+
+func TestEventWatcherSkipsPastEvents(t *testing.T) {
+	past := newTestEvent("test", "old", corev1.EventTypeNormal, 3, time.Now().Add(-time.Hour))
+	client := fake.NewSimpleClientset(past)
+	sink := setupEventWatcher(t, client, nil, nil)
+
+	waitForLogRecords(t, sink, 0)
+
+	// The past event is reported once it recurs.
+	past.Count = 4
+	past.LastTimestamp = v1.NewTime(time.Now().Add(time.Second))
+	updateEvent(t, client, past)
+	waitForLogRecords(t, sink, 1)
+
+	lr := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.SeverityNumberINFO, lr.SeverityNumber())
+}
+
+func TestEventCountAndTime(t *testing.T) {
+	ts := time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC)
+	ev := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{CreationTimestamp: v1.NewTime(ts)},
+	}
+	assert.EqualValues(t, 1, eventCount(ev))
+	assert.Equal(t, ts, eventTime(ev))
+
+	ev.EventTime = v1.NewMicroTime(ts.Add(time.Minute))
+	assert.Equal(t, ts.Add(time.Minute), eventTime(ev))
+
+	ev.Count = 2
+	ev.Series = &corev1.EventSeries{Count: 5, LastObservedTime: v1.NewMicroTime(ts.Add(time.Hour))}
+	assert.EqualValues(t, 5, eventCount(ev))
+	assert.Equal(t, ts.Add(time.Hour), eventTime(ev))
+}
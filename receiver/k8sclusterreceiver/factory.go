@@ -25,6 +25,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/k8sconfig"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sharedcomponent"
 )
 
 const (
@@ -33,6 +34,12 @@ const (
 
 	// Default config values.
 	defaultCollectionInterval = 10 * time.Second
+
+	defaultLeaseName      = "otel-k8s-cluster-receiver"
+	defaultLeaseNamespace = "default"
+	defaultLeaseDuration  = 15 * time.Second
+	defaultRenewDeadline  = 10 * time.Second
+	defaultRetryPeriod    = 2 * time.Second
 )
 
 var defaultNodeConditionsToReport = []string{"Ready"}
@@ -43,6 +50,10 @@ type receiverOptions struct {
 	collectionInterval         time.Duration
 	nodeConditionTypesToReport []string
 	metadataExporters          []string
+	eventNamespaces            []string
+	eventTypes                 []string
+	// Set when leader election is enabled.
+	leaderElection *leaderElectionOptions
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -56,25 +67,71 @@ func createDefaultConfig() configmodels.Receiver {
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
+		LeaderElection: LeaderElectionConfig{
+			LeaseName:      defaultLeaseName,
+			LeaseNamespace: defaultLeaseNamespace,
+			LeaseDuration:  defaultLeaseDuration,
+			RenewDeadline:  defaultRenewDeadline,
+			RetryPeriod:    defaultRetryPeriod,
+		},
 	}
 }
 
 func createMetricsReceiver(
 	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.registerMetricsConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// createLogsReceiver creates a logs receiver, for Kubernetes events, based on
+// provided config.
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.registerLogsConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// getReceiver returns the receiver for the given config, creating it on
+// first use so metrics and logs pipelines share the same K8s client.
+func getReceiver(params component.ReceiverCreateParams, cfg configmodels.Receiver) (*kubernetesReceiver, error) {
 	rCfg := cfg.(*Config)
 
-	rOptions, err := rCfg.getReceiverOptions()
+	r, err := receivers.GetOrAdd(rCfg, func() (interface{}, error) {
+		rOptions, err := rCfg.getReceiverOptions()
+		if err != nil {
+			return nil, err
+		}
+		return newReceiver(params.Logger, rOptions)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return newReceiver(params.Logger, rOptions, consumer)
+	return r.(*kubernetesReceiver), nil
 }
 
+// receivers holds the kubernetesReceiver shared by the metrics and logs
+// pipelines of each configuration.
+var receivers = sharedcomponent.NewComponents()
+
 // NewFactory creates a factory for k8s_cluster receiver.
 func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
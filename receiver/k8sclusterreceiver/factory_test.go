@@ -45,6 +45,13 @@ func TestFactory(t *testing.T) {
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
+		LeaderElection: LeaderElectionConfig{
+			LeaseName:      defaultLeaseName,
+			LeaseNamespace: defaultLeaseNamespace,
+			LeaseDuration:  defaultLeaseDuration,
+			RenewDeadline:  defaultRenewDeadline,
+			RetryPeriod:    defaultRetryPeriod,
+		},
 	}, rCfg)
 
 	r, err := f.CreateTraceReceiver(
@@ -73,3 +80,41 @@ func TestFactory(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, r)
 }
+
+func TestFactoryLogsReceiver(t *testing.T) {
+	f := NewFactory()
+	rCfg := f.CreateDefaultConfig().(*Config)
+	rCfg.makeClient = func(apiConf k8sconfig.APIConfig) (kubernetes.Interface, error) {
+		return nil, nil
+	}
+
+	lr, err := f.(component.LogsReceiverFactory).CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, lr)
+
+	// Metrics and logs pipelines share the receiver of the same config.
+	mr, err := f.CreateMetricsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, &exportertest.SinkMetricsExporter{},
+	)
+	require.NoError(t, err)
+	require.Same(t, lr, mr)
+
+	_, err = f.(component.LogsReceiverFactory).CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, nil,
+	)
+	require.Error(t, err)
+
+	// A receiver that was shut down is not handed out again.
+	require.NoError(t, mr.Shutdown(context.Background()))
+	lr, err = f.(component.LogsReceiverFactory).CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.NoError(t, err)
+	require.NotSame(t, mr, lr)
+}
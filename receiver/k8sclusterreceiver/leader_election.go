@@ -0,0 +1,95 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+type leaderElectionOptions struct {
+	leaseName      string
+	leaseNamespace string
+	identity       string
+	leaseDuration  time.Duration
+	renewDeadline  time.Duration
+	retryPeriod    time.Duration
+}
+
+// newLeaderElector returns an elector campaigning for the Lease configured
+// in the receiver options. lead is called with a context that is cancelled
+// once the leadership is lost.
+func (kr *kubernetesReceiver) newLeaderElector(lead func(ctx context.Context)) (*leaderelection.LeaderElector, error) {
+	opts := kr.options.leaderElection
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      opts.leaseName,
+			Namespace: opts.leaseNamespace,
+		},
+		Client: kr.options.client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: opts.identity,
+		},
+	}
+
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   opts.leaseDuration,
+		RenewDeadline:   opts.renewDeadline,
+		RetryPeriod:     opts.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            kr.options.name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				kr.logger.Info("Started leading, collecting cluster data",
+					zap.String("identity", opts.identity))
+				lead(ctx)
+			},
+			OnStoppedLeading: func() {
+				kr.logger.Info("Stopped leading", zap.String("identity", opts.identity))
+			},
+			OnNewLeader: func(identity string) {
+				if identity != opts.identity {
+					kr.logger.Info("New leader elected", zap.String("leader", identity))
+				}
+			},
+		},
+	})
+}
+
+// runLeaderElection campaigns for the leadership until ctx is done. Every
+// time the leadership is acquired, lead is called, and once it is lost the
+// receiver becomes a follower again and waits to take over the lease.
+func (kr *kubernetesReceiver) runLeaderElection(ctx context.Context, le *leaderelection.LeaderElector, lead func(ctx context.Context)) {
+	for {
+		le.Run(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// An elector does not campaign again once its leadership is lost.
+		var err error
+		if le, err = kr.newLeaderElector(lead); err != nil {
+			kr.logger.Error("Failed to restart leader election", zap.Error(err))
+			return
+		}
+	}
+}
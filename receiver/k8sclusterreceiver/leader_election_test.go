@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func setupLeaderElectionReceiver(t *testing.T, client *fake.Clientset, identity string) (*kubernetesReceiver, *exportertest.SinkMetricsExporter) {
+	r, err := newReceiver(zap.NewNop(), &receiverOptions{
+		name:                       "k8s_cluster",
+		client:                     client,
+		collectionInterval:         50 * time.Millisecond,
+		nodeConditionTypesToReport: []string{"Ready"},
+		leaderElection: &leaderElectionOptions{
+			leaseName:      "test-lease",
+			leaseNamespace: "test",
+			identity:       identity,
+			leaseDuration:  time.Second,
+			renewDeadline:  500 * time.Millisecond,
+			retryPeriod:    100 * time.Millisecond,
+		},
+	})
+	require.NoError(t, err)
+
+	sink := &exportertest.SinkMetricsExporter{}
+	require.NoError(t, r.registerMetricsConsumer(sink))
+	return r, sink
+}
+
+func leaseHolder(t *testing.T, client *fake.Clientset) string {
+	lease, err := client.CoordinationV1().Leases("test").Get(context.Background(), "test-lease", v1.GetOptions{})
+	if err != nil || lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+func TestLeaderElection(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createPods(t, client, 2)
+
+	ctx := context.Background()
+
+	leader, leaderSink := setupLeaderElectionReceiver(t, client, "leader")
+	require.NoError(t, leader.Start(ctx, componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return leaseHolder(t, client) == "leader"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	follower, followerSink := setupLeaderElectionReceiver(t, client, "follower")
+	require.NoError(t, follower.Start(ctx, componenttest.NewNopHost()))
+	defer follower.Shutdown(ctx)
+
+	// Only the leader emits.
+	require.Eventually(t, func() bool {
+		return leaderSink.MetricsCount() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 0, followerSink.MetricsCount())
+
+	// The follower takes over once the leader is gone.
+	require.NoError(t, leader.Shutdown(ctx))
+	require.Eventually(t, func() bool {
+		return leaseHolder(t, client) == "follower"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return followerSink.MetricsCount() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The former leader does not emit anymore.
+	count := leaderSink.MetricsCount()
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, count, leaderSink.MetricsCount())
+}
+
+func TestLeaderElectionInvalidConfig(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r, _ := setupLeaderElectionReceiver(t, client, "leader")
+	r.options.leaderElection.renewDeadline = 2 * time.Second
+
+	require.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestLeaderElectionOptions(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+
+	opts, err := cfg.getLeaderElectionOptions()
+	require.NoError(t, err)
+	assert.Nil(t, opts)
+
+	cfg.LeaderElection.Enabled = true
+	opts, err = cfg.getLeaderElectionOptions()
+	require.NoError(t, err)
+	assert.Equal(t, defaultLeaseName, opts.leaseName)
+	assert.Equal(t, defaultLeaseNamespace, opts.leaseNamespace)
+	assert.NotEmpty(t, opts.identity)
+
+	cfg.LeaderElection.LeaseNamespace = ""
+	_, err = cfg.getLeaderElectionOptions()
+	require.Error(t, err)
+}
@@ -16,9 +16,11 @@ package k8sclusterreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
@@ -31,28 +33,86 @@ const (
 )
 
 var _ component.MetricsReceiver = (*kubernetesReceiver)(nil)
+var _ component.LogsReceiver = (*kubernetesReceiver)(nil)
 
 type kubernetesReceiver struct {
+	sync.Mutex
 	resourceWatcher *resourceWatcher
 
-	options  *receiverOptions
-	logger   *zap.Logger
-	consumer consumer.MetricsConsumer
-	cancel   context.CancelFunc
+	options      *receiverOptions
+	logger       *zap.Logger
+	consumer     consumer.MetricsConsumer
+	logsConsumer consumer.LogsConsumer
+	cancel       context.CancelFunc
+
+	startOnce sync.Once
+	stopOnce  sync.Once
 }
 
 func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) error {
+	kr.Lock()
+	defer kr.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	kr.startOnce.Do(func() {
+		err = kr.start(ctx, host)
+	})
+	return err
+}
+
+func (kr *kubernetesReceiver) start(ctx context.Context, host component.Host) error {
 	var c context.Context
 	c, kr.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, transport, kr.options.name))
 
-	exporters := host.GetExporters()
-	if err := kr.resourceWatcher.setupMetadataExporters(
-		exporters[configmodels.MetricsDataType], kr.options.metadataExporters); err != nil {
+	exporters := host.GetExporters()[configmodels.MetricsDataType]
+	if kr.consumer != nil {
+		if err := kr.resourceWatcher.setupMetadataExporters(exporters, kr.options.metadataExporters); err != nil {
+			return err
+		}
+	}
+
+	if kr.options.leaderElection == nil {
+		kr.collect(c, kr.resourceWatcher)
+		return nil
+	}
+
+	// Every term of leadership starts with fresh informers, informers can
+	// not be restarted once stopped.
+	lead := func(leaderCtx context.Context) {
+		rw, err := newResourceWatcher(kr.logger, kr.options)
+		if err == nil && kr.consumer != nil {
+			err = rw.setupMetadataExporters(exporters, kr.options.metadataExporters)
+		}
+		if err != nil {
+			kr.logger.Error("Failed to set up resource watcher", zap.Error(err))
+			return
+		}
+		kr.collect(leaderCtx, rw)
+	}
+
+	le, err := kr.newLeaderElector(lead)
+	if err != nil {
 		return err
 	}
+	go kr.runLeaderElection(c, le, lead)
+
+	return nil
+}
+
+// collect starts watching the cluster and emitting data to the registered
+// consumers until ctx is done.
+func (kr *kubernetesReceiver) collect(ctx context.Context, rw *resourceWatcher) {
+	if kr.logsConsumer != nil {
+		ew := newEventWatcher(kr.logger, kr.options, kr.logsConsumer)
+		ew.startWatchingEvents(ctx)
+	}
+
+	if kr.consumer == nil {
+		return
+	}
 
 	go func() {
-		kr.resourceWatcher.startWatchingResources(c.Done())
+		rw.startWatchingResources(ctx.Done())
 
 		ticker := time.NewTicker(kr.options.collectionInterval)
 		defer ticker.Stop()
@@ -60,24 +120,57 @@ func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) er
 		for {
 			select {
 			case <-ticker.C:
-				kr.dispatchMetrics(c)
-			case <-c.Done():
+				kr.dispatchMetrics(ctx, rw)
+			case <-ctx.Done():
 				return
 			}
 		}
 	}()
+}
+
+func (kr *kubernetesReceiver) Shutdown(context.Context) error {
+	receivers.Remove(kr)
+
+	kr.Lock()
+	defer kr.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	kr.stopOnce.Do(func() {
+		if kr.cancel != nil {
+			kr.cancel()
+		}
+		err = nil
+	})
+	return err
+}
 
+func (kr *kubernetesReceiver) registerMetricsConsumer(mc consumer.MetricsConsumer) error {
+	if mc == nil {
+		return componenterror.ErrNilNextConsumer
+	}
+
+	kr.Lock()
+	defer kr.Unlock()
+
+	kr.consumer = mc
 	return nil
 }
 
-func (kr *kubernetesReceiver) Shutdown(context.Context) error {
-	kr.cancel()
+func (kr *kubernetesReceiver) registerLogsConsumer(lc consumer.LogsConsumer) error {
+	if lc == nil {
+		return componenterror.ErrNilNextConsumer
+	}
+
+	kr.Lock()
+	defer kr.Unlock()
+
+	kr.logsConsumer = lc
 	return nil
 }
 
-func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context) {
+func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context, rw *resourceWatcher) {
 	now := time.Now()
-	mds := kr.resourceWatcher.dataCollector.CollectMetricData(now)
+	mds := rw.dataCollector.CollectMetricData(now)
 	resourceMetrics := pdatautil.MetricsFromMetricsData(mds)
 
 	c := obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
@@ -89,10 +182,8 @@ func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context) {
 }
 
 // newReceiver creates the Kubernetes cluster receiver with the given configuration.
-func newReceiver(
-	logger *zap.Logger, rOptions *receiverOptions,
-	consumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
-
+// Consumers are registered separately for metrics and logs pipelines.
+func newReceiver(logger *zap.Logger, rOptions *receiverOptions) (*kubernetesReceiver, error) {
 	resourceWatcher, err := newResourceWatcher(logger, rOptions)
 	if err != nil {
 		return nil, err
@@ -102,6 +193,5 @@ func newReceiver(
 		resourceWatcher: resourceWatcher,
 		logger:          logger,
 		options:         rOptions,
-		consumer:        consumer,
 	}, nil
 }
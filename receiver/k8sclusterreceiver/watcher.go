@@ -25,6 +25,8 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -69,6 +71,10 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	)
 	rw.setupInformers(&corev1.ResourceQuota{}, factory.Core().V1().ResourceQuotas().Informer())
 	rw.setupInformers(&corev1.Service{}, factory.Core().V1().Services().Informer())
+	rw.setupInformers(&corev1.Endpoints{}, factory.Core().V1().Endpoints().Informer())
+	rw.setupInformers(&corev1.PersistentVolumeClaim{},
+		factory.Core().V1().PersistentVolumeClaims().Informer(),
+	)
 	rw.setupInformers(&appsv1.DaemonSet{}, factory.Apps().V1().DaemonSets().Informer())
 	rw.setupInformers(&appsv1.Deployment{}, factory.Apps().V1().Deployments().Informer())
 	rw.setupInformers(&appsv1.ReplicaSet{}, factory.Apps().V1().ReplicaSets().Informer())
@@ -78,6 +84,10 @@ func (rw *resourceWatcher) prepareSharedInformerFactory() {
 	rw.setupInformers(&v2beta1.HorizontalPodAutoscaler{},
 		factory.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer(),
 	)
+	rw.setupInformers(&networkingv1beta1.Ingress{}, factory.Networking().V1beta1().Ingresses().Informer())
+	rw.setupInformers(&policyv1beta1.PodDisruptionBudget{},
+		factory.Policy().V1beta1().PodDisruptionBudgets().Informer(),
+	)
 
 	rw.sharedInformerFactory = factory
 }
@@ -16,11 +16,14 @@ package kubeletstatsreceiver
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+	k8s "k8s.io/client-go/kubernetes"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 )
 
@@ -35,12 +38,30 @@ type Config struct {
 	// ExtraMetadataLabels contains list of extra metadata that should be taken from /pods endpoint
 	// and put as extra labels on metrics resource.
 	// No additional metadata is fetched by default, so there are no extra calls to /pods endpoint.
-	// Supported values include container.id and k8s.volume.type.
+	// Supported values include container.id, k8s.volume.type, k8s.pod.labels,
+	// k8s.pod.qos_class, k8s.workload and k8s.node.labels.
 	ExtraMetadataLabels []kubelet.MetadataLabel `mapstructure:"extra_metadata_labels"`
 
+	// LabelFilter selects the pod and node labels added by the k8s.pod.labels
+	// and k8s.node.labels extra metadata labels. All labels are added by default.
+	LabelFilter kubelet.LabelFilter `mapstructure:"label_filter"`
+
+	// K8sAPIConfig configures the client of the Kubernetes API server used
+	// to look up owner workloads of pods and node labels. It is required
+	// by the k8s.workload and k8s.node.labels extra metadata labels.
+	K8sAPIConfig *k8sconfig.APIConfig `mapstructure:"k8s_api_config"`
+
 	// MetricGroupsToCollect provides a list of metrics groups to collect metrics from.
 	// "container", "pod", "node" and "volume" are the only valid groups.
 	MetricGroupsToCollect []kubelet.MetricGroup `mapstructure:"metric_groups"`
+
+	// MetricSources provides a list of kubelet endpoints to collect metrics from.
+	// "summary" (/stats/summary), "cadvisor" (/metrics/cadvisor) and "resource"
+	// (/metrics/resource) are the only valid sources. Only "summary" is used by default.
+	MetricSources []kubelet.MetricSource `mapstructure:"metric_sources"`
+
+	// For mocking.
+	makeClient func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
 }
 
 // getReceiverOptions returns receiverOptions is the config is valid,
@@ -56,14 +77,67 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		return nil, err
 	}
 
+	sources, err := getMetricSources(cfg.MetricSources)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sAPIClient, err := cfg.getK8sAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
 	return &receiverOptions{
 		name:                  cfg.Name(),
 		collectionInterval:    cfg.CollectionInterval,
 		extraMetadataLabels:   cfg.ExtraMetadataLabels,
+		labelFilter:           cfg.LabelFilter,
+		k8sAPIClient:          k8sAPIClient,
 		metricGroupsToCollect: mgs,
+		metricSources:         sources,
 	}, nil
 }
 
+// getK8sAPIClient returns a client of the Kubernetes API server if any of
+// the extra metadata labels requires it, nil otherwise.
+func (cfg *Config) getK8sAPIClient() (k8s.Interface, error) {
+	if !kubelet.RequiresAPIMetadata(cfg.ExtraMetadataLabels) {
+		return nil, nil
+	}
+	if cfg.K8sAPIConfig == nil {
+		return nil, errors.New("k8s_api_config must be set to use k8s.workload or k8s.node.labels extra metadata labels")
+	}
+
+	if cfg.makeClient == nil {
+		cfg.makeClient = k8sconfig.MakeClient
+	}
+	return cfg.makeClient(*cfg.K8sAPIConfig)
+}
+
+// getMetricSources returns a set of kubelet.MetricSource values from the
+// provided list, nil if it is empty. Returns an err if invalid entries are
+// encountered.
+func getMetricSources(sources []kubelet.MetricSource) (map[kubelet.MetricSource]bool, error) {
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[kubelet.MetricSource]bool, len(sources))
+	for _, s := range sources {
+		if !kubelet.ValidMetricSources[s] {
+			return nil, fmt.Errorf("invalid entry in metric_sources: %q", s)
+		}
+		out[s] = true
+	}
+
+	// /metrics/resource is a subset of /stats/summary.
+	if out[kubelet.SummaryMetricSource] && out[kubelet.ResourceMetricSource] {
+		return nil, errors.New("metric_sources: summary and resource can not be used together")
+	}
+
+	return out, nil
+}
+
 // getMapFromSlice returns a set of kubelet.MetricGroup values from
 // the provided list. Returns an err if invalid entries are encountered.
 func getMapFromSlice(collect []kubelet.MetricGroup) (map[kubelet.MetricGroup]bool, error) {
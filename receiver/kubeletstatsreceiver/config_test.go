@@ -26,6 +26,8 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
 	"go.opentelemetry.io/collector/config/configtls"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
@@ -151,12 +153,69 @@ func TestLoadConfig(t *testing.T) {
 			kubelet.VolumeMetricGroup,
 		},
 	}, metricGroupsCfg)
+
+	metricSourcesCfg := cfg.Receivers["kubeletstats/metric_sources"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/metric_sources",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "serviceAccount",
+			},
+		},
+		CollectionInterval: 20 * time.Second,
+		MetricGroupsToCollect: []kubelet.MetricGroup{
+			kubelet.ContainerMetricGroup,
+			kubelet.PodMetricGroup,
+			kubelet.NodeMetricGroup,
+		},
+		MetricSources: []kubelet.MetricSource{
+			kubelet.SummaryMetricSource,
+			kubelet.CAdvisorMetricSource,
+		},
+	}, metricSourcesCfg)
+
+	podMetadataCfg := cfg.Receivers["kubeletstats/pod_metadata"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/pod_metadata",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "serviceAccount",
+			},
+		},
+		CollectionInterval: duration,
+		ExtraMetadataLabels: []kubelet.MetadataLabel{
+			kubelet.MetadataLabelPodLabels,
+			kubelet.MetadataLabelQOSClass,
+			kubelet.MetadataLabelWorkload,
+			kubelet.MetadataLabelNodeLabels,
+		},
+		LabelFilter: kubelet.LabelFilter{
+			Exclude: []string{"pod-template-hash", "controller-revision-hash"},
+		},
+		K8sAPIConfig: &k8sconfig.APIConfig{
+			AuthType: "serviceAccount",
+		},
+		MetricGroupsToCollect: []kubelet.MetricGroup{
+			kubelet.ContainerMetricGroup,
+			kubelet.PodMetricGroup,
+			kubelet.NodeMetricGroup,
+		},
+	}, podMetadataCfg)
 }
 
 func TestGetReceiverOptions(t *testing.T) {
+	fakeK8sClient := fake.NewSimpleClientset()
 	type fields struct {
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect []kubelet.MetricGroup
+		metricSources         []kubelet.MetricSource
+		k8sAPIConfig          *k8sconfig.APIConfig
 	}
 	tests := []struct {
 		name    string
@@ -207,6 +266,73 @@ func TestGetReceiverOptions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Valid metric sources",
+			fields: fields{
+				metricSources: []kubelet.MetricSource{
+					kubelet.ResourceMetricSource,
+					kubelet.CAdvisorMetricSource,
+				},
+			},
+			want: &receiverOptions{
+				name:                  typeStr,
+				metricGroupsToCollect: map[kubelet.MetricGroup]bool{},
+				metricSources: map[kubelet.MetricSource]bool{
+					kubelet.ResourceMetricSource: true,
+					kubelet.CAdvisorMetricSource: true,
+				},
+				collectionInterval: 10 * time.Second,
+			},
+		},
+		{
+			name: "Invalid metric source",
+			fields: fields{
+				metricSources: []kubelet.MetricSource{
+					"unsupported",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Valid workload metadata",
+			fields: fields{
+				extraMetadataLabels: []kubelet.MetadataLabel{
+					kubelet.MetadataLabelWorkload,
+				},
+				k8sAPIConfig: &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeNone},
+			},
+			want: &receiverOptions{
+				name: typeStr,
+				extraMetadataLabels: []kubelet.MetadataLabel{
+					kubelet.MetadataLabelWorkload,
+				},
+				metricGroupsToCollect: map[kubelet.MetricGroup]bool{},
+				collectionInterval:    10 * time.Second,
+				k8sAPIClient:          fakeK8sClient,
+			},
+		},
+		{
+			name: "Workload metadata without k8s_api_config",
+			fields: fields{
+				extraMetadataLabels: []kubelet.MetadataLabel{
+					kubelet.MetadataLabelWorkload,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Summary and resource metric sources",
+			fields: fields{
+				metricSources: []kubelet.MetricSource{
+					kubelet.SummaryMetricSource,
+					kubelet.ResourceMetricSource,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -217,6 +343,11 @@ func TestGetReceiverOptions(t *testing.T) {
 				CollectionInterval:    10 * time.Second,
 				ExtraMetadataLabels:   tt.fields.extraMetadataLabels,
 				MetricGroupsToCollect: tt.fields.metricGroupsToCollect,
+				MetricSources:         tt.fields.metricSources,
+				K8sAPIConfig:          tt.fields.k8sAPIConfig,
+				makeClient: func(apiConf k8sconfig.APIConfig) (k8s.Interface, error) {
+					return fakeK8sClient, nil
+				},
 			}
 			got, err := cfg.getReceiverOptions()
 			if (err != nil) != tt.wantErr {
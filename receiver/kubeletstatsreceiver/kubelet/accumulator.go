@@ -63,10 +63,13 @@ func (a *metricDataAccumulator) nodeStats(s stats.NodeStats) {
 		return
 	}
 
+	resource := nodeResource(s)
+	a.metadata.setNodeMetadata(a.logger, resource.Labels, s.NodeName)
+
 	// todo s.Runtime.ImageFs
 	a.accumulate(
 		timestampProto(s.StartTime.Time),
-		nodeResource(s),
+		resource,
 
 		cpuMetrics(nodePrefix, s.CPU),
 		fsMetrics(nodePrefix, s.Fs),
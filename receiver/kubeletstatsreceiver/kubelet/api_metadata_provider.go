@@ -0,0 +1,233 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultAPIMetadataCacheTTL is how long metadata looked up in the Kubernetes
+	// API server is cached by default.
+	DefaultAPIMetadataCacheTTL = 5 * time.Minute
+
+	// apiRequestTimeout bounds each lookup, so that an unresponsive API server
+	// does not stall the collection of metrics.
+	apiRequestTimeout = 5 * time.Second
+	// apiErrorCacheTTL is how long failed lookups are cached, so that the API
+	// server is not queried for every pod on every collection during an outage.
+	apiErrorCacheTTL = 30 * time.Second
+)
+
+// Workload is the top-level controller owning a pod, e.g. the Deployment
+// of a pod created through a ReplicaSet.
+type Workload struct {
+	Kind string
+	Name string
+	UID  types.UID
+}
+
+// APIMetadataProvider looks up metadata that is not part of the kubelet API
+// responses in the Kubernetes API server: owners of ReplicaSets and Jobs, and
+// node labels. Results are cached for cacheTTL, as they rarely change, and
+// failures for a shorter time. The cache is not locked during lookups, and
+// concurrent lookups of the same owner or node share one request.
+type APIMetadataProvider struct {
+	client         kubernetes.Interface
+	cacheTTL       time.Duration
+	errorCacheTTL  time.Duration
+	requestTimeout time.Duration
+	now            func() time.Time
+
+	lookups singleflight.Group
+
+	mu         sync.Mutex
+	owners     map[types.UID]cachedWorkload
+	nodeLabels map[string]cachedLabels
+}
+
+type cachedWorkload struct {
+	workload Workload
+	err      error
+	expires  time.Time
+}
+
+type cachedLabels struct {
+	labels  map[string]string
+	err     error
+	expires time.Time
+}
+
+func NewAPIMetadataProvider(client kubernetes.Interface, cacheTTL time.Duration) *APIMetadataProvider {
+	return &APIMetadataProvider{
+		client:         client,
+		cacheTTL:       cacheTTL,
+		errorCacheTTL:  apiErrorCacheTTL,
+		requestTimeout: apiRequestTimeout,
+		now:            time.Now,
+		owners:         map[types.UID]cachedWorkload{},
+		nodeLabels:     map[string]cachedLabels{},
+	}
+}
+
+// Workload returns the workload owning the pod, following ReplicaSets to
+// their Deployment and Jobs to their CronJob. The zero Workload is returned
+// for pods without a controller.
+func (p *APIMetadataProvider) Workload(pod *v1.Pod) (Workload, error) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return Workload{}, nil
+	}
+
+	switch ref.Kind {
+	case "ReplicaSet", "Job":
+	default:
+		return Workload{Kind: ref.Kind, Name: ref.Name, UID: ref.UID}, nil
+	}
+
+	if cached, ok := p.cachedOwner(ref.UID); ok {
+		return cached.workload, cached.err
+	}
+
+	v, _, _ := p.lookups.Do("owner/"+string(ref.UID), func() (interface{}, error) {
+		if cached, ok := p.cachedOwner(ref.UID); ok {
+			return cached, nil
+		}
+		workload, err := p.lookUpWorkload(pod.Namespace, ref)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		now := p.now()
+		p.pruneOwners(now)
+		cached := cachedWorkload{workload: workload, expires: now.Add(p.cacheTTL)}
+		if err != nil {
+			cached = cachedWorkload{err: err, expires: now.Add(p.errorCacheTTL)}
+		}
+		p.owners[ref.UID] = cached
+		return cached, nil
+	})
+	cached := v.(cachedWorkload)
+	return cached.workload, cached.err
+}
+
+func (p *APIMetadataProvider) cachedOwner(uid types.UID) (cachedWorkload, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cached, ok := p.owners[uid]
+	return cached, ok && p.now().Before(cached.expires)
+}
+
+// LookUpWorkloads looks up the workloads of the pods concurrently, so that
+// the following calls to Workload are served from the cache. As every lookup
+// is bounded by the request timeout, it returns within about that time even
+// if the API server is unresponsive.
+func (p *APIMetadataProvider) LookUpWorkloads(pods []v1.Pod) {
+	var wg sync.WaitGroup
+	for i := range pods {
+		wg.Add(1)
+		go func(pod *v1.Pod) {
+			defer wg.Done()
+			// Failures are cached and reported by the caller of Workload.
+			_, _ = p.Workload(pod)
+		}(&pods[i])
+	}
+	wg.Wait()
+}
+
+// lookUpWorkload returns the controller of the ReplicaSet or Job referenced
+// by ref, or the ReplicaSet or Job itself if it has no controller.
+func (p *APIMetadataProvider) lookUpWorkload(namespace string, ref *metav1.OwnerReference) (Workload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+	defer cancel()
+
+	var meta *metav1.ObjectMeta
+	switch ref.Kind {
+	case "ReplicaSet":
+		rs, err := p.client.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return Workload{}, fmt.Errorf("failed to look up ReplicaSet %q: %w", ref.Name, err)
+		}
+		meta = &rs.ObjectMeta
+	case "Job":
+		job, err := p.client.BatchV1().Jobs(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return Workload{}, fmt.Errorf("failed to look up Job %q: %w", ref.Name, err)
+		}
+		meta = &job.ObjectMeta
+	}
+
+	// ReplicaSets and Jobs created directly are workloads themselves.
+	if parent := metav1.GetControllerOfNoCopy(meta); parent != nil {
+		return Workload{Kind: parent.Kind, Name: parent.Name, UID: parent.UID}, nil
+	}
+	return Workload{Kind: ref.Kind, Name: ref.Name, UID: ref.UID}, nil
+}
+
+// pruneOwners removes expired entries, so that owners of deleted pods do not
+// accumulate in the cache.
+func (p *APIMetadataProvider) pruneOwners(now time.Time) {
+	for uid, cached := range p.owners {
+		if !now.Before(cached.expires) {
+			delete(p.owners, uid)
+		}
+	}
+}
+
+// NodeLabels returns the labels of the node with the given name.
+func (p *APIMetadataProvider) NodeLabels(nodeName string) (map[string]string, error) {
+	if cached, ok := p.cachedNodeLabels(nodeName); ok {
+		return cached.labels, cached.err
+	}
+
+	v, _, _ := p.lookups.Do("node/"+nodeName, func() (interface{}, error) {
+		if cached, ok := p.cachedNodeLabels(nodeName); ok {
+			return cached, nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+		defer cancel()
+		node, err := p.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		now := p.now()
+		var cached cachedLabels
+		if err != nil {
+			err = fmt.Errorf("failed to look up node %q: %w", nodeName, err)
+			cached = cachedLabels{err: err, expires: now.Add(p.errorCacheTTL)}
+		} else {
+			cached = cachedLabels{labels: node.Labels, expires: now.Add(p.cacheTTL)}
+		}
+		p.nodeLabels[nodeName] = cached
+		return cached, nil
+	})
+	cached := v.(cachedLabels)
+	return cached.labels, cached.err
+}
+
+func (p *APIMetadataProvider) cachedNodeLabels(nodeName string) (cachedLabels, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cached, ok := p.nodeLabels[nodeName]
+	return cached, ok && p.now().Before(cached.expires)
+}
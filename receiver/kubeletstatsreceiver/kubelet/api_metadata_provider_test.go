@@ -0,0 +1,283 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func TestWorkload(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-5456b4b8cd", Namespace: "default", UID: "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("Deployment", "web", "deployment-uid")},
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "standalone", Namespace: "default", UID: "standalone-uid",
+		}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name: "backup-1597243200", Namespace: "default", UID: "job-uid",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("CronJob", "backup", "cronjob-uid")},
+		}},
+	)
+	p := NewAPIMetadataProvider(client, time.Minute)
+
+	tests := []struct {
+		name      string
+		owners    []metav1.OwnerReference
+		want      Workload
+		wantError string
+	}{
+		{
+			name: "no_owner",
+			want: Workload{},
+		},
+		{
+			name:   "deployment",
+			owners: []metav1.OwnerReference{controllerRef("ReplicaSet", "web-5456b4b8cd", "rs-uid")},
+			want:   Workload{Kind: "Deployment", Name: "web", UID: "deployment-uid"},
+		},
+		{
+			name:   "standalone_replicaset",
+			owners: []metav1.OwnerReference{controllerRef("ReplicaSet", "standalone", "standalone-uid")},
+			want:   Workload{Kind: "ReplicaSet", Name: "standalone", UID: "standalone-uid"},
+		},
+		{
+			name:   "cronjob",
+			owners: []metav1.OwnerReference{controllerRef("Job", "backup-1597243200", "job-uid")},
+			want:   Workload{Kind: "CronJob", Name: "backup", UID: "cronjob-uid"},
+		},
+		{
+			name:   "statefulset",
+			owners: []metav1.OwnerReference{controllerRef("StatefulSet", "db", "sts-uid")},
+			want:   Workload{Kind: "StatefulSet", Name: "db", UID: "sts-uid"},
+		},
+		{
+			name:      "missing_replicaset",
+			owners:    []metav1.OwnerReference{controllerRef("ReplicaSet", "missing", "missing-uid")},
+			wantError: `failed to look up ReplicaSet "missing": replicasets.apps "missing" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name: "pod", Namespace: "default", OwnerReferences: tt.owners,
+			}}
+			got, err := p.Workload(pod)
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWorkloadCache(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "web-5456b4b8cd", Namespace: "default", UID: "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("Deployment", "web", "deployment-uid")},
+		}},
+	)
+	now := time.Now()
+	p := NewAPIMetadataProvider(client, time.Minute)
+	p.now = func() time.Time { return now }
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "pod", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", "web-5456b4b8cd", "rs-uid")},
+	}}
+	want := Workload{Kind: "Deployment", Name: "web", UID: "deployment-uid"}
+	got, err := p.Workload(pod)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	err = client.AppsV1().ReplicaSets("default").Delete(context.Background(), "web-5456b4b8cd", metav1.DeleteOptions{})
+	require.NoError(t, err)
+
+	// The owner is served from the cache until it expires.
+	got, err = p.Workload(pod)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	now = now.Add(time.Minute)
+	_, err = p.Workload(pod)
+	require.Error(t, err)
+	require.Len(t, p.owners, 1, "the expired owner should be replaced by the failure")
+	assert.Error(t, p.owners["rs-uid"].err)
+}
+
+func TestNodeLabels(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "minikube",
+		Labels: map[string]string{"kubernetes.io/os": "linux"},
+	}})
+	p := NewAPIMetadataProvider(client, time.Minute)
+
+	labels, err := p.NodeLabels("minikube")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, labels)
+
+	err = client.CoreV1().Nodes().Delete(context.Background(), "minikube", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	labels, err = p.NodeLabels("minikube")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, labels)
+
+	_, err = p.NodeLabels("unknown")
+	require.EqualError(t, err, `failed to look up node "unknown": nodes "unknown" not found`)
+}
+
+func TestLookupErrorsCached(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	now := time.Now()
+	p := NewAPIMetadataProvider(client, time.Minute)
+	p.now = func() time.Time { return now }
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "pod", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", "web-5456b4b8cd", "rs-uid")},
+	}}
+	_, err := p.Workload(pod)
+	require.Error(t, err)
+	_, err = p.NodeLabels("minikube")
+	require.Error(t, err)
+
+	_, err = client.AppsV1().ReplicaSets("default").Create(context.Background(), &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-5456b4b8cd", Namespace: "default"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = client.CoreV1().Nodes().Create(context.Background(), &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "minikube"},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// Failures are served from the cache until they expire.
+	_, err = p.Workload(pod)
+	require.Error(t, err)
+	_, err = p.NodeLabels("minikube")
+	require.Error(t, err)
+	assert.Len(t, client.Actions(), 4, "failed lookups should not be retried")
+
+	now = now.Add(apiErrorCacheTTL)
+	workload, err := p.Workload(pod)
+	require.NoError(t, err)
+	assert.Equal(t, Workload{Kind: "ReplicaSet", Name: "web-5456b4b8cd", UID: "rs-uid"}, workload)
+	_, err = p.NodeLabels("minikube")
+	require.NoError(t, err)
+}
+
+func TestLookupTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer apiServer.Close()
+	defer close(unblock)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	require.NoError(t, err)
+	p := NewAPIMetadataProvider(client, time.Minute)
+	p.requestTimeout = 50 * time.Millisecond
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.NodeLabels("minikube")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lookup should time out")
+	}
+}
+
+func TestLookUpWorkloadsConcurrently(t *testing.T) {
+	unblock := make(chan struct{})
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer apiServer.Close()
+	defer close(unblock)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: apiServer.URL})
+	require.NoError(t, err)
+	p := NewAPIMetadataProvider(client, time.Minute)
+	p.requestTimeout = 200 * time.Millisecond
+	p.owners["cached-uid"] = cachedWorkload{
+		workload: Workload{Kind: "Deployment", Name: "cached", UID: "deployment-uid"},
+		expires:  time.Now().Add(time.Minute),
+	}
+
+	var pods []v1.Pod
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		pods = append(pods, v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "pod-" + name, Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", name, types.UID(name+"-uid"))},
+		}})
+	}
+	cachedPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "cached-pod", Namespace: "default",
+		OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", "cached", "cached-uid")},
+	}}
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		p.LookUpWorkloads(pods)
+		close(done)
+	}()
+
+	// Cached owners are served while the lookups are in flight.
+	time.Sleep(50 * time.Millisecond)
+	workload, err := p.Workload(cachedPod)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", workload.Name)
+
+	select {
+	case <-done:
+		assert.Less(t, int64(time.Since(start)), int64(3*p.requestTimeout),
+			"the lookups should time out together, not one after another")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lookups should time out")
+	}
+	for _, pod := range pods {
+		_, err := p.Workload(&pod)
+		assert.Error(t, err, "the failure should be cached")
+	}
+}
+
+func controllerRef(kind, name string, uid types.UID) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{Kind: kind, Name: name, UID: uid, Controller: &controller}
+}
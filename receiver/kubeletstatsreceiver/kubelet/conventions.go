@@ -18,4 +18,10 @@ const (
 	labelNodeName   = "k8s.node.name"
 	labelVolumeName = "k8s.volume.name"
 	labelVolumeType = "k8s.volume.type"
+
+	labelPodQOSClass     = "k8s.pod.qos_class"
+	labelWorkloadKind    = "k8s.workload.kind"
+	labelWorkloadName    = "k8s.workload.name"
+	labelPodLabelsPrefix = "k8s.pod.labels."
+	labelNodeLabelPrefix = "k8s.node.labels."
 )
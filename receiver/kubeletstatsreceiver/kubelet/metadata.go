@@ -20,6 +20,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -29,11 +30,83 @@ type MetadataLabel string
 const (
 	MetadataLabelContainerID MetadataLabel = conventions.AttributeContainerID
 	MetadataLabelVolumeType  MetadataLabel = labelVolumeType
+	// MetadataLabelPodLabels adds labels of pods as k8s.pod.labels.<key>
+	// to pod and container resources.
+	MetadataLabelPodLabels MetadataLabel = "k8s.pod.labels"
+	// MetadataLabelQOSClass adds the QoS class of pods to pod and container
+	// resources.
+	MetadataLabelQOSClass MetadataLabel = labelPodQOSClass
+	// MetadataLabelWorkload adds the kind and name of the workload owning
+	// pods to pod and container resources. Requires Kubernetes API access.
+	MetadataLabelWorkload MetadataLabel = "k8s.workload"
+	// MetadataLabelNodeLabels adds labels of the node as k8s.node.labels.<key>
+	// to the node resource. Requires Kubernetes API access.
+	MetadataLabelNodeLabels MetadataLabel = "k8s.node.labels"
 )
 
 var supportedLabels = map[MetadataLabel]bool{
 	MetadataLabelContainerID: true,
 	MetadataLabelVolumeType:  true,
+	MetadataLabelPodLabels:   true,
+	MetadataLabelQOSClass:    true,
+	MetadataLabelWorkload:    true,
+	MetadataLabelNodeLabels:  true,
+}
+
+// podMetadataLabels are the metadata labels describing pods.
+var podMetadataLabels = []MetadataLabel{
+	MetadataLabelPodLabels,
+	MetadataLabelQOSClass,
+	MetadataLabelWorkload,
+}
+
+// workloadNameLabels are the semantic convention labels of workload names
+// by kind.
+var workloadNameLabels = map[string]string{
+	"Deployment":  conventions.AttributeK8sDeployment,
+	"ReplicaSet":  conventions.AttributeK8sReplicaSet,
+	"StatefulSet": conventions.AttributeK8sStatefulSet,
+	"DaemonSet":   conventions.AttributeK8sDaemonSet,
+	"Job":         conventions.AttributeK8sJob,
+	"CronJob":     conventions.AttributeK8sCronJob,
+}
+
+// RequiresAPIMetadata returns whether any of the metadata labels needs
+// lookups in the Kubernetes API server.
+func RequiresAPIMetadata(labels []MetadataLabel) bool {
+	for _, label := range labels {
+		if label == MetadataLabelWorkload || label == MetadataLabelNodeLabels {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelFilter selects the Kubernetes labels added by MetadataLabelPodLabels
+// and MetadataLabelNodeLabels.
+type LabelFilter struct {
+	// Include is a list of label keys to add. All labels are added if empty.
+	Include []string `mapstructure:"include"`
+	// Exclude is a list of label keys not to add. It takes precedence over Include.
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// keep returns whether the label with the given key passes the filter.
+func (f LabelFilter) keep(key string) bool {
+	for _, k := range f.Exclude {
+		if k == key {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, k := range f.Include {
+		if k == key {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateMetadataLabelsConfig validates that provided list of metadata labels is supported
@@ -55,6 +128,11 @@ func ValidateMetadataLabelsConfig(labels []MetadataLabel) error {
 type Metadata struct {
 	Labels       map[MetadataLabel]bool
 	PodsMetadata *v1.PodList
+	// LabelFilter selects the pod and node labels to add.
+	LabelFilter LabelFilter
+	// APIMetadata looks up workloads and node labels, it is required by
+	// MetadataLabelWorkload and MetadataLabelNodeLabels.
+	APIMetadata *APIMetadataProvider
 }
 
 func NewMetadata(labels []MetadataLabel, podsMetadata *v1.PodList) Metadata {
@@ -141,3 +219,103 @@ func (m *Metadata) setExtraVolumeMetadata(podUID string, volumeName string, labe
 
 	return fmt.Errorf("pod %q with volume %q not found in the fetched metadata", podUID, volumeName)
 }
+
+// setPodMetadata sets labels describing the pod with the given UID, i.e. its
+// labels, QoS class and owner workload, in `labels` based on the metadata
+// labels provided. Failures to look up the workload in the Kubernetes API
+// server are logged, the other labels are set regardless.
+func (m *Metadata) setPodMetadata(logger *zap.Logger, labels map[string]string, podUID string) error {
+	if !m.hasAnyLabel(podMetadataLabels) {
+		return nil
+	}
+
+	// Cannot proceed, if metadata is unavailable.
+	if m.PodsMetadata == nil {
+		return errors.New("pods metadata were not fetched")
+	}
+
+	pod := m.getPod(podUID)
+	if pod == nil {
+		return fmt.Errorf("pod %q not found in the fetched metadata", podUID)
+	}
+
+	if m.Labels[MetadataLabelPodLabels] {
+		m.setFilteredLabels(labels, labelPodLabelsPrefix, pod.Labels)
+	}
+	if m.Labels[MetadataLabelQOSClass] && pod.Status.QOSClass != "" {
+		labels[labelPodQOSClass] = string(pod.Status.QOSClass)
+	}
+	if m.Labels[MetadataLabelWorkload] {
+		m.setWorkloadLabels(logger, labels, pod)
+	}
+	return nil
+}
+
+func (m *Metadata) setWorkloadLabels(logger *zap.Logger, labels map[string]string, pod *v1.Pod) {
+	if m.APIMetadata == nil {
+		logger.Warn("Kubernetes API metadata is not available, skipping workload labels")
+		return
+	}
+	workload, err := m.APIMetadata.Workload(pod)
+	if err != nil {
+		logger.Warn("Failed to look up workload of pod, skipping workload labels",
+			zap.String("pod", pod.Name), zap.String("namespace", pod.Namespace), zap.Error(err))
+		return
+	}
+	if workload.Kind == "" {
+		return
+	}
+	labels[labelWorkloadKind] = workload.Kind
+	labels[labelWorkloadName] = workload.Name
+	if nameLabel, ok := workloadNameLabels[workload.Kind]; ok {
+		labels[nameLabel] = workload.Name
+	}
+}
+
+// setNodeMetadata sets labels of the node with the given name in `labels`
+// if MetadataLabelNodeLabels is provided. Failures to look up the node in the
+// Kubernetes API server are logged.
+func (m *Metadata) setNodeMetadata(logger *zap.Logger, labels map[string]string, nodeName string) {
+	if !m.Labels[MetadataLabelNodeLabels] {
+		return
+	}
+	if m.APIMetadata == nil {
+		logger.Warn("Kubernetes API metadata is not available, skipping node labels")
+		return
+	}
+
+	nodeLabels, err := m.APIMetadata.NodeLabels(nodeName)
+	if err != nil {
+		logger.Warn("Failed to look up labels of node, skipping node labels",
+			zap.String("node", nodeName), zap.Error(err))
+		return
+	}
+	m.setFilteredLabels(labels, labelNodeLabelPrefix, nodeLabels)
+}
+
+func (m *Metadata) setFilteredLabels(labels map[string]string, prefix string, k8sLabels map[string]string) {
+	for k, v := range k8sLabels {
+		if m.LabelFilter.keep(k) {
+			labels[prefix+k] = v
+		}
+	}
+}
+
+func (m *Metadata) hasAnyLabel(metadataLabels []MetadataLabel) bool {
+	for _, l := range metadataLabels {
+		if m.Labels[l] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Metadata) getPod(podUID string) *v1.Pod {
+	uid := types.UID(podUID)
+	for i := range m.PodsMetadata.Items {
+		if m.PodsMetadata.Items[i].UID == uid {
+			return &m.PodsMetadata.Items[i]
+		}
+	}
+	return nil
+}
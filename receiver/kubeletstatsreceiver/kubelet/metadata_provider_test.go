@@ -43,6 +43,14 @@ func (f testRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f testRestClient) CAdvisorMetrics() ([]byte, error) {
+	return []byte{}, nil
+}
+
+func (f testRestClient) ResourceMetrics() ([]byte, error) {
+	return []byte{}, nil
+}
+
 func TestPods(t *testing.T) {
 	tests := []struct {
 		name      string
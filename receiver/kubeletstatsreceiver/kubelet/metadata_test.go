@@ -16,12 +16,15 @@ package kubelet
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestValidateMetadataLabelsConfig(t *testing.T) {
@@ -45,6 +48,14 @@ func TestValidateMetadataLabelsConfig(t *testing.T) {
 			labels:    []MetadataLabel{MetadataLabelVolumeType},
 			wantError: "",
 		},
+		{
+			name: "pod_metadata_valid",
+			labels: []MetadataLabel{
+				MetadataLabelPodLabels, MetadataLabelQOSClass,
+				MetadataLabelWorkload, MetadataLabelNodeLabels,
+			},
+			wantError: "",
+		},
 		{
 			name:      "container_id_duplicate",
 			labels:    []MetadataLabel{MetadataLabelContainerID, MetadataLabelContainerID},
@@ -290,3 +301,170 @@ func TestSetExtraLabelsForVolumeTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestSetPodMetadata(t *testing.T) {
+	pods := &v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:       types.UID("uid-1234"),
+					Namespace: "default",
+					Labels: map[string]string{
+						"app":               "web",
+						"team":              "storefront",
+						"pod-template-hash": "5456b4b8cd",
+					},
+					OwnerReferences: []metav1.OwnerReference{controllerRef("StatefulSet", "web", "sts-uid")},
+				},
+				Status: v1.PodStatus{QOSClass: v1.PodQOSBurstable},
+			},
+		},
+	}
+	// the ReplicaSet owning the pod is missing in the API server.
+	replicaSetPods := &v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:             types.UID("uid-5678"),
+					Namespace:       "default",
+					OwnerReferences: []metav1.OwnerReference{controllerRef("ReplicaSet", "web-5456b4b8cd", "rs-uid")},
+				},
+				Status: v1.PodStatus{QOSClass: v1.PodQOSGuaranteed},
+			},
+		},
+	}
+	apiMetadata := NewAPIMetadataProvider(fake.NewSimpleClientset(), time.Minute)
+
+	tests := []struct {
+		name        string
+		labels      []MetadataLabel
+		pods        *v1.PodList
+		filter      LabelFilter
+		apiMetadata *APIMetadataProvider
+		podUID      string
+		want        map[string]string
+		wantError   string
+	}{
+		{
+			name:   "no_labels",
+			podUID: "uid-1234",
+			want:   map[string]string{},
+		},
+		{
+			name:   "pod_labels",
+			labels: []MetadataLabel{MetadataLabelPodLabels},
+			pods:   pods,
+			podUID: "uid-1234",
+			want: map[string]string{
+				"k8s.pod.labels.app":               "web",
+				"k8s.pod.labels.team":              "storefront",
+				"k8s.pod.labels.pod-template-hash": "5456b4b8cd",
+			},
+		},
+		{
+			name:   "pod_labels_filtered",
+			labels: []MetadataLabel{MetadataLabelPodLabels},
+			pods:   pods,
+			filter: LabelFilter{Include: []string{"app", "pod-template-hash"}, Exclude: []string{"pod-template-hash"}},
+			podUID: "uid-1234",
+			want: map[string]string{
+				"k8s.pod.labels.app": "web",
+			},
+		},
+		{
+			name:   "qos_class",
+			labels: []MetadataLabel{MetadataLabelQOSClass},
+			pods:   pods,
+			podUID: "uid-1234",
+			want: map[string]string{
+				"k8s.pod.qos_class": "Burstable",
+			},
+		},
+		{
+			name:        "workload",
+			labels:      []MetadataLabel{MetadataLabelWorkload},
+			pods:        pods,
+			apiMetadata: apiMetadata,
+			podUID:      "uid-1234",
+			want: map[string]string{
+				"k8s.workload.kind":    "StatefulSet",
+				"k8s.workload.name":    "web",
+				"k8s.statefulset.name": "web",
+			},
+		},
+		{
+			name:   "workload_without_api_metadata",
+			labels: []MetadataLabel{MetadataLabelWorkload, MetadataLabelQOSClass},
+			pods:   pods,
+			podUID: "uid-1234",
+			want: map[string]string{
+				"k8s.pod.qos_class": "Burstable",
+			},
+		},
+		{
+			name:        "workload_lookup_error",
+			labels:      []MetadataLabel{MetadataLabelWorkload, MetadataLabelQOSClass},
+			pods:        replicaSetPods,
+			apiMetadata: apiMetadata,
+			podUID:      "uid-5678",
+			want: map[string]string{
+				"k8s.pod.qos_class": "Guaranteed",
+			},
+		},
+		{
+			name:      "no_metadata",
+			labels:    []MetadataLabel{MetadataLabelPodLabels},
+			podUID:    "uid-1234",
+			wantError: "pods metadata were not fetched",
+		},
+		{
+			name:      "pod_not_found",
+			labels:    []MetadataLabel{MetadataLabelQOSClass},
+			pods:      pods,
+			podUID:    "uid-5678",
+			wantError: "pod \"uid-5678\" not found in the fetched metadata",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := NewMetadata(tt.labels, tt.pods)
+			metadata.LabelFilter = tt.filter
+			metadata.APIMetadata = tt.apiMetadata
+			fields := map[string]string{}
+			err := metadata.setPodMetadata(zap.NewNop(), fields, tt.podUID)
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, fields)
+		})
+	}
+}
+
+func TestSetNodeMetadata(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name: "minikube",
+		Labels: map[string]string{
+			"kubernetes.io/os":              "linux",
+			"topology.kubernetes.io/zone":   "us-east-1a",
+			"node.kubernetes.io/node-group": "default",
+		},
+	}})
+	metadata := NewMetadata([]MetadataLabel{MetadataLabelNodeLabels}, nil)
+	metadata.LabelFilter = LabelFilter{Exclude: []string{"kubernetes.io/os"}}
+
+	fields := map[string]string{}
+	metadata.setNodeMetadata(zap.NewNop(), fields, "minikube")
+	assert.Empty(t, fields, "node labels are skipped without API metadata")
+
+	metadata.APIMetadata = NewAPIMetadataProvider(client, time.Minute)
+	metadata.setNodeMetadata(zap.NewNop(), fields, "unknown")
+	assert.Empty(t, fields, "node labels are skipped when the node can not be looked up")
+
+	metadata.setNodeMetadata(zap.NewNop(), fields, "minikube")
+	assert.Equal(t, map[string]string{
+		"k8s.node.labels.topology.kubernetes.io/zone":   "us-east-1a",
+		"k8s.node.labels.node.kubernetes.io/node-group": "default",
+	}, fields)
+}
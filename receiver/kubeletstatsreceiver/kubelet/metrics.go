@@ -40,6 +40,16 @@ func MetricsData(
 	for _, podStats := range summary.Pods {
 		// propagate the pod resource down to the container
 		podResource := podResource(podStats)
+		err := metadata.setPodMetadata(logger, podResource.Labels, podStats.PodRef.UID)
+		if err != nil {
+			logger.Warn(
+				"Failed to gather additional pod metadata. Skipping metric collection.",
+				zap.String("pod", podStats.PodRef.Name),
+				zap.String("namespace", podStats.PodRef.Namespace),
+				zap.Error(err),
+			)
+			continue
+		}
 		acc.podStats(podResource, podStats)
 		for _, containerStats := range podStats.Containers {
 			acc.containerStats(podResource, containerStats)
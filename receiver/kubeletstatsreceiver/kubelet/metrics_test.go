@@ -36,6 +36,14 @@ func (f fakeRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f fakeRestClient) CAdvisorMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/cadvisor-metrics.txt")
+}
+
+func (f fakeRestClient) ResourceMetrics() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/resource-metrics.txt")
+}
+
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
 	statsProvider := NewStatsProvider(rc)
@@ -0,0 +1,555 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+)
+
+type MetricSource string
+
+const (
+	// SummaryMetricSource is the /stats/summary endpoint.
+	SummaryMetricSource = MetricSource("summary")
+	// CAdvisorMetricSource is the /metrics/cadvisor Prometheus endpoint.
+	CAdvisorMetricSource = MetricSource("cadvisor")
+	// ResourceMetricSource is the /metrics/resource Prometheus endpoint.
+	ResourceMetricSource = MetricSource("resource")
+)
+
+var ValidMetricSources = map[MetricSource]bool{
+	SummaryMetricSource:  true,
+	CAdvisorMetricSource: true,
+	ResourceMetricSource: true,
+}
+
+// promMetric describes how a Prometheus metric family is converted.
+type promMetric struct {
+	// name of the converted metric, without the node, pod or container prefix.
+	name string
+	unit string
+	// metricType is one of GAUGE_INT64, GAUGE_DOUBLE, CUMULATIVE_INT64 or
+	// CUMULATIVE_DOUBLE.
+	metricType metricspb.MetricDescriptor_Type
+	// labels set on every converted time series.
+	labels map[string]string
+	// promLabels are the Prometheus labels copied to the converted time series.
+	promLabels []string
+	// sandbox is set for metrics that are reported for the pod sandbox, i.e.
+	// the "POD" container, rather than for the pod cgroup.
+	sandbox bool
+}
+
+// cadvisorMetrics are the cAdvisor metrics that are not part of /stats/summary.
+var cadvisorMetrics = map[string]promMetric{
+	"container_cpu_cfs_periods_total": {
+		name: "cpu.cfs.periods", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	"container_cpu_cfs_throttled_periods_total": {
+		name: "cpu.cfs.throttled_periods", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	"container_cpu_cfs_throttled_seconds_total": {
+		name: "cpu.cfs.throttled_time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"container_fs_reads_bytes_total": {
+		name: "disk.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "read"}, promLabels: []string{"device"},
+	},
+	"container_fs_writes_bytes_total": {
+		name: "disk.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "write"}, promLabels: []string{"device"},
+	},
+	"container_fs_reads_total": {
+		name: "disk.operations", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "read"}, promLabels: []string{"device"},
+	},
+	"container_fs_writes_total": {
+		name: "disk.operations", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "write"}, promLabels: []string{"device"},
+	},
+	"container_network_receive_bytes_total": {
+		name: "network.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_network_transmit_bytes_total": {
+		name: "network.io", unit: "By", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_network_receive_errors_total": {
+		name: "network.errors", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_network_transmit_errors_total": {
+		name: "network.errors", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_network_receive_packets_dropped_total": {
+		name: "network.dropped", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "receive"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_network_transmit_packets_dropped_total": {
+		name: "network.dropped", unit: "1", metricType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		labels: map[string]string{directionLabel: "transmit"}, promLabels: []string{"interface"}, sandbox: true,
+	},
+	"container_memory_cache": {
+		name: "memory.cache", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"container_memory_swap": {
+		name: "memory.swap", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"container_memory_mapped_file": {
+		name: "memory.mapped_file", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"container_memory_max_usage_bytes": {
+		name: "memory.max_usage", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+}
+
+// resourceMetrics are the metrics of the /metrics/resource endpoint. They
+// are a subset of /stats/summary meant for resource metrics pipelines.
+var resourceMetrics = map[string]promMetric{
+	"node_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"node_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"pod_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"pod_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	"container_cpu_usage_seconds_total": {
+		name: "cpu.time", unit: "s", metricType: metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+	},
+	"container_memory_working_set_bytes": {
+		name: "memory.working_set", unit: "By", metricType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+}
+
+const (
+	// Sandbox container of a pod in cAdvisor metrics.
+	podSandboxContainer = "POD"
+	// cAdvisor metric with start times of cgroups, used as start time of
+	// cumulative metrics.
+	cadvisorStartTimeMetric = "container_start_time_seconds"
+)
+
+// CAdvisorMetricsData converts metric families of the /metrics/cadvisor
+// endpoint into node, pod and container metrics.
+func CAdvisorMetricsData(
+	logger *zap.Logger,
+	families map[string]*dto.MetricFamily,
+	nodeName string,
+	metadata Metadata,
+	typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool,
+) []consumerdata.MetricsData {
+	return prometheusMetricsData(logger, families, cadvisorMetrics, nodeName, metadata, typeStr, metricGroupsToCollect)
+}
+
+// ResourceMetricsData converts metric families of the /metrics/resource
+// endpoint into node, pod and container metrics.
+func ResourceMetricsData(
+	logger *zap.Logger,
+	families map[string]*dto.MetricFamily,
+	nodeName string,
+	metadata Metadata,
+	typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool,
+) []consumerdata.MetricsData {
+	return prometheusMetricsData(logger, families, resourceMetrics, nodeName, metadata, typeStr, metricGroupsToCollect)
+}
+
+// promResource collects the converted metrics of a node, pod or container.
+type promResource struct {
+	resource  *resourcepb.Resource
+	startTime *timestamp.Timestamp
+	metrics   []*metricspb.Metric
+	// keys of the time series already converted, a pod's network metrics
+	// may be reported both for its cgroup and its sandbox.
+	series map[string]bool
+}
+
+func prometheusMetricsData(
+	logger *zap.Logger,
+	families map[string]*dto.MetricFamily,
+	conversions map[string]promMetric,
+	nodeName string,
+	metadata Metadata,
+	typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool,
+) []consumerdata.MetricsData {
+	c := &promConverter{
+		logger:                logger,
+		nodeName:              nodeName,
+		metadata:              metadata,
+		metricGroupsToCollect: metricGroupsToCollect,
+		podUIDs:               podUIDs(metadata.PodsMetadata),
+		startTimes:            podStartTimes(metadata.PodsMetadata),
+		resources:             map[string]*promResource{},
+	}
+	for _, m := range families[cadvisorStartTimeMetric].GetMetric() {
+		labels := promLabels(m)
+		if group, ok := seriesMetricGroup(cadvisorStartTimeMetric, promMetric{}, labels); ok {
+			c.startTimes[resourceKey(group, labels)] = timestampProto(time.Unix(0, int64(promValue(m)*1e9)))
+		}
+	}
+
+	// Sort families to emit metrics in a stable order.
+	names := make([]string, 0, len(families))
+	for name := range families {
+		if _, ok := conversions[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pm := conversions[name]
+		for _, m := range families[name].GetMetric() {
+			c.convert(name, pm, m)
+		}
+	}
+
+	acc := &metricDataAccumulator{
+		metadata:              metadata,
+		logger:                logger,
+		metricGroupsToCollect: metricGroupsToCollect,
+		time:                  time.Now(),
+	}
+	keys := make([]string, 0, len(c.resources))
+	for key := range c.resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		r := c.resources[key]
+		acc.accumulate(r.startTime, r.resource, r.metrics)
+	}
+
+	for _, md := range acc.m {
+		md.Resource.Labels["receiver"] = typeStr
+	}
+	return acc.m
+}
+
+type promConverter struct {
+	logger                *zap.Logger
+	nodeName              string
+	metadata              Metadata
+	metricGroupsToCollect map[MetricGroup]bool
+	podUIDs               map[string]string
+	startTimes            map[string]*timestamp.Timestamp
+	resources             map[string]*promResource
+}
+
+func (c *promConverter) convert(family string, pm promMetric, m *dto.Metric) {
+	labels := promLabels(m)
+	group, ok := seriesMetricGroup(family, pm, labels)
+	if !ok || !c.metricGroupsToCollect[group] {
+		return
+	}
+
+	r := c.getResource(group, labels)
+	if r == nil {
+		return
+	}
+
+	metricLabels := make(map[string]string, len(pm.labels)+len(pm.promLabels))
+	for k, v := range pm.labels {
+		metricLabels[k] = v
+	}
+	for _, l := range pm.promLabels {
+		metricLabels[l] = labels[l]
+	}
+
+	key := seriesKey(family, metricLabels)
+	if r.series[key] {
+		return
+	}
+	r.series[key] = true
+
+	metric := newPromMetric(groupPrefix(group)+pm.name, pm, promValue(m))
+	applyLabels(metric, metricLabels)
+	r.metrics = append(r.metrics, metric)
+}
+
+// getResource returns the resource the time series belongs to, nil if it
+// can not be determined.
+func (c *promConverter) getResource(group MetricGroup, labels map[string]string) *promResource {
+	key := resourceKey(group, labels)
+	if r, ok := c.resources[key]; ok {
+		return r
+	}
+
+	var resource *resourcepb.Resource
+	switch group {
+	case NodeMetricGroup:
+		resource = &resourcepb.Resource{
+			Type:   "k8s", // k8s/node
+			Labels: map[string]string{labelNodeName: c.nodeName},
+		}
+		c.metadata.setNodeMetadata(c.logger, resource.Labels, c.nodeName)
+	default:
+		resource = c.podResource(labels)
+		if resource == nil {
+			return nil
+		}
+		if group == ContainerMetricGroup {
+			resource = c.containerResource(resource, labels["container"])
+			if resource == nil {
+				return nil
+			}
+		}
+	}
+
+	r := &promResource{resource: resource, startTime: c.startTimes[key], series: map[string]bool{}}
+	c.resources[key] = r
+	return r
+}
+
+func resourceKey(group MetricGroup, labels map[string]string) string {
+	switch group {
+	case PodMetricGroup:
+		return "pod/" + labels["namespace"] + "/" + labels["pod"]
+	case ContainerMetricGroup:
+		return "container/" + labels["namespace"] + "/" + labels["pod"] + "/" + labels["container"]
+	}
+	return "node"
+}
+
+var cgroupPodUIDRegexp = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12}|[0-9a-f]{32})`)
+
+func (c *promConverter) podResource(labels map[string]string) *resourcepb.Resource {
+	namespace, name := labels["namespace"], labels["pod"]
+	uid, ok := c.podUIDs[namespace+"/"+name]
+	if !ok {
+		// Fall back to the pod UID in the cgroup path of cAdvisor metrics,
+		// e.g. /kubepods/burstable/pod<uid>/<container id>.
+		match := cgroupPodUIDRegexp.FindStringSubmatch(labels["id"])
+		if match == nil {
+			c.logger.Debug("Failed to find UID of pod, skipping its metrics",
+				zap.String("pod", name), zap.String("namespace", namespace))
+			return nil
+		}
+		uid = strings.ReplaceAll(match[1], "_", "-")
+	}
+
+	resource := &resourcepb.Resource{
+		Type: "k8s", // k8s/pod
+		Labels: map[string]string{
+			conventions.AttributeK8sPodUID:    uid,
+			conventions.AttributeK8sPod:       name,
+			conventions.AttributeK8sNamespace: namespace,
+		},
+	}
+	if err := c.metadata.setPodMetadata(c.logger, resource.Labels, uid); err != nil {
+		c.logger.Warn("Failed to gather additional pod metadata. Skipping metric collection.",
+			zap.String("pod", name), zap.String("namespace", namespace), zap.Error(err))
+		return nil
+	}
+	return resource
+}
+
+func (c *promConverter) containerResource(pod *resourcepb.Resource, container string) *resourcepb.Resource {
+	labels := map[string]string{}
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	labels[conventions.AttributeK8sContainer] = container
+	err := c.metadata.setExtraLabels(
+		labels, labels[conventions.AttributeK8sPodUID],
+		MetadataLabelContainerID, container,
+	)
+	if err != nil {
+		c.logger.Warn("failed to fetch container metrics", zap.String("pod", labels[conventions.AttributeK8sPod]),
+			zap.String("container", container), zap.Error(err))
+		return nil
+	}
+	return &resourcepb.Resource{
+		Type:   "k8s", // k8s/pod/container
+		Labels: labels,
+	}
+}
+
+// seriesMetricGroup returns whether a time series describes a node, a pod or
+// a container. ok is false for time series of other cgroups.
+func seriesMetricGroup(family string, pm promMetric, labels map[string]string) (group MetricGroup, ok bool) {
+	switch {
+	case strings.HasPrefix(family, "node_"):
+		return NodeMetricGroup, true
+	case strings.HasPrefix(family, "pod_"):
+		return PodMetricGroup, labels["pod"] != ""
+	}
+
+	container, pod := labels["container"], labels["pod"]
+	switch {
+	case pod == "" && container == "":
+		// The root cgroup of cAdvisor metrics is the node.
+		return NodeMetricGroup, labels["id"] == "/"
+	case pod == "":
+		return "", false
+	case container == "":
+		return PodMetricGroup, true
+	case container == podSandboxContainer:
+		return PodMetricGroup, pm.sandbox
+	}
+	return ContainerMetricGroup, true
+}
+
+func groupPrefix(group MetricGroup) string {
+	switch group {
+	case NodeMetricGroup:
+		return nodePrefix
+	case PodMetricGroup:
+		return podPrefix
+	}
+	return containerPrefix
+}
+
+// promLabels returns labels of a time series. Labels used by older kubelets
+// are renamed to their current names.
+func promLabels(m *dto.Metric) map[string]string {
+	out := make(map[string]string, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		out[lp.GetName()] = lp.GetValue()
+	}
+	for old, current := range map[string]string{"container_name": "container", "pod_name": "pod"} {
+		if v, ok := out[old]; ok && out[current] == "" {
+			out[current] = v
+		}
+	}
+	return out
+}
+
+func seriesKey(family string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(family)
+	for _, k := range keys {
+		b.WriteString("," + k + "=" + labels[k])
+	}
+	return b.String()
+}
+
+func promValue(m *dto.Metric) float64 {
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
+
+func newPromMetric(name string, pm promMetric, value float64) *metricspb.Metric {
+	switch pm.metricType {
+	case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+		return cumulativeDouble(name, pm.unit, &value)
+	case metricspb.MetricDescriptor_GAUGE_DOUBLE:
+		return doubleGauge(name, pm.unit, &value)
+	}
+
+	intValue := uint64(math.Max(value, 0))
+	if pm.metricType == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+		return cumulativeInt(name, pm.unit, &intValue)
+	}
+	return intGauge(name, pm.unit, &intValue)
+}
+
+// podUIDs returns UIDs of pods by namespace and name.
+func podUIDs(pods *v1.PodList) map[string]string {
+	out := map[string]string{}
+	if pods == nil {
+		return out
+	}
+	for _, pod := range pods.Items {
+		out[pod.Namespace+"/"+pod.Name] = string(pod.UID)
+	}
+	return out
+}
+
+// podStartTimes returns start times of pods and their running containers
+// by resource key.
+func podStartTimes(pods *v1.PodList) map[string]*timestamp.Timestamp {
+	out := map[string]*timestamp.Timestamp{}
+	if pods == nil {
+		return out
+	}
+	for _, pod := range pods.Items {
+		labels := map[string]string{"namespace": pod.Namespace, "pod": pod.Name}
+		if pod.Status.StartTime != nil {
+			out[resourceKey(PodMetricGroup, labels)] = timestampProto(pod.Status.StartTime.Time)
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Running != nil {
+				labels["container"] = cs.Name
+				out[resourceKey(ContainerMetricGroup, labels)] = timestampProto(cs.State.Running.StartedAt.Time)
+			}
+		}
+	}
+	return out
+}
+
+// NodeName returns the name of the node the pods run on.
+func NodeName(pods *v1.PodList) string {
+	if pods == nil {
+		return ""
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			return pod.Spec.NodeName
+		}
+	}
+	return ""
+}
+
+// WithoutNetworkMetrics removes network metrics from data collected from
+// /stats/summary. It only reports the default interface, cAdvisor metrics
+// are used instead when enabled.
+func WithoutNetworkMetrics(mds []consumerdata.MetricsData) []consumerdata.MetricsData {
+	for i, md := range mds {
+		metrics := md.Metrics[:0]
+		for _, m := range md.Metrics {
+			name := m.GetMetricDescriptor().GetName()
+			if strings.HasSuffix(name, ".network.io") || strings.HasSuffix(name, ".network.errors") {
+				continue
+			}
+			metrics = append(metrics, m)
+		}
+		mds[i].Metrics = metrics
+	}
+	return mds
+}
@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"bytes"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// PrometheusProvider wraps a RestClient, returning metric families parsed
+// from the Prometheus endpoints of the kubelet API.
+type PrometheusProvider struct {
+	rc RestClient
+}
+
+func NewPrometheusProvider(rc RestClient) *PrometheusProvider {
+	return &PrometheusProvider{rc: rc}
+}
+
+// CAdvisorMetrics calls the /metrics/cadvisor kubelet endpoint and parses
+// the results into metric families.
+func (p *PrometheusProvider) CAdvisorMetrics() (map[string]*dto.MetricFamily, error) {
+	return parseMetricFamilies(p.rc.CAdvisorMetrics())
+}
+
+// ResourceMetrics calls the /metrics/resource kubelet endpoint and parses
+// the results into metric families.
+func (p *PrometheusProvider) ResourceMetrics() (map[string]*dto.MetricFamily, error) {
+	return parseMetricFamilies(p.rc.ResourceMetrics())
+}
+
+func parseMetricFamilies(data []byte, err error) (map[string]*dto.MetricFamily, error) {
+	if err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(bytes.NewReader(data))
+}
@@ -0,0 +1,196 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+)
+
+func prometheusFixtures(t *testing.T, labels []MetadataLabel) (*PrometheusProvider, Metadata) {
+	rc := &fakeRestClient{}
+	podsMetadata, err := NewMetadataProvider(rc).Pods()
+	require.NoError(t, err)
+	return NewPrometheusProvider(rc), NewMetadata(labels, podsMetadata)
+}
+
+// metricsByResource indexes metrics by resource name and metric name with
+// labels, e.g. "pod/coredns-66bff467f8-szddj" and "k8s.pod.network.io{direction=receive,interface=eth0}".
+func metricsByResource(mds []consumerdata.MetricsData) map[string]map[string]*metricspb.Metric {
+	out := map[string]map[string]*metricspb.Metric{}
+	for _, md := range mds {
+		var name string
+		switch l := md.Resource.Labels; {
+		case l["k8s.container.name"] != "":
+			name = "container/" + l["k8s.pod.name"] + "/" + l["k8s.container.name"]
+		case l["k8s.pod.name"] != "":
+			name = "pod/" + l["k8s.pod.name"]
+		default:
+			name = "node/" + l["k8s.node.name"]
+		}
+		metrics := map[string]*metricspb.Metric{}
+		for _, m := range md.Metrics {
+			metrics[seriesKey(m.MetricDescriptor.Name, metricLabelsMap(m))] = m
+		}
+		out[name] = metrics
+	}
+	return out
+}
+
+func metricLabelsMap(m *metricspb.Metric) map[string]string {
+	out := map[string]string{}
+	for i, k := range m.MetricDescriptor.LabelKeys {
+		out[k.Key] = m.Timeseries[0].LabelValues[i].Value
+	}
+	return out
+}
+
+func TestCAdvisorMetricsData(t *testing.T) {
+	provider, metadata := prometheusFixtures(t, []MetadataLabel{MetadataLabelContainerID})
+	families, err := provider.CAdvisorMetrics()
+	require.NoError(t, err)
+
+	mds := CAdvisorMetricsData(zap.NewNop(), families, "minikube", metadata, "kubeletstats", ValidMetricGroups)
+	requireMetricsDataOk(t, mds)
+	for _, md := range mds {
+		assert.Equal(t, "kubeletstats", md.Resource.Labels["receiver"])
+	}
+
+	byResource := metricsByResource(mds)
+	require.Equal(t, 4, len(byResource))
+
+	node := byResource["node/minikube"]
+	require.Equal(t, 4, len(node))
+	assert.EqualValues(t, 52428800,
+		node["k8s.node.network.io,direction=receive,interface=eth0"].Timeseries[0].Points[0].GetInt64Value())
+	assert.Contains(t, node, "k8s.node.network.io,direction=receive,interface=docker0")
+	assert.Contains(t, node, "k8s.node.disk.io,device=/dev/sda,direction=read")
+	assert.Contains(t, node, "k8s.node.memory.cache")
+
+	// Network metrics reported for both the pod cgroup and the sandbox are
+	// only converted once.
+	pod := byResource["pod/coredns-66bff467f8-szddj"]
+	require.Equal(t, 6, len(pod))
+	assert.Contains(t, pod, "k8s.pod.network.io,direction=receive,interface=eth0")
+	assert.Contains(t, pod, "k8s.pod.network.io,direction=receive,interface=net1")
+	assert.Contains(t, pod, "k8s.pod.network.errors,direction=transmit,interface=eth0")
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+		pod["k8s.pod.cpu.cfs.throttled_time"].MetricDescriptor.Type)
+	assert.Equal(t, 0.75, pod["k8s.pod.cpu.cfs.throttled_time"].Timeseries[0].Points[0].GetDoubleValue())
+	assert.NotContains(t, pod, "k8s.pod.memory.cache")
+
+	container := byResource["container/coredns-66bff467f8-szddj/coredns"]
+	require.Equal(t, 7, len(container))
+	assert.EqualValues(t, 11,
+		container["container.cpu.cfs.throttled_periods"].Timeseries[0].Points[0].GetInt64Value())
+	assert.Contains(t, container, "container.disk.io,device=/dev/sdb,direction=read")
+	assert.Contains(t, container, "container.disk.io,device=/dev/sda,direction=write")
+	assert.EqualValues(t, 1638400, container["container.memory.cache"].Timeseries[0].Points[0].GetInt64Value())
+	assert.EqualValues(t, 1597234600,
+		container["container.cpu.cfs.periods"].Timeseries[0].StartTimestamp.Seconds)
+
+	for _, md := range mds {
+		if md.Resource.Labels["k8s.container.name"] == "coredns" {
+			assert.Equal(t, map[string]string{
+				"k8s.pod.uid":        "0adffe8e-9849-4e05-b4cd-92d2d1e1f1c3",
+				"k8s.pod.name":       "coredns-66bff467f8-szddj",
+				"k8s.namespace.name": "kube-system",
+				"k8s.container.name": "coredns",
+				"container.id":       "bd76db53336d07eb",
+				"receiver":           "kubeletstats",
+			}, md.Resource.Labels)
+		}
+	}
+}
+
+func TestCAdvisorMetricsDataMetricGroups(t *testing.T) {
+	provider, metadata := prometheusFixtures(t, nil)
+	families, err := provider.CAdvisorMetrics()
+	require.NoError(t, err)
+
+	mds := CAdvisorMetricsData(zap.NewNop(), families, "minikube", metadata, "", map[MetricGroup]bool{
+		PodMetricGroup: true,
+	})
+	require.Equal(t, 1, len(mds))
+	assert.Equal(t, "coredns-66bff467f8-szddj", mds[0].Resource.Labels["k8s.pod.name"])
+
+	require.Equal(t, 0, len(CAdvisorMetricsData(zap.NewNop(), families, "minikube", metadata, "", map[MetricGroup]bool{})))
+}
+
+func TestCAdvisorPodUIDFromCgroup(t *testing.T) {
+	provider, _ := prometheusFixtures(t, nil)
+	families, err := provider.CAdvisorMetrics()
+	require.NoError(t, err)
+
+	// Without pods metadata, the pod UID is taken from the cgroup path.
+	mds := CAdvisorMetricsData(zap.NewNop(), families, "minikube", Metadata{}, "", map[MetricGroup]bool{
+		ContainerMetricGroup: true,
+	})
+	uids := map[string]string{}
+	for _, md := range mds {
+		uids[md.Resource.Labels["k8s.container.name"]] = md.Resource.Labels["k8s.pod.uid"]
+	}
+	assert.Equal(t, map[string]string{
+		"coredns": "0adffe8e-9849-4e05-b4cd-92d2d1e1f1c3",
+		"server":  "42ad382b-ed0b-446d-9aab-3fdce8b4f9e2",
+	}, uids)
+}
+
+func TestResourceMetricsData(t *testing.T) {
+	provider, metadata := prometheusFixtures(t, nil)
+	families, err := provider.ResourceMetrics()
+	require.NoError(t, err)
+
+	// The endpoint does not expose start times, they are only known for
+	// running containers from the pods metadata.
+	mds := ResourceMetricsData(zap.NewNop(), families, "minikube", metadata, "", ValidMetricGroups)
+	for _, md := range mds {
+		requireResourceOk(t, md.Resource)
+		for _, m := range md.Metrics {
+			requireDescriptorOk(t, m.MetricDescriptor)
+			assert.Nil(t, m.Timeseries[0].StartTimestamp)
+		}
+	}
+
+	byResource := metricsByResource(mds)
+	require.Equal(t, 3, len(byResource))
+	assert.Equal(t, 11893.2, byResource["node/minikube"]["k8s.node.cpu.time"].Timeseries[0].Points[0].GetDoubleValue())
+	assert.EqualValues(t, 1073741824,
+		byResource["node/minikube"]["k8s.node.memory.working_set"].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, 4.25,
+		byResource["container/go-hello-world-5456b4b8cd-99vxc/server"]["container.cpu.time"].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Contains(t, byResource["container/coredns-66bff467f8-szddj/coredns"], "container.memory.working_set")
+}
+
+func TestWithoutNetworkMetrics(t *testing.T) {
+	mds := WithoutNetworkMetrics(fakeMetrics())
+	for _, md := range mds {
+		for _, m := range md.Metrics {
+			assert.NotContains(t, m.MetricDescriptor.Name, "network")
+		}
+	}
+	assert.Equal(t, len(fakeMetrics()), len(mds))
+}
+
+func TestNodeName(t *testing.T) {
+	_, metadata := prometheusFixtures(t, nil)
+	assert.Equal(t, "minikube", NodeName(metadata.PodsMetadata))
+	assert.Equal(t, "", NodeName(nil))
+}
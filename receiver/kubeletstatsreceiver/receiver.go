@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
+	k8s "k8s.io/client-go/kubernetes"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
@@ -40,7 +41,12 @@ type receiverOptions struct {
 	name                  string
 	collectionInterval    time.Duration
 	extraMetadataLabels   []kubelet.MetadataLabel
+	labelFilter           kubelet.LabelFilter
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	// metricSources defaults to kubelet.SummaryMetricSource when nil.
+	metricSources map[kubelet.MetricSource]bool
+	// k8sAPIClient is set when extraMetadataLabels require Kubernetes API lookups.
+	k8sAPIClient k8s.Interface
 }
 
 func newReceiver(rOptions *receiverOptions,
@@ -18,10 +18,13 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 	// todo replace with scraping lib when it's ready
@@ -35,11 +38,16 @@ type runnable struct {
 	receiverName          string
 	statsProvider         *kubelet.StatsProvider
 	metadataProvider      *kubelet.MetadataProvider
+	prometheusProvider    *kubelet.PrometheusProvider
+	apiMetadataProvider   *kubelet.APIMetadataProvider
 	consumer              consumer.MetricsConsumer
 	logger                *zap.Logger
 	restClient            kubelet.RestClient
 	extraMetadataLabels   []kubelet.MetadataLabel
+	labelFilter           kubelet.LabelFilter
+	k8sAPIClient          k8s.Interface
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	metricSources         map[kubelet.MetricSource]bool
 }
 
 func newRunnable(
@@ -49,6 +57,10 @@ func newRunnable(
 	logger *zap.Logger,
 	rOptions *receiverOptions,
 ) *runnable {
+	metricSources := rOptions.metricSources
+	if len(metricSources) == 0 {
+		metricSources = map[kubelet.MetricSource]bool{kubelet.SummaryMetricSource: true}
+	}
 	return &runnable{
 		ctx:                   ctx,
 		receiverName:          rOptions.name,
@@ -56,7 +68,10 @@ func newRunnable(
 		restClient:            restClient,
 		logger:                logger,
 		extraMetadataLabels:   rOptions.extraMetadataLabels,
+		labelFilter:           rOptions.labelFilter,
+		k8sAPIClient:          rOptions.k8sAPIClient,
 		metricGroupsToCollect: rOptions.metricGroupsToCollect,
+		metricSources:         metricSources,
 	}
 }
 
@@ -64,20 +79,33 @@ func newRunnable(
 func (r *runnable) Setup() error {
 	r.statsProvider = kubelet.NewStatsProvider(r.restClient)
 	r.metadataProvider = kubelet.NewMetadataProvider(r.restClient)
+	r.prometheusProvider = kubelet.NewPrometheusProvider(r.restClient)
+	if r.k8sAPIClient != nil {
+		r.apiMetadataProvider = kubelet.NewAPIMetadataProvider(r.k8sAPIClient, kubelet.DefaultAPIMetadataCacheTTL)
+	}
 	return nil
 }
 
 func (r *runnable) Run() error {
 	const transport = "http"
-	summary, err := r.statsProvider.StatsSummary()
-	if err != nil {
-		r.logger.Error("call to /stats/summary endpoint failed", zap.Error(err))
-		return nil
+	var mds []consumerdata.MetricsData
+	var nodeName string
+
+	var summary *stats.Summary
+	var err error
+	if r.metricSources[kubelet.SummaryMetricSource] {
+		summary, err = r.statsProvider.StatsSummary()
+		if err != nil {
+			r.logger.Error("call to /stats/summary endpoint failed", zap.Error(err))
+			return nil
+		}
+		nodeName = summary.Node.NodeName
 	}
 
 	var podsMetadata *v1.PodList
-	// fetch metadata only when extra metadata labels are needed
-	if len(r.extraMetadataLabels) > 0 {
+	// fetch metadata only when extra metadata labels are needed, or to
+	// identify pods of Prometheus metrics.
+	if len(r.extraMetadataLabels) > 0 || r.hasPrometheusSource() {
 		podsMetadata, err = r.metadataProvider.Pods()
 		if err != nil {
 			r.logger.Error("call to /pods endpoint failed", zap.Error(err))
@@ -86,7 +114,43 @@ func (r *runnable) Run() error {
 	}
 
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata)
-	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
+	metadata.LabelFilter = r.labelFilter
+	metadata.APIMetadata = r.apiMetadataProvider
+	if metadata.Labels[kubelet.MetadataLabelWorkload] && r.apiMetadataProvider != nil && podsMetadata != nil {
+		// Look up the workloads of all pods at once rather than one after
+		// another while building the metrics.
+		r.apiMetadataProvider.LookUpWorkloads(podsMetadata.Items)
+	}
+	if summary != nil {
+		summaryMds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
+		if r.metricSources[kubelet.CAdvisorMetricSource] {
+			summaryMds = kubelet.WithoutNetworkMetrics(summaryMds)
+		}
+		mds = append(mds, summaryMds...)
+	}
+
+	if nodeName == "" {
+		nodeName = kubelet.NodeName(podsMetadata)
+	}
+	if r.metricSources[kubelet.CAdvisorMetricSource] {
+		families, err := r.prometheusProvider.CAdvisorMetrics()
+		if err != nil {
+			r.logger.Error("call to /metrics/cadvisor endpoint failed", zap.Error(err))
+		} else {
+			mds = append(mds, kubelet.CAdvisorMetricsData(
+				r.logger, families, nodeName, metadata, typeStr, r.metricGroupsToCollect)...)
+		}
+	}
+	if r.metricSources[kubelet.ResourceMetricSource] {
+		families, err := r.prometheusProvider.ResourceMetrics()
+		if err != nil {
+			r.logger.Error("call to /metrics/resource endpoint failed", zap.Error(err))
+		} else {
+			mds = append(mds, kubelet.ResourceMetricsData(
+				r.logger, families, nodeName, metadata, typeStr, r.metricGroupsToCollect)...)
+		}
+	}
+
 	metrics := pdatautil.MetricsFromMetricsData(mds)
 
 	var numTimeSeries, numPoints int
@@ -102,3 +166,7 @@ func (r *runnable) Run() error {
 
 	return nil
 }
+
+func (r *runnable) hasPrometheusSource() bool {
+	return r.metricSources[kubelet.CAdvisorMetricSource] || r.metricSources[kubelet.ResourceMetricSource]
+}
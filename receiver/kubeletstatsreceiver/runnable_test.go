@@ -26,6 +26,10 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 )
@@ -46,6 +50,8 @@ const (
 	volumeMetrics    = 5
 )
 
+var isController = true
+
 var allMetricGroups = map[kubelet.MetricGroup]bool{
 	kubelet.ContainerMetricGroup: true,
 	kubelet.PodMetricGroup:       true,
@@ -137,6 +143,108 @@ func TestRunnableWithMetadata(t *testing.T) {
 	}
 }
 
+func TestRunnableWithPodMetadata(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "go-hello-world-5456b4b8cd", Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "go-hello-world", Controller: &isController}},
+		}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "coredns-66bff467f8", Namespace: "kube-system",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "coredns", Controller: &isController}},
+		}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   "minikube",
+			Labels: map[string]string{"kubernetes.io/hostname": "minikube", "kubernetes.io/os": "linux"},
+		}},
+	)
+	consumer := &exportertest.SinkMetricsExporter{}
+	r := newRunnable(
+		context.Background(),
+		consumer,
+		&fakeRestClient{},
+		zap.NewNop(),
+		&receiverOptions{
+			extraMetadataLabels: []kubelet.MetadataLabel{
+				kubelet.MetadataLabelPodLabels,
+				kubelet.MetadataLabelQOSClass,
+				kubelet.MetadataLabelWorkload,
+				kubelet.MetadataLabelNodeLabels,
+			},
+			labelFilter:           kubelet.LabelFilter{Exclude: []string{"pod-template-hash", "kubernetes.io/hostname"}},
+			k8sAPIClient:          k8sClient,
+			metricGroupsToCollect: allMetricGroups,
+		},
+	)
+	require.NoError(t, r.Setup())
+	require.NoError(t, r.Run())
+	require.Equal(t, dataLen, consumer.MetricsCount())
+
+	resources := map[string]map[string]string{}
+	for _, metrics := range consumer.AllMetrics() {
+		for _, md := range pdatautil.MetricsToMetricsData(metrics) {
+			labels := md.Resource.Labels
+			key := labels["k8s.node.name"] + labels["k8s.pod.name"] + "/" + labels["k8s.container.name"] + labels["k8s.volume.name"]
+			resources[key] = labels
+		}
+	}
+
+	node := resources["minikube/"]
+	require.Equal(t, "linux", node["k8s.node.labels.kubernetes.io/os"])
+	require.NotContains(t, node, "k8s.node.labels.kubernetes.io/hostname")
+
+	for _, key := range []string{"go-hello-world-5456b4b8cd-99vxc/", "go-hello-world-5456b4b8cd-99vxc/server"} {
+		labels := resources[key]
+		require.Equal(t, "go-hello-world", labels["k8s.pod.labels.app"], key)
+		require.NotContains(t, labels, "k8s.pod.labels.pod-template-hash", key)
+		require.Equal(t, "BestEffort", labels["k8s.pod.qos_class"], key)
+		require.Equal(t, "Deployment", labels["k8s.workload.kind"], key)
+		require.Equal(t, "go-hello-world", labels["k8s.workload.name"], key)
+		require.Equal(t, "go-hello-world", labels["k8s.deployment.name"], key)
+	}
+
+	proxy := resources["kube-proxy-v48tf/"]
+	require.Equal(t, "DaemonSet", proxy["k8s.workload.kind"])
+	require.Equal(t, "kube-proxy", proxy["k8s.daemonset.name"])
+
+	scheduler := resources["kube-scheduler-minikube/"]
+	require.NotNil(t, scheduler)
+	require.NotContains(t, scheduler, "k8s.workload.kind")
+}
+
+func TestRunnableWithFailedAPIMetadata(t *testing.T) {
+	// neither the ReplicaSets nor the node can be found in the API server.
+	consumer := &exportertest.SinkMetricsExporter{}
+	r := newRunnable(
+		context.Background(),
+		consumer,
+		&fakeRestClient{},
+		zap.NewNop(),
+		&receiverOptions{
+			extraMetadataLabels: []kubelet.MetadataLabel{
+				kubelet.MetadataLabelPodLabels,
+				kubelet.MetadataLabelWorkload,
+				kubelet.MetadataLabelNodeLabels,
+			},
+			k8sAPIClient:          fake.NewSimpleClientset(),
+			metricGroupsToCollect: allMetricGroups,
+		},
+	)
+	require.NoError(t, r.Setup())
+	require.NoError(t, r.Run())
+	require.Equal(t, dataLen, consumer.MetricsCount(), "metrics should be emitted without the API metadata")
+
+	for _, metrics := range consumer.AllMetrics() {
+		for _, md := range pdatautil.MetricsToMetricsData(metrics) {
+			labels := md.Resource.Labels
+			if labels["k8s.pod.name"] == "go-hello-world-5456b4b8cd-99vxc" && labels["k8s.volume.name"] == "" {
+				require.Equal(t, "go-hello-world", labels["k8s.pod.labels.app"])
+				require.NotContains(t, labels, "k8s.workload.name")
+			}
+		}
+	}
+}
+
 func TestRunnableWithMetricGroups(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -210,13 +318,84 @@ func TestRunnableWithMetricGroups(t *testing.T) {
 	}
 }
 
+func TestRunnableWithMetricSources(t *testing.T) {
+	tests := []struct {
+		name        string
+		sources     map[kubelet.MetricSource]bool
+		wantMetrics []string
+		wantAbsent  []string
+	}{
+		{
+			name: "summary_and_cadvisor",
+			sources: map[kubelet.MetricSource]bool{
+				kubelet.SummaryMetricSource:  true,
+				kubelet.CAdvisorMetricSource: true,
+			},
+			wantMetrics: []string{
+				"k8s.node.cpu.time",
+				"container.cpu.cfs.throttled_periods",
+				"k8s.pod.network.io",
+			},
+		},
+		{
+			name: "resource",
+			sources: map[kubelet.MetricSource]bool{
+				kubelet.ResourceMetricSource: true,
+			},
+			wantMetrics: []string{
+				"k8s.node.cpu.time",
+				"container.cpu.time",
+				"container.memory.working_set",
+			},
+			wantAbsent: []string{
+				"k8s.node.filesystem.usage",
+				"container.cpu.cfs.throttled_periods",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := &exportertest.SinkMetricsExporter{}
+			r := newRunnable(
+				context.Background(),
+				consumer,
+				&fakeRestClient{},
+				zap.NewNop(),
+				&receiverOptions{
+					metricGroupsToCollect: allMetricGroups,
+					metricSources:         tt.sources,
+				},
+			)
+			require.NoError(t, r.Setup())
+			require.NoError(t, r.Run())
+
+			names := map[string]bool{}
+			for _, m := range consumer.AllMetrics() {
+				for _, md := range pdatautil.MetricsToMetricsData(m) {
+					for _, metric := range md.Metrics {
+						names[metric.MetricDescriptor.Name] = true
+					}
+				}
+			}
+			for _, name := range tt.wantMetrics {
+				require.True(t, names[name], "missing metric %q", name)
+			}
+			for _, name := range tt.wantAbsent {
+				require.False(t, names[name], "unexpected metric %q", name)
+			}
+		})
+	}
+}
+
 func TestClientErrors(t *testing.T) {
 	tests := []struct {
 		name                  string
 		statsSummaryFail      bool
 		podsFail              bool
+		cadvisorFail          bool
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect map[kubelet.MetricGroup]bool
+		metricSources         map[kubelet.MetricSource]bool
 		numLogs               int
 	}{
 		{
@@ -251,6 +430,23 @@ func TestClientErrors(t *testing.T) {
 			metricGroupsToCollect: allMetricGroups,
 			numLogs:               1,
 		},
+		{
+			name:                  "pods_endpoint_error_with_cadvisor",
+			podsFail:              true,
+			metricGroupsToCollect: allMetricGroups,
+			metricSources:         map[kubelet.MetricSource]bool{kubelet.CAdvisorMetricSource: true},
+			numLogs:               1,
+		},
+		{
+			name:                  "cadvisor_endpoint_error",
+			cadvisorFail:          true,
+			metricGroupsToCollect: allMetricGroups,
+			metricSources: map[kubelet.MetricSource]bool{
+				kubelet.SummaryMetricSource:  true,
+				kubelet.CAdvisorMetricSource: true,
+			},
+			numLogs: 1,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -258,6 +454,7 @@ func TestClientErrors(t *testing.T) {
 			options := &receiverOptions{
 				extraMetadataLabels:   test.extraMetadataLabels,
 				metricGroupsToCollect: test.metricGroupsToCollect,
+				metricSources:         test.metricSources,
 			}
 			r := newRunnable(
 				context.Background(),
@@ -265,6 +462,7 @@ func TestClientErrors(t *testing.T) {
 				&fakeRestClient{
 					statsSummaryFail: test.statsSummaryFail,
 					podsFail:         test.podsFail,
+					cadvisorFail:     test.cadvisorFail,
 				},
 				zap.New(core),
 				options,
@@ -319,6 +517,7 @@ var _ kubelet.RestClient = (*fakeRestClient)(nil)
 type fakeRestClient struct {
 	statsSummaryFail bool
 	podsFail         bool
+	cadvisorFail     bool
 }
 
 func (f *fakeRestClient) StatsSummary() ([]byte, error) {
@@ -334,3 +533,14 @@ func (f *fakeRestClient) Pods() ([]byte, error) {
 	}
 	return ioutil.ReadFile("testdata/pods.json")
 }
+
+func (f *fakeRestClient) CAdvisorMetrics() ([]byte, error) {
+	if f.cadvisorFail {
+		return nil, errors.New("")
+	}
+	return ioutil.ReadFile("testdata/cadvisor-metrics.txt")
+}
+
+func (f *fakeRestClient) ResourceMetrics() ([]byte, error) {
+	return ioutil.ReadFile("testdata/resource-metrics.txt")
+}
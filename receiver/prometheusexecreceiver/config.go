@@ -32,4 +32,36 @@ type Config struct {
 	Port int `mapstructure:"port"`
 	// SubprocessConfig is the configuration needed for the subprocess
 	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+	// Subprocesses is a list of additional subprocesses run and scraped by the Receiver, each with its own settings
+	Subprocesses []SubprocessInstanceConfig `mapstructure:"subprocesses"`
+	// RestartBackoff configures the delay before a subprocess that exited is restarted
+	RestartBackoff RestartBackoffConfig `mapstructure:"restart_backoff"`
+}
+
+// SubprocessInstanceConfig is the configuration of one of the subprocesses listed under subprocesses
+type SubprocessInstanceConfig struct {
+	// Name identifies the subprocess and is used as the Prometheus job name
+	Name string `mapstructure:"name"`
+	// ScrapeInterval is the time between each scrape of this subprocess, it defaults to the Receiver's scrape_interval
+	ScrapeInterval time.Duration `mapstructure:"scrape_interval"`
+	// Port is the port assigned to this subprocess, and to its {{port}} template variables
+	Port int `mapstructure:"port"`
+	// Labels are extra labels added to every metric scraped from this subprocess
+	Labels map[string]string `mapstructure:"labels"`
+	// SubprocessConfig is the configuration needed for the subprocess
+	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+}
+
+// RestartBackoffConfig defines how the delay before restarting a crashed subprocess grows
+type RestartBackoffConfig struct {
+	// InitialDelay is the delay before a healthy subprocess is restarted
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	// Multiplier is the factor by which the delay scales for each crash past HealthyCrashCount
+	Multiplier float64 `mapstructure:"multiplier"`
+	// MaxDelay caps the delay before a restart, no cap is applied if it is 0
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+	// HealthyProcessTime is the time a subprocess needs to stay alive to be considered healthy
+	HealthyProcessTime time.Duration `mapstructure:"healthy_process_time"`
+	// HealthyCrashCount is the amount of times a subprocess can crash (within HealthyProcessTime) before being considered unstable
+	HealthyCrashCount int `mapstructure:"healthy_crash_count"`
 }
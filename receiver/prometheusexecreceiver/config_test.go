@@ -28,6 +28,13 @@ import (
 )
 
 var (
+	defaultRestartBackoff = RestartBackoffConfig{
+		InitialDelay:       time.Second,
+		Multiplier:         2,
+		HealthyProcessTime: 30 * time.Minute,
+		HealthyCrashCount:  3,
+	}
+
 	wantReceiver2 = &Config{
 		ReceiverSettings: configmodels.ReceiverSettings{
 			TypeVal: configmodels.Type("prometheus_exec"),
@@ -39,6 +46,7 @@ var (
 			Command: "mysqld_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartBackoff: defaultRestartBackoff,
 	}
 
 	wantReceiver3 = &Config{
@@ -51,6 +59,7 @@ var (
 			Command: "postgres_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartBackoff: defaultRestartBackoff,
 	}
 
 	wantReceiver4 = &Config{
@@ -73,6 +82,7 @@ var (
 				},
 			},
 		},
+		RestartBackoff: defaultRestartBackoff,
 	}
 
 	wantReceiver5 = &Config{
@@ -85,6 +95,49 @@ var (
 			Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
+		RestartBackoff: defaultRestartBackoff,
+	}
+
+	wantReceiver6 = &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type("prometheus_exec"),
+			NameVal: "prometheus_exec/end_to_end_test/3",
+		},
+		ScrapeInterval: 100 * time.Millisecond,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Env: []subprocessmanager.EnvConfig{},
+		},
+		Subprocesses: []SubprocessInstanceConfig{
+			{
+				Name: "exporter_a",
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command:          "go run ./test_prometheus_exporter.go {{port}}",
+					WorkingDirectory: "./testdata/end_to_end_metrics_test",
+				},
+				Labels: map[string]string{"exporter": "a"},
+			},
+			{
+				Name:           "exporter_b",
+				ScrapeInterval: 200 * time.Millisecond,
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
+					Env: []subprocessmanager.EnvConfig{
+						{
+							Name:  "PORT",
+							Value: "{{port}}",
+						},
+					},
+				},
+				Labels: map[string]string{"exporter": "b"},
+			},
+		},
+		RestartBackoff: RestartBackoffConfig{
+			InitialDelay:       100 * time.Millisecond,
+			Multiplier:         1.5,
+			MaxDelay:           time.Minute,
+			HealthyProcessTime: 10 * time.Minute,
+			HealthyCrashCount:  5,
+		},
 	}
 )
 
@@ -101,7 +154,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, config)
 
-	assert.Equal(t, len(config.Receivers), 5)
+	assert.Equal(t, len(config.Receivers), 6)
 
 	receiver1 := config.Receivers[receiverType]
 	assert.Equal(t, factory.CreateDefaultConfig(), receiver1)
@@ -117,4 +170,7 @@ func TestLoadConfig(t *testing.T) {
 
 	receiver5 := config.Receivers["prometheus_exec/end_to_end_test/2"]
 	assert.Equal(t, wantReceiver5, receiver5)
+
+	receiver6 := config.Receivers["prometheus_exec/end_to_end_test/3"]
+	assert.Equal(t, wantReceiver6, receiver6)
 }
@@ -53,6 +53,12 @@ func createDefaultConfig() configmodels.Receiver {
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Env: []subprocessmanager.EnvConfig{},
 		},
+		RestartBackoff: RestartBackoffConfig{
+			InitialDelay:       initialDelay,
+			Multiplier:         delayMultiplier,
+			HealthyProcessTime: healthyProcessTime,
+			HealthyCrashCount:  healthyCrashCount,
+		},
 	}
 }
 
@@ -75,40 +75,48 @@ func TestCreateTraceAndMetricsReceiver(t *testing.T) {
 		params:   component.ReceiverCreateParams{Logger: zap.NewNop()},
 		config:   receiver.(*Config),
 		consumer: nil,
-		promReceiverConfig: &prometheusreceiver.Config{
-			ReceiverSettings: configmodels.ReceiverSettings{
-				TypeVal: "prometheus_exec",
-				NameVal: "prometheus_exec/test",
-			},
-			PrometheusConfig: &promconfig.Config{
-				ScrapeConfigs: []*promconfig.ScrapeConfig{
-					{
-						ScrapeInterval:  model.Duration(60 * time.Second),
-						ScrapeTimeout:   model.Duration(10 * time.Second),
-						Scheme:          "http",
-						MetricsPath:     "/metrics",
-						JobName:         "test",
-						HonorLabels:     false,
-						HonorTimestamps: true,
-						ServiceDiscoveryConfig: sdconfig.ServiceDiscoveryConfig{
-							StaticConfigs: []*targetgroup.Group{
-								{
-									Targets: []model.LabelSet{
-										{model.AddressLabel: model.LabelValue("localhost:9104")},
+		subprocesses: []*subprocess{
+			{
+				name: "test",
+				promReceiverConfig: &prometheusreceiver.Config{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						TypeVal: "prometheus_exec",
+						NameVal: "prometheus_exec/test",
+					},
+					PrometheusConfig: &promconfig.Config{
+						ScrapeConfigs: []*promconfig.ScrapeConfig{
+							{
+								ScrapeInterval:  model.Duration(60 * time.Second),
+								ScrapeTimeout:   model.Duration(10 * time.Second),
+								Scheme:          "http",
+								MetricsPath:     "/metrics",
+								JobName:         "test",
+								HonorLabels:     false,
+								HonorTimestamps: true,
+								ServiceDiscoveryConfig: sdconfig.ServiceDiscoveryConfig{
+									StaticConfigs: []*targetgroup.Group{
+										{
+											Targets: []model.LabelSet{
+												{model.AddressLabel: model.LabelValue("localhost:9104")},
+											},
+										},
 									},
 								},
 							},
 						},
 					},
 				},
+				templateConfig: &subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+					Env:     []subprocessmanager.EnvConfig{},
+				},
+				subprocessConfig: &subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+					Env:     []subprocessmanager.EnvConfig{},
+				},
+				port: 9104,
 			},
 		},
-		subprocessConfig: &subprocessmanager.SubprocessConfig{
-			Command: "mysqld_exporter",
-			Env:     []subprocessmanager.EnvConfig{},
-		},
-		port:               9104,
-		prometheusReceiver: nil,
 	}
 
 	assert.Equal(t, wantPer, metricReceiver)
@@ -7,6 +7,7 @@ require (
 	github.com/prometheus/common v0.11.1
 	github.com/prometheus/prometheus v1.8.2-0.20200626085723-c448ada63d83
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.8.1-0.20200818152037-30c3c343c558
 	go.uber.org/zap v1.15.0
 )
@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewProcessRestarts,
+		viewProcessExitCode,
+	)
+}
+
+var (
+	tagReceiver, _   = tag.NewKey("receiver")
+	tagSubprocess, _ = tag.NewKey("subprocess")
+
+	mProcessRestarts = stats.Int64("otelcol/prometheus_exec/process_restarts", "Number of times a subprocess was restarted", "1")
+	mProcessExitCode = stats.Int64("otelcol/prometheus_exec/process_exit_code", "Exit code of the last run of a subprocess", "1")
+)
+
+var viewProcessRestarts = &view.View{
+	Name:        mProcessRestarts.Name(),
+	Description: mProcessRestarts.Description(),
+	Measure:     mProcessRestarts,
+	TagKeys:     []tag.Key{tagReceiver, tagSubprocess},
+	Aggregation: view.Sum(),
+}
+
+var viewProcessExitCode = &view.View{
+	Name:        mProcessExitCode.Name(),
+	Description: mProcessExitCode.Description(),
+	Measure:     mProcessExitCode,
+	TagKeys:     []tag.Key{tagReceiver, tagSubprocess},
+	Aggregation: view.LastValue(),
+}
+
+// recordProcessRestart increments the metric that records the restarts of a subprocess.
+func recordProcessRestart(receiverName, subprocessName string) {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagReceiver, receiverName), tag.Upsert(tagSubprocess, subprocessName)},
+		mProcessRestarts.M(int64(1)))
+}
+
+// recordProcessExitCode records the exit code of the last run of a subprocess.
+func recordProcessExitCode(receiverName, subprocessName string, exitCode int) {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagReceiver, receiverName), tag.Upsert(tagSubprocess, subprocessName)},
+		mProcessExitCode.M(int64(exitCode)))
+}
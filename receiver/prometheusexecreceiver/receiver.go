@@ -16,12 +16,15 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
 	"net"
+	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -42,11 +45,11 @@ const (
 	portTemplate string = "{{port}}"
 	// healthyProcessTime is the default time a process needs to stay alive to be considered healthy
 	healthyProcessTime time.Duration = 30 * time.Minute
-	// healthyCrashCount is the amount of times a process can crash (within the healthyProcessTime) before being considered unstable - it may be trying to find a port
+	// healthyCrashCount is the default amount of times a process can crash (within the healthyProcessTime) before being considered unstable - it may be trying to find a port
 	healthyCrashCount int = 3
-	// delayMutiplier is the factor by which the delay scales
+	// delayMutiplier is the default factor by which the delay scales
 	delayMultiplier float64 = 2.0
-	// initialDelay is the initial delay before a process is restarted
+	// initialDelay is the default initial delay before a process is restarted
 	initialDelay time.Duration = 1 * time.Second
 	// default path to scrape metrics at endpoint
 	defaultMetricsPath = "/metrics"
@@ -54,23 +57,34 @@ const (
 	defaultScrapeTimeout = 10 * time.Second
 )
 
+// promReceiverMu serializes creating and starting Prometheus receivers, since their scrape
+// managers register with package-global state in Prometheus that is not safe for concurrent use
+var promReceiverMu sync.Mutex
+
 type prometheusExecReceiver struct {
 	params   component.ReceiverCreateParams
 	config   *Config
 	consumer consumer.MetricsConsumer
 
+	// Subprocesses run and scraped by this receiver
+	subprocesses []*subprocess
+
+	// Shutdown channel
+	shutdownCh chan struct{}
+}
+
+// subprocess holds the data needed to run and scrape a single subprocess of the receiver
+type subprocess struct {
+	// name of the subprocess, also used as the Prometheus job name
+	name string
+
 	// Prometheus receiver config
 	promReceiverConfig *prometheusreceiver.Config
 
-	// Subprocess data
+	// Subprocess data, templateConfig keeps the {{port}} placeholders that are filled in subprocessConfig on each run
+	templateConfig   *subprocessmanager.SubprocessConfig
 	subprocessConfig *subprocessmanager.SubprocessConfig
 	port             int
-
-	// Underlying receiver data
-	prometheusReceiver component.MetricsReceiver
-
-	// Shutdown channel
-	shutdownCh chan struct{}
 }
 
 type runResult struct {
@@ -80,31 +94,107 @@ type runResult struct {
 
 // new returns a prometheusExecReceiver
 func new(params component.ReceiverCreateParams, config *Config, consumer consumer.MetricsConsumer) (*prometheusExecReceiver, error) {
-	if config.SubprocessConfig.Command == "" {
+	if config.SubprocessConfig.Command == "" && len(config.Subprocesses) == 0 {
 		return nil, fmt.Errorf("no command to execute entered in config file for %v", config.Name())
 	}
-	subprocessConfig := getSubprocessConfig(config)
-	promReceiverConfig := getPromReceiverConfig(config)
+	if err := validateRestartBackoff(&config.RestartBackoff); err != nil {
+		return nil, fmt.Errorf("invalid restart_backoff in config file for %v: %w", config.Name(), err)
+	}
+
+	subprocesses, err := getSubprocesses(config)
+	if err != nil {
+		return nil, err
+	}
 
 	return &prometheusExecReceiver{
-		params:             params,
-		config:             config,
-		consumer:           consumer,
-		subprocessConfig:   subprocessConfig,
-		promReceiverConfig: promReceiverConfig,
-		port:               config.Port,
+		params:       params,
+		config:       config,
+		consumer:     consumer,
+		subprocesses: subprocesses,
 	}, nil
 }
 
+// validateRestartBackoff makes sure the backoff parameters produce a non-decreasing delay
+func validateRestartBackoff(backoff *RestartBackoffConfig) error {
+	if backoff.InitialDelay < 0 {
+		return errors.New("initial_delay must not be negative")
+	}
+	if backoff.Multiplier < 1 {
+		return errors.New("multiplier must be at least 1")
+	}
+	if backoff.MaxDelay < 0 {
+		return errors.New("max_delay must not be negative")
+	}
+	if backoff.HealthyCrashCount < 0 {
+		return errors.New("healthy_crash_count must not be negative")
+	}
+	return nil
+}
+
+// getSubprocesses returns the subprocesses described by the config: the one defined at the top level of the receiver, if any,
+// followed by the ones listed under subprocesses
+func getSubprocesses(cfg *Config) ([]*subprocess, error) {
+	subprocesses := make([]*subprocess, 0, len(cfg.Subprocesses)+1)
+	names := map[string]bool{}
+
+	if cfg.SubprocessConfig.Command != "" {
+		instance := SubprocessInstanceConfig{
+			Name:             extractName(cfg),
+			ScrapeInterval:   cfg.ScrapeInterval,
+			Port:             cfg.Port,
+			SubprocessConfig: cfg.SubprocessConfig,
+		}
+		subprocesses = append(subprocesses, newSubprocess(cfg, &instance))
+		names[instance.Name] = true
+	}
+
+	for i := range cfg.Subprocesses {
+		instance := cfg.Subprocesses[i]
+		if instance.Name == "" {
+			return nil, fmt.Errorf("no name entered for subprocess %d in config file for %v", i, cfg.Name())
+		}
+		if names[instance.Name] {
+			return nil, fmt.Errorf("duplicate subprocess name %q in config file for %v", instance.Name, cfg.Name())
+		}
+		names[instance.Name] = true
+
+		if instance.SubprocessConfig.Command == "" {
+			return nil, fmt.Errorf("no command to execute entered for subprocess %q in config file for %v", instance.Name, cfg.Name())
+		}
+		for label := range instance.Labels {
+			if !model.LabelName(label).IsValid() {
+				return nil, fmt.Errorf("invalid label name %q for subprocess %q in config file for %v", label, instance.Name, cfg.Name())
+			}
+		}
+		if instance.ScrapeInterval == 0 {
+			instance.ScrapeInterval = cfg.ScrapeInterval
+		}
+		subprocesses = append(subprocesses, newSubprocess(cfg, &instance))
+	}
+
+	return subprocesses, nil
+}
+
+// newSubprocess returns the subprocess for one instance of the config
+func newSubprocess(cfg *Config, instance *SubprocessInstanceConfig) *subprocess {
+	return &subprocess{
+		name:               instance.Name,
+		promReceiverConfig: getPromReceiverConfig(cfg, instance),
+		templateConfig:     getSubprocessConfig(instance),
+		subprocessConfig:   getSubprocessConfig(instance),
+		port:               instance.Port,
+	}
+}
+
 // getPromReceiverConfig returns the Prometheus receiver config
-func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
+func getPromReceiverConfig(cfg *Config, instance *SubprocessInstanceConfig) *prometheusreceiver.Config {
 	scrapeConfig := &config.ScrapeConfig{}
 
-	scrapeConfig.ScrapeInterval = model.Duration(cfg.ScrapeInterval)
+	scrapeConfig.ScrapeInterval = model.Duration(instance.ScrapeInterval)
 	scrapeConfig.ScrapeTimeout = model.Duration(defaultScrapeTimeout)
 	scrapeConfig.Scheme = "http"
 	scrapeConfig.MetricsPath = defaultMetricsPath
-	scrapeConfig.JobName = extractName(cfg)
+	scrapeConfig.JobName = instance.Name
 	scrapeConfig.HonorLabels = false
 	scrapeConfig.HonorTimestamps = true
 
@@ -113,8 +203,9 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 		StaticConfigs: []*targetgroup.Group{
 			{
 				Targets: []model.LabelSet{
-					{model.AddressLabel: model.LabelValue(fmt.Sprintf("localhost:%v", cfg.Port))},
+					{model.AddressLabel: model.LabelValue(fmt.Sprintf("localhost:%v", instance.Port))},
 				},
+				Labels: getLabelSet(instance.Labels),
 			},
 		},
 	}
@@ -131,12 +222,26 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	}
 }
 
+// getLabelSet returns the extra labels of a subprocess as the label set of its Prometheus target group
+func getLabelSet(labels map[string]string) model.LabelSet {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	labelSet := make(model.LabelSet, len(labels))
+	for name, value := range labels {
+		labelSet[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return labelSet
+}
+
 // getSubprocessConfig returns the subprocess config
-func getSubprocessConfig(cfg *Config) *subprocessmanager.SubprocessConfig {
+func getSubprocessConfig(instance *SubprocessInstanceConfig) *subprocessmanager.SubprocessConfig {
 	subprocessConfig := &subprocessmanager.SubprocessConfig{}
 
-	subprocessConfig.Command = cfg.SubprocessConfig.Command
-	subprocessConfig.Env = cfg.SubprocessConfig.Env
+	subprocessConfig.Command = instance.SubprocessConfig.Command
+	subprocessConfig.Env = instance.SubprocessConfig.Env
+	subprocessConfig.WorkingDirectory = instance.SubprocessConfig.WorkingDirectory
 
 	return subprocessConfig
 }
@@ -151,37 +256,39 @@ func extractName(cfg *Config) string {
 	return splitName[0]
 }
 
-// Start creates the configs and calls the function that handles the prometheus_exec receiver
+// Start creates the configs and calls the function that handles each subprocess of the prometheus_exec receiver
 func (per *prometheusExecReceiver) Start(ctx context.Context, host component.Host) error {
 	// Shutdown channel
 	per.shutdownCh = make(chan struct{})
 
-	go per.manageProcess(context.Background(), host)
+	for _, sp := range per.subprocesses {
+		go per.manageProcess(context.Background(), host, sp)
+	}
 
 	return nil
 }
 
 // manageProcess is an infinite loop that handles starting and restarting Prometheus-receiver/subprocess pairs
-func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host component.Host) {
+func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host component.Host, sp *subprocess) {
 	var crashCount int
 
 	for {
 
-		receiver, err := per.createAndStartReceiver(ctx, host)
+		receiver, err := per.createAndStartReceiver(ctx, host, sp)
 		if err != nil {
-			per.params.Logger.Error("createReceiver() error", zap.String("error", err.Error()))
+			per.params.Logger.Error("createReceiver() error", zap.String("subprocess", sp.name), zap.String("error", err.Error()))
 			return
 		}
 
-		elapsed := per.runProcess(ctx)
+		elapsed := per.runProcess(ctx, sp)
 
 		err = receiver.Shutdown(ctx)
 		if err != nil {
-			per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
+			per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("subprocess", sp.name), zap.String("error", err.Error()))
 			return
 		}
 
-		crashCount = per.computeCrashCount(ctx, elapsed, crashCount)
+		crashCount = per.computeCrashCount(elapsed, crashCount)
 		per.computeDelayAndSleep(elapsed, crashCount)
 
 		// Exit loop if shutdown was signaled
@@ -190,12 +297,14 @@ func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host compo
 			return
 		default:
 		}
+
+		recordProcessRestart(per.config.Name(), sp.name)
 	}
 }
 
 // createAndStartReceiver will create the underlying Prometheus receiver and generate a random port if one is needed, then start it
-func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, host component.Host) (component.MetricsReceiver, error) {
-	currentPort := per.port
+func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, host component.Host, sp *subprocess) (component.MetricsReceiver, error) {
+	currentPort := sp.port
 
 	// Generate a port if none was specified
 	if currentPort == 0 {
@@ -205,19 +314,22 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 			return nil, fmt.Errorf("generateRandomPort() error - killing this single process/receiver: %w", err)
 		}
 
-		per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].ServiceDiscoveryConfig.StaticConfigs[0].Targets = []model.LabelSet{
+		sp.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].ServiceDiscoveryConfig.StaticConfigs[0].Targets = []model.LabelSet{
 			{model.AddressLabel: model.LabelValue(fmt.Sprintf("localhost:%v", currentPort))},
 		}
 	}
 
-	// Create and start the underlying Prometheus receiver
+	// Create and start the underlying Prometheus receiver, one subprocess at a time
+	promReceiverMu.Lock()
+	defer promReceiverMu.Unlock()
+
 	factory := prometheusreceiver.NewFactory()
-	receiver, err := factory.CreateMetricsReceiver(ctx, per.params, per.promReceiverConfig, per.consumer)
+	receiver, err := factory.CreateMetricsReceiver(ctx, per.params, sp.promReceiverConfig, per.consumer)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Prometheus receiver - killing this single process/receiver: %w", err)
 	}
 
-	per.subprocessConfig = per.fillPortPlaceholders(currentPort)
+	sp.subprocessConfig = fillPortPlaceholders(sp.templateConfig, currentPort)
 
 	err = receiver.Start(ctx, host)
 	if err != nil {
@@ -228,18 +340,19 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 }
 
 // runProcess will run the process and return runtime, or handle a shutdown if one is triggered while the subprocess is running
-func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration {
+func (per *prometheusExecReceiver) runProcess(ctx context.Context, sp *subprocess) time.Duration {
 	childCtx, cancel := context.WithCancel(ctx)
 	run := make(chan runResult, 1)
 
-	go per.handleProcessResult(childCtx, run)
+	go per.handleProcessResult(childCtx, sp, run)
 
 	select {
 	case result := <-run:
 		// Log the error from the subprocess without returning it since we want to restart the process if it exited
 		if result.subprocessErr != nil {
-			per.params.Logger.Info("Subprocess error", zap.String("error", result.subprocessErr.Error()))
+			per.params.Logger.Info("Subprocess error", zap.String("subprocess", sp.name), zap.String("error", result.subprocessErr.Error()))
 		}
+		recordProcessExitCode(per.config.Name(), sp.name, getExitCode(result.subprocessErr))
 		cancel()
 		return result.elapsed
 
@@ -250,14 +363,27 @@ func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration
 }
 
 // handleProcessResult calls the process manager's run function and pipes the return value into the channel
-func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, run chan<- runResult) {
-	elapsed, subprocessErr := per.subprocessConfig.Run(childCtx, per.params.Logger)
+func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, sp *subprocess, run chan<- runResult) {
+	elapsed, subprocessErr := sp.subprocessConfig.Run(childCtx, per.params.Logger)
 	run <- runResult{elapsed, subprocessErr}
 }
 
+// getExitCode returns the exit code of a subprocess given the error returned by its run, or -1 if the subprocess did not exit by itself
+func getExitCode(subprocessErr error) int {
+	if subprocessErr == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(subprocessErr, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
 // computeDelayAndSleep will compute how long the process should delay before restarting and handle a shutdown while this goroutine waits
 func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, crashCount int) {
-	sleepTime := getDelay(elapsed, healthyProcessTime, crashCount, healthyCrashCount)
+	sleepTime := getDelay(elapsed, crashCount, &per.config.RestartBackoff)
 	per.params.Logger.Info("Subprocess start delay", zap.String("time until process restarts", sleepTime.String()))
 
 	select {
@@ -270,8 +396,8 @@ func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, c
 }
 
 // computeCrashCount will compute crashCount according to runtime
-func (per *prometheusExecReceiver) computeCrashCount(ctx context.Context, elapsed time.Duration, crashCount int) int {
-	if elapsed > healthyProcessTime {
+func (per *prometheusExecReceiver) computeCrashCount(elapsed time.Duration, crashCount int) int {
+	if elapsed > per.config.RestartBackoff.HealthyProcessTime {
 		return 1
 	}
 	crashCount++
@@ -280,15 +406,20 @@ func (per *prometheusExecReceiver) computeCrashCount(ctx context.Context, elapse
 }
 
 // fillPortPlaceholders will check if any of the strings in the process data have the {{port}} placeholder, and replace it if necessary
-func (per *prometheusExecReceiver) fillPortPlaceholders(newPort int) *subprocessmanager.SubprocessConfig {
+func fillPortPlaceholders(templateConfig *subprocessmanager.SubprocessConfig, newPort int) *subprocessmanager.SubprocessConfig {
 	port := strconv.Itoa(newPort)
 
-	newConfig := *per.subprocessConfig
+	newConfig := *templateConfig
 
-	newConfig.Command = strings.ReplaceAll(per.config.SubprocessConfig.Command, portTemplate, port)
+	newConfig.Command = strings.ReplaceAll(templateConfig.Command, portTemplate, port)
 
-	for i, env := range per.config.SubprocessConfig.Env {
-		newConfig.Env[i].Value = strings.ReplaceAll(env.Value, portTemplate, port)
+	// Copy the env so the placeholders of the template are kept for the next run
+	newConfig.Env = make([]subprocessmanager.EnvConfig, len(templateConfig.Env))
+	for i, env := range templateConfig.Env {
+		newConfig.Env[i] = subprocessmanager.EnvConfig{
+			Name:  env.Name,
+			Value: strings.ReplaceAll(env.Value, portTemplate, port),
+		}
 	}
 
 	return &newConfig
@@ -305,17 +436,24 @@ func generateRandomPort() (int, error) {
 }
 
 // getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm
-func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int) time.Duration {
-	// Return the initialDelay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
-	if elapsed > healthyProcessDuration || crashCount <= healthyCrashCount {
-		return initialDelay
+func getDelay(elapsed time.Duration, crashCount int, backoff *RestartBackoffConfig) time.Duration {
+	// Return the initial delay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
+	if elapsed > backoff.HealthyProcessTime || crashCount <= backoff.HealthyCrashCount {
+		return backoff.InitialDelay
 	}
 
-	// Return initialDelay times 2 to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
-	return initialDelay * time.Duration(math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64()))
+	// Return the initial delay times the multiplier to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
+	delay := float64(backoff.InitialDelay) * math.Pow(backoff.Multiplier, float64(crashCount-backoff.HealthyCrashCount)+rand.Float64())
+	if backoff.MaxDelay > 0 && delay > float64(backoff.MaxDelay) {
+		return backoff.MaxDelay
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
 }
 
-// Shutdown stops the underlying Prometheus receiver.
+// Shutdown stops the underlying Prometheus receivers.
 func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
 	close(per.shutdownCh)
 	return nil
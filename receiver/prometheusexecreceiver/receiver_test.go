@@ -16,12 +16,15 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
+	"os/exec"
 	"path"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -30,6 +33,8 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
 )
 
 // loadConfigAssertNoError loads the test config and asserts there are no errors, and returns the receiver wanted
@@ -69,6 +74,181 @@ func TestEndToEnd(t *testing.T) {
 	endToEndScrapeTest(t, receiverConfig, "end-to-end port not defined")
 }
 
+// TestEndToEndMultipleSubprocesses runs two subprocesses in a single receiver and checks both are scraped with their own labels
+func TestEndToEndMultipleSubprocesses(t *testing.T) {
+	receiverConfig := loadConfigAssertNoError(t, "prometheus_exec/end_to_end_test/3")
+
+	sink := &exportertest.SinkMetricsExporter{}
+	wrapper, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, receiverConfig.(*Config), sink)
+	require.NoError(t, err, "new() returned an error")
+	require.Len(t, wrapper.subprocesses, 2)
+
+	ctx := context.Background()
+	require.NoError(t, wrapper.Start(ctx, componenttest.NewNopHost()), "Start() returned an error")
+	defer func() { assert.NoError(t, wrapper.Shutdown(ctx)) }()
+
+	// Every subprocess must be scraped, then restarted and scraped a second time
+	scrapesByJob := map[string]int{}
+	labelsByJob := map[string]string{}
+	const waitFor = 30 * time.Second
+	const tick = 100 * time.Millisecond
+	require.Eventuallyf(t, func() bool {
+		scrapesByJob = map[string]int{}
+		for _, m := range sink.AllMetrics() {
+			for _, md := range pdatautil.MetricsToMetricsData(m) {
+				job := md.Node.GetServiceInfo().GetName()
+				scrapesByJob[job]++
+				metric := md.Metrics[0]
+				for i, key := range metric.MetricDescriptor.LabelKeys {
+					if key.Key == "exporter" {
+						labelsByJob[job] = metric.Timeseries[0].LabelValues[i].Value
+					}
+				}
+			}
+		}
+		return scrapesByJob["exporter_a"] >= 2 && scrapesByJob["exporter_b"] >= 2
+	}, waitFor, tick, "Two scrapes of each subprocess not completed after %v, got %v", waitFor, scrapesByJob)
+
+	assert.Equal(t, map[string]string{"exporter_a": "a", "exporter_b": "b"}, labelsByJob)
+
+	// The subprocesses exit after each scrape so restarts and exit codes must have been recorded
+	for _, v := range []*view.View{viewProcessRestarts, viewProcessExitCode} {
+		rows, err := view.RetrieveData(v.Name)
+		require.NoError(t, err)
+		subprocesses := map[string]bool{}
+		for _, row := range rows {
+			for _, tag := range row.Tags {
+				if tag.Key == tagSubprocess {
+					subprocesses[tag.Value] = true
+				}
+			}
+		}
+		assert.True(t, subprocesses["exporter_a"], "no %v recorded for exporter_a", v.Name)
+		assert.True(t, subprocesses["exporter_b"], "no %v recorded for exporter_b", v.Name)
+	}
+}
+
+func TestNewInvalidSubprocesses(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{
+			name: "missing name",
+			config: &Config{
+				Subprocesses: []SubprocessInstanceConfig{
+					{SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "node_exporter"}},
+				},
+				RestartBackoff: createDefaultConfig().(*Config).RestartBackoff,
+			},
+		},
+		{
+			name: "missing command",
+			config: &Config{
+				Subprocesses: []SubprocessInstanceConfig{
+					{Name: "node"},
+				},
+				RestartBackoff: createDefaultConfig().(*Config).RestartBackoff,
+			},
+		},
+		{
+			name: "duplicate name",
+			config: &Config{
+				Subprocesses: []SubprocessInstanceConfig{
+					{Name: "node", SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "node_exporter"}},
+					{Name: "node", SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "node_exporter"}},
+				},
+				RestartBackoff: createDefaultConfig().(*Config).RestartBackoff,
+			},
+		},
+		{
+			name: "invalid label",
+			config: &Config{
+				Subprocesses: []SubprocessInstanceConfig{
+					{
+						Name:             "node",
+						Labels:           map[string]string{"not-valid": "value"},
+						SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "node_exporter"},
+					},
+				},
+				RestartBackoff: createDefaultConfig().(*Config).RestartBackoff,
+			},
+		},
+		{
+			name: "invalid backoff multiplier",
+			config: &Config{
+				SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "node_exporter"},
+				RestartBackoff: RestartBackoffConfig{
+					InitialDelay: time.Second,
+					Multiplier:   0.5,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, test.config, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFillPortPlaceholders(t *testing.T) {
+	template := &subprocessmanager.SubprocessConfig{
+		Command: "exporter --port={{port}}",
+		Env: []subprocessmanager.EnvConfig{
+			{Name: "PORT", Value: "{{port}}"},
+		},
+		WorkingDirectory: "/tmp",
+	}
+
+	got := fillPortPlaceholders(template, 1234)
+	assert.Equal(t, &subprocessmanager.SubprocessConfig{
+		Command: "exporter --port=1234",
+		Env: []subprocessmanager.EnvConfig{
+			{Name: "PORT", Value: "1234"},
+		},
+		WorkingDirectory: "/tmp",
+	}, got)
+
+	// The template must keep its placeholders for the next runs
+	got = fillPortPlaceholders(template, 5678)
+	assert.Equal(t, "exporter --port=5678", got.Command)
+	assert.Equal(t, "5678", got.Env[0].Value)
+	assert.Equal(t, "{{port}}", template.Env[0].Value)
+}
+
+func TestGetDelay(t *testing.T) {
+	backoff := &RestartBackoffConfig{
+		InitialDelay:       time.Second,
+		Multiplier:         2,
+		HealthyProcessTime: 30 * time.Minute,
+		HealthyCrashCount:  3,
+	}
+
+	// Healthy processes are restarted after the initial delay
+	assert.Equal(t, time.Second, getDelay(time.Hour, 10, backoff))
+	assert.Equal(t, time.Second, getDelay(time.Second, 3, backoff))
+
+	// Unstable processes back off exponentially, with some jitter
+	delay := getDelay(time.Second, 5, backoff)
+	assert.True(t, delay >= 4*time.Second && delay < 8*time.Second, "unexpected delay %v", delay)
+
+	// The delay is capped by the max delay
+	backoff.MaxDelay = 10 * time.Second
+	assert.Equal(t, 10*time.Second, getDelay(time.Second, 20, backoff))
+}
+
+func TestGetExitCode(t *testing.T) {
+	assert.Equal(t, 0, getExitCode(nil))
+	assert.Equal(t, -1, getExitCode(errors.New("process could not start")))
+
+	err := exec.Command("go", "run", "./subprocessmanager/testdata/test_crasher.go").Run()
+	require.Error(t, err)
+	assert.Equal(t, 1, getExitCode(err))
+}
+
 // endToEndScrapeTest creates a receiver that invokes `go run test_prometheus_exporter.go` and waits until it has scraped the /metrics endpoint twice - the application will crash between each scrape
 func endToEndScrapeTest(t *testing.T, receiverConfig configmodels.Receiver, testName string) {
 	sink := &exportertest.SinkMetricsExporter{}
@@ -20,6 +20,8 @@ type SubprocessConfig struct {
 	Command string `mapstructure:"exec"`
 	// Env is a list of env variables to pass to a specific command
 	Env []EnvConfig `mapstructure:"env"`
+	// WorkingDirectory is the directory the command is run in, it defaults to the Collector's working directory
+	WorkingDirectory string `mapstructure:"working_directory"`
 }
 
 // EnvConfig is the config definition of each key-value pair for environment variables
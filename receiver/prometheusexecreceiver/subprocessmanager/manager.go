@@ -46,6 +46,7 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 	// Create the command object and attach current os environment + environment variables defined by the user
 	childProcess := exec.Command(args[0], argsSlice...)
 	childProcess.Env = append(os.Environ(), formatEnvSlice(&proc.Env)...)
+	childProcess.Dir = proc.WorkingDirectory
 
 	// Handle the subprocess standard and error outputs in goroutines
 	stdoutReader, stdoutErr := childProcess.StdoutPipe()
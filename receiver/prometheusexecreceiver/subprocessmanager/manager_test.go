@@ -16,6 +16,8 @@ package subprocessmanager
 
 import (
 	"context"
+	"errors"
+	"os/exec"
 	"reflect"
 	"testing"
 	"time"
@@ -148,3 +150,27 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRunWorkingDirectory(t *testing.T) {
+	process := &SubprocessConfig{
+		Command:          "go vet test_crasher.go",
+		WorkingDirectory: "testdata",
+	}
+
+	// go vet only succeeds if the command is run in the directory of the file
+	logger := zap.NewNop()
+	if _, err := process.Run(context.Background(), logger); err != nil {
+		t.Fatalf("Run() err = %v, want nil", err)
+	}
+
+	process.WorkingDirectory = ""
+	var exitErr *exec.ExitError
+	if _, err := process.Run(context.Background(), logger); !errors.As(err, &exitErr) {
+		t.Errorf("Run() err = %v, want an exit error", err)
+	}
+
+	process.WorkingDirectory = "does_not_exist"
+	if _, err := process.Run(context.Background(), logger); err == nil || errors.As(err, &exitErr) {
+		t.Errorf("Run() err = %v, want a start error", err)
+	}
+}
@@ -34,7 +34,7 @@ func main() {
 func server() {
 	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
 		defer os.Exit(1)
-		file, err := ioutil.TempFile("testdata", "metrics")
+		file, err := ioutil.TempFile("", "metrics")
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -47,7 +47,10 @@ func server() {
 			log.Fatal(err)
 		}
 		http.ServeFile(w, r, file.Name())
-		return
+		// Flush the response before the deferred os.Exit, otherwise it may never reach the scraper
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
 	})
 
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%v", os.Args[1]), nil))
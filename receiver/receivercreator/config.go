@@ -16,6 +16,7 @@ package receivercreator
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/spf13/cast"
 	"github.com/spf13/viper"
@@ -30,6 +31,9 @@ const (
 	endpointConfigKey = "endpoint"
 	// configKey is the key name in a subreceiver.
 	configKey = "config"
+	// annotationPrefix is the prefix of endpoint annotations overriding the config of
+	// receivers created for the endpoint (ie io.opentelemetry.receiver.<receiver type>/<key>).
+	annotationPrefix = "io.opentelemetry.receiver."
 )
 
 // receiverConfig describes a receiver instance with a default config.
@@ -54,6 +58,11 @@ type receiverTemplate struct {
 	// based on receiverTemplate.
 	Rule string `mapstructure:"rule"`
 	rule rule
+
+	// AnnotationOverrides lists the config keys that annotations on the endpoint may
+	// override. A key also allows the keys nested below it, e.g. "tls" allows
+	// "tls.insecure". Annotations are ignored when it is empty, the default.
+	AnnotationOverrides []string `mapstructure:"annotation_overrides"`
 }
 
 // newReceiverTemplate creates a receiverTemplate instance from the full name of a subreceiver
@@ -79,8 +88,34 @@ type Config struct {
 	receiverTemplates             map[string]receiverTemplate
 	// WatchObservers are the extensions to listen to endpoints from.
 	WatchObservers []configmodels.Type `mapstructure:"watch_observers"`
+	// ResourceAttributes is a map of endpoint type (pod, port, hostport, container,
+	// service or node) to the resource attributes set on the metrics and traces of receivers
+	// created for endpoints of that type. Attribute values may contain expressions
+	// in backticks that are expanded like values of the receiver config.
+	ResourceAttributes resourceAttributes `mapstructure:"resource_attributes"`
+	// Restart configures restarting of receivers that fail to start.
+	Restart RestartSettings `mapstructure:"restart"`
+}
+
+// RestartSettings configures how receivers that fail to start are retried. The interval
+// between attempts starts at InitialInterval and doubles after each failed attempt
+// up to MaxInterval.
+type RestartSettings struct {
+	// Enabled indicates whether receivers that fail to start are retried.
+	Enabled bool `mapstructure:"enabled"`
+	// InitialInterval is the time to wait before the first restart attempt.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval is the upper bound on the time between restart attempts.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// MaxAttempts is the number of restart attempts after which a receiver is given up
+	// on. Zero means no limit.
+	MaxAttempts int `mapstructure:"max_attempts"`
 }
 
+// resourceAttributes maps endpoint types to resource attribute names and the
+// expressions their values are expanded from.
+type resourceAttributes map[string]map[string]string
+
 // Copied from the Viper but changed to use the same delimiter.
 // See https://github.com/spf13/viper/issues/871
 func viperSub(v *viper.Viper, key string) *viper.Viper {
@@ -17,9 +17,11 @@ package receivercreator
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/antonmedv/expr"
+	"go.opentelemetry.io/collector/config/configmodels"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -128,3 +130,93 @@ func expandMap(cfg map[string]interface{}, env observer.EndpointEnv) (map[string
 
 	return resolved, nil
 }
+
+// endpointAnnotations returns the annotations of the k8s object endpoint e was discovered from.
+func endpointAnnotations(e observer.Endpoint) map[string]string {
+	switch o := e.Details.(type) {
+	case observer.Pod:
+		return o.Annotations
+	case observer.Port:
+		return o.Pod.Annotations
+	case observer.Service:
+		return o.Annotations
+	case observer.Node:
+		return o.Annotations
+	default:
+		return nil
+	}
+}
+
+// annotationConfig returns the config overrides set on endpoint e for receivers of type
+// typeStr, keeping only the keys in allowed or nested below them. Overrides are
+// annotations of the form io.opentelemetry.receiver.<type>/<key> where dots in key
+// address nested config values. For instance:
+//
+//   io.opentelemetry.receiver.redis/collection_interval: 30s -> {"collection_interval": "30s"}
+//   io.opentelemetry.receiver.redis/tls.insecure: "true" -> {"tls": {"insecure": "true"}}
+//
+// Annotation values are used as is and are not expanded.
+func annotationConfig(e observer.Endpoint, typeStr configmodels.Type, allowed []string) userConfigMap {
+	cfg := userConfigMap{}
+	if len(allowed) == 0 {
+		return cfg
+	}
+
+	annotations := endpointAnnotations(e)
+
+	// Sort keys so that conflicting annotations are resolved consistently.
+	var keys []string
+	for k := range annotations {
+		if strings.HasPrefix(k, annotationPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		parts := strings.SplitN(strings.TrimPrefix(k, annotationPrefix), "/", 2)
+		if len(parts) != 2 || parts[0] != string(typeStr) || parts[1] == "" {
+			continue
+		}
+		if !overrideAllowed(parts[1], allowed) {
+			continue
+		}
+
+		path := strings.Split(parts[1], ".")
+		m := map[string]interface{}(cfg)
+		for _, p := range path[:len(path)-1] {
+			next, ok := m[p].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				m[p] = next
+			}
+			m = next
+		}
+		m[path[len(path)-1]] = annotations[k]
+	}
+
+	return cfg
+}
+
+// overrideAllowed returns whether key is one of allowed or nested below one of them.
+func overrideAllowed(key string, allowed []string) bool {
+	for _, a := range allowed {
+		if key == a || strings.HasPrefix(key, a+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeConfigMaps recursively merges src into dst, values from src taking precedence.
+func mergeConfigMaps(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeConfigMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+}
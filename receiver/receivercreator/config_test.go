@@ -17,6 +17,7 @@ package receivercreator
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -85,5 +86,31 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, userConfigMap{
 		endpointConfigKey: "localhost:12345",
 	}, r1.receiverTemplates["examplereceiver/1"].config)
+	assert.Equal(t, []string{"collection_interval"}, r1.receiverTemplates["examplereceiver/1"].AnnotationOverrides)
 	assert.Equal(t, []configmodels.Type{"mock_observer"}, r1.WatchObservers)
+	assert.Equal(t, RestartSettings{
+		Enabled:         true,
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Minute,
+		MaxAttempts:     3,
+	}, r1.Restart)
+
+	// Attributes of configured endpoint types replace the defaults, others are kept.
+	assert.Equal(t, map[string]string{
+		"k8s.pod.name": "`pod.name`",
+		"app":          "`pod.labels[\"app\"]`",
+	}, r1.ResourceAttributes[portType])
+	assert.Equal(t, defaultResourceAttributes()[podType], r1.ResourceAttributes[podType])
+}
+
+func TestInvalidResourceAttributes(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+
+	factories.Receivers[configmodels.Type(typeStr)] = NewFactory()
+	_, err = configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "invalid-resource-attributes.yaml"), factories,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `resource attributes for unsupported endpoint type "unknown"`)
 }
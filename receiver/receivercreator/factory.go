@@ -17,12 +17,15 @@ package receivercreator
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sharedcomponent"
 )
 
 // This file implements factory for receiver_creator. A receiver_creator can create other receivers at runtime.
@@ -37,7 +40,8 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithCustomUnmarshaler(customUnmarshaler),
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -46,7 +50,13 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		receiverTemplates: map[string]receiverTemplate{},
+		receiverTemplates:  map[string]receiverTemplate{},
+		ResourceAttributes: defaultResourceAttributes(),
+		Restart: RestartSettings{
+			Enabled:         true,
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     5 * time.Minute,
+		},
 	}
 }
 
@@ -56,9 +66,41 @@ func createMetricsReceiver(
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
-	return newReceiverCreator(params.Logger, consumer, cfg.(*Config))
+	r := createReceiver(params, cfg)
+	if err := r.registerMetricsConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func createTraceReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	r := createReceiver(params, cfg)
+	if err := r.registerTraceConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func createReceiver(params component.ReceiverCreateParams, cfg configmodels.Receiver) *receiverCreator {
+	rCfg := cfg.(*Config)
+
+	// There must be one receiver_creator for both metrics and traces so that receivers
+	// are only created once per endpoint.
+	r, _ := receivers.GetOrAdd(rCfg, func() (interface{}, error) {
+		return newReceiverCreator(params.Logger, rCfg), nil
+	})
+	return r.(*receiverCreator)
 }
 
+// receivers holds the receiverCreator shared by the metrics and trace pipelines of
+// each configuration.
+var receivers = sharedcomponent.NewComponents()
+
 func customUnmarshaler(sourceViperSection *viper.Viper, intoCfg interface{}) error {
 	if sourceViperSection == nil {
 		// Nothing to do if there is no config given.
@@ -70,6 +112,14 @@ func customUnmarshaler(sourceViperSection *viper.Viper, intoCfg interface{}) err
 		return err
 	}
 
+	for endpointType := range c.ResourceAttributes {
+		switch endpointType {
+		case podType, portType, hostPortType, containerType, serviceType, nodeType:
+		default:
+			return fmt.Errorf("resource attributes for unsupported endpoint type %q", endpointType)
+		}
+	}
+
 	receiversCfg := viperSub(sourceViperSection, receiversConfigKey)
 
 	for subreceiverKey := range receiversCfg.AllSettings() {
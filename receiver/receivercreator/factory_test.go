@@ -20,7 +20,6 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/configerror"
 	"go.uber.org/zap"
 )
 
@@ -38,7 +37,17 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
 	mReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, nil)
-	assert.Error(t, err)
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
+	assert.Equal(t, errNilNextConsumer, err)
 	assert.Nil(t, mReceiver)
+
+	// Metrics and trace receivers share a single instance per config.
+	mReceiver, err = factory.CreateTraceReceiver(context.Background(), params, cfg, &mockTraceConsumer{})
+	assert.NoError(t, err, "receiver creation failed")
+	assert.Same(t, tReceiver, mReceiver)
+
+	// A receiver that was shut down is not handed out again.
+	assert.NoError(t, tReceiver.Shutdown(context.Background()))
+	mReceiver, err = factory.CreateTraceReceiver(context.Background(), params, cfg, &mockTraceConsumer{})
+	assert.NoError(t, err, "receiver creation failed")
+	assert.NotSame(t, tReceiver, mReceiver)
 }
@@ -19,7 +19,9 @@ import (
 )
 
 var pod = observer.Pod{
-	Name: "pod-1",
+	Name:      "pod-1",
+	Namespace: "default",
+	UID:       "pod-1-UID",
 	Labels: map[string]string{
 		"app":    "redis",
 		"region": "west-1",
@@ -46,6 +48,51 @@ var portEndpoint = observer.Endpoint{
 	},
 }
 
+var serviceEndpoint = observer.Endpoint{
+	ID:     "service-1",
+	Target: "10.96.0.12:6379",
+	Details: observer.Service{
+		Name:      "redis",
+		Namespace: "default",
+		ClusterIP: "10.96.0.12",
+		PortName:  "redis",
+		Port:      6379,
+		Transport: observer.ProtocolTCP,
+	},
+}
+
+var nodeEndpoint = observer.Endpoint{
+	ID:     "node-1",
+	Target: "10.0.0.5:10250",
+	Details: observer.Node{
+		Name: "node-1",
+		Labels: map[string]string{
+			"zone": "west-1a",
+		},
+		InternalIP:          "10.0.0.5",
+		KubeletEndpointPort: 10250,
+	},
+}
+
+var containerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "172.17.0.2:6379",
+	Details: observer.Container{
+		Name:        "cache",
+		Image:       "redis",
+		Tag:         "6.0",
+		ContainerID: "5e1f",
+		Labels: map[string]string{
+			"app": "cache",
+		},
+		Network:   "bridge",
+		Host:      "172.17.0.2",
+		Port:      6379,
+		HostPort:  16379,
+		Transport: observer.ProtocolTCP,
+	},
+}
+
 var unsupportedEndpoint = observer.Endpoint{
 	ID:      "endpoint-1",
 	Target:  "localhost:1234",
@@ -7,6 +7,7 @@ require (
 	github.com/census-instrumentation/opencensus-proto v0.3.0
 	github.com/googleapis/gnostic v0.4.1 // indirect
 	github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer v0.0.0-00010101000000-000000000000
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
 	github.com/spf13/cast v1.3.1
 	github.com/spf13/viper v1.7.1
 	github.com/stretchr/testify v1.6.1
@@ -14,7 +15,7 @@ require (
 	go.uber.org/zap v1.15.0
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e // indirect
 	google.golang.org/grpc/examples v0.0.0-20200728194956-1c32b02682df // indirect
-	k8s.io/client-go v0.18.6 // indirect
+	k8s.io/client-go v0.18.8 // indirect
 	k8s.io/utils v0.0.0-20200724153422-f32512634ab7 // indirect
 )
 
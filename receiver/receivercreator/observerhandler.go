@@ -1,4 +1,4 @@
-// Copyright 2020, OpenTelemetry Authors
+// Copyright The OpenTelemetry Authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -15,10 +15,15 @@
 package receivercreator
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
@@ -34,6 +39,18 @@ type observerHandler struct {
 	receiverTemplates map[string]receiverTemplate
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
+	// receiverNamesByEndpointID maps endpoint IDs to the template names of the receivers
+	// running for them.
+	receiverNamesByEndpointID map[observer.EndpointID][]string
+	// restartsByEndpointID maps endpoint IDs to the stop channels of pending restarts of
+	// receivers that failed to start.
+	restartsByEndpointID map[observer.EndpointID][]chan struct{}
+	// resourceAttributes are the resource attributes set on the data of receivers by endpoint type.
+	resourceAttributes resourceAttributes
+	// consumers are the consumers the data of started receivers is sent to.
+	consumers receiverConsumers
+	// restart configures restarting of receivers that fail to start.
+	restart RestartSettings
 	// runner starts and stops receiver instances.
 	runner runner
 }
@@ -43,6 +60,10 @@ func (obs *observerHandler) Shutdown() error {
 	obs.Lock()
 	defer obs.Unlock()
 
+	for id := range obs.restartsByEndpointID {
+		obs.stopRestarts(id)
+	}
+
 	var errs []error
 
 	for _, rcvr := range obs.receiversByEndpointID.Values() {
@@ -61,10 +82,20 @@ func (obs *observerHandler) Shutdown() error {
 }
 
 // OnAdd responds to endpoint add notifications.
+//
+// Templates are evaluated in the lexical order of their names. When the rules of several
+// templates of the same receiver type match an endpoint only the first one is started
+// for it, so that an endpoint is never collected from twice by the same kind of receiver.
 func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 	obs.Lock()
 	defer obs.Unlock()
 
+	names := make([]string, 0, len(obs.receiverTemplates))
+	for name := range obs.receiverTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	for _, e := range added {
 		env, err := observer.EndpointToEnv(e)
 		if err != nil {
@@ -72,7 +103,17 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 			continue
 		}
 
-		for _, template := range obs.receiverTemplates {
+		attrs, err := obs.resourceAttributes.resolve(e, env)
+		if err != nil {
+			obs.logger.Error("unable to resolve resource attributes", zap.String("endpoint", string(e.ID)), zap.Error(err))
+		}
+		consumers := obs.consumers.withResourceAttributes(attrs)
+
+		// Receiver types already matched by a template for this endpoint.
+		matched := map[configmodels.Type]string{}
+
+		for _, name := range names {
+			template := obs.receiverTemplates[name]
 			if matches, err := template.rule.eval(env); err != nil {
 				obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
 				continue
@@ -80,6 +121,15 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				continue
 			}
 
+			if first, ok := matched[template.typeStr]; ok {
+				obs.logger.Warn("skipping receiver as another receiver of the same type matched the endpoint",
+					zap.String("name", template.fullName),
+					zap.String("matched", first),
+					zap.String("endpoint_id", string(e.ID)))
+				continue
+			}
+			matched[template.typeStr] = template.fullName
+
 			obs.logger.Info("starting receiver",
 				zap.String("name", template.fullName),
 				zap.String("type", string(template.typeStr)),
@@ -92,6 +142,10 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				continue
 			}
 
+			// Annotations set on the endpoint for the keys the template allows take
+			// precedence over the template config.
+			mergeConfigMaps(resolvedConfig, annotationConfig(e, template.typeStr, template.AnnotationOverrides))
+
 			discoveredConfig := userConfigMap{}
 
 			// If user didn't set endpoint set to default value.
@@ -106,20 +160,28 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 				continue
 			}
 
-			rcvr, err := obs.runner.start(receiverConfig{
+			rcvrCfg := receiverConfig{
 				fullName: template.fullName,
 				typeStr:  template.typeStr,
 				config:   resolvedConfig,
-			}, resolvedDiscoveredConfig)
+			}
+			rcvr, err := obs.runner.start(rcvrCfg, resolvedDiscoveredConfig, consumers)
 
 			if err != nil {
-				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
+				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName), zap.Error(err))
+				if obs.restart.Enabled && errors.Is(err, errReceiverStart) {
+					obs.scheduleRestart(e.ID, template.fullName, func() (component.Receiver, error) {
+						return obs.runner.start(rcvrCfg, resolvedDiscoveredConfig, consumers)
+					})
+				}
 				continue
 			}
 
-			obs.receiversByEndpointID.Put(e.ID, rcvr)
+			obs.putReceiver(e.ID, template.fullName, rcvr)
 		}
 	}
+
+	obs.logRunning()
 }
 
 // OnRemove responds to endpoint removal notifications.
@@ -128,6 +190,8 @@ func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 	defer obs.Unlock()
 
 	for _, e := range removed {
+		obs.stopRestarts(e.ID)
+
 		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID) {
 			obs.logger.Info("stopping receiver", zap.Reflect("receiver", rcvr), zap.String("endpoint_id", string(e.ID)))
 
@@ -137,7 +201,10 @@ func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
 			}
 		}
 		obs.receiversByEndpointID.RemoveAll(e.ID)
+		delete(obs.receiverNamesByEndpointID, e.ID)
 	}
+
+	obs.logRunning()
 }
 
 // OnChange responds to endpoint change notifications.
@@ -146,3 +213,151 @@ func (obs *observerHandler) OnChange(changed []observer.Endpoint) {
 	obs.OnRemove(changed)
 	obs.OnAdd(changed)
 }
+
+// putReceiver records rcvr as running for endpoint id. Must be called with the lock held.
+func (obs *observerHandler) putReceiver(id observer.EndpointID, name string, rcvr component.Receiver) {
+	obs.receiversByEndpointID.Put(id, rcvr)
+	obs.receiverNamesByEndpointID[id] = append(obs.receiverNamesByEndpointID[id], name)
+}
+
+// scheduleRestart retries starting the receiver name that failed to start for endpoint id
+// using start, backing off between attempts, until it starts, the endpoint is removed or
+// the handler is shut down. Must be called with the lock held.
+func (obs *observerHandler) scheduleRestart(id observer.EndpointID, name string, start func() (component.Receiver, error)) {
+	stop := make(chan struct{})
+	obs.restartsByEndpointID[id] = append(obs.restartsByEndpointID[id], stop)
+
+	go func() {
+		interval := obs.restart.InitialInterval
+		for attempt := 1; obs.restart.MaxAttempts == 0 || attempt <= obs.restart.MaxAttempts; attempt++ {
+			timer := time.NewTimer(interval)
+			select {
+			case <-stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			if obs.attemptRestart(id, name, attempt, stop, start) {
+				return
+			}
+
+			interval *= 2
+			if interval > obs.restart.MaxInterval {
+				interval = obs.restart.MaxInterval
+			}
+		}
+
+		obs.Lock()
+		defer obs.Unlock()
+		if obs.removeRestart(id, stop) {
+			obs.logger.Error("giving up restarting receiver",
+				zap.String("receiver", name),
+				zap.String("endpoint_id", string(id)),
+				zap.Int("attempts", obs.restart.MaxAttempts))
+		}
+	}()
+}
+
+// attemptRestart makes a single attempt at restarting a receiver, returning whether
+// restarting it is over, either because it started or because the restart was stopped.
+// The lock is not held while the receiver starts, so that a slow start does not block
+// endpoint notifications and shutdown.
+func (obs *observerHandler) attemptRestart(
+	id observer.EndpointID,
+	name string,
+	attempt int,
+	stop chan struct{},
+	start func() (component.Receiver, error),
+) bool {
+	if stopped(stop) {
+		return true
+	}
+
+	rcvr, err := start()
+	if err != nil {
+		obs.logger.Warn("failed to restart receiver",
+			zap.String("receiver", name),
+			zap.String("endpoint_id", string(id)),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+		return false
+	}
+
+	obs.Lock()
+	defer obs.Unlock()
+
+	// The endpoint may have been removed or the handler shut down while the
+	// receiver was starting, in which case nothing else will stop it.
+	if stopped(stop) {
+		if err := obs.runner.shutdown(rcvr); err != nil {
+			obs.logger.Error("failed to stop receiver", zap.Reflect("receiver", rcvr))
+		}
+		return true
+	}
+
+	obs.logger.Info("restarted receiver",
+		zap.String("receiver", name),
+		zap.String("endpoint_id", string(id)),
+		zap.Int("attempt", attempt))
+	obs.removeRestart(id, stop)
+	obs.putReceiver(id, name, rcvr)
+	obs.logRunning()
+	return true
+}
+
+// removeRestart removes the pending restart with the given stop channel, returning
+// whether it was pending. Must be called with the lock held.
+func (obs *observerHandler) removeRestart(id observer.EndpointID, stop chan struct{}) bool {
+	restarts := obs.restartsByEndpointID[id]
+	for i, s := range restarts {
+		if s != stop {
+			continue
+		}
+		restarts = append(restarts[:i], restarts[i+1:]...)
+		if len(restarts) == 0 {
+			delete(obs.restartsByEndpointID, id)
+		} else {
+			obs.restartsByEndpointID[id] = restarts
+		}
+		return true
+	}
+	return false
+}
+
+// stopped reports whether the restart with the given stop channel was stopped.
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopRestarts stops all pending restarts for endpoint id. Must be called with the lock held.
+func (obs *observerHandler) stopRestarts(id observer.EndpointID) {
+	for _, stop := range obs.restartsByEndpointID[id] {
+		close(stop)
+	}
+	delete(obs.restartsByEndpointID, id)
+}
+
+// logRunning logs the receivers currently running and the number of receivers pending
+// a restart. Must be called with the lock held.
+func (obs *observerHandler) logRunning() {
+	var running []string
+	for id, names := range obs.receiverNamesByEndpointID {
+		for _, name := range names {
+			running = append(running, fmt.Sprintf("%s{endpoint_id=%q}", name, id))
+		}
+	}
+	sort.Strings(running)
+
+	pending := 0
+	for _, restarts := range obs.restartsByEndpointID {
+		pending += len(restarts)
+	}
+
+	obs.logger.Info("running receivers", zap.Strings("receivers", running), zap.Int("pending_restarts", pending))
+}
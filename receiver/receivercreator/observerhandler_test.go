@@ -15,14 +15,19 @@
 package receivercreator
 
 import (
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.uber.org/zap"
+	zapObserver "go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -31,9 +36,10 @@ type mockRunner struct {
 	mock.Mock
 }
 
-func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
-	args := run.Called(receiver, discoveredConfig)
-	return args.Get(0).(component.Receiver), args.Error(1)
+func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap, consumers receiverConsumers) (component.Receiver, error) {
+	args := run.Called(receiver, discoveredConfig, consumers)
+	rcvr, _ := args.Get(0).(component.Receiver)
+	return rcvr, args.Error(1)
 }
 
 func (run *mockRunner) shutdown(rcvr component.Receiver) error {
@@ -49,13 +55,15 @@ func TestOnAdd(t *testing.T) {
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
+			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`), nil},
 		},
-		receiversByEndpointID: receiverMap{},
-		runner:                runner,
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
 	}
 
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
 
 	handler.OnAdd([]observer.Endpoint{
 		portEndpoint,
@@ -70,9 +78,11 @@ func TestOnRemove(t *testing.T) {
 	runner := &mockRunner{}
 	rcvr := &componenttest.ExampleReceiverProducer{}
 	handler := &observerHandler{
-		logger:                zap.NewNop(),
-		receiversByEndpointID: receiverMap{},
-		runner:                runner,
+		logger:                    zap.NewNop(),
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
 	}
 
 	handler.receiversByEndpointID.Put("port-1", rcvr)
@@ -93,16 +103,18 @@ func TestOnChange(t *testing.T) {
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
+			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`), nil},
 		},
-		receiversByEndpointID: receiverMap{},
-		runner:                runner,
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
 	}
 
 	handler.receiversByEndpointID.Put("port-1", oldRcvr)
 
 	runner.On("shutdown", oldRcvr).Return(nil)
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(newRcvr, nil)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(newRcvr, nil)
 
 	handler.OnChange([]observer.Endpoint{portEndpoint})
 
@@ -114,9 +126,11 @@ func TestOnChange(t *testing.T) {
 func TestDynamicConfig(t *testing.T) {
 	runner := &mockRunner{}
 	handler := &observerHandler{
-		logger:                zap.NewNop(),
-		receiversByEndpointID: receiverMap{},
-		runner:                runner,
+		logger:                    zap.NewNop(),
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
 		receiverTemplates: map[string]receiverTemplate{
 			"name/1": {
 				receiverConfig: receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"endpoint": "`endpoint`:6379"}, fullName: "name/1"},
@@ -129,10 +143,311 @@ func TestDynamicConfig(t *testing.T) {
 		fullName: "name/1",
 		typeStr:  "name",
 		config:   userConfigMap{endpointConfigKey: "localhost:6379"},
-	}, userConfigMap{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	}, userConfigMap{}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
 	handler.OnAdd([]observer.Endpoint{
 		podEndpoint,
 	})
 
 	runner.AssertExpectations(t)
 }
+
+func newAnnotationHandler(runner runner, annotationOverrides []string) *observerHandler {
+	return &observerHandler{
+		logger:                    zap.NewNop(),
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {
+				receiverConfig: receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{
+					"collection_interval": "10s",
+					"tls":                 map[string]interface{}{"ca_file": "/ca.crt"},
+				}, fullName: "name/1"},
+				Rule:                "type.port",
+				rule:                newRuleOrPanic("type.port"),
+				AnnotationOverrides: annotationOverrides,
+			},
+		},
+	}
+}
+
+func annotatedPortEndpoint() observer.Endpoint {
+	annotatedPod := pod
+	annotatedPod.Annotations = map[string]string{
+		"io.opentelemetry.receiver.name/collection_interval": "30s",
+		"io.opentelemetry.receiver.name/tls.insecure":        "true",
+		"io.opentelemetry.receiver.name/endpoint":            "evil.example.com:6379",
+		"io.opentelemetry.receiver.other/password":           "secret",
+	}
+	annotatedEndpoint := portEndpoint
+	annotatedEndpoint.Details = observer.Port{Name: "http", Pod: annotatedPod, Port: 1234, Transport: observer.ProtocolTCP}
+	return annotatedEndpoint
+}
+
+func TestAnnotationConfig(t *testing.T) {
+	runner := &mockRunner{}
+	handler := newAnnotationHandler(runner, []string{"collection_interval", "tls", "password"})
+
+	// The endpoint annotation is not allowed and ignored, as is the password
+	// annotation for receivers of another type.
+	runner.On("start", receiverConfig{
+		fullName: "name/1",
+		typeStr:  "name",
+		config: userConfigMap{
+			"collection_interval": "30s",
+			"tls":                 map[string]interface{}{"ca_file": "/ca.crt", "insecure": "true"},
+		},
+	}, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	handler.OnAdd([]observer.Endpoint{annotatedPortEndpoint()})
+
+	runner.AssertExpectations(t)
+}
+
+func TestAnnotationConfigDisabledByDefault(t *testing.T) {
+	runner := &mockRunner{}
+	handler := newAnnotationHandler(runner, nil)
+
+	runner.On("start", receiverConfig{
+		fullName: "name/1",
+		typeStr:  "name",
+		config: userConfigMap{
+			"collection_interval": "10s",
+			"tls":                 map[string]interface{}{"ca_file": "/ca.crt"},
+		},
+	}, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	handler.OnAdd([]observer.Endpoint{annotatedPortEndpoint()})
+
+	runner.AssertExpectations(t)
+}
+
+func TestResourceAttributes(t *testing.T) {
+	runner := &mockRunner{}
+	nextConsumer := &mockMetricsConsumer{}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{}, fullName: "name/1"}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`), nil},
+		},
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		resourceAttributes:        defaultResourceAttributes(),
+		consumers:                 receiverConsumers{metrics: nextConsumer},
+		runner:                    runner,
+	}
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{
+		metrics: &enhancingConsumer{
+			nextMetricsConsumer: nextConsumer,
+			attrs: map[string]string{
+				"k8s.pod.name":       "pod-1",
+				"k8s.pod.uid":        "pod-1-UID",
+				"k8s.namespace.name": "default",
+			},
+		},
+	}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	runner.AssertExpectations(t)
+}
+
+func TestMultipleRulesMatch(t *testing.T) {
+	runner := &mockRunner{}
+	name1 := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{}, fullName: "name/1"}
+	name2 := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{}, fullName: "name/2"}
+	other := receiverConfig{typeStr: configmodels.Type("other"), config: userConfigMap{}, fullName: "other"}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/2": {name2, "", newRuleOrPanic(`type.port`), nil},
+			"name/1": {name1, "", newRuleOrPanic(`type.port`), nil},
+			"other":  {other, "", newRuleOrPanic(`type.port`), nil},
+		},
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		runner:                    runner,
+	}
+
+	// Only the first template in name order of each receiver type is started.
+	runner.On("start", name1, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	runner.On("start", other, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	runner.AssertExpectations(t)
+	runner.AssertNumberOfCalls(t, "start", 2)
+	assert.Equal(t, []string{"name/1", "other"}, handler.receiverNamesByEndpointID["port-1"])
+}
+
+func newRestartingHandler(runner runner, maxAttempts int) (*observerHandler, receiverConfig) {
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{}, fullName: "name/1"}
+	return &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`), nil},
+		},
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		restart: RestartSettings{
+			Enabled:         true,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     2 * time.Millisecond,
+			MaxAttempts:     maxAttempts,
+		},
+		runner: runner,
+	}, rcvrCfg
+}
+
+func restartsPending(handler *observerHandler) int {
+	handler.Lock()
+	defer handler.Unlock()
+	return len(handler.restartsByEndpointID)
+}
+
+func TestRestart(t *testing.T) {
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 0)
+	rcvr := &componenttest.ExampleReceiverProducer{}
+	startErr := fmt.Errorf("%w name/1: port in use", errReceiverStart)
+
+	discovered := userConfigMap{endpointConfigKey: "localhost:1234"}
+	runner.On("start", rcvrCfg, discovered, receiverConsumers{}).Return(nil, startErr).Times(3)
+	runner.On("start", rcvrCfg, discovered, receiverConsumers{}).Return(rcvr, nil).Once()
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	require.Eventually(t, func() bool {
+		return restartsPending(handler) == 0
+	}, 5*time.Second, time.Millisecond)
+
+	runner.AssertExpectations(t)
+	handler.Lock()
+	defer handler.Unlock()
+	assert.Equal(t, []component.Receiver{rcvr}, handler.receiversByEndpointID.Get("port-1"))
+	assert.Equal(t, []string{"name/1"}, handler.receiverNamesByEndpointID["port-1"])
+}
+
+func TestRestartGivesUp(t *testing.T) {
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 2)
+	startErr := fmt.Errorf("%w name/1: port in use", errReceiverStart)
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(nil, startErr)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	require.Eventually(t, func() bool {
+		return restartsPending(handler) == 0
+	}, 5*time.Second, time.Millisecond)
+
+	// Initial attempt followed by two restarts.
+	runner.AssertNumberOfCalls(t, "start", 3)
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+}
+
+func TestRestartStopped(t *testing.T) {
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 0)
+	handler.restart.InitialInterval = time.Hour
+	startErr := fmt.Errorf("%w name/1: port in use", errReceiverStart)
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(nil, startErr)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+	assert.Equal(t, 1, restartsPending(handler))
+
+	handler.OnRemove([]observer.Endpoint{portEndpoint})
+	assert.Equal(t, 0, restartsPending(handler))
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+	assert.Equal(t, 1, restartsPending(handler))
+
+	require.NoError(t, handler.Shutdown())
+	assert.Equal(t, 0, restartsPending(handler))
+	runner.AssertNumberOfCalls(t, "start", 2)
+}
+
+func TestRestartStoppedWhileStarting(t *testing.T) {
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 0)
+	rcvr := &componenttest.ExampleReceiverProducer{}
+	startErr := fmt.Errorf("%w name/1: port in use", errReceiverStart)
+	starting := make(chan struct{})
+	release := make(chan struct{})
+	shutdown := make(chan struct{})
+
+	discovered := userConfigMap{endpointConfigKey: "localhost:1234"}
+	runner.On("start", rcvrCfg, discovered, receiverConsumers{}).Return(nil, startErr).Once()
+	runner.On("start", rcvrCfg, discovered, receiverConsumers{}).Run(func(mock.Arguments) {
+		close(starting)
+		<-release
+	}).Return(rcvr, nil).Once()
+	runner.On("shutdown", rcvr).Run(func(mock.Arguments) {
+		close(shutdown)
+	}).Return(nil).Once()
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+	<-starting
+
+	// The endpoint can be removed while the receiver is being restarted.
+	handler.OnRemove([]observer.Endpoint{portEndpoint})
+	assert.Equal(t, 0, restartsPending(handler))
+	close(release)
+
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("receiver started after its endpoint was removed was not shut down")
+	}
+
+	runner.AssertExpectations(t)
+	handler.Lock()
+	defer handler.Unlock()
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
+}
+
+func TestNoRestartOnCreationError(t *testing.T) {
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 0)
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(nil, errors.New("invalid config"))
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	assert.Equal(t, 0, restartsPending(handler))
+	runner.AssertNumberOfCalls(t, "start", 1)
+}
+
+func TestLogRunning(t *testing.T) {
+	core, logs := zapObserver.New(zap.InfoLevel)
+	runner := &mockRunner{}
+	handler, rcvrCfg := newRestartingHandler(runner, 0)
+	handler.logger = zap.New(core)
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, receiverConsumers{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	runner.On("shutdown", mock.Anything).Return(nil)
+
+	otherPortEndpoint := portEndpoint
+	otherPortEndpoint.ID = "port-2"
+	handler.OnAdd([]observer.Endpoint{portEndpoint, otherPortEndpoint})
+
+	running := logs.FilterMessage("running receivers").All()
+	require.Len(t, running, 1)
+	assert.Equal(t, []interface{}{
+		`name/1{endpoint_id="port-1"}`,
+		`name/1{endpoint_id="port-2"}`,
+	}, running[0].ContextMap()["receivers"])
+
+	handler.OnRemove([]observer.Endpoint{otherPortEndpoint})
+
+	running = logs.FilterMessage("running receivers").All()
+	require.Len(t, running, 2)
+	assert.Equal(t, []interface{}{
+		`name/1{endpoint_id="port-1"}`,
+	}, running[1].ContextMap()["receivers"])
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -32,27 +33,44 @@ var (
 )
 
 var _ component.MetricsReceiver = (*receiverCreator)(nil)
+var _ component.TraceReceiver = (*receiverCreator)(nil)
 
-// receiverCreator implements consumer.MetricsConsumer.
+// receiverCreator implements component.MetricsReceiver and component.TraceReceiver. A single
+// instance is shared by the metrics and traces pipelines it is part of.
 type receiverCreator struct {
-	nextConsumer    consumer.MetricsConsumer
-	logger          *zap.Logger
-	cfg             *Config
-	observerHandler observerHandler
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTraceConsumer   consumer.TraceConsumer
+	logger              *zap.Logger
+	cfg                 *Config
+	observerHandler     observerHandler
+	startOnce           sync.Once
+	shutdownOnce        sync.Once
 }
 
 // newReceiverCreator creates the receiver_creator with the given parameters.
-func newReceiverCreator(logger *zap.Logger, nextConsumer consumer.MetricsConsumer, cfg *Config) (component.MetricsReceiver, error) {
+func newReceiverCreator(logger *zap.Logger, cfg *Config) *receiverCreator {
+	return &receiverCreator{
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// registerMetricsConsumer sets the consumer metrics of created receivers are sent to.
+func (rc *receiverCreator) registerMetricsConsumer(nextConsumer consumer.MetricsConsumer) error {
 	if nextConsumer == nil {
-		return nil, errNilNextConsumer
+		return errNilNextConsumer
 	}
+	rc.nextMetricsConsumer = nextConsumer
+	return nil
+}
 
-	r := &receiverCreator{
-		logger:       logger,
-		nextConsumer: nextConsumer,
-		cfg:          cfg,
+// registerTraceConsumer sets the consumer traces of created receivers are sent to.
+func (rc *receiverCreator) registerTraceConsumer(nextConsumer consumer.TraceConsumer) error {
+	if nextConsumer == nil {
+		return errNilNextConsumer
 	}
-	return r, nil
+	rc.nextTraceConsumer = nextConsumer
+	return nil
 }
 
 // loggingHost provides a safer version of host that logs errors instead of exiting the process.
@@ -68,16 +86,31 @@ func (h *loggingHost) ReportFatalError(err error) {
 
 var _ component.Host = (*loggingHost)(nil)
 
-// Start receiver_creator.
+// Start receiver_creator. It is only started once even if part of several pipelines.
 func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error {
+	var err error
+	rc.startOnce.Do(func() {
+		err = rc.start(host)
+	})
+	return err
+}
+
+func (rc *receiverCreator) start(host component.Host) error {
 	rc.observerHandler = observerHandler{
-		logger:                rc.logger,
-		receiverTemplates:     rc.cfg.receiverTemplates,
-		receiversByEndpointID: receiverMap{},
+		logger:                    rc.logger,
+		receiverTemplates:         rc.cfg.receiverTemplates,
+		receiversByEndpointID:     receiverMap{},
+		receiverNamesByEndpointID: map[observer.EndpointID][]string{},
+		restartsByEndpointID:      map[observer.EndpointID][]chan struct{}{},
+		resourceAttributes:        rc.cfg.ResourceAttributes,
+		consumers: receiverConsumers{
+			metrics: rc.nextMetricsConsumer,
+			traces:  rc.nextTraceConsumer,
+		},
+		restart: rc.cfg.Restart,
 		runner: &receiverRunner{
-			logger:       rc.logger,
-			nextConsumer: rc.nextConsumer,
-			idNamespace:  rc.cfg.Name(),
+			logger:      rc.logger,
+			idNamespace: rc.cfg.Name(),
 			// TODO: not really sure what context should be used here for starting subreceivers
 			// as don't think it makes sense to use Start context as the lifetimes are different.
 			ctx:  context.Background(),
@@ -123,5 +156,11 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 
 // Shutdown stops the receiver_creator and all its receivers started at runtime.
 func (rc *receiverCreator) Shutdown(ctx context.Context) error {
-	return rc.observerHandler.Shutdown()
+	receivers.Remove(rc)
+
+	var err error
+	rc.shutdownOnce.Do(func() {
+		err = rc.observerHandler.Shutdown()
+	})
+	return err
 }
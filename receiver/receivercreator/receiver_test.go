@@ -60,6 +60,19 @@ func (p *mockMetricsConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metri
 	return nil
 }
 
+type mockTraceConsumer struct {
+	Traces     []pdata.Traces
+	TotalSpans int
+}
+
+var _ consumer.TraceConsumer = &mockTraceConsumer{}
+
+func (p *mockTraceConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	p.Traces = append(p.Traces, td)
+	p.TotalSpans += td.SpanCount()
+	return nil
+}
+
 type mockObserver struct {
 }
 
@@ -147,11 +160,61 @@ func TestMockedEndToEnd(t *testing.T) {
 	// TODO: Will have to rework once receivers are started asynchronously to Start().
 	assert.Len(t, mockConsumer.Metrics, 1)
 
+	// Metrics are tagged with the resource attributes of the endpoint.
+	attrs := pdatautil.MetricsToInternalMetrics(mockConsumer.Metrics[0]).ResourceMetrics().At(0).Resource().Attributes()
+	podName, ok := attrs.Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+	app, ok := attrs.Get("app")
+	require.True(t, ok)
+	assert.Equal(t, "redis", app.StringVal())
+
 	shutdown()
 
 	assert.True(t, dyn.observerHandler.receiversByEndpointID.Values()[0].(*componenttest.ExampleReceiverProducer).Stopped)
 }
 
+func TestMockedEndToEndMetricsAndTraces(t *testing.T) {
+	host, cfg := exampleCreatorFactory(t)
+	host.extensions = map[configmodels.Extension]component.ServiceExtension{
+		&configmodels.ExtensionSettings{
+			TypeVal: "mock_observer",
+			NameVal: "mock_observer",
+		}: &mockObserver{},
+	}
+	dynCfg := cfg.Receivers["receiver_creator/1"]
+	factory := NewFactory()
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	metricsConsumer := &mockMetricsConsumer{}
+	traceConsumer := &mockTraceConsumer{}
+	metricsRcvr, err := factory.CreateMetricsReceiver(context.Background(), params, dynCfg, metricsConsumer)
+	require.NoError(t, err)
+	traceRcvr, err := factory.CreateTraceReceiver(context.Background(), params, dynCfg, traceConsumer)
+	require.NoError(t, err)
+	require.Same(t, metricsRcvr, traceRcvr)
+
+	// Each pipeline starts and stops the shared receiver_creator.
+	require.NoError(t, metricsRcvr.Start(context.Background(), host))
+	require.NoError(t, traceRcvr.Start(context.Background(), host))
+	defer func() {
+		assert.NoError(t, metricsRcvr.Shutdown(context.Background()))
+		assert.NoError(t, traceRcvr.Shutdown(context.Background()))
+	}()
+
+	dyn := metricsRcvr.(*receiverCreator)
+	require.Equal(t, 1, dyn.observerHandler.receiversByEndpointID.Size())
+
+	example := dyn.observerHandler.receiversByEndpointID.Values()[0].(*componenttest.ExampleReceiverProducer)
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	require.NoError(t, example.TraceConsumer.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, traceConsumer.Traces, 1)
+	podName, ok := traceConsumer.Traces[0].ResourceSpans().At(0).Resource().Attributes().Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+}
+
 func TestLoggingHost(t *testing.T) {
 	core, obs := zapObserver.New(zap.ErrorLevel)
 	host := &loggingHost{
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+// Endpoint types resource attributes can be configured for.
+const (
+	podType       = "pod"
+	portType      = "port"
+	hostPortType  = "hostport"
+	containerType = "container"
+	serviceType   = "service"
+	nodeType      = "node"
+)
+
+// defaultResourceAttributes are the resource attributes set on the data of created receivers
+// when not overridden by the user.
+func defaultResourceAttributes() resourceAttributes {
+	return resourceAttributes{
+		podType: {
+			conventions.AttributeK8sPod:       "`name`",
+			conventions.AttributeK8sPodUID:    "`uid`",
+			conventions.AttributeK8sNamespace: "`namespace`",
+		},
+		portType: {
+			conventions.AttributeK8sPod:       "`pod.name`",
+			conventions.AttributeK8sPodUID:    "`pod.uid`",
+			conventions.AttributeK8sNamespace: "`pod.namespace`",
+		},
+		hostPortType: {
+			conventions.AttributeProcessExecutableName: "`name`",
+			conventions.AttributeProcessExecutablePath: "`executable`",
+			conventions.AttributeContainerID:           "`container_id`",
+		},
+		containerType: {
+			conventions.AttributeContainerName:  "`name`",
+			conventions.AttributeContainerID:    "`container_id`",
+			conventions.AttributeContainerImage: "`image`",
+			conventions.AttributeContainerTag:   "`tag`",
+		},
+		serviceType: {
+			"k8s.service.name":                "`name`",
+			conventions.AttributeK8sNamespace: "`namespace`",
+		},
+		nodeType: {
+			"k8s.node.name": "`name`",
+		},
+	}
+}
+
+// endpointType returns the type of the endpoint that resource attributes are configured by.
+func endpointType(e observer.Endpoint) (string, error) {
+	switch e.Details.(type) {
+	case observer.Pod:
+		return podType, nil
+	case observer.Port:
+		return portType, nil
+	case observer.HostPort:
+		return hostPortType, nil
+	case observer.Container:
+		return containerType, nil
+	case observer.Service:
+		return serviceType, nil
+	case observer.Node:
+		return nodeType, nil
+	default:
+		return "", fmt.Errorf("unknown endpoint details type %T", e.Details)
+	}
+}
+
+// resolve expands the resource attributes configured for the type of endpoint e using env
+// as variables available within the expressions. Attributes expanding to an empty value
+// are omitted.
+func (ra resourceAttributes) resolve(e observer.Endpoint, env observer.EndpointEnv) (map[string]string, error) {
+	typ, err := endpointType(e)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := map[string]string{}
+	for attr, expr := range ra[typ] {
+		res, err := evalBackticksInConfigValue(expr, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed evaluating resource attribute %q: %v", attr, err)
+		}
+		if res == nil {
+			continue
+		}
+		if val := fmt.Sprintf("%v", res); val != "" {
+			resolved[attr] = val
+		}
+	}
+	return resolved, nil
+}
+
+// receiverConsumers are the consumers the data of created receivers is sent to. Receivers
+// are only created for the data types that have a non-nil consumer.
+type receiverConsumers struct {
+	metrics consumer.MetricsConsumer
+	traces  consumer.TraceConsumer
+}
+
+// withResourceAttributes returns consumers setting attrs on the resource of data before
+// passing it on to c.
+func (c receiverConsumers) withResourceAttributes(attrs map[string]string) receiverConsumers {
+	if len(attrs) == 0 {
+		return c
+	}
+
+	var enhanced receiverConsumers
+	if c.metrics != nil {
+		enhanced.metrics = &enhancingConsumer{nextMetricsConsumer: c.metrics, attrs: attrs}
+	}
+	if c.traces != nil {
+		enhanced.traces = &enhancingConsumer{nextTraceConsumer: c.traces, attrs: attrs}
+	}
+	return enhanced
+}
+
+// enhancingConsumer adds the resource attributes of the endpoint a receiver was created
+// for to the data it produces before passing it on to the next consumer.
+type enhancingConsumer struct {
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTraceConsumer   consumer.TraceConsumer
+	attrs               map[string]string
+}
+
+var _ consumer.MetricsConsumer = (*enhancingConsumer)(nil)
+var _ consumer.TraceConsumer = (*enhancingConsumer)(nil)
+
+// ConsumeMetrics sets the endpoint resource attributes on md, keeping any attribute
+// already set by the receiver.
+func (ec *enhancingConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	imd := pdatautil.MetricsToInternalMetrics(md)
+	rms := imd.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ec.putAttributes(rm.Resource())
+	}
+	return ec.nextMetricsConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromInternalMetrics(imd))
+}
+
+// ConsumeTraces sets the endpoint resource attributes on td, keeping any attribute
+// already set by the receiver.
+func (ec *enhancingConsumer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ec.putAttributes(rs.Resource())
+	}
+	return ec.nextTraceConsumer.ConsumeTraces(ctx, td)
+}
+
+func (ec *enhancingConsumer) putAttributes(resource pdata.Resource) {
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	attrs := resource.Attributes()
+	for attr, val := range ec.attrs {
+		attrs.InsertString(attr, val)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"testing"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+)
+
+func TestResolveResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint observer.Endpoint
+		want     map[string]string
+	}{
+		{
+			name:     "pod",
+			endpoint: podEndpoint,
+			want: map[string]string{
+				"k8s.pod.name":       "pod-1",
+				"k8s.pod.uid":        "pod-1-UID",
+				"k8s.namespace.name": "default",
+			},
+		},
+		{
+			name: "host port",
+			endpoint: observer.Endpoint{
+				ID:     "port-1",
+				Target: "localhost:6379",
+				Details: observer.HostPort{
+					Name:       "redis-server",
+					Executable: "/usr/bin/redis-server",
+					Port:       6379,
+					Transport:  observer.ProtocolTCP,
+				},
+			},
+			want: map[string]string{
+				"process.executable.name": "redis-server",
+				"process.executable.path": "/usr/bin/redis-server",
+			},
+		},
+		{
+			name:     "container",
+			endpoint: containerEndpoint,
+			want: map[string]string{
+				"container.name":       "cache",
+				"container.id":         "5e1f",
+				"container.image.name": "redis",
+				"container.image.tag":  "6.0",
+			},
+		},
+		{
+			name:     "service",
+			endpoint: serviceEndpoint,
+			want: map[string]string{
+				"k8s.service.name":   "redis",
+				"k8s.namespace.name": "default",
+			},
+		},
+		{
+			name:     "node",
+			endpoint: nodeEndpoint,
+			want: map[string]string{
+				"k8s.node.name": "node-1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := observer.EndpointToEnv(tt.endpoint)
+			require.NoError(t, err)
+			attrs, err := defaultResourceAttributes().resolve(tt.endpoint, env)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, attrs)
+		})
+	}
+
+	_, err := defaultResourceAttributes().resolve(unsupportedEndpoint, observer.EndpointEnv{})
+	assert.Error(t, err)
+
+	env, err := observer.EndpointToEnv(podEndpoint)
+	require.NoError(t, err)
+	_, err = resourceAttributes{podType: {"k8s.pod.name": "`name"}}.resolve(podEndpoint, env)
+	assert.Error(t, err)
+}
+
+func TestEnhancingMetricsConsumer(t *testing.T) {
+	nextConsumer := &mockMetricsConsumer{}
+	consumers := receiverConsumers{metrics: nextConsumer}
+	assert.Equal(t, consumers, consumers.withResourceAttributes(nil))
+
+	ec := consumers.withResourceAttributes(map[string]string{
+		"k8s.pod.name":       "pod-1",
+		"k8s.namespace.name": "default",
+	}).metrics
+	md := pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{
+		{
+			Node: &commonpb.Node{},
+			Resource: &resourcepb.Resource{
+				Labels: map[string]string{"k8s.pod.name": "set-by-receiver"},
+			},
+			Metrics: []*metricspb.Metric{
+				{
+					MetricDescriptor: &metricspb.MetricDescriptor{
+						Name: "my-metric",
+						Type: metricspb.MetricDescriptor_GAUGE_INT64,
+					},
+				},
+			},
+		},
+		{
+			Node: &commonpb.Node{},
+			Metrics: []*metricspb.Metric{
+				{
+					MetricDescriptor: &metricspb.MetricDescriptor{
+						Name: "my-metric",
+						Type: metricspb.MetricDescriptor_GAUGE_INT64,
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, ec.ConsumeMetrics(context.Background(), md))
+	require.Len(t, nextConsumer.Metrics, 1)
+
+	rms := pdatautil.MetricsToInternalMetrics(nextConsumer.Metrics[0]).ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+
+	attrs := rms.At(0).Resource().Attributes()
+	podName, ok := attrs.Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "set-by-receiver", podName.StringVal())
+	namespace, ok := attrs.Get("k8s.namespace.name")
+	require.True(t, ok)
+	assert.Equal(t, "default", namespace.StringVal())
+
+	attrs = rms.At(1).Resource().Attributes()
+	podName, ok = attrs.Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+}
+
+func TestEnhancingTraceConsumer(t *testing.T) {
+	nextConsumer := &mockTraceConsumer{}
+	ec := receiverConsumers{traces: nextConsumer}.withResourceAttributes(map[string]string{
+		"k8s.pod.name": "pod-1",
+	}).traces
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	require.NoError(t, ec.ConsumeTraces(context.Background(), td))
+	require.Len(t, nextConsumer.Traces, 1)
+
+	podName, ok := nextConsumer.Traces[0].ResourceSpans().At(0).Resource().Attributes().Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+}
@@ -47,6 +47,10 @@ func Test_ruleEval(t *testing.T) {
 		{"basic port", args{`type.port && name == "http" && pod.labels["app"] == "redis"`, portEndpoint}, true, false},
 		{"basic pod", args{`type.pod && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"annotations", args{`type.pod && annotations["scrape"] == "true"`, podEndpoint}, true, false},
+		{"basic container", args{`type.container && image == "redis" && labels["app"] == "cache"`, containerEndpoint}, true, false},
+		{"basic service", args{`type.service && name == "redis" && namespace == "default"`, serviceEndpoint}, true, false},
+		{"basic node", args{`type.node && labels["zone"] == "west-1a"`, nodeEndpoint}, true, false},
+		{"container is not a port", args{`type.port && port == 6379`, containerEndpoint}, false, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -82,6 +86,9 @@ func Test_newRule(t *testing.T) {
 		{"does not start with type", args{"port == 1234"}, true},
 		{"invalid syntax", args{"port =="}, true},
 		{"valid", args{`type.port && port_name == "http"`}, false},
+		{"valid container", args{`type.container && image == "redis"`}, false},
+		{"valid service", args{`type.service && name == "redis"`}, false},
+		{"valid node", args{`type.node && name == "node-1"`}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -16,36 +16,40 @@ package receivercreator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
-	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 )
 
+// errReceiverStart is wrapped by the errors of receivers that were created but failed to start.
+var errReceiverStart = errors.New("failed starting receiver")
+
 // runner starts and stops receiver instances.
 type runner interface {
-	// start a receiver instance from its static config and discovered config.
-	start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error)
+	// start a receiver instance from its static config and discovered config, sending its
+	// data to consumers.
+	start(receiver receiverConfig, discoveredConfig userConfigMap, consumers receiverConsumers) (component.Receiver, error)
 	// shutdown a receiver.
 	shutdown(rcvr component.Receiver) error
 }
 
 // receiverRunner handles starting/stopping of a concrete subreceiver instance.
 type receiverRunner struct {
-	logger       *zap.Logger
-	nextConsumer consumer.MetricsConsumer
-	idNamespace  string
-	ctx          context.Context
-	host         component.Host
+	logger      *zap.Logger
+	idNamespace string
+	ctx         context.Context
+	host        component.Host
 }
 
 var _ runner = (*receiverRunner)(nil)
 
 // start a receiver instance from its static config and discovered config.
-func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
+func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userConfigMap, consumers receiverConsumers) (component.Receiver, error) {
 	factory := run.host.GetFactory(component.KindReceiver, receiver.typeStr)
 
 	if factory == nil {
@@ -58,13 +62,19 @@ func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userC
 	if err != nil {
 		return nil, err
 	}
-	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg)
+	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg, consumers)
 	if err != nil {
 		return nil, err
 	}
 
 	if err := recvr.Start(run.ctx, run.host); err != nil {
-		return nil, fmt.Errorf("failed starting receiver %s: %v", cfg.Name(), err)
+		// Release whatever the receiver acquired before failing, since it is
+		// discarded and may be started again from scratch.
+		if shutdownErr := recvr.Shutdown(run.ctx); shutdownErr != nil {
+			run.logger.Warn("failed to shut down receiver that failed to start",
+				zap.String("receiver", cfg.Name()), zap.Error(shutdownErr))
+		}
+		return nil, fmt.Errorf("%w %s: %v", errReceiverStart, cfg.Name(), err)
 	}
 
 	return recvr, nil
@@ -104,8 +114,42 @@ func (run *receiverRunner) loadRuntimeReceiverConfig(
 	return receiverConfig, nil
 }
 
-// createRuntimeReceiver creates a receiver that is discovered at runtime.
-func (run *receiverRunner) createRuntimeReceiver(factory component.ReceiverFactory, cfg configmodels.Receiver) (component.MetricsReceiver, error) {
+// createRuntimeReceiver creates a receiver that is discovered at runtime for each data type
+// supported by both the receiver and consumers.
+func (run *receiverRunner) createRuntimeReceiver(
+	factory component.ReceiverFactory,
+	cfg configmodels.Receiver,
+	consumers receiverConsumers,
+) (component.Receiver, error) {
 	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
-	return factory.CreateMetricsReceiver(context.Background(), params, cfg, run.nextConsumer)
+	var rcvr component.Receiver
+
+	if consumers.metrics != nil {
+		metricsReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, consumers.metrics)
+		switch {
+		case err == configerror.ErrDataTypeIsNotSupported:
+		case err != nil:
+			return nil, err
+		default:
+			rcvr = metricsReceiver
+		}
+	}
+
+	if consumers.traces != nil {
+		traceReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, consumers.traces)
+		switch {
+		case err == configerror.ErrDataTypeIsNotSupported:
+		case err != nil:
+			return nil, err
+		case rcvr != nil && rcvr != component.Receiver(traceReceiver):
+			return nil, fmt.Errorf("factory for %q must return the same receiver instance for all data types", cfg.Type())
+		default:
+			rcvr = traceReceiver
+		}
+	}
+
+	if rcvr == nil {
+		return nil, fmt.Errorf("receiver %q does not support any data type consumed by %s", cfg.Type(), run.idNamespace)
+	}
+	return rcvr, nil
 }
@@ -15,16 +15,21 @@
 package receivercreator
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 )
 
 func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
-	run := &receiverRunner{logger: zap.NewNop(), nextConsumer: &mockMetricsConsumer{}, idNamespace: "receiver_creator/1"}
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1"}
 	exampleFactory := &componenttest.ExampleReceiverFactory{}
 	template, err := newReceiverTemplate("examplereceiver/1", nil)
 	require.NoError(t, err)
@@ -41,10 +46,98 @@ func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
 
 	// Test that metric receiver can be created from loaded config.
 	t.Run("test create receiver from loaded config", func(t *testing.T) {
-		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig)
+		nextConsumer := &mockMetricsConsumer{}
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, receiverConsumers{metrics: nextConsumer})
 		require.NoError(t, err)
 		assert.NotNil(t, recvr)
 		exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
-		assert.Equal(t, run.nextConsumer, exampleReceiver.MetricsConsumer)
+		assert.Equal(t, nextConsumer, exampleReceiver.MetricsConsumer)
 	})
 }
+
+func Test_createRuntimeReceiverDataTypes(t *testing.T) {
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1"}
+	exampleFactory := &componenttest.ExampleReceiverFactory{}
+	template, err := newReceiverTemplate("examplereceiver/1", nil)
+	require.NoError(t, err)
+
+	load := func(t *testing.T) *componenttest.ExampleReceiver {
+		cfg, err := run.loadRuntimeReceiverConfig(exampleFactory, template.receiverConfig, userConfigMap{
+			endpointConfigKey: "localhost:12345",
+		})
+		require.NoError(t, err)
+		return cfg.(*componenttest.ExampleReceiver)
+	}
+
+	t.Run("metrics and traces", func(t *testing.T) {
+		metricsConsumer := &mockMetricsConsumer{}
+		traceConsumer := &mockTraceConsumer{}
+		recvr, err := run.createRuntimeReceiver(exampleFactory, load(t), receiverConsumers{metrics: metricsConsumer, traces: traceConsumer})
+		require.NoError(t, err)
+		exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
+		assert.Equal(t, metricsConsumer, exampleReceiver.MetricsConsumer)
+		assert.Equal(t, traceConsumer, exampleReceiver.TraceConsumer)
+	})
+
+	t.Run("traces only receiver", func(t *testing.T) {
+		cfg := load(t)
+		cfg.FailMetricsCreation = true
+		traceConsumer := &mockTraceConsumer{}
+		recvr, err := run.createRuntimeReceiver(exampleFactory, cfg, receiverConsumers{metrics: &mockMetricsConsumer{}, traces: traceConsumer})
+		require.NoError(t, err)
+		exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
+		assert.Nil(t, exampleReceiver.MetricsConsumer)
+		assert.Equal(t, traceConsumer, exampleReceiver.TraceConsumer)
+	})
+
+	t.Run("unsupported data types", func(t *testing.T) {
+		cfg := load(t)
+		cfg.FailTraceCreation = true
+		_, err := run.createRuntimeReceiver(exampleFactory, cfg, receiverConsumers{traces: &mockTraceConsumer{}})
+		assert.EqualError(t, err, `receiver "examplereceiver" does not support any data type consumed by receiver_creator/1`)
+	})
+}
+
+// failingReceiverFactory creates receivers that fail to start.
+type failingReceiverFactory struct {
+	componenttest.ExampleReceiverFactory
+	rcvr *failingReceiver
+}
+
+func (f *failingReceiverFactory) CreateMetricsReceiver(
+	context.Context,
+	component.ReceiverCreateParams,
+	configmodels.Receiver,
+	consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	return f.rcvr, nil
+}
+
+type failingReceiver struct {
+	shutdown bool
+}
+
+func (r *failingReceiver) Start(context.Context, component.Host) error {
+	return errors.New("port in use")
+}
+
+func (r *failingReceiver) Shutdown(context.Context) error {
+	r.shutdown = true
+	return nil
+}
+
+func Test_startShutsDownFailedReceiver(t *testing.T) {
+	factory := &failingReceiverFactory{rcvr: &failingReceiver{}}
+	host := &mockHostFactories{factories: component.Factories{
+		Receivers: map[configmodels.Type]component.ReceiverFactoryBase{factory.Type(): factory},
+	}}
+	run := &receiverRunner{logger: zap.NewNop(), idNamespace: "receiver_creator/1", ctx: context.Background(), host: host}
+	template, err := newReceiverTemplate("examplereceiver/1", nil)
+	require.NoError(t, err)
+
+	rcvr, err := run.start(template.receiverConfig, userConfigMap{endpointConfigKey: "localhost:12345"},
+		receiverConsumers{metrics: &mockMetricsConsumer{}})
+	assert.True(t, errors.Is(err, errReceiverStart))
+	assert.Nil(t, rcvr)
+	assert.True(t, factory.rcvr.shutdown)
+}
@@ -22,9 +22,16 @@ import (
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
 	retrieveInfo() (string, error)
+	// retrieves up to count of the most recent slow log entries, as returned
+	// by SLOWLOG GET
+	retrieveSlowlog(count int) (interface{}, error)
+	// retrieves the latest latency spikes, as returned by LATENCY LATEST
+	retrieveLatency() (interface{}, error)
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
+	// releases the connections held by the client
+	close() error
 }
 
 // Wraps a real Redis client, implements `client` interface.
@@ -46,7 +53,20 @@ func (c *redisClient) delimiter() string {
 	return "\r\n"
 }
 
-// Retrieve Redis INFO. We retrieve all of the 'sections'.
+// Retrieve Redis INFO. We retrieve all of the 'sections', which unlike the
+// default set includes commandstats.
 func (c *redisClient) retrieveInfo() (string, error) {
-	return c.client.Info().Result()
+	return c.client.Info("all").Result()
+}
+
+func (c *redisClient) retrieveSlowlog(count int) (interface{}, error) {
+	return c.client.Do("slowlog", "get", count).Result()
+}
+
+func (c *redisClient) retrieveLatency() (interface{}, error) {
+	return c.client.Do("latency", "latest").Result()
+}
+
+func (c *redisClient) close() error {
+	return c.client.Close()
 }
@@ -39,6 +39,18 @@ func (fakeClient) retrieveInfo() (string, error) {
 	return readFile("info")
 }
 
+func (fakeClient) retrieveSlowlog(int) (interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func (fakeClient) retrieveLatency() (interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func (fakeClient) close() error {
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
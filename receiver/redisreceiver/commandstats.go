@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Holds the fields of a line of the Commandstats section of the INFO command:
+// e.g. "cmdstat_get:calls=21,usec=175,usec_per_call=8.33"
+type commandStats struct {
+	cmd         string
+	calls       int
+	usec        int
+	usecPerCall float64
+}
+
+// Turns a commandstats value (the part after the colon
+// e.g. "calls=21,usec=175,usec_per_call=8.33") into a commandStats struct.
+// Fields added by later Redis versions, like rejected_calls, are ignored.
+func parseCommandStatsString(cmd string, str string) (*commandStats, error) {
+	cs := commandStats{cmd: cmd}
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.Split(pairStr, "=")
+		if len(pair) != 2 {
+			return nil, fmt.Errorf(
+				"unexpected commandstats pair '%s'",
+				pairStr,
+			)
+		}
+		var err error
+		switch pair[0] {
+		case "calls":
+			cs.calls, err = strconv.Atoi(pair[1])
+		case "usec":
+			cs.usec, err = strconv.Atoi(pair[1])
+		case "usec_per_call":
+			cs.usecPerCall, err = strconv.ParseFloat(pair[1], 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &cs, nil
+}
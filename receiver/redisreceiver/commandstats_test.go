@@ -0,0 +1,71 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	cs, err := parseCommandStatsString("get", "calls=21,usec=175,usec_per_call=8.33,rejected_calls=0,failed_calls=0")
+	require.Nil(t, err)
+	require.Equal(t, "get", cs.cmd)
+	require.Equal(t, 21, cs.calls)
+	require.Equal(t, 175, cs.usec)
+	require.Equal(t, 8.33, cs.usecPerCall)
+}
+
+func TestParseMalformedCommandStats(t *testing.T) {
+	tests := []struct{ name, stats string }{
+		{"bad calls", "calls=x,usec=175,usec_per_call=8.33"},
+		{"bad usec_per_call", "calls=21,usec=175,usec_per_call=x"},
+		{"missing equals", "calls=21,usec"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseCommandStatsString("get", test.stats)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestCommandStatsMetrics(t *testing.T) {
+	i := info{
+		"cmdstat_set":  "calls=3,usec=30,usec_per_call=10.00",
+		"cmdstat_get":  "calls=21,usec=175,usec_per_call=8.33",
+		"cmdstat_info": "calls=x",
+	}
+	m, warnings := i.buildCommandStatsProtoMetrics(getDefaultTimeBundle())
+	require.Equal(t, 1, len(warnings))
+	require.Equal(t, 6, len(m))
+
+	require.Equal(t, "redis/commands/calls", m[0].MetricDescriptor.Name)
+	require.Equal(t, "cmd", m[0].MetricDescriptor.LabelKeys[0].Key)
+	require.Equal(t, "get", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, m[0].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 21}, m[0].Timeseries[0].Points[0].Value)
+
+	require.Equal(t, "redis/commands/time", m[1].MetricDescriptor.Name)
+	require.Equal(t, "us", m[1].MetricDescriptor.Unit)
+
+	require.Equal(t, "redis/commands/time_per_call", m[2].MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, m[2].MetricDescriptor.Type)
+	require.Equal(t, &metricspb.Point_DoubleValue{DoubleValue: 8.33}, m[2].Timeseries[0].Points[0].Value)
+
+	require.Equal(t, "set", m[3].Timeseries[0].LabelValues[0].Value)
+}
@@ -15,16 +15,31 @@
 package redisreceiver
 
 import (
+	"fmt"
+	"path"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 type config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	// TODO: Use one of the configs from core.
-	// The target endpoint.
+	// The target endpoint. In cluster mode this is any node of the cluster,
+	// in sentinel mode one of the sentinels.
 	Endpoint string `mapstructure:"endpoint"`
+	// How the nodes to scrape are found: "standalone" (the default) scrapes
+	// the endpoint only, "cluster" every node listed by CLUSTER NODES and
+	// "sentinel" the master and replicas known to the sentinel.
+	Mode string `mapstructure:"mode"`
+	// The name of the master monitored by the sentinel. Required in sentinel
+	// mode.
+	SentinelMasterName string `mapstructure:"sentinel_master_name"`
+	// Optional credentials for the sentinel, which usually differ from the
+	// ones of the Redis servers.
+	SentinelUsername string `mapstructure:"sentinel_username"`
+	SentinelPassword string `mapstructure:"sentinel_password"`
 	// The duration between Redis metric fetches.
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
 	// The logical name of the Redis server. This value will be added as a
@@ -33,7 +48,112 @@ type config struct {
 
 	// TODO allow users to add additional resource key value pairs?
 
+	// Optional username. When set, the connection is authenticated against
+	// the ACL of a Redis 6.0 or later server.
+	Username string `mapstructure:"username"`
+
 	// Optional password. Must match the password specified in the
-	// requirepass server configuration option.
+	// requirepass server configuration option, or the password of the ACL
+	// user.
 	Password string `mapstructure:"password"`
+
+	// Optional TLS settings. Connections are made in plain text when absent.
+	TLS *configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Selects the INFO fields turned into metrics.
+	Metrics metricsConfig `mapstructure:"metrics"`
+	// Collection of the slow log.
+	Slowlog slowlogConfig `mapstructure:"slowlog"`
+	// Collection of the latency monitor.
+	Latency latencyConfig `mapstructure:"latency"`
+}
+
+type metricsConfig struct {
+	// INFO keys to build metrics from, as path.Match patterns: e.g.
+	// "used_memory*" or "cmdstat_*". All keys are included when empty.
+	Include []string `mapstructure:"include"`
+	// INFO keys not to build metrics from, as path.Match patterns. Applied
+	// after Include.
+	Exclude []string `mapstructure:"exclude"`
+	// Additional INFO fields to turn into metrics. These are collected
+	// regardless of Include and Exclude, and replace the built-in metric of
+	// the same key if there's one.
+	Custom []customMetricConfig `mapstructure:"custom"`
+}
+
+// Maps an INFO field to a metric.
+type customMetricConfig struct {
+	// The INFO key: e.g. "lazyfree_pending_objects".
+	Key string `mapstructure:"key"`
+	// The metric name. Defaults to "redis/" followed by the key.
+	Name string `mapstructure:"name"`
+	// Either "gauge" (the default) or "cumulative".
+	Type string `mapstructure:"type"`
+	// Either "int" (the default) or "double".
+	ValueType   string `mapstructure:"value_type"`
+	Units       string `mapstructure:"units"`
+	Description string `mapstructure:"description"`
+}
+
+type slowlogConfig struct {
+	// Whether SLOWLOG GET is called on every collection. New entries are
+	// emitted as metrics and, in a logs pipeline, as log records.
+	Enabled bool `mapstructure:"enabled"`
+	// The number of most recent entries retrieved on every collection.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+type latencyConfig struct {
+	// Whether LATENCY LATEST is called on every collection. Requires the
+	// latency monitor to be enabled with latency-monitor-threshold.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Custom metric types and value types.
+const (
+	gaugeType       = "gauge"
+	cumulativeType  = "cumulative"
+	intValueType    = "int"
+	doubleValueType = "double"
+)
+
+func (cfg *config) validate() error {
+	switch cfg.Mode {
+	case "", standaloneMode, clusterMode:
+	case sentinelMode:
+		if cfg.SentinelMasterName == "" {
+			return fmt.Errorf("%q must be set in %s mode", "sentinel_master_name", sentinelMode)
+		}
+	default:
+		return fmt.Errorf("unsupported mode %q, must be one of %q, %q or %q",
+			cfg.Mode, standaloneMode, clusterMode, sentinelMode)
+	}
+
+	for _, pattern := range append(cfg.Metrics.Include, cfg.Metrics.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid metrics key pattern %q: %w", pattern, err)
+		}
+	}
+	for _, custom := range cfg.Metrics.Custom {
+		if custom.Key == "" {
+			return fmt.Errorf("%q must be set for custom metrics", "key")
+		}
+		switch custom.Type {
+		case "", gaugeType, cumulativeType:
+		default:
+			return fmt.Errorf("unsupported type %q of custom metric %q, must be %q or %q",
+				custom.Type, custom.Key, gaugeType, cumulativeType)
+		}
+		switch custom.ValueType {
+		case "", intValueType, doubleValueType:
+		default:
+			return fmt.Errorf("unsupported value_type %q of custom metric %q, must be %q or %q",
+				custom.ValueType, custom.Key, intValueType, doubleValueType)
+		}
+	}
+
+	if cfg.Slowlog.Enabled && cfg.Slowlog.MaxEntries <= 0 {
+		return fmt.Errorf("%q must be positive", "slowlog.max_entries")
+	}
+	return nil
 }
@@ -22,6 +22,8 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sharedcomponent"
 )
 
 const (
@@ -33,11 +35,15 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
-	return &config{CollectionInterval: 10 * time.Second}
+	return &config{
+		CollectionInterval: 10 * time.Second,
+		Slowlog:            slowlogConfig{MaxEntries: 128},
+	}
 }
 
 func createMetricsReceiver(
@@ -46,7 +52,48 @@ func createMetricsReceiver(
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.registerMetricsConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// createLogsReceiver creates a logs receiver, for the slow log entries, based
+// on provided config.
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.registerLogsConsumer(consumer); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// getReceiver returns the receiver for the given config, creating it on
+// first use so metrics and logs pipelines share the same connections.
+func getReceiver(params component.ReceiverCreateParams, cfg configmodels.Receiver) (*redisReceiver, error) {
 	oCfg := cfg.(*config)
+	if err := oCfg.validate(); err != nil {
+		return nil, err
+	}
 
-	return newRedisReceiver(params.Logger, oCfg, consumer), nil
+	r, _ := receivers.GetOrAdd(oCfg, func() (interface{}, error) {
+		return newRedisReceiver(params.Logger, oCfg), nil
+	})
+	return r.(*redisReceiver), nil
 }
+
+// receivers holds the redisReceiver shared by the metrics and logs pipelines
+// of each configuration.
+var receivers = sharedcomponent.NewComponents()
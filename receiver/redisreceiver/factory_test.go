@@ -0,0 +1,91 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	tests := []struct {
+		name    string
+		mode    string
+		master  string
+		metrics metricsConfig
+		slowlog slowlogConfig
+		wantErr bool
+	}{
+		{name: "default"},
+		{name: "standalone", mode: standaloneMode},
+		{name: "cluster", mode: clusterMode},
+		{name: "sentinel", mode: sentinelMode, master: "mymaster"},
+		{name: "sentinel_without_master", mode: sentinelMode, wantErr: true},
+		{name: "unknown", mode: "replicated", wantErr: true},
+		{name: "bad_pattern", metrics: metricsConfig{Include: []string{"cmdstat_["}}, wantErr: true},
+		{name: "custom_without_key", metrics: metricsConfig{Custom: []customMetricConfig{{Name: "foo"}}}, wantErr: true},
+		{name: "custom_bad_type", metrics: metricsConfig{Custom: []customMetricConfig{{Key: "foo", Type: "histogram"}}}, wantErr: true},
+		{name: "custom_bad_value_type", metrics: metricsConfig{Custom: []customMetricConfig{{Key: "foo", ValueType: "string"}}}, wantErr: true},
+		{name: "custom", metrics: metricsConfig{Custom: []customMetricConfig{{Key: "foo", Type: cumulativeType, ValueType: doubleValueType}}}},
+		{name: "slowlog_without_entries", slowlog: slowlogConfig{Enabled: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*config)
+			cfg.Mode = tt.mode
+			cfg.SentinelMasterName = tt.master
+			cfg.Metrics = tt.metrics
+			if tt.slowlog.Enabled {
+				cfg.Slowlog = tt.slowlog
+			}
+			r, err := createMetricsReceiver(context.Background(), params, cfg, &exportertest.SinkMetricsExporter{})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, r)
+		})
+	}
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	cfg := createDefaultConfig().(*config)
+	cfg.Slowlog.Enabled = true
+
+	mr, err := createMetricsReceiver(context.Background(), params, cfg, &exportertest.SinkMetricsExporter{})
+	require.NoError(t, err)
+	lr, err := createLogsReceiver(context.Background(), params, cfg, &exportertest.SinkLogsExporter{})
+	require.NoError(t, err)
+	// Both pipelines share the same receiver.
+	assert.Same(t, mr, lr)
+
+	_, err = createLogsReceiver(context.Background(), params, cfg, nil)
+	assert.Error(t, err)
+
+	// A receiver that was shut down is not handed out again.
+	require.NoError(t, mr.Shutdown(context.Background()))
+	lr, err = createLogsReceiver(context.Background(), params, cfg, &exportertest.SinkLogsExporter{})
+	require.NoError(t, err)
+	assert.NotSame(t, mr, lr)
+}
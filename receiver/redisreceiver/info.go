@@ -17,7 +17,9 @@ package redisreceiver
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 )
@@ -77,6 +79,109 @@ func (i info) buildKeyspaceProtoMetrics(t *timeBundle) (
 	return protoMetrics, warnings
 }
 
+// Builds proto metrics from the Replication section of Redis INFO. A master
+// reports one line per connected replica: e.g.
+// "slave0:ip=10.0.0.2,port=6379,state=online,offset=1234,lag=0". A replica
+// reports the state of the link to its master instead. Returns proto metrics
+// and parsing errors, to be treated as warnings, if there were any.
+func (i info) buildReplicationProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for n := 0; ; n++ {
+		str, ok := i["slave"+strconv.Itoa(n)]
+		if !ok {
+			break
+		}
+		r, parsingError := parseReplicaString(str)
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildReplicaMetrics(r, t)...)
+	}
+
+	if i.getRole() != replicaRole {
+		return protoMetrics, warnings
+	}
+	if status, ok := i["master_link_status"]; ok {
+		protoMetrics = append(protoMetrics, buildMasterLinkUpMetric(status == "up", t))
+	}
+	// Only build the metrics of the keys present, which may have been
+	// filtered out.
+	var replicaMetrics []*redisMetric
+	for _, m := range getReplicaRedisMetrics() {
+		if _, ok := i[m.key]; ok {
+			replicaMetrics = append(replicaMetrics, m)
+		}
+	}
+	replicaProtoMetrics, replicaWarnings := i.buildFixedProtoMetrics(replicaMetrics, t)
+	protoMetrics = append(protoMetrics, replicaProtoMetrics...)
+	warnings = append(warnings, replicaWarnings...)
+	return protoMetrics, warnings
+}
+
+// Builds proto metrics from the Commandstats section of Redis INFO, one line
+// per command that has been called: e.g.
+// "cmdstat_get:calls=21,usec=175,usec_per_call=8.33". Metrics are sorted by
+// command. Returns proto metrics and parsing errors, to be treated as
+// warnings, if there were any.
+func (i info) buildCommandStatsProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	const cmdstatPrefix = "cmdstat_"
+	var keys []string
+	for key := range i {
+		if strings.HasPrefix(key, cmdstatPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		cs, parsingError := parseCommandStatsString(strings.TrimPrefix(key, cmdstatPrefix), i[key])
+		if parsingError != nil {
+			warnings = append(warnings, parsingError)
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildCommandStatsMetrics(cs, t)...)
+	}
+	return protoMetrics, warnings
+}
+
+// Returns the keys matching the filter, which the keyspace, replication and
+// commandstats metrics are then built from. The role is always kept, it isn't
+// a metric but decides which replication metrics are built.
+func (i info) filter(f *keyFilter) info {
+	if f.isEmpty() {
+		return i
+	}
+	filtered := make(info, len(i))
+	for key, val := range i {
+		if key == "role" || f.matches(key) {
+			filtered[key] = val
+		}
+	}
+	return filtered
+}
+
+// The replication roles, as reported on the Resource of a node's metrics.
+const (
+	masterRole  = "master"
+	replicaRole = "replica"
+)
+
+// Returns the replication role of the server, or an empty string if it isn't
+// reported. Redis calls replicas "slave", which is translated.
+func (i info) getRole() string {
+	switch role := i["role"]; role {
+	case "slave":
+		return replicaRole
+	default:
+		return role
+	}
+}
+
 func (i info) getUptimeInSeconds() (int, error) {
 	const uptimeKey = "uptime_in_seconds"
 	uptimeStr, ok := i[uptimeKey]
@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+)
+
+// An event of the latency monitor, as returned by LATENCY LATEST: e.g.
+// ["command", 1405067976, 251, 1001], the latest and maximum latencies being
+// in milliseconds.
+type latencyEvent struct {
+	name     string
+	latestMs int64
+	maxMs    int64
+}
+
+// Turns the reply to LATENCY LATEST into latencyEvent structs, sorted by
+// name.
+func parseLatencyLatest(reply interface{}) ([]*latencyEvent, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected latency reply %T", reply)
+	}
+	events := make([]*latencyEvent, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected latency event %v", item)
+		}
+		name, ok1 := fields[0].(string)
+		latest, ok2 := fields[2].(int64)
+		max, ok3 := fields[3].(int64)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, fmt.Errorf("unexpected latency event %v", item)
+		}
+		events = append(events, &latencyEvent{name: name, latestMs: latest, maxMs: max})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].name < events[j].name })
+	return events, nil
+}
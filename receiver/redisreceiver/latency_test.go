@@ -0,0 +1,51 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyLatest(t *testing.T) {
+	events, err := parseLatencyLatest([]interface{}{
+		[]interface{}{"fork", int64(1405067980), int64(12), int64(12)},
+		[]interface{}{"command", int64(1405067976), int64(251), int64(1001)},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []*latencyEvent{
+		{name: "command", latestMs: 251, maxMs: 1001},
+		{name: "fork", latestMs: 12, maxMs: 12},
+	}, events)
+
+	m := buildLatencyMetrics(events[0], getDefaultTimeBundle())
+	require.Equal(t, "redis/latency/latest", m[0].MetricDescriptor.Name)
+	require.Equal(t, "ms", m[0].MetricDescriptor.Unit)
+	require.Equal(t, "command", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 251}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "redis/latency/max", m[1].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 1001}, m[1].Timeseries[0].Points[0].Value)
+}
+
+func TestParseMalformedLatencyLatest(t *testing.T) {
+	_, err := parseLatencyLatest("foo")
+	require.NotNil(t, err)
+	_, err = parseLatencyLatest([]interface{}{[]interface{}{"command", int64(1)}})
+	require.NotNil(t, err)
+	_, err = parseLatencyLatest([]interface{}{[]interface{}{"command", int64(1), "2", int64(3)}})
+	require.NotNil(t, err)
+}
@@ -70,6 +70,14 @@ func getDefaultRedisMetrics() []*redisMetric {
 	}
 }
 
+// Called once at startup. Returns the metrics only reported by replicas we
+// want to extract from Redis INFO.
+func getReplicaRedisMetrics() []*redisMetric {
+	return []*redisMetric{
+		masterLastIOSecondsAgo(),
+	}
+}
+
 func uptimeInSeconds() *redisMetric {
 	return &redisMetric{
 		key:    "uptime_in_seconds",
@@ -336,3 +344,13 @@ func masterReplOffset() *redisMetric {
 		desc:   "The server's current replication offset",
 	}
 }
+
+func masterLastIOSecondsAgo() *redisMetric {
+	return &redisMetric{
+		key:    "master_last_io_seconds_ago",
+		name:   "redis/replication/master/last_io",
+		units:  "s",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		desc:   "Seconds since the last interaction with the master, -1 if the link is down",
+	}
+}
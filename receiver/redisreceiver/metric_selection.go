@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"path"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// Selects INFO keys by the include and exclude patterns of the configuration.
+type keyFilter struct {
+	include []string
+	exclude []string
+}
+
+func newKeyFilter(cfg metricsConfig) *keyFilter {
+	return &keyFilter{
+		include: cfg.Include,
+		exclude: cfg.Exclude,
+	}
+}
+
+// Tells whether metrics are built from the INFO key. Patterns are validated
+// with the configuration, so match errors can't happen here.
+func (f *keyFilter) matches(key string) bool {
+	included := len(f.include) == 0
+	for _, pattern := range f.include {
+		if ok, _ := path.Match(pattern, key); ok {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, pattern := range f.exclude {
+		if ok, _ := path.Match(pattern, key); ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *keyFilter) isEmpty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// Called once at startup. Returns the built-in metrics passing the filter,
+// followed by the custom metrics of the configuration. A custom metric
+// replaces the built-in one with the same key.
+func selectRedisMetrics(cfg metricsConfig) []*redisMetric {
+	custom := make(map[string]bool, len(cfg.Custom))
+	for _, c := range cfg.Custom {
+		custom[c.Key] = true
+	}
+
+	filter := newKeyFilter(cfg)
+	var metrics []*redisMetric
+	for _, m := range getDefaultRedisMetrics() {
+		if filter.matches(m.key) && !custom[m.key] {
+			metrics = append(metrics, m)
+		}
+	}
+	for _, c := range cfg.Custom {
+		metrics = append(metrics, c.toRedisMetric())
+	}
+	return metrics
+}
+
+func (c customMetricConfig) toRedisMetric() *redisMetric {
+	m := &redisMetric{
+		key:   c.Key,
+		name:  c.Name,
+		units: c.Units,
+		desc:  c.Description,
+	}
+	if m.name == "" {
+		m.name = "redis/" + c.Key
+	}
+	switch {
+	case c.Type == cumulativeType && c.ValueType == doubleValueType:
+		m.mdType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+	case c.Type == cumulativeType:
+		m.mdType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+	case c.ValueType == doubleValueType:
+		m.mdType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+	default:
+		m.mdType = metricspb.MetricDescriptor_GAUGE_INT64
+	}
+	return m
+}
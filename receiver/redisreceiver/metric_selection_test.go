@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		key      string
+		expected bool
+	}{
+		{name: "empty", key: "used_memory", expected: true},
+		{name: "included", include: []string{"used_memory*"}, key: "used_memory_rss", expected: true},
+		{name: "not included", include: []string{"used_memory*"}, key: "evicted_keys", expected: false},
+		{name: "excluded", exclude: []string{"cmdstat_*"}, key: "cmdstat_get", expected: false},
+		{name: "not excluded", exclude: []string{"cmdstat_*"}, key: "db0", expected: true},
+		{name: "included then excluded", include: []string{"used_*"}, exclude: []string{"used_cpu_*"}, key: "used_cpu_sys", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newKeyFilter(metricsConfig{Include: test.include, Exclude: test.exclude})
+			require.Equal(t, test.expected, f.matches(test.key))
+		})
+	}
+}
+
+func TestSelectRedisMetrics(t *testing.T) {
+	require.Equal(t, len(getDefaultRedisMetrics()), len(selectRedisMetrics(metricsConfig{})))
+
+	metrics := selectRedisMetrics(metricsConfig{
+		Include: []string{"used_memory*", "uptime_in_seconds"},
+		Exclude: []string{"used_memory_lua"},
+		Custom: []customMetricConfig{
+			{Key: "used_memory", Name: "redis/memory/used_bytes", Units: "By"},
+			{Key: "lazyfree_pending_objects"},
+			{Key: "allocator_frag_ratio", ValueType: doubleValueType},
+			{Key: "total_reads_processed", Type: cumulativeType},
+		},
+	})
+	var keys []string
+	for _, m := range metrics {
+		keys = append(keys, m.key)
+	}
+	require.Equal(t, []string{
+		"uptime_in_seconds",
+		"used_memory_rss",
+		"used_memory_peak",
+		"used_memory",
+		"lazyfree_pending_objects",
+		"allocator_frag_ratio",
+		"total_reads_processed",
+	}, keys)
+
+	require.Equal(t, "redis/memory/used_bytes", metrics[3].name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, metrics[3].mdType)
+	require.Equal(t, "redis/lazyfree_pending_objects", metrics[4].name)
+	require.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, metrics[5].mdType)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[6].mdType)
+}
+
+func TestCustomMetricFromInfo(t *testing.T) {
+	redisMetrics := selectRedisMetrics(metricsConfig{
+		Include: []string{"none"},
+		Custom:  []customMetricConfig{{Key: "allocator_frag_ratio", ValueType: doubleValueType}},
+	})
+	protoMetrics, warnings, err := fetchMetrics(redisMetrics)
+	require.Nil(t, err)
+	require.Nil(t, warnings)
+	require.Equal(t, 1, len(protoMetrics))
+	requireDoublePtEqual(t, 1.24, protoMetrics[0])
+}
+
+func TestInfoFilter(t *testing.T) {
+	i := info{
+		"role":        "master",
+		"db0":         "keys=1,expires=2,avg_ttl=3",
+		"cmdstat_get": "calls=21,usec=175,usec_per_call=8.33",
+	}
+	require.Equal(t, i, i.filter(newKeyFilter(metricsConfig{})))
+	require.Equal(t, info{
+		"role": "master",
+		"db0":  "keys=1,expires=2,avg_ttl=3",
+	}, i.filter(newKeyFilter(metricsConfig{Exclude: []string{"cmdstat_*"}})))
+}
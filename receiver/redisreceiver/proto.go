@@ -73,6 +73,108 @@ func buildKeyspaceTTLMetric(k *keyspace, t *timeBundle) *metricspb.Metric {
 	return newProtoMetric(m, pt, t)
 }
 
+func buildReplicaMetrics(r *replica, t *timeBundle) []*metricspb.Metric {
+	offset := &redisMetric{
+		name:   "redis/replication/replica/offset",
+		desc:   "The replication offset acknowledged by the replica",
+		labels: map[string]string{"replica": r.addr, "state": r.state},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	lag := &redisMetric{
+		name:   "redis/replication/replica/lag",
+		desc:   "Seconds since the last acknowledgement received from the replica",
+		units:  "s",
+		labels: map[string]string{"replica": r.addr, "state": r.state},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	return []*metricspb.Metric{
+		newProtoMetric(offset, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(r.offset)}}, t),
+		newProtoMetric(lag, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(r.lag)}}, t),
+	}
+}
+
+func buildMasterLinkUpMetric(up bool, t *timeBundle) *metricspb.Metric {
+	m := &redisMetric{
+		name:   "redis/replication/master/link_up",
+		desc:   "Whether the link of the replica to its master is up (1) or down (0)",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	var val int64
+	if up {
+		val = 1
+	}
+	return newProtoMetric(m, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: val}}, t)
+}
+
+func buildCommandStatsMetrics(cs *commandStats, t *timeBundle) []*metricspb.Metric {
+	calls := &redisMetric{
+		name:   "redis/commands/calls",
+		desc:   "Number of calls of the command since server start",
+		labels: map[string]string{"cmd": cs.cmd},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	usec := &redisMetric{
+		name:   "redis/commands/time",
+		desc:   "CPU time consumed by the command since server start",
+		units:  "us",
+		labels: map[string]string{"cmd": cs.cmd},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	usecPerCall := &redisMetric{
+		name:   "redis/commands/time_per_call",
+		desc:   "Average CPU time consumed per call of the command",
+		units:  "us",
+		labels: map[string]string{"cmd": cs.cmd},
+		mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+	return []*metricspb.Metric{
+		newProtoMetric(calls, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(cs.calls)}}, t),
+		newProtoMetric(usec, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: int64(cs.usec)}}, t),
+		newProtoMetric(usecPerCall, &metricspb.Point{Value: &metricspb.Point_DoubleValue{DoubleValue: cs.usecPerCall}}, t),
+	}
+}
+
+func buildSlowlogMetrics(cmd string, calls int64, maxDurationUsec int64, t *timeBundle) []*metricspb.Metric {
+	callsMetric := &redisMetric{
+		name:   "redis/slowlog/calls",
+		desc:   "Number of calls of the command added to the slow log since the previous collection",
+		labels: map[string]string{"cmd": cmd},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	durationMetric := &redisMetric{
+		name:   "redis/slowlog/max_duration",
+		desc:   "Longest call of the command added to the slow log since the previous collection",
+		units:  "us",
+		labels: map[string]string{"cmd": cmd},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	return []*metricspb.Metric{
+		newProtoMetric(callsMetric, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: calls}}, t),
+		newProtoMetric(durationMetric, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: maxDurationUsec}}, t),
+	}
+}
+
+func buildLatencyMetrics(e *latencyEvent, t *timeBundle) []*metricspb.Metric {
+	latest := &redisMetric{
+		name:   "redis/latency/latest",
+		desc:   "Latency of the latest spike of the event",
+		units:  "ms",
+		labels: map[string]string{"event": e.name},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	max := &redisMetric{
+		name:   "redis/latency/max",
+		desc:   "Latency of the largest spike of the event since server start",
+		units:  "ms",
+		labels: map[string]string{"event": e.name},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	return []*metricspb.Metric{
+		newProtoMetric(latest, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: e.latestMs}}, t),
+		newProtoMetric(max, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: e.maxMs}}, t),
+	}
+}
+
 // Create new protobuf Metric.
 // Arguments:
 //   * redisMetric -- the fixed metadata to build the protobuf metric
@@ -16,53 +16,143 @@ package redisreceiver
 
 import (
 	"context"
+	"crypto/tls"
+	"sync"
 
 	"github.com/go-redis/redis/v7"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
+// A receiver for a configuration, shared by the metrics and logs pipelines
+// using it so that Redis is only scraped once.
 type redisReceiver struct {
-	logger         *zap.Logger
-	config         *config
-	consumer       consumer.MetricsConsumer
-	intervalRunner *interval.Runner
+	sync.Mutex
+	logger          *zap.Logger
+	config          *config
+	metricsConsumer consumer.MetricsConsumer
+	logsConsumer    consumer.LogsConsumer
+	topology        topology
+	intervalRunner  *interval.Runner
+	startOnce       sync.Once
+	stopOnce        sync.Once
 }
 
+var _ component.MetricsReceiver = (*redisReceiver)(nil)
+var _ component.LogsReceiver = (*redisReceiver)(nil)
+
+// Creates a receiver without any consumers, these are registered by the
+// factory for each pipeline using the receiver.
 func newRedisReceiver(
 	logger *zap.Logger,
 	config *config,
-	consumer consumer.MetricsConsumer,
 ) *redisReceiver {
 	return &redisReceiver{
-		logger:   logger,
-		config:   config,
-		consumer: consumer,
+		logger: logger,
+		config: config,
 	}
 }
 
-// Set up and kick off the interval runner.
+func (r *redisReceiver) registerMetricsConsumer(mc consumer.MetricsConsumer) error {
+	if mc == nil {
+		return componenterror.ErrNilNextConsumer
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.metricsConsumer = mc
+	return nil
+}
+
+func (r *redisReceiver) registerLogsConsumer(lc consumer.LogsConsumer) error {
+	if lc == nil {
+		return componenterror.ErrNilNextConsumer
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.logsConsumer = lc
+	return nil
+}
+
+// Set up and kick off the interval runner. Only the first call of the
+// pipelines sharing the receiver does so.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
-	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, c, r.config.ServiceName, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
+	r.Lock()
+	defer r.Unlock()
 
-	go func() {
-		if err := r.intervalRunner.Start(); err != nil {
-			host.ReportFatalError(err)
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		r.topology, err = r.newTopology()
+		if err != nil {
+			return
 		}
-	}()
+		redisRunnable := newRedisRunnable(ctx, r.topology, r.config, r.metricsConsumer, r.logsConsumer, r.logger)
+		r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
 
-	return nil
+		go func() {
+			if err := r.intervalRunner.Start(); err != nil {
+				host.ReportFatalError(err)
+			}
+		}()
+	})
+	return err
 }
 
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
-	r.intervalRunner.Stop()
-	return nil
+	receivers.Remove(r)
+
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.intervalRunner == nil {
+			return
+		}
+		r.intervalRunner.Stop()
+		err = r.topology.close()
+	})
+	return err
+}
+
+// Creates the topology matching the configured mode. All Redis servers are
+// connected to with the same credentials and TLS settings.
+func (r *redisReceiver) newTopology() (topology, error) {
+	var tlsConfig *tls.Config
+	if r.config.TLS != nil {
+		var err error
+		if tlsConfig, err = r.config.TLS.LoadTLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+	options := func(addr string) *redis.Options {
+		return &redis.Options{
+			Addr:      addr,
+			Username:  r.config.Username,
+			Password:  r.config.Password,
+			TLSConfig: tlsConfig,
+		}
+	}
+	newClient := func(addr string) client {
+		return newRedisClient(options(addr))
+	}
+
+	switch r.config.Mode {
+	case clusterMode:
+		return newClusterTopology(redis.NewClient(options(r.config.Endpoint)), newClient), nil
+	case sentinelMode:
+		sentinel := redis.NewSentinelClient(&redis.Options{
+			Addr:      r.config.Endpoint,
+			Username:  r.config.SentinelUsername,
+			Password:  r.config.SentinelPassword,
+			TLSConfig: tlsConfig,
+		})
+		return newSentinelTopology(sentinel, r.config.SentinelMasterName, newClient), nil
+	default:
+		return newStandaloneTopology(r.config.Endpoint, newClient(r.config.Endpoint)), nil
+	}
 }
@@ -18,8 +18,11 @@ import (
 	"context"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
@@ -29,78 +32,157 @@ import (
 
 var _ interval.Runnable = (*redisRunnable)(nil)
 
-// Runs intermittently, fetching info from Redis, creating metrics/datapoints,
-// and feeding them to a metricsConsumer.
+// The Resource labels telling apart the metrics of different nodes.
+const (
+	nodeAddressLabel = "redis.node.address"
+	nodeRoleLabel    = "redis.node.role"
+)
+
+// Runs intermittently, fetching info from every Redis node of the topology,
+// creating metrics/datapoints, and feeding them to a metricsConsumer. Slow
+// log entries are fed to a logsConsumer, if there's one.
 type redisRunnable struct {
 	ctx             context.Context
 	metricsConsumer consumer.MetricsConsumer
-	redisSvc        *redisSvc
+	logsConsumer    consumer.LogsConsumer
+	topology        topology
+	config          *config
 	redisMetrics    []*redisMetric
+	keyFilter       *keyFilter
 	logger          *zap.Logger
-	timeBundle      *timeBundle
-	serviceName     string
+	timeBundles     map[string]*timeBundle
+	// The id of the most recent slow log entry seen per node.
+	slowlogIDs map[string]int64
+}
+
+// The data scraped from a single node.
+type nodeData struct {
+	metrics *consumerdata.MetricsData
+	slowlog []*slowlogEntry
 }
 
 func newRedisRunnable(
 	ctx context.Context,
-	client client,
-	serviceName string,
+	topology topology,
+	config *config,
 	metricsConsumer consumer.MetricsConsumer,
+	logsConsumer consumer.LogsConsumer,
 	logger *zap.Logger,
 ) *redisRunnable {
 	return &redisRunnable{
 		ctx:             ctx,
-		serviceName:     serviceName,
-		redisSvc:        newRedisSvc(client),
+		topology:        topology,
+		config:          config,
 		metricsConsumer: metricsConsumer,
+		logsConsumer:    logsConsumer,
 		logger:          logger,
+		timeBundles:     map[string]*timeBundle{},
+		slowlogIDs:      map[string]int64{},
 	}
 }
 
 // Builds a data structure of all of the keys, types, converters and such to
 // later extract data from Redis.
 func (r *redisRunnable) Setup() error {
-	r.redisMetrics = getDefaultRedisMetrics()
+	r.redisMetrics = selectRedisMetrics(r.config.Metrics)
+	r.keyFilter = newKeyFilter(r.config.Metrics)
 	return nil
 }
 
-// Run is called periodically, querying Redis and building Metrics to send to
-// the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
-// defined at startup time. Then builds 'keyspace' metrics if there are any
-// keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
+// Run is called periodically, querying every Redis node and building Metrics
+// to send to the next consumer. Each node's metrics get their own Resource,
+// labeled with the node's address and replication role. A node that can't be
+// scraped is skipped.
 func (r *redisRunnable) Run() error {
 	const dataFormat = "redis"
 	const transport = "http" // todo verify this
 	ctx := obsreport.StartMetricsReceiveOp(r.ctx, dataFormat, transport)
 
-	inf, err := r.redisSvc.info()
+	nodes, err := r.topology.nodes()
 	if err != nil {
 		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
 		return nil
 	}
 
+	timeBundles := make(map[string]*timeBundle, len(nodes))
+	slowlogIDs := make(map[string]int64, len(nodes))
+	var mds []consumerdata.MetricsData
+	var nodesData []*nodeData
+	var errs []error
+	for _, node := range nodes {
+		data, err := r.scrapeNode(node, timeBundles, slowlogIDs)
+		if err != nil {
+			r.logger.Warn("failed to scrape redis node", zap.String("node", node.addr), zap.Error(err))
+			errs = append(errs, err)
+			continue
+		}
+		mds = append(mds, *data.metrics)
+		nodesData = append(nodesData, data)
+	}
+	// Forget the nodes that are gone.
+	r.timeBundles = timeBundles
+	r.slowlogIDs = slowlogIDs
+
+	r.consumeSlowlog(nodesData)
+
+	if r.metricsConsumer == nil || len(mds) == 0 {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, componenterror.CombineErrors(errs))
+		return nil
+	}
+
+	err = r.metricsConsumer.ConsumeMetrics(r.ctx, pdatautil.MetricsFromMetricsData(mds))
+	var numTimeSeries, numPoints int
+	for _, md := range mds {
+		ts, pts := obsreport.CountMetricPoints(md)
+		numTimeSeries += ts
+		numPoints += pts
+	}
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+
+	return nil
+}
+
+// Builds the metrics of a single node. First builds 'fixed' metrics
+// (non-keyspace metrics) defined at startup time. Then builds 'keyspace'
+// metrics if there are any keyspace lines returned by Redis. There should be
+// one keyspace line per active Redis database, of which there can be 16.
+// Then come the replication and per command metrics, and last the slow log
+// and latency metrics when enabled.
+func (r *redisRunnable) scrapeNode(
+	node *redisNode,
+	timeBundles map[string]*timeBundle,
+	slowlogIDs map[string]int64,
+) (*nodeData, error) {
+	inf, err := newRedisSvc(node.client).info()
+	if err != nil {
+		return nil, err
+	}
+
 	uptime, err := inf.getUptimeInSeconds()
 	if err != nil {
-		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
-		return nil
+		return nil, err
 	}
 
-	if r.timeBundle == nil {
-		r.timeBundle = newTimeBundle(time.Now(), uptime)
+	tb, ok := r.timeBundles[node.addr]
+	if !ok {
+		tb = newTimeBundle(time.Now(), uptime)
 	} else {
-		r.timeBundle.update(time.Now(), uptime)
+		tb.update(time.Now(), uptime)
 	}
+	timeBundles[node.addr] = tb
 
-	metrics, warnings := inf.buildFixedProtoMetrics(r.redisMetrics, r.timeBundle)
+	// The fixed metrics were filtered at startup, the other ones are built
+	// from the filtered keys.
+	metrics, warnings := inf.buildFixedProtoMetrics(r.redisMetrics, tb)
 	if warnings != nil {
 		r.logger.Warn(
 			"errors parsing redis string",
 			zap.Errors("parsing errors", warnings),
 		)
 	}
+	filtered := inf.filter(r.keyFilter)
 
-	keyspaceMetrics, warnings := inf.buildKeyspaceProtoMetrics(r.timeBundle)
+	keyspaceMetrics, warnings := filtered.buildKeyspaceProtoMetrics(tb)
 	metrics = append(metrics, keyspaceMetrics...)
 	if warnings != nil {
 		r.logger.Warn(
@@ -109,11 +191,113 @@ func (r *redisRunnable) Run() error {
 		)
 	}
 
-	md := newMetricsData(metrics, r.serviceName)
+	replicationMetrics, warnings := filtered.buildReplicationProtoMetrics(tb)
+	metrics = append(metrics, replicationMetrics...)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing replication string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
 
-	err = r.metricsConsumer.ConsumeMetrics(r.ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{*md}))
-	numTimeSeries, numPoints := obsreport.CountMetricPoints(*md)
-	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	commandStatsMetrics, warnings := filtered.buildCommandStatsProtoMetrics(tb)
+	metrics = append(metrics, commandStatsMetrics...)
+	if warnings != nil {
+		r.logger.Warn(
+			"errors parsing commandstats string",
+			zap.Errors("parsing errors", warnings),
+		)
+	}
 
-	return nil
+	data := &nodeData{}
+	if r.config.Slowlog.Enabled {
+		data.slowlog = r.scrapeSlowlog(node, slowlogIDs)
+		metrics = append(metrics, buildSlowlogProtoMetrics(data.slowlog, tb)...)
+	}
+
+	if r.config.Latency.Enabled {
+		metrics = append(metrics, r.scrapeLatency(node, tb)...)
+	}
+
+	data.metrics = newMetricsData(metrics, r.config.ServiceName)
+	data.metrics.Resource.Labels[nodeAddressLabel] = node.addr
+	if role := inf.getRole(); role != "" {
+		data.metrics.Resource.Labels[nodeRoleLabel] = role
+	}
+	return data, nil
+}
+
+// Returns the slow log entries of the node added since the previous
+// collection. Failures are only logged, so they don't prevent the INFO
+// metrics from being sent.
+func (r *redisRunnable) scrapeSlowlog(node *redisNode, slowlogIDs map[string]int64) []*slowlogEntry {
+	lastID, hasLast := r.slowlogIDs[node.addr]
+	if hasLast {
+		slowlogIDs[node.addr] = lastID
+	}
+	reply, err := node.client.retrieveSlowlog(r.config.Slowlog.MaxEntries)
+	if err != nil {
+		r.logger.Warn("failed to retrieve slowlog", zap.String("node", node.addr), zap.Error(err))
+		return nil
+	}
+	entries, err := parseSlowlog(reply)
+	if err != nil {
+		r.logger.Warn("errors parsing slowlog", zap.String("node", node.addr), zap.Error(err))
+		return nil
+	}
+	if len(entries) > 0 {
+		slowlogIDs[node.addr] = entries[len(entries)-1].id
+	}
+	return newSlowlogEntries(entries, lastID, hasLast)
+}
+
+// Returns the latency metrics of the node. Failures are only logged, so they
+// don't prevent the INFO metrics from being sent.
+func (r *redisRunnable) scrapeLatency(node *redisNode, tb *timeBundle) []*metricspb.Metric {
+	reply, err := node.client.retrieveLatency()
+	if err != nil {
+		r.logger.Warn("failed to retrieve latency", zap.String("node", node.addr), zap.Error(err))
+		return nil
+	}
+	events, err := parseLatencyLatest(reply)
+	if err != nil {
+		r.logger.Warn("errors parsing latency", zap.String("node", node.addr), zap.Error(err))
+		return nil
+	}
+	var metrics []*metricspb.Metric
+	for _, e := range events {
+		metrics = append(metrics, buildLatencyMetrics(e, tb)...)
+	}
+	return metrics
+}
+
+// Sends the new slow log entries of every node to the logs consumer, with
+// the same Resource as the node's metrics.
+func (r *redisRunnable) consumeSlowlog(nodesData []*nodeData) {
+	if r.logsConsumer == nil {
+		return
+	}
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	for _, data := range nodesData {
+		if len(data.slowlog) == 0 {
+			continue
+		}
+		rl := pdata.NewResourceLogs()
+		rl.InitEmpty()
+		rl.Resource().InitEmpty()
+		attrs := rl.Resource().Attributes()
+		for k, v := range data.metrics.Resource.Labels {
+			attrs.InsertString(k, v)
+		}
+		rl.InstrumentationLibraryLogs().Resize(1)
+		appendSlowlogRecords(rl.InstrumentationLibraryLogs().At(0).Logs(), data.slowlog)
+		rls.Append(&rl)
+	}
+	if rls.Len() == 0 {
+		return
+	}
+	if err := r.logsConsumer.ConsumeLogs(r.ctx, ld); err != nil {
+		r.logger.Warn("failed to consume slowlog", zap.Error(err))
+	}
 }
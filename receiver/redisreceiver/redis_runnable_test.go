@@ -16,9 +16,12 @@ package redisreceiver
 
 import (
 	"context"
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 )
@@ -26,7 +29,7 @@ import (
 func TestRedisRunnable(t *testing.T) {
 	consumer := &exportertest.SinkMetricsExporter{}
 	logger, _ := zap.NewDevelopment()
-	runner := newRedisRunnable(context.Background(), newFakeClient(), "", consumer, logger)
+	runner := newRedisRunnable(context.Background(), newStandaloneTopology("localhost:6379", newFakeClient()), createDefaultConfig().(*config), consumer, nil, logger)
 	err := runner.Setup()
 	require.Nil(t, err)
 	err = runner.Run()
@@ -34,3 +37,71 @@ func TestRedisRunnable(t *testing.T) {
 	// + 6 because there are two keyspace entries each of which has three metrics
 	require.Equal(t, len(getDefaultRedisMetrics())+6, consumer.MetricsCount())
 }
+
+func TestRedisRunnableSlowlogAndLatency(t *testing.T) {
+	server := newRESPStub(t, nil)
+	server.handleInfo(stubInfo(t)...)
+	var mu sync.Mutex
+	slowlog := []interface{}{
+		[]interface{}{2, 1309448221, 15, []string{"PING"}, "127.0.0.1:58217", "worker"},
+		[]interface{}{1, 1309448128, 30, []string{"GET", "foo"}, "127.0.0.1:58217", ""},
+	}
+	server.handle("slowlog", func(args []string) interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"slowlog", "get", "10"}, args)
+		return slowlog
+	})
+	server.handle("latency", func([]string) interface{} {
+		return []interface{}{[]interface{}{"command", 1405067976, 251, 1001}}
+	})
+
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = server.addr()
+	cfg.Metrics.Include = []string{"uptime_in_seconds"}
+	cfg.Slowlog = slowlogConfig{Enabled: true, MaxEntries: 10}
+	cfg.Latency.Enabled = true
+
+	topology, err := newRedisReceiver(zap.NewNop(), cfg).newTopology()
+	require.NoError(t, err)
+	defer topology.close()
+	metricsSink := &exportertest.SinkMetricsExporter{}
+	logsSink := &exportertest.SinkLogsExporter{}
+	runner := newRedisRunnable(context.Background(), topology, cfg, metricsSink, logsSink, zap.NewNop())
+	require.NoError(t, runner.Setup())
+
+	require.NoError(t, runner.Run())
+	var names []string
+	for _, md := range pdatautil.MetricsToMetricsData(metricsSink.AllMetrics()[0]) {
+		for _, m := range md.Metrics {
+			names = append(names, m.MetricDescriptor.Name)
+		}
+	}
+	require.Equal(t, []string{
+		"redis/uptime",
+		"redis/slowlog/calls",
+		"redis/slowlog/max_duration",
+		"redis/slowlog/calls",
+		"redis/slowlog/max_duration",
+		"redis/latency/latest",
+		"redis/latency/max",
+	}, names)
+	require.Equal(t, 2, logsSink.LogRecordsCount())
+	rl := logsSink.AllLogs()[0].ResourceLogs().At(0)
+	addr, _ := rl.Resource().Attributes().Get(nodeAddressLabel)
+	require.Equal(t, server.addr(), addr.StringVal())
+	require.Equal(t, "GET foo", rl.InstrumentationLibraryLogs().At(0).Logs().At(0).Body().StringVal())
+
+	// Entries already seen aren't reported again.
+	require.NoError(t, runner.Run())
+	require.Equal(t, 2, logsSink.LogRecordsCount())
+
+	mu.Lock()
+	slowlog = append([]interface{}{
+		[]interface{}{3, 1309448300, 20, []string{"SET", "foo", "bar"}, "127.0.0.1:58217", ""},
+	}, slowlog...)
+	mu.Unlock()
+	require.NoError(t, runner.Run())
+	require.Equal(t, 3, logsSink.LogRecordsCount())
+	require.Equal(t, "SET foo bar", logsSink.AllLogs()[1].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Body().StringVal())
+}
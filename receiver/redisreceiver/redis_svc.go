@@ -45,7 +45,9 @@ func (p *redisSvc) info() (info, error) {
 		if len(line) == 0 || strings.HasPrefix(line, "#") {
 			continue
 		}
-		pair := strings.Split(line, ":")
+		// Values may contain colons themselves, e.g. the IPv6 address of a
+		// replica, so only split on the first one.
+		pair := strings.SplitN(line, ":", 2)
 		if len(pair) == 2 { // defensive, should always == 2
 			attrs[pair[0]] = pair[1]
 		}
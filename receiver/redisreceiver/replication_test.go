@@ -0,0 +1,87 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReplica(t *testing.T) {
+	r, err := parseReplicaString("ip=10.0.0.2,port=6379,state=online,offset=1234,lag=1")
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.2:6379", r.addr)
+	require.Equal(t, "online", r.state)
+	require.Equal(t, 1234, r.offset)
+	require.Equal(t, 1, r.lag)
+
+	r, err = parseReplicaString("ip=::1,port=6380,state=wait_bgsave,offset=0,lag=0")
+	require.Nil(t, err)
+	require.Equal(t, "[::1]:6380", r.addr)
+}
+
+func TestParseMalformedReplica(t *testing.T) {
+	tests := []struct{ name, replica string }{
+		{"missing address", "state=online,offset=1234,lag=1"},
+		{"bad offset", "ip=10.0.0.2,port=6379,offset=x"},
+		{"missing equals", "ip=10.0.0.2,port"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseReplicaString(test.replica)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestReplicationMetricsOnMaster(t *testing.T) {
+	i := info{
+		"role":   "master",
+		"slave0": "ip=10.0.0.2,port=6379,state=online,offset=1234,lag=1",
+		"slave1": "ip=10.0.0.3,port=6379,state=online,offset=1200,lag=3",
+	}
+	m, warnings := i.buildReplicationProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 4, len(m))
+
+	require.Equal(t, "redis/replication/replica/offset", m[0].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 1234}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "redis/replication/replica/lag", m[3].MetricDescriptor.Name)
+	require.Equal(t, "s", m[3].MetricDescriptor.Unit)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 3}, m[3].Timeseries[0].Points[0].Value)
+	labels := map[string]string{}
+	for idx, key := range m[3].MetricDescriptor.LabelKeys {
+		labels[key.Key] = m[3].Timeseries[0].LabelValues[idx].Value
+	}
+	require.Equal(t, map[string]string{"replica": "10.0.0.3:6379", "state": "online"}, labels)
+}
+
+func TestReplicationMetricsOnReplica(t *testing.T) {
+	i := info{
+		"role":                       "slave",
+		"master_link_status":         "up",
+		"master_last_io_seconds_ago": "2",
+	}
+	require.Equal(t, replicaRole, i.getRole())
+	m, warnings := i.buildReplicationProtoMetrics(getDefaultTimeBundle())
+	require.Nil(t, warnings)
+	require.Equal(t, 2, len(m))
+	require.Equal(t, "redis/replication/master/link_up", m[0].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 1}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "redis/replication/master/last_io", m[1].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 2}, m[1].Timeseries[0].Points[0].Value)
+}
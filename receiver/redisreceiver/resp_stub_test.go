@@ -0,0 +1,251 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The reply to a command answered with a RESP simple string, e.g. "+OK".
+type respStatus string
+
+// Answers a command. The first argument is the lowercased command name.
+type respHandler func(args []string) interface{}
+
+// A minimal server speaking RESP2, enough to run the receiver against
+// something that behaves like Redis. It may be configured while serving.
+type respStub struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	// mu guards the fields below.
+	mu       sync.Mutex
+	username string
+	password string
+	handlers map[string]respHandler
+	conns    []net.Conn
+}
+
+// Starts a stub listening on a random local port, serving TLS when tlsConfig
+// isn't nil. The stub is closed when the test ends.
+func newRESPStub(t *testing.T, tlsConfig *tls.Config) *respStub {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+	s := &respStub{
+		ln:       ln,
+		handlers: map[string]respHandler{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *respStub) addr() string {
+	return s.ln.Addr().String()
+}
+
+// Requires clients to authenticate with AUTH before sending any other
+// command. The username may be empty.
+func (s *respStub) requireAuth(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+}
+
+func (s *respStub) handle(cmd string, h respHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[cmd] = h
+}
+
+func (s *respStub) handler(cmd string) respHandler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handlers[cmd]
+}
+
+func (s *respStub) authRequired() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.password != ""
+}
+
+// Answers INFO with the passed-in lines, delimited the way Redis does.
+func (s *respStub) handleInfo(lines ...string) {
+	s.handle("info", func([]string) interface{} {
+		return strings.Join(lines, "\r\n") + "\r\n"
+	})
+}
+
+func (s *respStub) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+func (s *respStub) serveConn(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	authenticated := !s.authRequired()
+	for {
+		args, err := readRESPCommand(r)
+		if err != nil {
+			return
+		}
+		var reply interface{}
+		cmd := strings.ToLower(args[0])
+		h := s.handler(cmd)
+		switch {
+		case cmd == "auth":
+			authenticated = s.checkAuth(args[1:])
+			reply = respStatus("OK")
+			if !authenticated {
+				reply = errors.New("WRONGPASS invalid username-password pair")
+			}
+		case !authenticated:
+			reply = errors.New("NOAUTH Authentication required.")
+		case h != nil:
+			reply = h(append([]string{cmd}, args[1:]...))
+		default:
+			reply = fmt.Errorf("ERR unknown command '%s'", args[0])
+		}
+		writeRESP(w, reply)
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+func (s *respStub) checkAuth(args []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch len(args) {
+	case 1:
+		return s.username == "" && args[0] == s.password
+	case 2:
+		return args[0] == s.username && args[1] == s.password
+	default:
+		return false
+	}
+}
+
+func (s *respStub) close() {
+	s.ln.Close()
+	s.mu.Lock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Reads a command, sent by clients as an array of bulk strings.
+func readRESPCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readRESPLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[0] != '*' {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("unexpected command length %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err = readRESPLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) < 2 || line[0] != '$' {
+			return nil, fmt.Errorf("unexpected argument %q", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readRESPLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func writeRESP(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("$-1\r\n")
+	case respStatus:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case error:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []string:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeRESP(w, item)
+		}
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeRESP(w, item)
+		}
+	default:
+		panic(fmt.Sprintf("unsupported reply %T", reply))
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// An entry of the slow log, as returned by SLOWLOG GET: e.g.
+// [14, 1309448221, 15, ["ping"], "127.0.0.1:58217", "worker-123"]. The client
+// address and name are only returned by Redis 4.0 and later.
+type slowlogEntry struct {
+	id           int64
+	time         time.Time
+	durationUsec int64
+	args         []string
+	clientAddr   string
+	clientName   string
+}
+
+// The lowercased name of the command that was slow.
+func (e *slowlogEntry) cmd() string {
+	if len(e.args) == 0 {
+		return ""
+	}
+	return strings.ToLower(e.args[0])
+}
+
+// Turns the reply to SLOWLOG GET into slowlogEntry structs, oldest first.
+func parseSlowlog(reply interface{}) ([]*slowlogEntry, error) {
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected slowlog reply %T", reply)
+	}
+	entries := make([]*slowlogEntry, 0, len(items))
+	for _, item := range items {
+		fields, ok := item.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected slowlog entry %v", item)
+		}
+		id, ok1 := fields[0].(int64)
+		ts, ok2 := fields[1].(int64)
+		duration, ok3 := fields[2].(int64)
+		args, ok4 := fields[3].([]interface{})
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("unexpected slowlog entry %v", item)
+		}
+		e := &slowlogEntry{
+			id:           id,
+			time:         time.Unix(ts, 0),
+			durationUsec: duration,
+		}
+		for _, arg := range args {
+			str, _ := arg.(string)
+			e.args = append(e.args, str)
+		}
+		if len(fields) >= 6 {
+			e.clientAddr, _ = fields[4].(string)
+			e.clientName, _ = fields[5].(string)
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	return entries, nil
+}
+
+// Returns the entries logged after the one with lastID. All entries are new
+// when there's no previous one, or when the ids went backwards because the
+// server restarted.
+func newSlowlogEntries(entries []*slowlogEntry, lastID int64, hasLast bool) []*slowlogEntry {
+	if !hasLast || len(entries) == 0 || entries[len(entries)-1].id < lastID {
+		return entries
+	}
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].id > lastID })
+	return entries[i:]
+}
+
+// Builds, per command, the number of new slow log entries and their longest
+// duration. Metrics are sorted by command.
+func buildSlowlogProtoMetrics(entries []*slowlogEntry, t *timeBundle) []*metricspb.Metric {
+	calls := map[string]int64{}
+	maxDuration := map[string]int64{}
+	for _, e := range entries {
+		cmd := e.cmd()
+		calls[cmd]++
+		if e.durationUsec > maxDuration[cmd] {
+			maxDuration[cmd] = e.durationUsec
+		}
+	}
+	cmds := make([]string, 0, len(calls))
+	for cmd := range calls {
+		cmds = append(cmds, cmd)
+	}
+	sort.Strings(cmds)
+
+	var protoMetrics []*metricspb.Metric
+	for _, cmd := range cmds {
+		protoMetrics = append(protoMetrics, buildSlowlogMetrics(cmd, calls[cmd], maxDuration[cmd], t)...)
+	}
+	return protoMetrics
+}
+
+// The attributes of slow log records.
+const (
+	slowlogRecordName          = "redis.slowlog"
+	slowlogIDAttribute         = "redis.slowlog.id"
+	slowlogDurationAttribute   = "redis.slowlog.duration_us"
+	slowlogCommandAttribute    = "redis.command"
+	slowlogClientAddrAttribute = "redis.client.address"
+	slowlogClientNameAttribute = "redis.client.name"
+)
+
+// Appends a log record per slow log entry, whose body is the command line.
+func appendSlowlogRecords(logs pdata.LogSlice, entries []*slowlogEntry) {
+	start := logs.Len()
+	logs.Resize(start + len(entries))
+	for i, e := range entries {
+		lr := logs.At(start + i)
+		lr.SetName(slowlogRecordName)
+		lr.SetTimestamp(pdata.TimestampUnixNano(uint64(e.time.UnixNano())))
+		lr.Body().SetStringVal(strings.Join(e.args, " "))
+
+		attrs := lr.Attributes()
+		attrs.InitEmptyWithCapacity(5)
+		attrs.InsertInt(slowlogIDAttribute, e.id)
+		attrs.InsertInt(slowlogDurationAttribute, e.durationUsec)
+		attrs.InsertString(slowlogCommandAttribute, e.cmd())
+		if e.clientAddr != "" {
+			attrs.InsertString(slowlogClientAddrAttribute, e.clientAddr)
+		}
+		if e.clientName != "" {
+			attrs.InsertString(slowlogClientNameAttribute, e.clientName)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func testSlowlogReply() []interface{} {
+	return []interface{}{
+		[]interface{}{int64(14), int64(1309448221), int64(15), []interface{}{"PING"}, "127.0.0.1:58217", "worker-123"},
+		[]interface{}{int64(13), int64(1309448128), int64(30), []interface{}{"SLOWLOG", "GET", "100"}},
+		[]interface{}{int64(12), int64(1309448100), int64(45), []interface{}{"ping"}, "127.0.0.1:58217", ""},
+	}
+}
+
+func TestParseSlowlog(t *testing.T) {
+	entries, err := parseSlowlog(testSlowlogReply())
+	require.Nil(t, err)
+	require.Equal(t, 3, len(entries))
+
+	// Oldest first.
+	require.Equal(t, int64(12), entries[0].id)
+	require.Equal(t, int64(13), entries[1].id)
+	e := entries[2]
+	require.Equal(t, int64(14), e.id)
+	require.Equal(t, time.Unix(1309448221, 0), e.time)
+	require.Equal(t, int64(15), e.durationUsec)
+	require.Equal(t, []string{"PING"}, e.args)
+	require.Equal(t, "ping", e.cmd())
+	require.Equal(t, "127.0.0.1:58217", e.clientAddr)
+	require.Equal(t, "worker-123", e.clientName)
+	require.Equal(t, "", entries[1].clientAddr)
+}
+
+func TestParseMalformedSlowlog(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply interface{}
+	}{
+		{"not a list", "foo"},
+		{"entry not a list", []interface{}{"foo"}},
+		{"short entry", []interface{}{[]interface{}{int64(1), int64(2)}}},
+		{"bad id", []interface{}{[]interface{}{"1", int64(2), int64(3), []interface{}{}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseSlowlog(test.reply)
+			require.NotNil(t, err)
+		})
+	}
+}
+
+func TestNewSlowlogEntries(t *testing.T) {
+	entries, _ := parseSlowlog(testSlowlogReply())
+	require.Equal(t, 3, len(newSlowlogEntries(entries, 0, false)))
+	require.Equal(t, 1, len(newSlowlogEntries(entries, 13, true)))
+	require.Equal(t, 0, len(newSlowlogEntries(entries, 14, true)))
+	// The ids went backwards, the server restarted.
+	require.Equal(t, 3, len(newSlowlogEntries(entries, 100, true)))
+}
+
+func TestSlowlogMetrics(t *testing.T) {
+	entries, _ := parseSlowlog(testSlowlogReply())
+	m := buildSlowlogProtoMetrics(entries, getDefaultTimeBundle())
+	require.Equal(t, 4, len(m))
+
+	require.Equal(t, "redis/slowlog/calls", m[0].MetricDescriptor.Name)
+	require.Equal(t, "ping", m[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 2}, m[0].Timeseries[0].Points[0].Value)
+	require.Equal(t, "redis/slowlog/max_duration", m[1].MetricDescriptor.Name)
+	require.Equal(t, &metricspb.Point_Int64Value{Int64Value: 45}, m[1].Timeseries[0].Points[0].Value)
+	require.Equal(t, "slowlog", m[2].Timeseries[0].LabelValues[0].Value)
+}
+
+func TestSlowlogRecords(t *testing.T) {
+	entries, _ := parseSlowlog(testSlowlogReply())
+	logs := pdata.NewLogSlice()
+	appendSlowlogRecords(logs, entries)
+	require.Equal(t, 3, logs.Len())
+
+	lr := logs.At(2)
+	require.Equal(t, slowlogRecordName, lr.Name())
+	require.Equal(t, pdata.TimestampUnixNano(1309448221*uint64(time.Second)), lr.Timestamp())
+	require.Equal(t, "PING", lr.Body().StringVal())
+	attrs := lr.Attributes()
+	require.Equal(t, 5, attrs.Len())
+	id, _ := attrs.Get(slowlogIDAttribute)
+	require.Equal(t, int64(14), id.IntVal())
+	duration, _ := attrs.Get(slowlogDurationAttribute)
+	require.Equal(t, int64(15), duration.IntVal())
+	cmd, _ := attrs.Get(slowlogCommandAttribute)
+	require.Equal(t, "ping", cmd.StringVal())
+	name, _ := attrs.Get(slowlogClientNameAttribute)
+	require.Equal(t, "worker-123", name.StringVal())
+
+	require.Equal(t, "SLOWLOG GET 100", logs.At(1).Body().StringVal())
+	require.Equal(t, 3, logs.At(1).Attributes().Len())
+}
@@ -0,0 +1,258 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/collector/component/componenterror"
+)
+
+// The ways the receiver finds the Redis servers to scrape.
+const (
+	standaloneMode = "standalone"
+	clusterMode    = "cluster"
+	sentinelMode   = "sentinel"
+)
+
+// A Redis server to scrape, identified by its address.
+type redisNode struct {
+	addr   string
+	client client
+}
+
+// Finds the Redis servers to scrape. It's consulted on every collection so
+// that failovers, resharding and added replicas are picked up.
+type topology interface {
+	// returns the nodes to scrape
+	nodes() ([]*redisNode, error)
+	// releases the connections to all nodes
+	close() error
+}
+
+// A single Redis server, as configured.
+type standaloneTopology struct {
+	node *redisNode
+}
+
+var _ topology = (*standaloneTopology)(nil)
+
+func newStandaloneTopology(addr string, client client) *standaloneTopology {
+	return &standaloneTopology{node: &redisNode{addr: addr, client: client}}
+}
+
+func (t *standaloneTopology) nodes() ([]*redisNode, error) {
+	return []*redisNode{t.node}, nil
+}
+
+func (t *standaloneTopology) close() error {
+	return t.node.client.close()
+}
+
+// Keeps one client per discovered node. Clients are created for nodes that
+// show up and closed for nodes that are gone.
+type nodeCache struct {
+	newClient func(addr string) client
+	nodes     map[string]*redisNode
+}
+
+func newNodeCache(newClient func(addr string) client) *nodeCache {
+	return &nodeCache{
+		newClient: newClient,
+		nodes:     map[string]*redisNode{},
+	}
+}
+
+// Returns the nodes for the passed-in addresses, sorted by address.
+func (c *nodeCache) sync(addrs []string) []*redisNode {
+	current := make(map[string]*redisNode, len(addrs))
+	for _, addr := range addrs {
+		node, ok := c.nodes[addr]
+		if !ok {
+			node = &redisNode{addr: addr, client: c.newClient(addr)}
+		}
+		current[addr] = node
+	}
+	for addr, node := range c.nodes {
+		if _, ok := current[addr]; !ok {
+			_ = node.client.close()
+		}
+	}
+	c.nodes = current
+
+	nodes := make([]*redisNode, 0, len(current))
+	for _, node := range current {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].addr < nodes[j].addr })
+	return nodes
+}
+
+func (c *nodeCache) close() error {
+	var errs []error
+	for _, node := range c.nodes {
+		errs = append(errs, node.client.close())
+	}
+	c.nodes = map[string]*redisNode{}
+	return closeAll(errs...)
+}
+
+// Combines the errors returned when closing clients, ignoring nil ones.
+func closeAll(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	return componenterror.CombineErrors(nonNil)
+}
+
+// A Redis Cluster. The nodes are those listed by CLUSTER NODES on the seed
+// node, masters and replicas alike.
+type clusterTopology struct {
+	seed  *redis.Client
+	cache *nodeCache
+}
+
+var _ topology = (*clusterTopology)(nil)
+
+func newClusterTopology(seed *redis.Client, newClient func(addr string) client) *clusterTopology {
+	return &clusterTopology{
+		seed:  seed,
+		cache: newNodeCache(newClient),
+	}
+}
+
+func (t *clusterTopology) nodes() ([]*redisNode, error) {
+	str, err := t.seed.ClusterNodes().Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster nodes: %w", err)
+	}
+	return t.cache.sync(parseClusterNodes(str)), nil
+}
+
+func (t *clusterTopology) close() error {
+	return closeAll(t.cache.close(), t.seed.Close())
+}
+
+// Returns the addresses of the healthy nodes in the output of CLUSTER NODES:
+// e.g. "07c37dfeb235 127.0.0.1:30004@31004 slave e7d1eecce10f 0 1426238317239 4 connected"
+func parseClusterNodes(str string) []string {
+	var addrs []string
+	for _, line := range strings.Split(str, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if hasAnyFlag(fields[2], "fail", "handshake", "noaddr") {
+			continue
+		}
+		// Strip the cluster bus port and, since Redis 7, the hostname.
+		addr := fields[1]
+		if i := strings.IndexAny(addr, "@,"); i >= 0 {
+			addr = addr[:i]
+		}
+		if host, _, err := net.SplitHostPort(addr); err != nil || host == "" {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
+// A master and its replicas monitored by Redis Sentinel.
+type sentinelTopology struct {
+	sentinel   *redis.SentinelClient
+	masterName string
+	cache      *nodeCache
+}
+
+var _ topology = (*sentinelTopology)(nil)
+
+func newSentinelTopology(
+	sentinel *redis.SentinelClient,
+	masterName string,
+	newClient func(addr string) client,
+) *sentinelTopology {
+	return &sentinelTopology{
+		sentinel:   sentinel,
+		masterName: masterName,
+		cache:      newNodeCache(newClient),
+	}
+}
+
+func (t *sentinelTopology) nodes() ([]*redisNode, error) {
+	master, err := t.sentinel.GetMasterAddrByName(t.masterName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the address of master %q: %w", t.masterName, err)
+	}
+	if len(master) != 2 {
+		return nil, fmt.Errorf("unexpected address of master %q: %v", t.masterName, master)
+	}
+	replicas, err := t.sentinel.Slaves(t.masterName).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the replicas of master %q: %w", t.masterName, err)
+	}
+	addrs := append([]string{net.JoinHostPort(master[0], master[1])}, parseSentinelReplicas(replicas)...)
+	return t.cache.sync(addrs), nil
+}
+
+func (t *sentinelTopology) close() error {
+	return closeAll(t.cache.close(), t.sentinel.Close())
+}
+
+// Returns the addresses of the reachable replicas in the reply to SENTINEL
+// REPLICAS, a list of flat field/value lists.
+func parseSentinelReplicas(replicas []interface{}) []string {
+	var addrs []string
+	for _, r := range replicas {
+		fields, ok := r.([]interface{})
+		if !ok {
+			continue
+		}
+		replica := make(map[string]string, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			key, _ := fields[i].(string)
+			val, _ := fields[i+1].(string)
+			replica[key] = val
+		}
+		if replica["ip"] == "" || replica["port"] == "" {
+			continue
+		}
+		if hasAnyFlag(replica["flags"], "s_down", "o_down", "disconnected") {
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(replica["ip"], replica["port"]))
+	}
+	return addrs
+}
+
+// Tells whether the comma separated list of flags holds any of the passed-in
+// ones.
+func hasAnyFlag(flags string, wanted ...string) bool {
+	for _, flag := range strings.Split(flags, ",") {
+		for _, w := range wanted {
+			if flag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
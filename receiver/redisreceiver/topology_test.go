@@ -0,0 +1,279 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestParseClusterNodes(t *testing.T) {
+	nodes := strings.Join([]string{
+		"07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected",
+		"67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002,redis-2 master - 0 1426238316232 2 connected 5461-10922",
+		"292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30003@31003 master,fail - 1426238316232 1426238316232 3 connected 10923-16383",
+		"6ec23923021cf3ffec47632106199cb7f496ce01 127.0.0.1:30005@31005 handshake - 0 0 0 connected",
+		"824fe116063bc5fcf9f4ffd895bc17aee7731ac3 :0@0 slave,noaddr 292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 0 1426238317741 3 disconnected",
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460",
+		"",
+	}, "\n")
+	assert.Equal(t, []string{"127.0.0.1:30004", "127.0.0.1:30002", "127.0.0.1:30001"}, parseClusterNodes(nodes))
+}
+
+func TestParseSentinelReplicas(t *testing.T) {
+	replicas := []interface{}{
+		[]interface{}{"name", "10.0.0.2:6379", "ip", "10.0.0.2", "port", "6379", "flags", "slave"},
+		[]interface{}{"name", "10.0.0.3:6379", "ip", "10.0.0.3", "port", "6379", "flags", "slave,s_down"},
+		[]interface{}{"name", "[::1]:6379", "ip", "::1", "port", "6379", "flags", "slave"},
+		[]interface{}{"name", "incomplete", "flags", "slave"},
+	}
+	assert.Equal(t, []string{"10.0.0.2:6379", "[::1]:6379"}, parseSentinelReplicas(replicas))
+}
+
+func TestNodeCache(t *testing.T) {
+	var created []string
+	closed := map[string]bool{}
+	cache := newNodeCache(func(addr string) client {
+		created = append(created, addr)
+		return &closeRecordingClient{addr: addr, closed: closed}
+	})
+
+	nodes := cache.sync([]string{"b:6379", "a:6379"})
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "a:6379", nodes[0].addr)
+	assert.Equal(t, "b:6379", nodes[1].addr)
+
+	nodes = cache.sync([]string{"a:6379", "c:6379"})
+	require.Len(t, nodes, 2)
+	assert.Equal(t, []string{"b:6379", "a:6379", "c:6379"}, created)
+	assert.Equal(t, map[string]bool{"b:6379": true}, closed)
+
+	require.NoError(t, cache.close())
+	assert.Equal(t, map[string]bool{"a:6379": true, "b:6379": true, "c:6379": true}, closed)
+}
+
+type closeRecordingClient struct {
+	fakeClient
+	addr   string
+	closed map[string]bool
+}
+
+func (c *closeRecordingClient) close() error {
+	c.closed[c.addr] = true
+	return nil
+}
+
+func TestStandaloneTopologyWithACL(t *testing.T) {
+	server := newRESPStub(t, nil)
+	server.requireAuth("metrics", "s3cret")
+	server.handleInfo(stubInfo(t)...)
+
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = server.addr()
+	cfg.Username = "metrics"
+	cfg.Password = "s3cret"
+
+	mds := scrape(t, cfg)
+	require.Len(t, mds, 1)
+	assert.Equal(t, server.addr(), mds[0].Resource.Labels[nodeAddressLabel])
+	assert.Equal(t, masterRole, mds[0].Resource.Labels[nodeRoleLabel])
+
+	cfg.Password = "wrong"
+	assert.Empty(t, scrape(t, cfg))
+}
+
+func TestStandaloneTopologyWithTLS(t *testing.T) {
+	serverTLS, caFile := newTestTLSConfig(t)
+	server := newRESPStub(t, serverTLS)
+	server.handleInfo(stubInfo(t)...)
+
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = server.addr()
+	cfg.TLS = &configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{CAFile: caFile},
+	}
+	require.Len(t, scrape(t, cfg), 1)
+
+	// A plain text client can't talk to a TLS server.
+	cfg.TLS = nil
+	assert.Empty(t, scrape(t, cfg))
+}
+
+func TestClusterTopology(t *testing.T) {
+	master := newRESPStub(t, nil)
+	replica := newRESPStub(t, nil)
+	nodes := strings.Join([]string{
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca " + master.addr() + "@31001 myself,master - 0 0 1 connected 0-16383",
+		"07c37dfeb235213a872192d90877d0cd55635b91 " + replica.addr() + "@31004 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 1 connected",
+	}, "\n") + "\n"
+	master.handle("cluster", func(args []string) interface{} {
+		if len(args) == 2 && strings.EqualFold(args[1], "nodes") {
+			return nodes
+		}
+		return nil
+	})
+	master.handleInfo(stubInfo(t,
+		"connected_slaves:1",
+		"slave0:ip=127.0.0.1,port=7001,state=online,offset=1234,lag=1",
+	)...)
+	replica.handleInfo(stubInfo(t,
+		"role:slave",
+		"master_link_status:up",
+		"master_last_io_seconds_ago:2",
+	)...)
+
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = master.addr()
+	cfg.Mode = clusterMode
+
+	mds := scrape(t, cfg)
+	require.Len(t, mds, 2)
+	roles := map[string]string{}
+	names := map[string][]string{}
+	for _, md := range mds {
+		addr := md.Resource.Labels[nodeAddressLabel]
+		roles[addr] = md.Resource.Labels[nodeRoleLabel]
+		for _, m := range md.Metrics {
+			names[addr] = append(names[addr], m.MetricDescriptor.Name)
+		}
+	}
+	assert.Equal(t, map[string]string{master.addr(): masterRole, replica.addr(): replicaRole}, roles)
+	assert.Contains(t, names[master.addr()], "redis/replication/replica/lag")
+	assert.NotContains(t, names[master.addr()], "redis/replication/master/link_up")
+	assert.Contains(t, names[replica.addr()], "redis/replication/master/link_up")
+}
+
+func TestSentinelTopology(t *testing.T) {
+	master := newRESPStub(t, nil)
+	replica := newRESPStub(t, nil)
+	sentinel := newRESPStub(t, nil)
+	sentinel.requireAuth("", "sentinel-pass")
+	masterHost, masterPort, _ := net.SplitHostPort(master.addr())
+	replicaHost, replicaPort, _ := net.SplitHostPort(replica.addr())
+	sentinel.handle("sentinel", func(args []string) interface{} {
+		if len(args) != 3 || args[2] != "mymaster" {
+			return nil
+		}
+		switch strings.ToLower(args[1]) {
+		case "get-master-addr-by-name":
+			return []string{masterHost, masterPort}
+		case "slaves":
+			return []interface{}{
+				[]string{"name", replica.addr(), "ip", replicaHost, "port", replicaPort, "flags", "slave"},
+				[]string{"name", "127.0.0.1:1", "ip", "127.0.0.1", "port", "1", "flags", "slave,s_down,disconnected"},
+			}
+		}
+		return nil
+	})
+	master.requireAuth("", "redis-pass")
+	master.handleInfo(stubInfo(t)...)
+	replica.requireAuth("", "redis-pass")
+	replica.handleInfo(stubInfo(t, "role:slave", "master_link_status:down", "master_last_io_seconds_ago:-1")...)
+
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = sentinel.addr()
+	cfg.Mode = sentinelMode
+	cfg.SentinelMasterName = "mymaster"
+	cfg.SentinelPassword = "sentinel-pass"
+	cfg.Password = "redis-pass"
+
+	mds := scrape(t, cfg)
+	require.Len(t, mds, 2)
+	roles := map[string]string{}
+	for _, md := range mds {
+		roles[md.Resource.Labels[nodeAddressLabel]] = md.Resource.Labels[nodeRoleLabel]
+	}
+	assert.Equal(t, map[string]string{master.addr(): masterRole, replica.addr(): replicaRole}, roles)
+}
+
+// Runs a single collection against the topology of the passed-in config and
+// returns the metrics of every node scraped.
+func scrape(t *testing.T, cfg *config) []consumerdata.MetricsData {
+	r := newRedisReceiver(zap.NewNop(), cfg)
+	topology, err := r.newTopology()
+	require.NoError(t, err)
+	defer topology.close()
+
+	consumer := &exportertest.SinkMetricsExporter{}
+	runnable := newRedisRunnable(context.Background(), topology, cfg, consumer, nil, zap.NewNop())
+	require.NoError(t, runnable.Setup())
+	require.NoError(t, runnable.Run())
+
+	var mds []consumerdata.MetricsData
+	for _, m := range consumer.AllMetrics() {
+		mds = append(mds, pdatautil.MetricsToMetricsData(m)...)
+	}
+	return mds
+}
+
+// Returns the lines of the INFO reply in testdata, followed by the passed-in
+// ones. Later lines override earlier ones with the same key.
+func stubInfo(t *testing.T, extra ...string) []string {
+	str, err := readFile("info")
+	require.NoError(t, err)
+	return append(strings.Split(strings.TrimSpace(str), "\n"), extra...)
+}
+
+// Creates a server TLS config with a self-signed certificate for 127.0.0.1,
+// and writes the certificate to a file for clients to trust.
+func newTestTLSConfig(t *testing.T) (*tls.Config, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	dir, err := ioutil.TempDir("", "redisreceiver")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, caFile
+}
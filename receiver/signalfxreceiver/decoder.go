@@ -0,0 +1,281 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// sfxSourceDimension is the dimension used to keep the source of SignalFx v1
+// data points, which carry no other dimensions.
+const sfxSourceDimension = "sf_source"
+
+var (
+	errSFxV1InvalidLength  = errors.New("invalid length prefix for v1 data-point")
+	errSFxEventWithoutType = errors.New("event without \"eventType\"")
+)
+
+// jsonV2MetricTypes lists the keys of the SignalFx JSON v2 data point format
+// in the order that they are decoded.
+var jsonV2MetricTypes = []struct {
+	key        string
+	metricType sfxpb.MetricType
+}{
+	{key: "gauge", metricType: sfxpb.MetricType_GAUGE},
+	{key: "counter", metricType: sfxpb.MetricType_COUNTER},
+	{key: "cumulative_counter", metricType: sfxpb.MetricType_CUMULATIVE_COUNTER},
+}
+
+type jsonV2DataPoint struct {
+	Metric     string            `json:"metric"`
+	Value      interface{}       `json:"value"`
+	Dimensions map[string]string `json:"dimensions"`
+	Timestamp  int64             `json:"timestamp"`
+}
+
+type jsonV1DataPoint struct {
+	Source string      `json:"source"`
+	Metric string      `json:"metric"`
+	Value  interface{} `json:"value"`
+}
+
+type jsonEvent struct {
+	Category   *sfxpb.EventCategory   `json:"category"`
+	EventType  string                 `json:"eventType"`
+	Dimensions map[string]string      `json:"dimensions"`
+	Properties map[string]interface{} `json:"properties"`
+	Timestamp  int64                  `json:"timestamp"`
+}
+
+// decodeJSONV2DataPoints decodes the SignalFx JSON v2 format, an object
+// keyed by metric type with lists of data points as values.
+func decodeJSONV2DataPoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	msg := map[string][]*jsonV2DataPoint{}
+	if err := newJSONDecoder(body).Decode(&msg); err != nil {
+		return nil, err
+	}
+
+	for key := range msg {
+		if !isJSONV2MetricType(key) {
+			return nil, fmt.Errorf("unknown metric type %q", key)
+		}
+	}
+
+	var dps []*sfxpb.DataPoint
+	for _, mt := range jsonV2MetricTypes {
+		for _, jdp := range msg[mt.key] {
+			if jdp == nil {
+				continue
+			}
+			datum, err := jsonToDatum(jdp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", jdp.Metric, err)
+			}
+			dps = append(dps, &sfxpb.DataPoint{
+				Metric:     jdp.Metric,
+				Timestamp:  jdp.Timestamp,
+				Value:      datum,
+				MetricType: mt.metricType.Enum(),
+				Dimensions: toDimensions(jdp.Dimensions),
+			})
+		}
+	}
+	return dps, nil
+}
+
+// decodeJSONV1DataPoints decodes the SignalFx JSON v1 format, a stream of
+// concatenated data point objects. All v1 data points are gauges.
+func decodeJSONV1DataPoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var dps []*sfxpb.DataPoint
+	dec := newJSONDecoder(body)
+	for {
+		jdp := &jsonV1DataPoint{}
+		err := dec.Decode(jdp)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		datum, err := jsonToDatum(jdp.Value)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q: %w", jdp.Metric, err)
+		}
+		dps = append(dps, fromV1DataPoint(&sfxpb.DataPoint{
+			Source: jdp.Source,
+			Metric: jdp.Metric,
+			Value:  datum,
+		}))
+	}
+	return dps, nil
+}
+
+// decodeProtobufV1DataPoints decodes the SignalFx protobuf v1 format, a
+// stream of data point messages each prefixed by its varint encoded length.
+func decodeProtobufV1DataPoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var dps []*sfxpb.DataPoint
+	for len(body) > 0 {
+		size, n := binary.Uvarint(body)
+		if n <= 0 || size > uint64(len(body)-n) {
+			return nil, errSFxV1InvalidLength
+		}
+		body = body[n:]
+
+		dp := &sfxpb.DataPoint{}
+		if err := dp.Unmarshal(body[:size]); err != nil {
+			return nil, err
+		}
+		dps = append(dps, fromV1DataPoint(dp))
+		body = body[size:]
+	}
+	return dps, nil
+}
+
+// decodeJSONEvents decodes the SignalFx JSON event format, a list of event
+// objects.
+func decodeJSONEvents(body []byte) ([]*sfxpb.Event, error) {
+	var jevents []*jsonEvent
+	if err := newJSONDecoder(body).Decode(&jevents); err != nil {
+		return nil, err
+	}
+
+	events := make([]*sfxpb.Event, 0, len(jevents))
+	for _, jevent := range jevents {
+		if jevent == nil {
+			continue
+		}
+		if jevent.EventType == "" {
+			return nil, errSFxEventWithoutType
+		}
+
+		event := &sfxpb.Event{
+			EventType:  jevent.EventType,
+			Category:   jevent.Category,
+			Timestamp:  jevent.Timestamp,
+			Dimensions: toDimensions(jevent.Dimensions),
+		}
+		for _, key := range sortedKeys(jevent.Properties) {
+			value, err := jsonToPropertyValue(jevent.Properties[key])
+			if err != nil {
+				return nil, fmt.Errorf("event property %q: %w", key, err)
+			}
+			event.Properties = append(event.Properties, &sfxpb.Property{Key: key, Value: value})
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func newJSONDecoder(body []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// Keep numbers as json.Number so integers are not converted to float64.
+	dec.UseNumber()
+	return dec
+}
+
+func isJSONV2MetricType(key string) bool {
+	for _, mt := range jsonV2MetricTypes {
+		if mt.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fromV1DataPoint keeps the source of a v1 data point as a dimension.
+func fromV1DataPoint(dp *sfxpb.DataPoint) *sfxpb.DataPoint {
+	if dp.Source != "" {
+		dp.Dimensions = append(dp.Dimensions, &sfxpb.Dimension{
+			Key:   sfxSourceDimension,
+			Value: dp.Source,
+		})
+	}
+	return dp
+}
+
+func jsonToDatum(v interface{}) (sfxpb.Datum, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return sfxpb.Datum{IntValue: &i}, nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return sfxpb.Datum{}, err
+		}
+		return sfxpb.Datum{DoubleValue: &f}, nil
+	case string:
+		return sfxpb.Datum{StrValue: &val}, nil
+	}
+	return sfxpb.Datum{}, fmt.Errorf("unsupported value type %T", v)
+}
+
+func jsonToPropertyValue(v interface{}) (*sfxpb.PropertyValue, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return &sfxpb.PropertyValue{IntValue: &i}, nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &sfxpb.PropertyValue{DoubleValue: &f}, nil
+	case string:
+		return &sfxpb.PropertyValue{StrValue: &val}, nil
+	case bool:
+		return &sfxpb.PropertyValue{BoolValue: &val}, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", v)
+}
+
+// toDimensions converts a JSON dimension map, sorted by key so the resulting
+// label order is stable.
+func toDimensions(dims map[string]string) []*sfxpb.Dimension {
+	if len(dims) == 0 {
+		return nil
+	}
+	sfxDims := make([]*sfxpb.Dimension, 0, len(dims))
+	for _, key := range sortedStringKeys(dims) {
+		sfxDims = append(sfxDims, &sfxpb.Dimension{Key: key, Value: dims[key]})
+	}
+	return sfxDims
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeJSONV2DataPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []*sfxpb.DataPoint
+		wantErr bool
+	}{
+		{
+			name: "all_types",
+			body: `{
+				"cumulative_counter": [{"metric": "cc", "value": 3, "timestamp": 1000}],
+				"counter": [{"metric": "c", "value": 2.5}],
+				"gauge": [{"metric": "g", "value": "1.5", "dimensions": {"b": "2", "a": "1"}}]
+			}`,
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "g",
+					Value:      sfxpb.Datum{StrValue: strPtr("1.5")},
+					MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+					Dimensions: []*sfxpb.Dimension{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+				},
+				{
+					Metric:     "c",
+					Value:      sfxpb.Datum{DoubleValue: float64Ptr(2.5)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+				},
+				{
+					Metric:     "cc",
+					Timestamp:  1000,
+					Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+				},
+			},
+		},
+		{
+			name:    "unknown_type",
+			body:    `{"histogram": [{"metric": "h", "value": 1}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "bad_value",
+			body:    `{"gauge": [{"metric": "g", "value": true}]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeJSONV2DataPoints([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_decodeJSONV1DataPoints(t *testing.T) {
+	got, err := decodeJSONV1DataPoints([]byte(`{"source": "s", "metric": "m", "value": 1} {"metric": "n", "value": 2.5}`))
+	require.NoError(t, err)
+	assert.Equal(t, []*sfxpb.DataPoint{
+		{
+			Source:     "s",
+			Metric:     "m",
+			Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+			Dimensions: []*sfxpb.Dimension{{Key: "sf_source", Value: "s"}},
+		},
+		{
+			Metric: "n",
+			Value:  sfxpb.Datum{DoubleValue: float64Ptr(2.5)},
+		},
+	}, got)
+
+	_, err = decodeJSONV1DataPoints([]byte(`{"metric": "m", "value": null}`))
+	assert.Error(t, err)
+}
+
+func Test_decodeProtobufV1DataPoints(t *testing.T) {
+	_, err := decodeProtobufV1DataPoints([]byte{0x80})
+	assert.Equal(t, errSFxV1InvalidLength, err)
+
+	_, err = decodeProtobufV1DataPoints([]byte{0x05, 0x01})
+	assert.Equal(t, errSFxV1InvalidLength, err)
+
+	got, err := decodeProtobufV1DataPoints(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func Test_decodeJSONEvents(t *testing.T) {
+	got, err := decodeJSONEvents([]byte(`[{
+		"category": "ALERT",
+		"eventType": "e",
+		"dimensions": {"k": "v"},
+		"properties": {"s": "str", "i": 1, "d": 1.5, "b": true},
+		"timestamp": 1000
+	}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []*sfxpb.Event{
+		{
+			EventType:  "e",
+			Category:   sfxpb.EventCategory_ALERT.Enum(),
+			Timestamp:  1000,
+			Dimensions: []*sfxpb.Dimension{{Key: "k", Value: "v"}},
+			Properties: []*sfxpb.Property{
+				{Key: "b", Value: &sfxpb.PropertyValue{BoolValue: boolPtr(true)}},
+				{Key: "d", Value: &sfxpb.PropertyValue{DoubleValue: float64Ptr(1.5)}},
+				{Key: "i", Value: &sfxpb.PropertyValue{IntValue: int64Ptr(1)}},
+				{Key: "s", Value: &sfxpb.PropertyValue{StrValue: strPtr("str")}},
+			},
+		},
+	}, got)
+
+	_, err = decodeJSONEvents([]byte(`[{"category": "ALERT"}]`))
+	assert.Equal(t, errSFxEventWithoutType, err)
+
+	_, err = decodeJSONEvents([]byte(`[{"eventType": "e", "properties": {"o": {}}}]`))
+	assert.Error(t, err)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
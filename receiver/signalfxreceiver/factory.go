@@ -25,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sharedcomponent"
 )
 
 // This file implements factory for SignalFx receiver.
@@ -42,7 +44,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -83,7 +86,7 @@ func (rCfg *Config) validate() error {
 	return nil
 }
 
-// CreateMetricsReceiver creates a metrics receiver based on provided config.
+// createMetricsReceiver creates a metrics receiver based on provided config.
 func createMetricsReceiver(
 	_ context.Context,
 	params component.ReceiverCreateParams,
@@ -91,6 +94,42 @@ func createMetricsReceiver(
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.registerMetricsConsumer(consumer); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// createLogsReceiver creates a logs receiver, for SignalFx events, based on
+// provided config.
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.registerLogsConsumer(consumer); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// getReceiver returns the receiver for the given config, creating it on
+// first use so metrics and logs pipelines share the same HTTP server.
+func getReceiver(params component.ReceiverCreateParams, cfg configmodels.Receiver) (*sfxReceiver, error) {
 	rCfg := cfg.(*Config)
 
 	err := rCfg.validate()
@@ -98,5 +137,15 @@ func createMetricsReceiver(
 		return nil, err
 	}
 
-	return New(params.Logger, *rCfg, consumer)
+	r, err := receivers.GetOrAdd(rCfg, func() (interface{}, error) {
+		return newReceiver(params.Logger, *rCfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.(*sfxReceiver), nil
 }
+
+// receivers holds the sfxReceiver shared by the metrics and logs pipelines of
+// each configuration.
+var receivers = sharedcomponent.NewComponents()
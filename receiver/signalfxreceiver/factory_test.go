@@ -43,6 +43,26 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
 
+func TestCreateLogsReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:1" // Endpoint is required, not going to be used here.
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	lReceiver, err := factory.(component.LogsReceiverFactory).CreateLogsReceiver(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	assert.Nil(t, err, "receiver creation failed")
+	assert.NotNil(t, lReceiver, "receiver creation failed")
+
+	mReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Nil(t, err, "receiver creation failed")
+	assert.Same(t, lReceiver, mReceiver, "receiver must be shared between pipelines")
+
+	assert.NoError(t, mReceiver.Shutdown(context.Background()))
+	mReceiver, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Nil(t, err, "receiver creation failed")
+	assert.NotSame(t, lReceiver, mReceiver, "receiver must not be reused after shutdown")
+}
+
 func TestCreateInvalidHTTPEndpoint(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net"
 	"net/http"
 	"sync"
@@ -48,7 +49,7 @@ const (
 
 	responseOK                 = "OK"
 	responseInvalidMethod      = "Only \"POST\" method is supported"
-	responseInvalidContentType = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidContentType = "\"Content-Type\" must be either \"application/x-protobuf\" or \"application/json\""
 	responseInvalidEncoding    = "\"Content-Encoding\" must be \"gzip\" or empty"
 	responseErrGzipReader      = "Error on gzip body"
 	responseErrReadBody        = "Failed to read message body"
@@ -57,6 +58,7 @@ const (
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
@@ -64,6 +66,7 @@ const (
 
 var (
 	errNilNextConsumer = errors.New("nil nextConsumer")
+	errNilLogsConsumer = errors.New("nil logsConsumer")
 	errEmptyEndpoint   = errors.New("empty endpoint")
 
 	okRespBody               = initJSONResponse(responseOK)
@@ -76,12 +79,14 @@ var (
 	errNextConsumerRespBody  = initJSONResponse(responseErrNextConsumer)
 )
 
-// sfxReceiver implements the component.MetricsReceiver for SignalFx metric protocol.
+// sfxReceiver implements the component.MetricsReceiver and
+// component.LogsReceiver for the SignalFx data point and event protocols.
 type sfxReceiver struct {
 	sync.Mutex
 	logger       *zap.Logger
 	config       *Config
 	nextConsumer consumer.MetricsConsumer
+	logsConsumer consumer.LogsConsumer
 	server       *http.Server
 
 	startOnce sync.Once
@@ -89,6 +94,7 @@ type sfxReceiver struct {
 }
 
 var _ component.MetricsReceiver = (*sfxReceiver)(nil)
+var _ component.LogsReceiver = (*sfxReceiver)(nil)
 
 // New creates the SignalFx receiver with the given configuration.
 func New(
@@ -97,23 +103,57 @@ func New(
 	nextConsumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 
-	if nextConsumer == nil {
-		return nil, errNilNextConsumer
+	r, err := newReceiver(logger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.registerMetricsConsumer(nextConsumer); err != nil {
+		return nil, err
 	}
 
+	return r, nil
+}
+
+// newReceiver creates a SignalFx receiver without any consumers, these are
+// registered by the factory for each pipeline using the receiver.
+func newReceiver(logger *zap.Logger, config Config) (*sfxReceiver, error) {
 	if config.Endpoint == "" {
 		return nil, errEmptyEndpoint
 	}
 
 	r := &sfxReceiver{
-		logger:       logger,
-		config:       &config,
-		nextConsumer: nextConsumer,
+		logger: logger,
+		config: &config,
 	}
 
 	return r, nil
 }
 
+func (r *sfxReceiver) registerMetricsConsumer(mc consumer.MetricsConsumer) error {
+	if mc == nil {
+		return errNilNextConsumer
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.nextConsumer = mc
+	return nil
+}
+
+func (r *sfxReceiver) registerLogsConsumer(lc consumer.LogsConsumer) error {
+	if lc == nil {
+		return errNilLogsConsumer
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.logsConsumer = lc
+	return nil
+}
+
 // StartMetricsReception tells the receiver to start its processing.
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
@@ -134,7 +174,14 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 		}
 
 		mx := mux.NewRouter()
-		mx.HandleFunc("/v2/datapoint", r.handleReq)
+		// Only serve the endpoints of the data types that have a pipeline.
+		if r.nextConsumer != nil {
+			mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
+			mx.HandleFunc("/v1/datapoint", r.handleV1DatapointReq)
+		}
+		if r.logsConsumer != nil {
+			mx.HandleFunc("/v2/event", r.handleEventReq)
+		}
 
 		r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -156,38 +203,53 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 // StopMetricsReception tells the receiver that should stop reception,
 // giving it a chance to perform any necessary clean-up.
 func (r *sfxReceiver) Shutdown(context.Context) error {
+	receivers.Remove(r)
+
 	r.Lock()
 	defer r.Unlock()
 
 	err := componenterror.ErrAlreadyStopped
 	r.stopOnce.Do(func() {
+		err = nil
+		if r.server == nil {
+			// Never started.
+			return
+		}
 		err = r.server.Close()
 	})
 	return err
 }
 
-func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
-	transport := "http"
+func (r *sfxReceiver) transport() string {
 	if r.config.TLSSetting != nil {
-		transport = "https"
+		return "https"
 	}
-	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
-	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+	return "http"
+}
 
+// readBody validates the request headers and returns its decompressed body
+// and content type. On failure the request is already answered and ok is
+// false.
+func (r *sfxReceiver) readBody(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+) (body []byte, contentType string, ok bool) {
 	if req.Method != http.MethodPost {
 		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return
+		return nil, "", false
 	}
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
+	contentType = mediaType(req.Header.Get(httpContentTypeHeader))
+	if contentType != protobufContentType && contentType != jsonContentType {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
-		return
+		return nil, "", false
 	}
 
 	encoding := req.Header.Get(httpContentEncodingHeader)
 	if encoding != "" && encoding != gzipEncoding {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return
+		return nil, "", false
 	}
 
 	bodyReader := req.Body
@@ -196,29 +258,95 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 		bodyReader, err = gzip.NewReader(bodyReader)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return
+			return nil, "", false
 		}
 	}
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
+		return nil, "", false
+	}
+
+	return body, contentType, true
+}
+
+// mediaType returns the media type of a Content-Type header value without
+// any parameters, e.g. "application/json; charset=utf-8" becomes
+// "application/json".
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mt
+}
+
+func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	body, contentType, ok := r.readBody(ctx, resp, req)
+	if !ok {
 		return
 	}
 
-	msg := &sfxpb.DataPointUploadMessage{}
-	if err = msg.Unmarshal(body); err != nil {
+	var datapoints []*sfxpb.DataPoint
+	var err error
+	if contentType == jsonContentType {
+		datapoints, err = decodeJSONV2DataPoints(body)
+	} else {
+		msg := &sfxpb.DataPointUploadMessage{}
+		err = msg.Unmarshal(body)
+		datapoints = msg.Datapoints
+	}
+	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
-	if len(msg.Datapoints) == 0 {
+	r.consumeDatapoints(ctx, resp, req, datapoints)
+}
+
+func (r *sfxReceiver) handleV1DatapointReq(resp http.ResponseWriter, req *http.Request) {
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	body, contentType, ok := r.readBody(ctx, resp, req)
+	if !ok {
+		return
+	}
+
+	var datapoints []*sfxpb.DataPoint
+	var err error
+	if contentType == jsonContentType {
+		datapoints, err = decodeJSONV1DataPoints(body)
+	} else {
+		datapoints, err = decodeProtobufV1DataPoints(body)
+	}
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	r.consumeDatapoints(ctx, resp, req, datapoints)
+}
+
+func (r *sfxReceiver) consumeDatapoints(
+	ctx context.Context,
+	resp http.ResponseWriter,
+	req *http.Request,
+	datapoints []*sfxpb.DataPoint,
+) {
+	if len(datapoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	md, _ := signalFxV2ToMetricsData(r.logger, msg.Datapoints)
+	md, _ := signalFxV2ToMetricsData(r.logger, datapoints)
 
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
@@ -232,12 +360,68 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	err = r.nextConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
+	err := r.nextConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
+	obsreport.EndMetricsReceiveOp(
+		ctx,
+		typeStr,
+		len(datapoints),
+		len(datapoints),
+		err)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
+		return
+	}
+
+	resp.WriteHeader(http.StatusAccepted)
+	resp.Write(okRespBody)
+}
+
+func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request) {
+	// There are no obsreport operations for logs receivers yet, events are
+	// recorded with the metrics operation instead, one point per event.
+	transport := r.transport()
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	body, contentType, ok := r.readBody(ctx, resp, req)
+	if !ok {
+		return
+	}
+
+	var events []*sfxpb.Event
+	var err error
+	if contentType == jsonContentType {
+		events, err = decodeJSONEvents(body)
+	} else {
+		msg := &sfxpb.EventUploadMessage{}
+		err = msg.Unmarshal(body)
+		events = msg.Events
+	}
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	if len(events) == 0 {
+		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
+		resp.Write(okRespBody)
+		return
+	}
+
+	ld := signalFxV2EventsToLogData(events)
+
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			ld.ResourceLogs().At(0).Resource().Attributes().UpsertString(splunk.SFxAccessTokenLabel, accessToken)
+		}
+	}
+
+	err = r.logsConsumer.ConsumeLogs(ctx, ld)
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Datapoints),
-		len(msg.Datapoints),
+		len(events),
+		len(events),
 		err)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
@@ -28,10 +28,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"github.com/stretchr/testify/assert"
@@ -45,6 +47,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.opentelemetry.io/collector/testutil"
 	"go.opentelemetry.io/collector/testutil/metricstestutil"
 	"go.uber.org/zap"
@@ -169,7 +172,7 @@ func Test_signalfxeceiver_EndToEnd(t *testing.T) {
 	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
 }
 
-func Test_sfxReceiver_handleReq(t *testing.T) {
+func Test_sfxReceiver_handleDatapointReq(t *testing.T) {
 	config := createDefaultConfig().(*Config)
 	config.Endpoint = "localhost:0" // Actually not creating the endpoint
 
@@ -287,6 +290,31 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				body := `{"gauge":[{"metric":"single","value":13,"dimensions":{"k0":"v0"}}]}`
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", strings.NewReader(`{"gauge":`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
 		{
 			name: "bad_gzipped_msg",
 			req: func() *http.Request {
@@ -312,7 +340,7 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 
 			r := rcv.(*sfxReceiver)
 			w := httptest.NewRecorder()
-			r.handleReq(w, tt.req)
+			r.handleDatapointReq(w, tt.req)
 
 			resp := w.Result()
 			respBytes, err := ioutil.ReadAll(resp.Body)
@@ -478,7 +506,7 @@ func Test_sfxReceiver_AccessTokenPassthrough(t *testing.T) {
 
 			r := rcv.(*sfxReceiver)
 			w := httptest.NewRecorder()
-			r.handleReq(w, req)
+			r.handleDatapointReq(w, req)
 
 			resp := w.Result()
 			respBytes, err := ioutil.ReadAll(resp.Body)
@@ -508,6 +536,269 @@ func Test_sfxReceiver_AccessTokenPassthrough(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_handleV1DatapointReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	dp := &sfxpb.DataPoint{
+		Source: "myhost",
+		Metric: "single",
+		Value:  sfxpb.Datum{IntValue: int64Ptr(13)},
+	}
+	dpBytes, err := dp.Marshal()
+	require.NoError(t, err)
+	var protobufBody []byte
+	for i := 0; i < 2; i++ {
+		protobufBody = append(protobufBody, proto.EncodeVarint(uint64(len(dpBytes)))...)
+		protobufBody = append(protobufBody, dpBytes...)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int
+		wantBody    string
+		wantCount   int
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(`{"source":"myhost","metric":"single","value":13}{"source":"myhost","metric":"single","value":13}`),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantCount:   2,
+		},
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        protobufBody,
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantCount:   2,
+		},
+		{
+			name:        "truncated_protobuf",
+			contentType: "application/x-protobuf",
+			body:        protobufBody[:len(protobufBody)-1],
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+		{
+			name:        "bad_json",
+			contentType: "application/json",
+			body:        []byte(`{"metric":`),
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkMetricsExporter)
+			rcv, err := New(zap.NewNop(), *config, sink)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("POST", "http://localhost/v1/datapoint", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+
+			r := rcv.(*sfxReceiver)
+			w := httptest.NewRecorder()
+			r.handleV1DatapointReq(w, req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+
+			if tt.wantCount == 0 {
+				assert.Empty(t, sink.AllMetrics())
+				return
+			}
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			got := pdatautil.MetricsToMetricsData(mds[0])
+			require.Len(t, got, 1)
+			require.Len(t, got[0].Metrics, tt.wantCount)
+			assert.Equal(t, "sf_source", got[0].Metrics[0].MetricDescriptor.LabelKeys[0].Key)
+			assert.Equal(t, "myhost", got[0].Metrics[0].Timeseries[0].LabelValues[0].Value)
+		})
+	}
+}
+
+func Test_sfxReceiver_handleEventReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.AccessTokenPassthrough = true
+
+	msg := &sfxpb.EventUploadMessage{
+		Events: []*sfxpb.Event{
+			{
+				EventType:  "deployment",
+				Category:   sfxpb.EventCategory_USER_DEFINED.Enum(),
+				Timestamp:  1574092046011,
+				Dimensions: buildNDimensions(1),
+			},
+		},
+	}
+	msgBytes, err := msg.Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int
+		wantBody    string
+		wantCount   int
+	}{
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        msgBytes,
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantCount:   1,
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(`[{"category":"USER_DEFINED","eventType":"deployment","dimensions":{"k0":"v0"},"timestamp":1574092046011}]`),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantCount:   1,
+		},
+		{
+			name:        "empty_json",
+			contentType: "application/json",
+			body:        []byte(`[]`),
+			wantStatus:  http.StatusOK,
+			wantBody:    responseOK,
+		},
+		{
+			name:        "bad_protobuf",
+			contentType: "application/x-protobuf",
+			body:        []byte{1, 2, 3, 4},
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    responseErrUnmarshalBody,
+		},
+		{
+			name:        "incorrect_content_type",
+			contentType: "text/plain",
+			body:        msgBytes,
+			wantStatus:  http.StatusUnsupportedMediaType,
+			wantBody:    responseInvalidContentType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkLogsExporter)
+			r, err := newReceiver(zap.NewNop(), *config)
+			require.NoError(t, err)
+			require.NoError(t, r.registerLogsConsumer(sink))
+
+			req := httptest.NewRequest("POST", "http://localhost/v2/event", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("X-Sf-Token", "myToken")
+
+			w := httptest.NewRecorder()
+			r.handleEventReq(w, req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+			assert.Equal(t, tt.wantCount, sink.LogRecordsCount())
+
+			if tt.wantCount == 0 {
+				return
+			}
+			rl := sink.AllLogs()[0].ResourceLogs().At(0)
+			token, ok := rl.Resource().Attributes().Get("com.splunk.signalfx.access_token")
+			require.True(t, ok)
+			assert.Equal(t, "myToken", token.StringVal())
+			lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+			assert.Equal(t, "deployment", lr.Name())
+			dim, ok := lr.Attributes().Get("k0")
+			require.True(t, ok)
+			assert.Equal(t, "v0", dim.StringVal())
+		})
+	}
+}
+
+func Test_sfxReceiver_handleEventReqObsreport(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	sink := new(exportertest.SinkLogsExporter)
+	r, err := newReceiver(zap.NewNop(), *config)
+	require.NoError(t, err)
+	require.NoError(t, r.registerLogsConsumer(sink))
+
+	send := func(body string) int {
+		req := httptest.NewRequest("POST", "http://localhost/v2/event", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.handleEventReq(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusAccepted, send(`[{"category":"USER_DEFINED","eventType":"deployment","timestamp":1574092046011}]`))
+
+	sink.SetConsumeLogError(errors.New("consumer error"))
+	assert.Equal(t, http.StatusInternalServerError, send(`[`+
+		`{"category":"USER_DEFINED","eventType":"deployment","timestamp":1574092046011},`+
+		`{"category":"USER_DEFINED","eventType":"rollback","timestamp":1574092046012}]`))
+
+	obsreporttest.CheckReceiverMetricsViews(t, config.Name(), "http", 1, 2)
+}
+
+func Test_sfxReceiver_SharedMetricsAndLogs(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	metricsSink := new(exportertest.SinkMetricsExporter)
+	mr, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, metricsSink)
+	require.NoError(t, err)
+	logsSink := new(exportertest.SinkLogsExporter)
+	lr, err := factory.(component.LogsReceiverFactory).CreateLogsReceiver(context.Background(), params, cfg, logsSink)
+	require.NoError(t, err)
+	assert.Same(t, mr, lr)
+
+	require.NoError(t, mr.Start(context.Background(), componenttest.NewNopHost()))
+	defer mr.Shutdown(context.Background())
+
+	post := func(path, body string) int {
+		resp, err := http.Post("http://"+addr+path, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusAccepted, post("/v2/datapoint", `{"counter":[{"metric":"c","value":1}]}`))
+	assert.Equal(t, http.StatusAccepted, post("/v1/datapoint", `{"metric":"g","value":1.5}`))
+	assert.Equal(t, http.StatusAccepted, post("/v2/event", `[{"eventType":"deployment"}]`))
+
+	assert.Len(t, metricsSink.AllMetrics(), 2)
+	assert.Equal(t, 1, logsSink.LogRecordsCount())
+}
+
 func buildSFxMsg(time int64, value int64, dimensions uint) *sfxpb.DataPointUploadMessage {
 	return &sfxpb.DataPointUploadMessage{
 		Datapoints: []*sfxpb.DataPoint{
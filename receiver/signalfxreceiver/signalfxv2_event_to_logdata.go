@@ -0,0 +1,99 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+// signalFxV2EventsToLogData converts SignalFx event proto data into a
+// pdata.Logs with a single log record per event. The event type becomes the
+// log record name, dimensions become string attributes and the category and
+// properties are kept under the "com.splunk.signalfx" attribute namespace.
+func signalFxV2EventsToLogData(events []*sfxpb.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	rl.Resource().InitEmpty()
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	logSlice := ills.At(0).Logs()
+	logSlice.Resize(len(events))
+
+	n := 0
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+
+		lr := logSlice.At(n)
+		n++
+
+		lr.SetName(event.EventType)
+		// SignalFx timestamps are in milliseconds.
+		lr.SetTimestamp(pdata.TimestampUnixNano(event.Timestamp * 1e6))
+
+		attrs := lr.Attributes()
+		attrs.InitEmptyWithCapacity(len(event.Dimensions) + 2)
+		for _, dim := range event.Dimensions {
+			if dim == nil {
+				continue
+			}
+			attrs.InsertString(dim.Key, dim.Value)
+		}
+
+		if event.Category != nil {
+			attrs.InsertInt(splunk.SFxEventCategoryKey, int64(*event.Category))
+		}
+
+		if len(event.Properties) > 0 {
+			propMapVal := pdata.NewAttributeValueMap()
+			propMap := propMapVal.MapVal()
+			propMap.InitEmptyWithCapacity(len(event.Properties))
+			for _, prop := range event.Properties {
+				if prop == nil {
+					continue
+				}
+				insertPropertyValue(propMap, prop.Key, prop.Value)
+			}
+			attrs.Insert(splunk.SFxEventPropertiesKey, propMapVal)
+		}
+	}
+	logSlice.Resize(n)
+
+	return ld
+}
+
+func insertPropertyValue(attrs pdata.AttributeMap, key string, value *sfxpb.PropertyValue) {
+	switch {
+	case value == nil:
+		attrs.InsertNull(key)
+	case value.StrValue != nil:
+		attrs.InsertString(key, *value.StrValue)
+	case value.IntValue != nil:
+		attrs.InsertInt(key, *value.IntValue)
+	case value.DoubleValue != nil:
+		attrs.InsertDouble(key, *value.DoubleValue)
+	case value.BoolValue != nil:
+		attrs.InsertBool(key, *value.BoolValue)
+	default:
+		attrs.InsertNull(key)
+	}
+}
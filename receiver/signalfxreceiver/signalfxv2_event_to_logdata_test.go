@@ -0,0 +1,82 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func Test_signalFxV2EventsToLogData(t *testing.T) {
+	events := []*sfxpb.Event{
+		{
+			EventType:  "shutdown",
+			Category:   sfxpb.EventCategory_USER_DEFINED.Enum(),
+			Timestamp:  1574092046011,
+			Dimensions: buildNDimensions(2),
+			Properties: []*sfxpb.Property{
+				{Key: "env", Value: &sfxpb.PropertyValue{StrValue: strPtr("prod")}},
+				{Key: "isActive", Value: &sfxpb.PropertyValue{BoolValue: boolPtr(true)}},
+				{Key: "rack", Value: &sfxpb.PropertyValue{IntValue: int64Ptr(5)}},
+				{Key: "temp", Value: &sfxpb.PropertyValue{DoubleValue: float64Ptr(40.5)}},
+				{Key: "nil", Value: nil},
+			},
+		},
+		nil,
+		{
+			EventType: "startup",
+		},
+	}
+
+	ld := signalFxV2EventsToLogData(events)
+	require.Equal(t, 2, ld.LogRecordCount())
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+
+	lr := logs.At(0)
+	assert.Equal(t, "shutdown", lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(1574092046011*1e6), lr.Timestamp())
+
+	attrs := lr.Attributes()
+	assert.Equal(t, 4, attrs.Len())
+	dim, ok := attrs.Get("k1")
+	require.True(t, ok)
+	assert.Equal(t, "v1", dim.StringVal())
+	category, ok := attrs.Get("com.splunk.signalfx.event_category")
+	require.True(t, ok)
+	assert.Equal(t, int64(sfxpb.EventCategory_USER_DEFINED), category.IntVal())
+
+	propsVal, ok := attrs.Get("com.splunk.signalfx.event_properties")
+	require.True(t, ok)
+	props := propsVal.MapVal()
+	assert.Equal(t, 5, props.Len())
+	v, _ := props.Get("env")
+	assert.Equal(t, "prod", v.StringVal())
+	v, _ = props.Get("isActive")
+	assert.True(t, v.BoolVal())
+	v, _ = props.Get("rack")
+	assert.Equal(t, int64(5), v.IntVal())
+	v, _ = props.Get("temp")
+	assert.Equal(t, 40.5, v.DoubleVal())
+	v, _ = props.Get("nil")
+	assert.EqualValues(t, pdata.AttributeValueNULL, v.Type())
+
+	lr = logs.At(1)
+	assert.Equal(t, "startup", lr.Name())
+	assert.Equal(t, 0, lr.Attributes().Len())
+}
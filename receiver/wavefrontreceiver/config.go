@@ -17,6 +17,7 @@ package wavefrontreceiver
 import (
 	"time"
 
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 )
@@ -32,4 +33,9 @@ type Config struct {
 	// ExtractCollectdTags instructs the Wavefront receiver to attempt to extract
 	// tags in the CollectD format from the metric name. The default is false.
 	ExtractCollectdTags bool `mapstructure:"extract_collectd_tags"`
+
+	// HTTP configures the listener of the HTTP ingestion API, used by the
+	// Wavefront SDKs for direct ingestion, on the /report path. It is
+	// disabled if not set.
+	HTTP *confighttp.HTTPServerSettings `mapstructure:"http"`
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
@@ -57,6 +58,9 @@ func TestLoadConfig(t *testing.T) {
 			},
 			TCPIdleTimeout:      5 * time.Second,
 			ExtractCollectdTags: true,
+			HTTP: &confighttp.HTTPServerSettings{
+				Endpoint: "localhost:8081",
+			},
 		},
 		r1)
 }
@@ -18,13 +18,14 @@ import (
 	"context"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
 )
 
@@ -40,7 +41,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -57,33 +59,56 @@ func createDefaultConfig() configmodels.Receiver {
 }
 
 func createMetricsReceiver(
-	ctx context.Context,
+	_ context.Context,
 	params component.ReceiverCreateParams,
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
 
-	rCfg := cfg.(*Config)
+	r, err := getReceiver(params.Logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.metricsConsumer = consumer
+	return r, nil
+}
 
-	// Wavefront is very similar to Carbon: it is TCP based in which each received
-	// text line represents a single metric data point. They differ on the format
-	// of their textual representation.
-	//
-	// The Wavefront receiver leverages the Carbon receiver code by implementing
-	// a dedicated parser for its format.
-	carbonCfg := carbonreceiver.Config{
-		ReceiverSettings: rCfg.ReceiverSettings,
-		NetAddr: confignet.NetAddr{
-			Endpoint:  rCfg.Endpoint,
-			Transport: "tcp",
-		},
-		TCPIdleTimeout: rCfg.TCPIdleTimeout,
-		Parser: &protocol.Config{
-			Type: "plaintext", // TODO: update after other parsers are implemented for Carbon receiver.
-			Config: &WavefrontParser{
-				ExtractCollectdTags: rCfg.ExtractCollectdTags,
-			},
-		},
+func createTraceReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	if consumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
 	}
-	return carbonreceiver.New(params.Logger, carbonCfg, consumer)
+
+	r, err := getReceiver(params.Logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.tracesConsumer = consumer
+	return r, nil
 }
+
+// Wavefront is very similar to Carbon: it is TCP based in which each received
+// text line represents a single data point. However the same port also
+// receives spans, so the receiver routes each line to the metrics or to the
+// traces pipeline, still using the Carbon parser interface for metrics.
+func getReceiver(logger *zap.Logger, cfg configmodels.Receiver) (*wavefrontReceiver, error) {
+	rCfg := cfg.(*Config)
+	r, err := receivers.GetOrAdd(rCfg, func() (interface{}, error) {
+		return newReceiver(logger, rCfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.(*wavefrontReceiver), nil
+}
+
+// receivers holds the wavefrontReceiver shared by the metrics and trace
+// pipelines of each configuration.
+var receivers = sharedcomponent.NewComponents()
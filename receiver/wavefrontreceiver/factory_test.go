@@ -41,3 +41,25 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
+
+func TestCreateTraceReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0" // Endpoint is required, not going to be used here.
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.NoError(t, err)
+	tReceiver, err := createTraceReceiver(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	assert.NoError(t, err)
+	// The metrics and the traces pipelines share the same receiver.
+	assert.Same(t, mReceiver, tReceiver)
+
+	_, err = createTraceReceiver(context.Background(), params, cfg, nil)
+	assert.Error(t, err)
+
+	// A receiver that was shut down is not handed out again.
+	assert.NoError(t, mReceiver.Shutdown(context.Background()))
+	tReceiver, err = createTraceReceiver(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	assert.NoError(t, err)
+	assert.NotSame(t, mReceiver, tReceiver)
+}
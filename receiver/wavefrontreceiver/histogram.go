@@ -0,0 +1,178 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// histogramGranularities are the prefixes of the Wavefront histogram lines,
+// the histogram aggregates the values of a minute, an hour or a day.
+var histogramGranularities = map[string]bool{
+	"!M": true,
+	"!H": true,
+	"!D": true,
+}
+
+// centroid is a value of a Wavefront histogram and the number of times that
+// it was observed.
+type centroid struct {
+	mean  float64
+	count int64
+}
+
+// parseHistogram receives a Wavefront histogram, see
+// https://docs.wavefront.com/proxies_histograms.html#sending-histogram-distributions,
+// in the following format:
+//
+//	"{!M | !H | !D} [<timestamp>] {#<count> <mean>}+ <metricName> source=<source> [pointTags]"
+//
+// The histogram is converted to a gauge distribution, since it only covers
+// the values of its interval. The centroid means are used as the bucket
+// bounds, so each centroid count lands in its own bucket and no precision is
+// lost: bucket 0 is always empty and bucket i holds the count of the centroid
+// with mean bounds[i-1].
+func (wp *WavefrontParser) parseHistogram(line string) (*metricspb.Metric, error) {
+	granularity, rest := nextToken(line)
+	if !histogramGranularities[granularity] {
+		return nil, fmt.Errorf("invalid wavefront histogram granularity [%s]", line)
+	}
+
+	ts := timestamp.Timestamp{Seconds: time.Now().Unix()}
+	token, rest := nextToken(rest)
+	if !strings.HasPrefix(token, "#") {
+		unixTime, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp for wavefront histogram [%s]", line)
+		}
+		ts.Seconds = unixTime
+		token, rest = nextToken(rest)
+	}
+
+	var centroids []centroid
+	for strings.HasPrefix(token, "#") {
+		count, err := strconv.ParseInt(token[1:], 10, 64)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid count for wavefront histogram [%s]", line)
+		}
+		var meanStr string
+		meanStr, rest = nextToken(rest)
+		mean, err := strconv.ParseFloat(meanStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mean for wavefront histogram [%s]: %v", line, err)
+		}
+		centroids = append(centroids, centroid{mean: mean, count: count})
+		token, rest = nextToken(rest)
+	}
+	if len(centroids) == 0 {
+		return nil, fmt.Errorf("no centroids for wavefront histogram [%s]", line)
+	}
+
+	metricName := unDoubleQuote(token)
+	if metricName == "" {
+		return nil, fmt.Errorf("empty name for wavefront histogram [%s]", line)
+	}
+
+	labelKeys, labelValues, err := buildLabels(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wavefront histogram [%s]: %v", line, err)
+	}
+
+	if wp.ExtractCollectdTags {
+		metricName, labelKeys, labelValues = wp.injectCollectDLabels(metricName, labelKeys, labelValues)
+	}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      metricName,
+			Type:      metricspb.MetricDescriptor_GAUGE_DISTRIBUTION,
+			LabelKeys: labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: labelValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &ts,
+						Value: &metricspb.Point_DistributionValue{
+							DistributionValue: centroidsToDistribution(centroids),
+						},
+					},
+				},
+			},
+		},
+	}
+	return metric, nil
+}
+
+func centroidsToDistribution(centroids []centroid) *metricspb.DistributionValue {
+	sort.SliceStable(centroids, func(i, j int) bool {
+		return centroids[i].mean < centroids[j].mean
+	})
+
+	var count int64
+	var sum float64
+	bounds := make([]float64, 0, len(centroids))
+	buckets := []*metricspb.DistributionValue_Bucket{{}}
+	for _, c := range centroids {
+		count += c.count
+		sum += float64(c.count) * c.mean
+		// Bounds must be strictly increasing, merge centroids with the same mean.
+		if n := len(bounds); n > 0 && bounds[n-1] == c.mean {
+			buckets[n].Count += c.count
+			continue
+		}
+		bounds = append(bounds, c.mean)
+		buckets = append(buckets, &metricspb.DistributionValue_Bucket{Count: c.count})
+	}
+
+	mean := sum / float64(count)
+	var sumOfSquaredDeviation float64
+	for _, c := range centroids {
+		deviation := c.mean - mean
+		sumOfSquaredDeviation += float64(c.count) * deviation * deviation
+	}
+
+	return &metricspb.DistributionValue{
+		Count:                 count,
+		Sum:                   sum,
+		SumOfSquaredDeviation: sumOfSquaredDeviation,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
+
+// nextToken returns the text before the first space of s and the text after
+// it.
+func nextToken(s string) (string, string) {
+	parts := strings.SplitN(s, " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,166 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_wavefrontParser_parseHistogram(t *testing.T) {
+	tests := []struct {
+		line                string
+		extractCollectdTags bool
+		wantName            string
+		wantKeys            []string
+		wantValues          []string
+		wantTimestamp       *timestamp.Timestamp
+		wantDistribution    *metricspb.DistributionValue
+		wantErr             bool
+	}{
+		{
+			line:          "!M 1582231120 #2 1.5 #1 3 request.latency source=app1 region=us-west",
+			wantName:      "request.latency",
+			wantKeys:      []string{"source", "region"},
+			wantValues:    []string{"app1", "us-west"},
+			wantTimestamp: &timestamp.Timestamp{Seconds: 1582231120},
+			wantDistribution: buildDistribution(
+				3, 6, 1.5,
+				[]float64{1.5, 3},
+				[]int64{0, 2, 1}),
+		},
+		{
+			// Centroids are sorted and the ones with the same mean merged.
+			line:          `!H 1582231120 #1 10 #2 -2 #3 10 "quoted.name" source=app1`,
+			wantName:      "quoted.name",
+			wantKeys:      []string{"source"},
+			wantValues:    []string{"app1"},
+			wantTimestamp: &timestamp.Timestamp{Seconds: 1582231120},
+			wantDistribution: buildDistribution(
+				6, 36, 192,
+				[]float64{-2, 10},
+				[]int64{0, 2, 4}),
+		},
+		{
+			line:                "!D 1582231120 #1 0.5 cpu.[cpu=1]user source=app1",
+			extractCollectdTags: true,
+			wantName:            "cpu.user",
+			wantKeys:            []string{"source", "cpu"},
+			wantValues:          []string{"app1", "1"},
+			wantTimestamp:       &timestamp.Timestamp{Seconds: 1582231120},
+			wantDistribution: buildDistribution(
+				1, 0.5, 0,
+				[]float64{0.5},
+				[]int64{0, 1}),
+		},
+		{
+			// The timestamp is optional.
+			line:     "!M #1 1 no.timestamp",
+			wantName: "no.timestamp",
+			wantDistribution: buildDistribution(
+				1, 1, 0,
+				[]float64{1},
+				[]int64{0, 1}),
+		},
+		{
+			line:    "!S 1582231120 #1 1 invalid.granularity",
+			wantErr: true,
+		},
+		{
+			line:    "!M xyz #1 1 invalid.timestamp",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582231120 #0 1 zero.count",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582231120 #1 xyz invalid.mean",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582231120 no.centroids source=app1",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582231120 #1 1",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1582231120 #1 1 invalid.tags source",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			p := WavefrontParser{ExtractCollectdTags: tt.extractCollectdTags}
+			got, err := p.Parse(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantName, got.GetMetricDescriptor().GetName())
+			assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DISTRIBUTION, got.GetMetricDescriptor().GetType())
+			var keys, values []string
+			for i, key := range got.GetMetricDescriptor().GetLabelKeys() {
+				keys = append(keys, key.Key)
+				values = append(values, got.Timeseries[0].LabelValues[i].Value)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+			assert.Equal(t, tt.wantValues, values)
+
+			require.Len(t, got.Timeseries[0].Points, 1)
+			point := got.Timeseries[0].Points[0]
+			if tt.wantTimestamp != nil {
+				assert.Equal(t, tt.wantTimestamp, point.Timestamp)
+			} else {
+				assert.NotZero(t, point.Timestamp.Seconds)
+			}
+			assert.Equal(t, tt.wantDistribution, point.GetDistributionValue())
+		})
+	}
+}
+
+func buildDistribution(
+	count int64,
+	sum float64,
+	sumOfSquaredDeviation float64,
+	bounds []float64,
+	bucketCounts []int64,
+) *metricspb.DistributionValue {
+	buckets := make([]*metricspb.DistributionValue_Bucket, 0, len(bucketCounts))
+	for _, c := range bucketCounts {
+		buckets = append(buckets, &metricspb.DistributionValue_Bucket{Count: c})
+	}
+	return &metricspb.DistributionValue{
+		Count:                 count,
+		Sum:                   sum,
+		SumOfSquaredDeviation: sumOfSquaredDeviation,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
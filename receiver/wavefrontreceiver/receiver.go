@@ -0,0 +1,417 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdatautil"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const (
+	// reportPath is the path of the HTTP ingestion API used by the Wavefront
+	// SDKs and proxies, see https://docs.wavefront.com/direct_ingestion.html.
+	reportPath = "/report"
+
+	// maxLineSize is the maximum size of a single line.
+	maxLineSize = 1024 * 1024
+
+	// maxReportSize is the maximum size of the body of a request to the HTTP
+	// ingestion API, both as sent and once decompressed.
+	maxReportSize = 16 * 1024 * 1024
+
+	httpServerTimeout = 20 * time.Second
+)
+
+// formats of the "f" query parameter of the HTTP ingestion API supported by
+// the receiver, the format of each line is detected regardless of it.
+var supportedHTTPFormats = map[string]bool{
+	"":            true,
+	"wavefront":   true,
+	"graphite_v2": true,
+	"histogram":   true,
+	"trace":       true,
+}
+
+var (
+	errNoValidLines   = errors.New("no valid lines in the request")
+	errReportTooLarge = fmt.Errorf("request body larger than %d bytes", maxReportSize)
+)
+
+// wavefrontReceiver receives Wavefront metrics, histograms and spans, one
+// per line, over TCP and, if configured, over the HTTP ingestion API.
+type wavefrontReceiver struct {
+	sync.Mutex
+	logger          *zap.Logger
+	config          *Config
+	parser          *WavefrontParser
+	metricsConsumer consumer.MetricsConsumer
+	tracesConsumer  consumer.TraceConsumer
+	idleTimeout     time.Duration
+
+	ln         net.Listener
+	httpServer *http.Server
+	wg         sync.WaitGroup
+	connsMtx   sync.Mutex
+	conns      map[net.Conn]struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.MetricsReceiver = (*wavefrontReceiver)(nil)
+var _ component.TraceReceiver = (*wavefrontReceiver)(nil)
+
+func newReceiver(logger *zap.Logger, config *Config) (*wavefrontReceiver, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("empty endpoint")
+	}
+	if config.TCPIdleTimeout < 0 {
+		return nil, fmt.Errorf("invalid idle timeout: %v", config.TCPIdleTimeout)
+	}
+	idleTimeout := config.TCPIdleTimeout
+	if idleTimeout == 0 {
+		idleTimeout = transport.TCPIdleTimeoutDefault
+	}
+
+	r := &wavefrontReceiver{
+		logger: logger,
+		config: config,
+		parser: &WavefrontParser{
+			ExtractCollectdTags: config.ExtractCollectdTags,
+		},
+		idleTimeout: idleTimeout,
+		conns:       make(map[net.Conn]struct{}),
+	}
+	return r, nil
+}
+
+// Start starts the TCP listener and, if configured, the HTTP listener.
+func (r *wavefrontReceiver) Start(_ context.Context, host component.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		r.ln, err = net.Listen("tcp", r.config.Endpoint)
+		if err != nil {
+			err = fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+			return
+		}
+
+		if r.config.HTTP != nil {
+			var httpLn net.Listener
+			httpLn, err = r.config.HTTP.ToListener()
+			if err != nil {
+				r.ln.Close()
+				err = fmt.Errorf("failed to bind to address %s: %w", r.config.HTTP.Endpoint, err)
+				return
+			}
+
+			mx := http.NewServeMux()
+			mx.HandleFunc(reportPath, r.handleReport)
+			r.httpServer = r.config.HTTP.ToServer(mx)
+			r.httpServer.ReadHeaderTimeout = httpServerTimeout
+			r.httpServer.WriteTimeout = httpServerTimeout
+
+			go func() {
+				if errHTTP := r.httpServer.Serve(httpLn); errHTTP != http.ErrServerClosed {
+					host.ReportFatalError(errHTTP)
+				}
+			}()
+		}
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.acceptConnections()
+		}()
+	})
+
+	return err
+}
+
+// Shutdown stops the listeners and closes any open TCP connection.
+func (r *wavefrontReceiver) Shutdown(context.Context) error {
+	receivers.Remove(r)
+
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.ln == nil {
+			// Never started.
+			return
+		}
+
+		var errs []error
+		if closeErr := r.ln.Close(); closeErr != nil {
+			errs = append(errs, closeErr)
+		}
+		if r.httpServer != nil {
+			if closeErr := r.httpServer.Close(); closeErr != nil {
+				errs = append(errs, closeErr)
+			}
+		}
+
+		r.connsMtx.Lock()
+		for conn := range r.conns {
+			conn.Close()
+		}
+		r.connsMtx.Unlock()
+
+		r.wg.Wait()
+		err = componenterror.CombineErrors(errs)
+	})
+	return err
+}
+
+func (r *wavefrontReceiver) acceptConnections() {
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			r.logger.Debug("Wavefront receiver stopped accepting connections", zap.Error(err))
+			return
+		}
+
+		r.connsMtx.Lock()
+		r.conns[conn] = struct{}{}
+		r.connsMtx.Unlock()
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.handleConnection(conn)
+
+			r.connsMtx.Lock()
+			delete(r.conns, conn)
+			r.connsMtx.Unlock()
+		}()
+	}
+}
+
+func (r *wavefrontReceiver) handleConnection(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(r.idleTimeout)); err != nil {
+			r.logger.Debug("Wavefront receiver failed to set connection deadline", zap.Error(err))
+			return
+		}
+
+		// It is possible to have data in line and err to be io.EOF, typically
+		// when the last line is not terminated by a new line.
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line != "" {
+			if consumeErr := r.consumeLines(context.Background(), "tcp", []string{line}); consumeErr != nil {
+				// The protocol doesn't account for returning errors, close
+				// the connection to report it back to the client.
+				return
+			}
+		}
+
+		if err != nil {
+			if err != io.EOF {
+				r.logger.Debug("Wavefront receiver connection closed", zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+func (r *wavefrontReceiver) handleReport(resp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(resp, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := req.URL.Query().Get("f")
+	if !supportedHTTPFormats[format] {
+		http.Error(resp, fmt.Sprintf("unsupported format %q", format), http.StatusBadRequest)
+		return
+	}
+
+	if req.ContentLength > maxReportSize {
+		http.Error(resp, errReportTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// The limit of the MaxBytesReader is one byte higher, so that a body just
+	// above the limit is reported by the sizeLimitedReader as too large.
+	var body io.Reader = &sizeLimitedReader{r: http.MaxBytesReader(resp, req.Body, maxReportSize+1)}
+	switch req.Header.Get("Content-Encoding") {
+	case "":
+	case "gzip":
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			if err == errReportTooLarge {
+				http.Error(resp, err.Error(), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(resp, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer gzipReader.Close()
+		body = &sizeLimitedReader{r: gzipReader}
+	default:
+		http.Error(resp, "unsupported content encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if err == errReportTooLarge {
+			http.Error(resp, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transport := "http"
+	if r.config.HTTP.TLSSetting != nil {
+		transport = "https"
+	}
+	err := r.consumeLines(req.Context(), transport, lines)
+	switch {
+	case err == errNoValidLines:
+		http.Error(resp, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		http.Error(resp, err.Error(), http.StatusInternalServerError)
+	default:
+		resp.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// sizeLimitedReader fails with errReportTooLarge once more than maxReportSize
+// bytes are read from r.
+type sizeLimitedReader struct {
+	r    io.Reader
+	read int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > maxReportSize {
+		return 0, errReportTooLarge
+	}
+	return n, err
+}
+
+// consumeLines parses the lines and sends the resulting metrics and spans to
+// the respective next consumer. Invalid lines are dropped, if all the lines
+// are invalid errNoValidLines is returned. Otherwise, the error, if any, is
+// the one of the next consumers.
+func (r *wavefrontReceiver) consumeLines(ctx context.Context, transport string, lines []string) error {
+	var metrics []*metricspb.Metric
+	var spans []wavefrontSpan
+	var numInvalid int
+	for _, line := range lines {
+		var err error
+		if isSpanLine(line) {
+			var span wavefrontSpan
+			if span, err = parseSpan(line); err == nil {
+				spans = append(spans, span)
+			}
+		} else {
+			var metric *metricspb.Metric
+			if metric, err = r.parser.Parse(line); err == nil {
+				metrics = append(metrics, metric)
+			}
+		}
+		if err != nil {
+			numInvalid++
+			r.logger.Debug(
+				"Wavefront translation error",
+				zap.String("receiver", r.config.Name()),
+				zap.Error(err))
+		}
+	}
+	if numInvalid > 0 && numInvalid == len(lines) {
+		return errNoValidLines
+	}
+
+	ctx = obsreport.ReceiverContext(ctx, r.config.Name(), transport, r.config.Name())
+	var errs []error
+	if len(metrics) > 0 {
+		if err := r.consumeMetrics(ctx, transport, metrics); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(spans) > 0 {
+		if err := r.consumeSpans(ctx, transport, spans); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (r *wavefrontReceiver) consumeMetrics(ctx context.Context, transport string, metrics []*metricspb.Metric) error {
+	if r.metricsConsumer == nil {
+		r.logger.Debug("Wavefront receiver dropped metrics without a metrics pipeline", zap.Int("metrics", len(metrics)))
+		return nil
+	}
+
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+	md := consumerdata.MetricsData{Metrics: metrics}
+	err := r.metricsConsumer.ConsumeMetrics(ctx, pdatautil.MetricsFromMetricsData([]consumerdata.MetricsData{md}))
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, len(metrics), len(metrics), err)
+	return err
+}
+
+func (r *wavefrontReceiver) consumeSpans(ctx context.Context, transport string, spans []wavefrontSpan) error {
+	if r.tracesConsumer == nil {
+		r.logger.Debug("Wavefront receiver dropped spans without a traces pipeline", zap.Int("spans", len(spans)))
+		return nil
+	}
+
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.Name(), transport)
+	err := r.tracesConsumer.ConsumeTraces(ctx, spansToTraces(spans))
+	obsreport.EndTraceDataReceiveOp(ctx, typeStr, len(spans), err)
+	return err
+}
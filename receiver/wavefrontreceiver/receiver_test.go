@@ -15,9 +15,14 @@
 package wavefrontreceiver
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -28,6 +33,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/pdatautil"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testutil"
@@ -152,3 +158,190 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 		sink.Reset()
 	}
 }
+
+func Test_wavefrontreceiver_MetricsAndTraces(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+	addr := testutil.GetAvailableLocalAddress(t)
+	rCfg.Endpoint = addr
+
+	metricsSink := new(exportertest.SinkMetricsExporter)
+	tracesSink := new(exportertest.SinkTraceExporter)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mRcvr, err := createMetricsReceiver(context.Background(), params, rCfg, metricsSink)
+	require.NoError(t, err)
+	tRcvr, err := createTraceReceiver(context.Background(), params, rCfg, tracesSink)
+	require.NoError(t, err)
+
+	require.NoError(t, mRcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer mRcvr.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_, err = fmt.Fprint(conn,
+		"single.metric 1 1582231120 source=e2e\n"+
+			"!M 1582231120 #2 1.5 #1 3 request.latency source=e2e\n"+
+			"getAllUsers source=e2e traceId="+testTraceID+" spanId="+testSpanID+" 1552949776000 343\n"+
+			"invalid line\n")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	testutil.WaitFor(t, func() bool {
+		return metricsSink.MetricsCount() == 2 && tracesSink.SpansCount() == 1
+	})
+
+	var names []string
+	for _, md := range metricsSink.AllMetrics() {
+		for _, ocmd := range pdatautil.MetricsToMetricsData(md) {
+			for _, metric := range ocmd.Metrics {
+				names = append(names, metric.GetMetricDescriptor().GetName())
+			}
+		}
+	}
+	assert.Equal(t, []string{"single.metric", "request.latency"}, names)
+
+	traces := tracesSink.AllTraces()
+	require.Len(t, traces, 1)
+	assert.Equal(t, "getAllUsers", traces[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+
+	assert.NoError(t, tRcvr.Shutdown(context.Background()))
+	assert.Error(t, mRcvr.Shutdown(context.Background()))
+}
+
+func Test_wavefrontreceiver_HTTP(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	rCfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	httpAddr := testutil.GetAvailableLocalAddress(t)
+	rCfg.HTTP = &confighttp.HTTPServerSettings{Endpoint: httpAddr}
+
+	metricsSink := new(exportertest.SinkMetricsExporter)
+	tracesSink := new(exportertest.SinkTraceExporter)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	rcvr, err := createMetricsReceiver(context.Background(), params, rCfg, metricsSink)
+	require.NoError(t, err)
+	_, err = createTraceReceiver(context.Background(), params, rCfg, tracesSink)
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	body := "single.metric 1 1582231120 source=e2e\n" +
+		"getAllUsers source=e2e traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 343\n"
+	var gzipBody bytes.Buffer
+	gzipWriter := gzip.NewWriter(&gzipBody)
+	_, err = gzipWriter.Write([]byte(body))
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	// Many valid lines adding up to more than maxReportSize, which compress
+	// into a small request.
+	line := "single.metric 1 1582231120 source=e2e\n"
+	oversizedBody := []byte(strings.Repeat(line, maxReportSize/len(line)+1))
+	var oversizedGzipBody bytes.Buffer
+	gzipWriter = gzip.NewWriter(&oversizedGzipBody)
+	_, err = gzipWriter.Write(oversizedBody)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	require.Less(t, oversizedGzipBody.Len(), maxReportSize)
+
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		encoding   string
+		body       []byte
+		chunked    bool // sent without Content-Length
+		wantStatus int
+		wantPoints int
+		wantSpans  int
+	}{
+		{
+			name:       "plain",
+			method:     http.MethodPost,
+			query:      "?f=wavefront",
+			body:       []byte(body),
+			wantStatus: http.StatusAccepted,
+			wantPoints: 1,
+			wantSpans:  1,
+		},
+		{
+			name:       "gzip",
+			method:     http.MethodPost,
+			query:      "?f=trace",
+			encoding:   "gzip",
+			body:       gzipBody.Bytes(),
+			wantStatus: http.StatusAccepted,
+			wantPoints: 1,
+			wantSpans:  1,
+		},
+		{
+			name:       "invalid_method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "unsupported_format",
+			method:     http.MethodPost,
+			query:      "?f=spanLogs",
+			body:       []byte(body),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported_encoding",
+			method:     http.MethodPost,
+			encoding:   "deflate",
+			body:       []byte(body),
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "oversized",
+			method:     http.MethodPost,
+			body:       oversizedBody,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "oversized_chunked",
+			method:     http.MethodPost,
+			body:       oversizedBody,
+			chunked:    true,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "oversized_gzip",
+			method:     http.MethodPost,
+			encoding:   "gzip",
+			body:       oversizedGzipBody.Bytes(),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "no_valid_lines",
+			method:     http.MethodPost,
+			body:       []byte("invalid line\n"),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metricsSink.Reset()
+			tracesSink.Reset()
+
+			var reqBody io.Reader = bytes.NewReader(tt.body)
+			if tt.chunked {
+				reqBody = ioutil.NopCloser(reqBody)
+			}
+			req, err := http.NewRequest(tt.method, "http://"+httpAddr+reportPath+tt.query, reqBody)
+			require.NoError(t, err)
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			// The request is processed synchronously.
+			assert.Equal(t, tt.wantPoints, metricsSink.MetricsCount())
+			assert.Equal(t, tt.wantSpans, tracesSink.SpansCount())
+		})
+	}
+}
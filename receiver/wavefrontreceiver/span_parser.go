@@ -0,0 +1,254 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// Span tags with a special meaning for Wavefront, see
+// https://docs.wavefront.com/trace_data_details.html#span-tags.
+const (
+	spanTagSource      = "source"
+	spanTagTraceID     = "traceId"
+	spanTagSpanID      = "spanId"
+	spanTagParent      = "parent"
+	spanTagFollowsFrom = "followsFrom"
+	spanTagApplication = "application"
+	spanTagService     = "service"
+	spanTagCluster     = "cluster"
+	spanTagShard       = "shard"
+	spanTagSpanLogs    = "_spanLogs"
+)
+
+var (
+	errSpanMissingTraceID = errors.New("missing traceId")
+	errSpanMissingSpanID  = errors.New("missing spanId")
+)
+
+// resourceSpanTags maps the span tags that describe the application
+// emitting the span to the attributes of the span resource.
+var resourceSpanTags = map[string]string{
+	spanTagSource:      conventions.AttributeHostHostname,
+	spanTagService:     conventions.AttributeServiceName,
+	spanTagApplication: spanTagApplication,
+	spanTagCluster:     spanTagCluster,
+	spanTagShard:       spanTagShard,
+}
+
+var spanKinds = map[string]pdata.SpanKind{
+	string(tracetranslator.OpenTracingSpanKindClient):   pdata.SpanKindCLIENT,
+	string(tracetranslator.OpenTracingSpanKindServer):   pdata.SpanKindSERVER,
+	string(tracetranslator.OpenTracingSpanKindProducer): pdata.SpanKindPRODUCER,
+	string(tracetranslator.OpenTracingSpanKindConsumer): pdata.SpanKindCONSUMER,
+	string(tracetranslator.OpenTracingSpanKindInternal): pdata.SpanKindINTERNAL,
+}
+
+// wavefrontSpan is a span and the attributes of the resource that emitted it.
+type wavefrontSpan struct {
+	resourceAttrs map[string]string
+	span          pdata.Span
+}
+
+// isSpanLine reports if the line is a Wavefront span instead of a metric: a
+// span is identified by its mandatory traceId and spanId tags.
+func isSpanLine(line string) bool {
+	return strings.Contains(line, " "+spanTagTraceID+"=") &&
+		strings.Contains(line, " "+spanTagSpanID+"=")
+}
+
+// parseSpan receives a Wavefront span, see
+// https://docs.wavefront.com/trace_data_details.html#wavefront-span-format,
+// in the following format:
+//
+//	"<operationName> source=<source> <spanTags> <start_milliseconds> <duration_milliseconds>"
+//
+// The span tags include the mandatory traceId and spanId, UUIDs that are
+// converted to the trace ID and to the span ID (its last 8 bytes).
+func parseSpan(line string) (wavefrontSpan, error) {
+	ws := wavefrontSpan{resourceAttrs: make(map[string]string)}
+
+	operationName, rest := nextToken(line)
+	operationName = unDoubleQuote(operationName)
+	if operationName == "" {
+		return ws, fmt.Errorf("empty operation name for wavefront span [%s]", line)
+	}
+
+	var durationStr, startStr string
+	rest, durationStr = lastToken(rest)
+	rest, startStr = lastToken(rest)
+	startMillis, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return ws, fmt.Errorf("invalid start time for wavefront span [%s]", line)
+	}
+	durationMillis, err := strconv.ParseInt(durationStr, 10, 64)
+	if err != nil || durationMillis < 0 {
+		return ws, fmt.Errorf("invalid duration for wavefront span [%s]", line)
+	}
+
+	keys, values, err := buildLabels(rest)
+	if err != nil {
+		return ws, fmt.Errorf("invalid wavefront span [%s]: %v", line, err)
+	}
+
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetName(operationName)
+	start := time.Unix(0, 0).Add(time.Duration(startMillis) * time.Millisecond)
+	span.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+	span.SetEndTime(pdata.TimestampUnixNano(start.Add(time.Duration(durationMillis) * time.Millisecond).UnixNano()))
+
+	attrs := span.Attributes()
+	attrs.InitEmptyWithCapacity(len(keys))
+	var traceID, spanID []byte
+	for i, key := range keys {
+		value := values[i].Value
+		switch key.Key {
+		case spanTagTraceID:
+			traceID, err = parseWavefrontID(value)
+		case spanTagSpanID:
+			spanID, err = parseWavefrontID(value)
+			if err == nil {
+				spanID = spanID[8:]
+			}
+		case spanTagParent:
+			var parentID []byte
+			parentID, err = parseWavefrontID(value)
+			if err == nil {
+				span.SetParentSpanID(pdata.NewSpanID(parentID[8:]))
+			}
+		case spanTagFollowsFrom:
+			var followsFromID []byte
+			followsFromID, err = parseWavefrontID(value)
+			if err == nil {
+				addSpanLink(span, followsFromID[8:])
+			}
+		case tracetranslator.TagSpanKind:
+			if kind, ok := spanKinds[value]; ok {
+				span.SetKind(kind)
+			} else {
+				attrs.InsertString(key.Key, value)
+			}
+		case tracetranslator.TagError:
+			if value == "true" {
+				span.Status().InitEmpty()
+				span.Status().SetCode(pdata.StatusCode(tracetranslator.OCUnknown))
+			}
+		case spanTagSpanLogs:
+			// Span logs are not supported.
+		default:
+			if resourceKey, ok := resourceSpanTags[key.Key]; ok {
+				ws.resourceAttrs[resourceKey] = value
+			} else {
+				attrs.InsertString(key.Key, value)
+			}
+		}
+		if err != nil {
+			return ws, fmt.Errorf("invalid %s for wavefront span [%s]: %v", key.Key, line, err)
+		}
+	}
+
+	if traceID == nil {
+		return ws, fmt.Errorf("invalid wavefront span [%s]: %v", line, errSpanMissingTraceID)
+	}
+	if spanID == nil {
+		return ws, fmt.Errorf("invalid wavefront span [%s]: %v", line, errSpanMissingSpanID)
+	}
+	span.SetTraceID(pdata.NewTraceID(traceID))
+	span.SetSpanID(pdata.NewSpanID(spanID))
+
+	// Links use the trace ID of the span itself, Wavefront references only
+	// spans of the same trace.
+	for i := 0; i < span.Links().Len(); i++ {
+		span.Links().At(i).SetTraceID(pdata.NewTraceID(traceID))
+	}
+
+	ws.span = span
+	return ws, nil
+}
+
+func addSpanLink(span pdata.Span, spanID []byte) {
+	links := span.Links()
+	links.Resize(links.Len() + 1)
+	links.At(links.Len() - 1).SetSpanID(pdata.NewSpanID(spanID))
+}
+
+// parseWavefrontID parses the UUIDs used by Wavefront as trace and span IDs.
+func parseWavefrontID(s string) ([]byte, error) {
+	id, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil {
+		return nil, err
+	}
+	if len(id) != 16 {
+		return nil, fmt.Errorf("expected an UUID, got %q", s)
+	}
+	return id, nil
+}
+
+// spansToTraces groups the spans by resource.
+func spansToTraces(spans []wavefrontSpan) pdata.Traces {
+	traces := pdata.NewTraces()
+	rss := traces.ResourceSpans()
+	resourceIndexes := make(map[string]int)
+	for _, ws := range spans {
+		key := resourceKey(ws.resourceAttrs)
+		idx, ok := resourceIndexes[key]
+		if !ok {
+			idx = rss.Len()
+			resourceIndexes[key] = idx
+			rss.Resize(idx + 1)
+			rs := rss.At(idx)
+			rs.Resource().InitEmpty()
+			attrs := rs.Resource().Attributes()
+			attrs.InitEmptyWithCapacity(len(ws.resourceAttrs))
+			for k, v := range ws.resourceAttrs {
+				attrs.InsertString(k, v)
+			}
+			rs.InstrumentationLibrarySpans().Resize(1)
+		}
+		span := ws.span
+		rss.At(idx).InstrumentationLibrarySpans().At(0).Spans().Append(&span)
+	}
+	return traces
+}
+
+func resourceKey(attrs map[string]string) string {
+	pairs := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\n")
+}
+
+// lastToken returns the text before the last space of s and the text after
+// it.
+func lastToken(s string) (string, string) {
+	idx := strings.LastIndexByte(s, ' ')
+	if idx == -1 {
+		return "", s
+	}
+	return s[:idx], s[idx+1:]
+}
@@ -0,0 +1,178 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	testTraceID  = "7b3bf470-9456-11e8-9eb6-529269fb1459"
+	testSpanID   = "0313bafe-9457-11e8-9eb6-529269fb1459"
+	testParentID = "2f64e538-9457-11e8-9eb6-529269fb1459"
+)
+
+func Test_isSpanLine(t *testing.T) {
+	assert.True(t, isSpanLine("op source=s traceId="+testTraceID+" spanId="+testSpanID+" 1552949776000 343"))
+	assert.False(t, isSpanLine("metric 1 1582231120 source=s"))
+	assert.False(t, isSpanLine("metric 1 1582231120 source=s traceId="+testTraceID))
+	assert.False(t, isSpanLine("!M 1582231120 #1 1 metric source=s"))
+}
+
+func Test_parseSpan(t *testing.T) {
+	line := "getAllUsers source=localhost traceId=" + testTraceID + " spanId=" + testSpanID +
+		" parent=" + testParentID + " application=Wavefront service=auth cluster=us-west-2 shard=secondary" +
+		` http.method=GET span.kind=server error=true _spanLogs=true component="quoted value" 1552949776000 343`
+
+	ws, err := parseSpan(line)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		conventions.AttributeHostHostname: "localhost",
+		conventions.AttributeServiceName:  "auth",
+		"application":                     "Wavefront",
+		"cluster":                         "us-west-2",
+		"shard":                           "secondary",
+	}, ws.resourceAttrs)
+
+	span := ws.span
+	assert.Equal(t, "getAllUsers", span.Name())
+	assert.Equal(t, pdata.NewTraceID([]byte{0x7b, 0x3b, 0xf4, 0x70, 0x94, 0x56, 0x11, 0xe8, 0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), span.TraceID())
+	assert.Equal(t, pdata.NewSpanID([]byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), span.SpanID())
+	assert.Equal(t, pdata.NewSpanID([]byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), span.ParentSpanID())
+	assert.Equal(t, pdata.SpanKindSERVER, span.Kind())
+	start := time.Unix(1552949776, 0)
+	assert.Equal(t, pdata.TimestampUnixNano(start.UnixNano()), span.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(start.Add(343*time.Millisecond).UnixNano()), span.EndTime())
+	assert.Equal(t, pdata.StatusCode(tracetranslator.OCUnknown), span.Status().Code())
+
+	attrs := span.Attributes()
+	assert.Equal(t, 2, attrs.Len())
+	method, ok := attrs.Get("http.method")
+	require.True(t, ok)
+	assert.Equal(t, "GET", method.StringVal())
+	quoted, ok := attrs.Get("component")
+	require.True(t, ok)
+	assert.Equal(t, "quoted value", quoted.StringVal())
+}
+
+func Test_parseSpan_FollowsFrom(t *testing.T) {
+	ws, err := parseSpan("consume source=s traceId=" + testTraceID + " spanId=" + testSpanID +
+		" followsFrom=" + testParentID + " span.kind=unknown 1552949776000 0")
+	require.NoError(t, err)
+
+	span := ws.span
+	assert.Nil(t, span.ParentSpanID().Bytes())
+	assert.Equal(t, pdata.SpanKindUNSPECIFIED, span.Kind())
+	assert.True(t, span.Status().IsNil())
+	kind, ok := span.Attributes().Get(tracetranslator.TagSpanKind)
+	require.True(t, ok)
+	assert.Equal(t, "unknown", kind.StringVal())
+
+	require.Equal(t, 1, span.Links().Len())
+	link := span.Links().At(0)
+	assert.Equal(t, span.TraceID(), link.TraceID())
+	assert.Equal(t, pdata.NewSpanID([]byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), link.SpanID())
+}
+
+func Test_parseSpan_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{
+			name: "missing_trace_id",
+			line: "op source=s spanId=" + testSpanID + " 1552949776000 343",
+		},
+		{
+			name: "missing_span_id",
+			line: "op source=s traceId=" + testTraceID + " 1552949776000 343",
+		},
+		{
+			name: "invalid_trace_id",
+			line: "op source=s traceId=xyz spanId=" + testSpanID + " 1552949776000 343",
+		},
+		{
+			name: "short_span_id",
+			line: "op source=s traceId=" + testTraceID + " spanId=0313bafe 1552949776000 343",
+		},
+		{
+			name: "invalid_parent",
+			line: "op source=s traceId=" + testTraceID + " spanId=" + testSpanID + " parent=xyz 1552949776000 343",
+		},
+		{
+			name: "missing_duration",
+			line: "op source=s traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000",
+		},
+		{
+			name: "negative_duration",
+			line: "op source=s traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 -1",
+		},
+		{
+			name: "invalid_start",
+			line: "op source=s traceId=" + testTraceID + " spanId=" + testSpanID + " now 343",
+		},
+		{
+			name: "empty_name",
+			line: `"" source=s traceId=` + testTraceID + " spanId=" + testSpanID + " 1552949776000 343",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSpan(tt.line)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_spansToTraces(t *testing.T) {
+	var spans []wavefrontSpan
+	for _, line := range []string{
+		"op0 source=host0 service=svc traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 1",
+		"op1 source=host1 service=svc traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 1",
+		"op2 service=svc source=host0 traceId=" + testTraceID + " spanId=" + testSpanID + " 1552949776000 1",
+	} {
+		ws, err := parseSpan(line)
+		require.NoError(t, err)
+		spans = append(spans, ws)
+	}
+
+	traces := spansToTraces(spans)
+	assert.Equal(t, 3, traces.SpanCount())
+	rss := traces.ResourceSpans()
+	require.Equal(t, 2, rss.Len())
+
+	wantSpans := [][]string{{"op0", "op2"}, {"op1"}}
+	for i := 0; i < rss.Len(); i++ {
+		host, ok := rss.At(i).Resource().Attributes().Get(conventions.AttributeHostHostname)
+		require.True(t, ok)
+		assert.Equal(t, "host"+string(rune('0'+i)), host.StringVal())
+
+		var names []string
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		require.Equal(t, 1, ilss.Len())
+		for j := 0; j < ilss.At(0).Spans().Len(); j++ {
+			names = append(names, ilss.At(0).Spans().At(j).Name())
+		}
+		assert.Equal(t, wantSpans[i], names)
+	}
+}
@@ -58,7 +58,14 @@ func (wp *WavefrontParser) BuildParser() (protocol.Parser, error) {
 // 	"<metricName> <metricValue> [<timestamp>] source=<source> [pointTags]"
 //
 // Detailed description of each element is available on the link above.
+//
+// Lines starting with "!M", "!H" or "!D" are Wavefront histograms, they are
+// handled by parseHistogram.
 func (wp *WavefrontParser) Parse(line string) (*metricspb.Metric, error) {
+	if strings.HasPrefix(line, "!") {
+		return wp.parseHistogram(line)
+	}
+
 	parts := strings.SplitN(line, " ", 3)
 	if len(parts) < 3 {
 		return nil, fmt.Errorf("invalid wavefront metric [%s]", line)